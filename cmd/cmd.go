@@ -3,8 +3,11 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/api7/apisix-mesh-agent/cmd/difflive"
 	"github.com/api7/apisix-mesh-agent/cmd/iptables"
 	"github.com/api7/apisix-mesh-agent/cmd/precheck"
+	"github.com/api7/apisix-mesh-agent/cmd/replay"
+	"github.com/api7/apisix-mesh-agent/cmd/selftest"
 	"github.com/api7/apisix-mesh-agent/cmd/sidecar"
 	"github.com/api7/apisix-mesh-agent/cmd/version"
 )
@@ -19,6 +22,9 @@ func NewMeshAgentCommand() *cobra.Command {
 		sidecar.NewCommand(),
 		version.NewCommand(),
 		precheck.NewCommand(),
+		replay.NewCommand(),
+		selftest.NewCommand(),
+		difflive.NewCommand(),
 		iptables.NewSetupCommand(),
 		iptables.NewCleanupIptablesCommand(),
 	)
@@ -0,0 +1,413 @@
+package difflive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/api7/apisix-mesh-agent/cmd/sidecar"
+	apisixutil "github.com/api7/apisix-mesh-agent/pkg/apisix"
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner/util"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner/xds/v3/file"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+const (
+	// EtcdSource fetches the live resources from the etcd (the one
+	// mimicked by the sidecar), through its HTTP gateway.
+	EtcdSource = "etcd"
+	// AdminAPISource fetches the live resources from the Admin API of
+	// Apache APISIX.
+	AdminAPISource = "admin-api"
+)
+
+var (
+	// _kinds are the kinds of resources, named as the etcd directories.
+	_kinds = []string{"routes", "upstreams", "plugin_configs", "consumers", "ssl"}
+	// _fetchTimeout is the timeout of each request to the live source.
+	_fetchTimeout = 10 * time.Second
+)
+
+func dief(template string, args ...interface{}) {
+	if !strings.HasSuffix(template, "\n") {
+		template += "\n"
+	}
+	_, _ = fmt.Fprintf(os.Stderr, template, args...)
+	os.Exit(1)
+}
+
+// NewCommand creates the diff-live command for apisix-mesh-agent.
+func NewCommand() *cobra.Command {
+	var (
+		source   string
+		endpoint string
+		adminKey string
+	)
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{
+		Use:   "diff-live --xds-watch-files <files> [flags]",
+		Short: "Diff the intended APISIX resources against the live ones",
+		Long: `Diff the intended APISIX resources against the live ones.
+
+The intended resources are translated from the xDS files (like the xds-v3-file provisioner in
+the oneshot mode), the live ones are fetched from the etcd mimicked by the sidecar (--source etcd)
+or the Admin API of Apache APISIX (--source admin-api). Resources which are missing, outdated or
+unexpected in the live source are printed, the command exits with 1 if there are any.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(cfg.XDSWatchFiles) == 0 {
+				dief("no xDS files, please specify them through --xds-watch-files")
+			}
+			intended, err := loadIntendedState(cfg)
+			if err != nil {
+				dief("failed to translate the xDS files: %s", err)
+			}
+			live, err := fetchLiveState(source, endpoint, cfg.EtcdKeyPrefix, adminKey)
+			if err != nil {
+				dief("failed to fetch the live resources: %s", err)
+			}
+			if !printDiff(os.Stdout, live, intended, cfg.DiffIgnoredFields) {
+				os.Exit(1)
+			}
+		},
+	}
+
+	// The intended resources are translated with the same options as the
+	// sidecar, only the logs are quieter by default.
+	cfg.LogLevel = "warn"
+	sidecar.AddTranslationFlags(cmd, cfg)
+	cmd.PersistentFlags().StringVar(&source, "source", EtcdSource, "the source of live resources, can be \"etcd\" or \"admin-api\"")
+	cmd.PersistentFlags().StringVar(&endpoint, "endpoint", "http://127.0.0.1:2379", "the endpoint of the live source")
+	cmd.PersistentFlags().StringVar(&adminKey, "admin-key", "", "the key of Admin API, only used by the admin-api source")
+	return cmd
+}
+
+// loadIntendedState translates the xDS files once and applies the events,
+// the state is the resources which the sidecar would write.
+func loadIntendedState(cfg *config.Config) (*util.Manifest, error) {
+	cfg.Provisioner = config.XDSV3FileProvisioner
+	cfg.XDSOneshot = true
+	p, err := file.NewXDSProvisioner(cfg)
+	if err != nil {
+		return nil, err
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- p.Run(stop)
+	}()
+
+	var (
+		routes        = make(map[string]*apisix.Route)
+		upstreams     = make(map[string]*apisix.Upstream)
+		pluginConfigs = make(map[string]*apisix.PluginConfig)
+		consumers     = make(map[string]*apisix.Consumer)
+		ssls          = make(map[string]*apisix.Ssl)
+	)
+	for events := range p.Channel() {
+		for _, ev := range events {
+			obj := ev.Object
+			if ev.Type == types.EventDelete {
+				obj = ev.Tombstone
+			}
+			switch o := obj.(type) {
+			case *apisix.Route:
+				if ev.Type == types.EventDelete {
+					delete(routes, o.Id)
+				} else {
+					routes[o.Id] = o
+				}
+			case *apisix.Upstream:
+				if ev.Type == types.EventDelete {
+					delete(upstreams, o.Id)
+				} else {
+					upstreams[o.Id] = o
+				}
+			case *apisix.PluginConfig:
+				if ev.Type == types.EventDelete {
+					delete(pluginConfigs, o.Id)
+				} else {
+					pluginConfigs[o.Id] = o
+				}
+			case *apisix.Consumer:
+				if ev.Type == types.EventDelete {
+					delete(consumers, o.Username)
+				} else {
+					consumers[o.Username] = o
+				}
+			case *apisix.Ssl:
+				if ev.Type == types.EventDelete {
+					delete(ssls, o.Id)
+				} else {
+					ssls[o.Id] = o
+				}
+			}
+		}
+	}
+	if err := <-errCh; err != nil {
+		return nil, err
+	}
+
+	var m util.Manifest
+	for _, r := range routes {
+		m.Routes = append(m.Routes, r)
+	}
+	for _, u := range upstreams {
+		m.Upstreams = append(m.Upstreams, u)
+	}
+	for _, pc := range pluginConfigs {
+		m.PluginConfigs = append(m.PluginConfigs, pc)
+	}
+	for _, c := range consumers {
+		m.Consumers = append(m.Consumers, c)
+	}
+	for _, s := range ssls {
+		m.Ssls = append(m.Ssls, s)
+	}
+	return &m, nil
+}
+
+// fetchLiveState fetches the resources from the live source.
+func fetchLiveState(source, endpoint, keyPrefix, adminKey string) (*util.Manifest, error) {
+	client := &http.Client{Timeout: _fetchTimeout}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	values := make(map[string][][]byte, len(_kinds))
+	for _, kind := range _kinds {
+		var (
+			items [][]byte
+			err   error
+		)
+		switch source {
+		case EtcdSource:
+			items, err = fetchFromEtcd(client, endpoint, keyPrefix+"/"+kind)
+		case AdminAPISource:
+			items, err = fetchFromAdminAPI(client, endpoint+"/apisix/admin/"+kind, adminKey)
+		default:
+			return nil, fmt.Errorf("unknown source %s", source)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", kind, err)
+		}
+		values[kind] = items
+	}
+	return decodeManifest(values)
+}
+
+// fetchFromEtcd fetches the values of keys in the directory through the
+// HTTP gateway of etcd, like Apache APISIX does.
+func fetchFromEtcd(client *http.Client, endpoint, dir string) ([][]byte, error) {
+	body, err := json.Marshal(map[string][]byte{
+		"key": []byte(dir),
+		// The range end of the prefix, e.g. "/apisix/routet" for "/apisix/routes".
+		"range_end": append([]byte(dir[:len(dir)-1]), dir[len(dir)-1]+1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint+"/v3/kv/range", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	data, err := doRequest(client, req)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Kvs []struct {
+			Value []byte `json:"value"`
+		} `json:"kvs"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	items := make([][]byte, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		items = append(items, kv.Value)
+	}
+	return items, nil
+}
+
+// fetchFromAdminAPI lists the resources through the Admin API, both the
+// v2 ("node.nodes") and v3 ("list") response formats are supported.
+func fetchFromAdminAPI(client *http.Client, url, adminKey string) ([][]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if adminKey != "" {
+		req.Header.Set("X-API-KEY", adminKey)
+	}
+	data, err := doRequest(client, req)
+	if err != nil {
+		return nil, err
+	}
+	type item struct {
+		Value json.RawMessage `json:"value"`
+	}
+	var resp struct {
+		Node struct {
+			// It's an empty object rather than an empty array if there
+			// are no resources.
+			Nodes json.RawMessage `json:"nodes"`
+		} `json:"node"`
+		List []item `json:"list"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	list := resp.List
+	if bytes.HasPrefix(bytes.TrimSpace(resp.Node.Nodes), []byte("[")) {
+		if err := json.Unmarshal(resp.Node.Nodes, &list); err != nil {
+			return nil, err
+		}
+	}
+	items := make([][]byte, 0, len(list))
+	for _, it := range list {
+		items = append(items, it.Value)
+	}
+	return items, nil
+}
+
+func doRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// The Admin API responds 404 if the directory doesn't exist.
+	if resp.StatusCode == http.StatusNotFound {
+		return []byte("{}"), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, data)
+	}
+	return data, nil
+}
+
+// decodeManifest decodes the values of resources, keyed by the kind.
+func decodeManifest(values map[string][][]byte) (*util.Manifest, error) {
+	var m util.Manifest
+	for _, value := range values["routes"] {
+		var r apisix.Route
+		if err := json.Unmarshal(value, &r); err != nil {
+			return nil, fmt.Errorf("bad route: %s", err)
+		}
+		m.Routes = append(m.Routes, &r)
+	}
+	for _, value := range values["upstreams"] {
+		var u apisix.Upstream
+		if err := json.Unmarshal(value, &u); err != nil {
+			return nil, fmt.Errorf("bad upstream: %s", err)
+		}
+		m.Upstreams = append(m.Upstreams, &u)
+	}
+	for _, value := range values["plugin_configs"] {
+		var pc apisix.PluginConfig
+		if err := json.Unmarshal(value, &pc); err != nil {
+			return nil, fmt.Errorf("bad plugin config: %s", err)
+		}
+		m.PluginConfigs = append(m.PluginConfigs, &pc)
+	}
+	for _, value := range values["consumers"] {
+		var c apisix.Consumer
+		if err := json.Unmarshal(value, &c); err != nil {
+			return nil, fmt.Errorf("bad consumer: %s", err)
+		}
+		m.Consumers = append(m.Consumers, &c)
+	}
+	for _, value := range values["ssl"] {
+		var s apisix.Ssl
+		if err := json.Unmarshal(value, &s); err != nil {
+			return nil, fmt.Errorf("bad ssl: %s", err)
+		}
+		m.Ssls = append(m.Ssls, &s)
+	}
+	return &m, nil
+}
+
+// resource is the common view of the APISIX resources for printing.
+type resource struct {
+	kind string
+	id   string
+	name string
+	obj  interface{}
+}
+
+func resources(m *util.Manifest) []resource {
+	var res []resource
+	for _, r := range m.Routes {
+		res = append(res, resource{kind: "route", id: r.Id, name: r.Name, obj: r})
+	}
+	for _, u := range m.Upstreams {
+		res = append(res, resource{kind: "upstream", id: u.Id, name: u.Name, obj: apisixutil.Redact(u)})
+	}
+	for _, pc := range m.PluginConfigs {
+		res = append(res, resource{kind: "plugin_config", id: pc.Id, name: pc.Desc, obj: pc})
+	}
+	// Consumers are identified by the username.
+	for _, c := range m.Consumers {
+		res = append(res, resource{kind: "consumer", id: c.Username, name: c.Desc, obj: c})
+	}
+	// Ssls are named by the SNIs, and the private keys are never printed.
+	for _, s := range m.Ssls {
+		res = append(res, resource{kind: "ssl", id: s.Id, name: strings.Join(s.Snis, ","), obj: apisixutil.Redact(s)})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].kind != res[j].kind {
+			return res[i].kind < res[j].kind
+		}
+		return res[i].id < res[j].id
+	})
+	return res
+}
+
+// printDiff prints the discrepancies between the live and the intended
+// resources, it reports whether they're consistent.
+func printDiff(w io.Writer, live, intended *util.Manifest, ignoredFields []string) bool {
+	added, deleted, updated := live.DiffFrom(intended, ignoredFields...)
+	liveObjs := make(map[string]interface{})
+	for _, res := range resources(live) {
+		liveObjs[res.kind+"/"+res.id] = res.obj
+	}
+	for _, res := range resources(added) {
+		fmt.Fprintf(w, "missing %s %s (%s)\n", res.kind, res.id, res.name)
+	}
+	for _, res := range resources(updated) {
+		fmt.Fprintf(w, "outdated %s %s (%s)\n", res.kind, res.id, res.name)
+		fmt.Fprintf(w, "  live:     %s\n", mustMarshal(liveObjs[res.kind+"/"+res.id]))
+		fmt.Fprintf(w, "  intended: %s\n", mustMarshal(res.obj))
+	}
+	for _, res := range resources(deleted) {
+		fmt.Fprintf(w, "unexpected %s %s (%s)\n", res.kind, res.id, res.name)
+	}
+	missing, outdated, unexpected := added.Size(), updated.Size(), deleted.Size()
+	fmt.Fprintf(w, "%d missing, %d outdated, %d unexpected resources\n", missing, outdated, unexpected)
+	return missing+outdated+unexpected == 0
+}
+
+func mustMarshal(obj interface{}) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	// Keep the redacted placeholders readable.
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(obj); err != nil {
+		return fmt.Sprintf("<failed to marshal: %s>", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,226 @@
+package difflive
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner/util"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+const _clusters = `
+{
+  "resources": [
+    {
+      "@type": "type.googleapis.com/envoy.config.cluster.v3.Cluster",
+      "name": "httpbin.default.svc.cluster.local",
+      "type": "STATIC",
+      "loadAssignment": {
+        "clusterName": "httpbin.default.svc.cluster.local",
+        "endpoints": [
+          {
+            "lbEndpoints": [
+              {
+                "endpoint": {
+                  "address": {
+                    "socketAddress": {
+                      "address": "10.0.3.11",
+                      "portValue": 8000
+                    }
+                  }
+                }
+              }
+            ]
+          }
+        ]
+      }
+    }
+  ]
+}
+`
+
+func TestLoadIntendedState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diff-live-")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "clusters.json")
+	assert.Nil(t, ioutil.WriteFile(filename, []byte(_clusters), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.XDSWatchFiles = []string{filename}
+	m, err := loadIntendedState(cfg)
+	assert.Nil(t, err)
+	assert.Len(t, m.Routes, 0)
+	assert.Len(t, m.Upstreams, 1)
+	assert.Equal(t, m.Upstreams[0].Id, id.GenID("httpbin.default.svc.cluster.local"))
+}
+
+func TestFetchLiveState(t *testing.T) {
+	route := `{"id":"1","name":"route1","uris":["/*"],"vars":[["arg_a","==","b"]],"plugins":{"limit-conn":{"conn":1,"burst":0},"echo":{"body":"hello","_meta":{"priority":1}}}}`
+	upstream := `{"id":"2","name":"upstream2","type":"roundrobin","nodes":[{"host":"10.0.3.11","port":8000,"weight":1}]}`
+	consumer := `{"username":"jack","plugins":{"key-auth":{"key":"auth-one"}}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/kv/range":
+			var req struct {
+				Key      []byte `json:"key"`
+				RangeEnd []byte `json:"range_end"`
+			}
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+			var values [][]byte
+			switch string(req.Key) {
+			case "/apisix/routes":
+				assert.Equal(t, string(req.RangeEnd), "/apisix/routet")
+				values = append(values, []byte(route))
+			case "/apisix/upstreams":
+				values = append(values, []byte(upstream))
+			case "/apisix/consumers":
+				values = append(values, []byte(consumer))
+			}
+			var resp struct {
+				Kvs []map[string][]byte `json:"kvs,omitempty"`
+			}
+			for _, v := range values {
+				resp.Kvs = append(resp.Kvs, map[string][]byte{"value": v})
+			}
+			assert.Nil(t, json.NewEncoder(w).Encode(resp))
+		case "/apisix/admin/routes":
+			assert.Equal(t, r.Header.Get("X-API-KEY"), "secret")
+			// The v2 format.
+			_, _ = w.Write([]byte(`{"node":{"nodes":[{"key":"/apisix/routes/1","value":` + route + `}]}}`))
+		case "/apisix/admin/upstreams":
+			// The v3 format.
+			_, _ = w.Write([]byte(`{"total":1,"list":[{"key":"/apisix/upstreams/2","value":` + upstream + `}]}`))
+		case "/apisix/admin/plugin_configs":
+			_, _ = w.Write([]byte(`{"node":{"nodes":{}}}`))
+		case "/apisix/admin/consumers":
+			_, _ = w.Write([]byte(`{"total":1,"list":[{"key":"/apisix/consumers/jack","value":` + consumer + `}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	for _, source := range []string{EtcdSource, AdminAPISource} {
+		m, err := fetchLiveState(source, srv.URL, "/apisix", "secret")
+		assert.Nil(t, err, source)
+		assert.Len(t, m.Routes, 1, source)
+		assert.Len(t, m.Upstreams, 1, source)
+		assert.Len(t, m.PluginConfigs, 0, source)
+		assert.Len(t, m.Consumers, 1, source)
+		assert.Equal(t, m.Consumers[0].Plugins.KeyAuth.Key, "auth-one", source)
+		assert.Equal(t, m.Routes[0].Vars[0].Vars, []string{"arg_a", "==", "b"}, source)
+		assert.Equal(t, m.Routes[0].Plugins.LimitConn.Conn, int32(1), source)
+		assert.Equal(t, m.Routes[0].Plugins.Custom, map[string]string{
+			"echo": `{"_meta":{"priority":1},"body":"hello"}`,
+		}, source)
+		assert.Equal(t, m.Upstreams[0].Nodes[0].Port, int32(8000), source)
+	}
+
+	_, err := fetchLiveState("zookeeper", srv.URL, "/apisix", "")
+	assert.NotNil(t, err)
+}
+
+func TestPrintDiff(t *testing.T) {
+	live := &util.Manifest{
+		Routes: []*apisix.Route{
+			{Id: "1", Name: "route1", Uris: []string{"/a"}},
+			{Id: "2", Name: "route2", Uris: []string{"/b"}},
+		},
+		Upstreams: []*apisix.Upstream{
+			{Id: "3", Name: "upstream3"},
+		},
+	}
+	intended := &util.Manifest{
+		Routes: []*apisix.Route{
+			{Id: "1", Name: "route1", Uris: []string{"/c"}},
+		},
+		Upstreams: []*apisix.Upstream{
+			{Id: "3", Name: "upstream3"},
+			{Id: "4", Name: "upstream4"},
+		},
+	}
+	var buffer strings.Builder
+	assert.False(t, printDiff(&buffer, live, intended, nil))
+	assert.Equal(t, buffer.String(), `missing upstream 4 (upstream4)
+outdated route 1 (route1)
+  live:     {"uris":["/a"],"name":"route1","id":"1"}
+  intended: {"uris":["/c"],"name":"route1","id":"1"}
+unexpected route 2 (route2)
+1 missing, 1 outdated, 1 unexpected resources
+`)
+
+	buffer.Reset()
+	assert.True(t, printDiff(&buffer, intended, intended, nil))
+	assert.Equal(t, buffer.String(), "0 missing, 0 outdated, 0 unexpected resources\n")
+}
+
+func TestPrintDiffWithSsls(t *testing.T) {
+	live := &util.Manifest{
+		Ssls: []*apisix.Ssl{
+			{Id: "1", Cert: "cert1", Key: "key1", Snis: []string{"httpbin.org"}},
+		},
+	}
+	intended := &util.Manifest{
+		Ssls: []*apisix.Ssl{
+			{Id: "1", Cert: "cert2", Key: "key2", Snis: []string{"httpbin.org"}},
+		},
+	}
+	var buffer strings.Builder
+	assert.False(t, printDiff(&buffer, live, intended, nil))
+	// Private keys are never printed.
+	assert.Equal(t, buffer.String(), `outdated ssl 1 (httpbin.org)
+  live:     {"id":"1","cert":"cert1","key":"<redacted>","snis":["httpbin.org"]}
+  intended: {"id":"1","cert":"cert2","key":"<redacted>","snis":["httpbin.org"]}
+0 missing, 1 outdated, 0 unexpected resources
+`)
+}
+
+func TestPrintDiffWithUpstreamClientKeys(t *testing.T) {
+	live := &util.Manifest{
+		Upstreams: []*apisix.Upstream{
+			{Id: "1", Name: "httpbin", Tls: &apisix.Upstream_TLS{ClientCert: "cert1", ClientKey: "key1"}},
+		},
+	}
+	intended := &util.Manifest{
+		Upstreams: []*apisix.Upstream{
+			{Id: "1", Name: "httpbin", Tls: &apisix.Upstream_TLS{ClientCert: "cert2", ClientKey: "key2"}},
+		},
+	}
+	var buffer strings.Builder
+	assert.False(t, printDiff(&buffer, live, intended, nil))
+	assert.Contains(t, buffer.String(), `"client_key":"<redacted>"`)
+	assert.NotContains(t, buffer.String(), "key1")
+	assert.NotContains(t, buffer.String(), "key2")
+	// The manifests are not changed.
+	assert.Equal(t, live.Upstreams[0].Tls.ClientKey, "key1")
+}
+
+func TestNewCommandFlags(t *testing.T) {
+	cmd := NewCommand()
+	// The translation options are shared with the sidecar.
+	for _, name := range []string{
+		"xds-watch-files",
+		"xds-watch-file-tenants",
+		"provenance-labels",
+		"source-hash-labels",
+		"shared-plugin-configs",
+		"dns-resolver-valid",
+		"diff-ignored-fields",
+		"etcd-key-prefix",
+	} {
+		assert.NotNil(t, cmd.PersistentFlags().Lookup(name), name)
+	}
+	assert.Nil(t, cmd.PersistentFlags().Lookup("grpc-listen"))
+	assert.Equal(t, cmd.PersistentFlags().Lookup("log-level").DefValue, "warn")
+}
@@ -3,6 +3,7 @@ package iptables
 import (
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,7 +18,7 @@ func TestCleanupIptables(t *testing.T) {
 	}()
 	rawStdout := os.Stdout
 	os.Stdout = f
-	cleanup(true)
+	cleanup(true, false)
 	os.Stdout = rawStdout
 
 	data, err := ioutil.ReadFile(f.Name())
@@ -36,3 +37,24 @@ iptables -t nat -X APISIX_INBOUND_REDIRECT
 `
 	assert.Equal(t, expect, string(data))
 }
+
+func TestCleanupIptablesWithIPv6(t *testing.T) {
+	f, err := ioutil.TempFile("./", "iptables-cleanup.*")
+	assert.Nil(t, err)
+	defer func() {
+		assert.Nil(t, f.Close())
+		assert.Nil(t, os.Remove(f.Name()))
+	}()
+	rawStdout := os.Stdout
+	os.Stdout = f
+	cleanup(true, true)
+	os.Stdout = rawStdout
+
+	data, err := ioutil.ReadFile(f.Name())
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 20)
+	assert.Equal(t, lines[10], "ip6tables -t nat -D PREROUTING -p tcp -j APISIX_INBOUND")
+	assert.Equal(t, lines[19], "ip6tables -t nat -X APISIX_INBOUND_REDIRECT")
+}
@@ -8,19 +8,27 @@ import (
 
 // NewCleanupIptablesCommand creates the cleanup-iptables sub-command object.
 func NewCleanupIptablesCommand() *cobra.Command {
-	var dryRun bool
+	var (
+		dryRun     bool
+		enableIPv6 string
+	)
 	cmd := &cobra.Command{
 		Use:   "cleanup-iptables [flags]",
 		Short: "Cleanup iptables rules for the port forwarding",
 		Run: func(cmd *cobra.Command, args []string) {
-			cleanup(dryRun)
+			ipv6, err := resolveIPv6Mode(enableIPv6)
+			if err != nil {
+				panic(err)
+			}
+			cleanup(dryRun, ipv6)
 		},
 	}
 	cmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "dry run mode")
+	addIPv6Flag(cmd, &enableIPv6)
 	return cmd
 }
 
-func cleanup(dryRun bool) {
+func cleanup(dryRun, ipv6 bool) {
 	var ext dependencies.Dependencies
 	if dryRun {
 		ext = &dependencies.StdoutStubDependencies{}
@@ -28,6 +36,9 @@ func cleanup(dryRun bool) {
 		ext = &dependencies.RealDependencies{}
 	}
 	removeOldChains(ext, "iptables")
+	if ipv6 {
+		removeOldChains(ext, "ip6tables")
+	}
 }
 
 func removeOldChains(ext dependencies.Dependencies, cmd string) {
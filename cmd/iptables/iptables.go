@@ -1,6 +1,8 @@
 package iptables
 
 import (
+	"fmt"
+	"net"
 	"os/user"
 	"strings"
 
@@ -12,17 +14,30 @@ import (
 	"github.com/api7/apisix-mesh-agent/pkg/types"
 )
 
+var (
+	// Use variable so unit test cases can change it, the result depends
+	// on the network of the running environment.
+	_detectIPv6 = hasIPv6Address
+)
+
 type iptablesConstructor struct {
 	iptables *builder.IptablesBuilderImpl
 	cfg      *config.Config
 	dep      dependencies.Dependencies
+	// whether to generate the IPv6 rules (by ip6tables) too.
+	enableIPv6 bool
+	// the outbound destination CIDRs which are not redirected, split
+	// by the IP family.
+	outboundExcludeCIDRsV4 []string
+	outboundExcludeCIDRsV6 []string
 }
 
 // NewSetupCommand creates the iptables sub-command object.
 func NewSetupCommand() *cobra.Command {
 	var (
-		cfg       config.Config
-		proxyUser string
+		cfg        config.Config
+		proxyUser  string
+		enableIPv6 string
 	)
 	cmd := &cobra.Command{
 		Use:   "iptables [flags]",
@@ -37,6 +52,9 @@ if outbound TCP traffic (say the destination port is 80) is desired to be interc
 	apisix-mesh-agent iptables --apisix-port 9080 --inbound-ports 80 --outbound-ports 80
 
 --dry-run option can be specified if you just want to see which rules will be generated (but no effects).
+
+The same rules are generated for IPv6 (by ip6tables) if the pod has a global IPv6 address, use the --enable-ipv6
+option to turn it on or off explicitly.
 `,
 		Run: func(cmd *cobra.Command, args []string) {
 			var dep dependencies.Dependencies
@@ -46,17 +64,35 @@ if outbound TCP traffic (say the destination port is 80) is desired to be interc
 				dep = &dependencies.RealDependencies{}
 			}
 
-			usr, err := user.Lookup(proxyUser)
+			// The explicit uid and gid take precedence over the ones of
+			// the APISIX user.
+			if cfg.ProxyUID == "" && cfg.ProxyGID == "" && proxyUser != "" {
+				usr, err := user.Lookup(proxyUser)
+				if err != nil {
+					panic(err)
+				}
+				cfg.ProxyUID = usr.Uid
+				cfg.ProxyGID = usr.Gid
+			}
+
+			ipv6, err := resolveIPv6Mode(enableIPv6)
+			if err != nil {
+				panic(err)
+			}
+
+			v4CIDRs, v6CIDRs, err := splitCIDRs(cfg.OutboundIPRangesExclude)
 			if err != nil {
 				panic(err)
 			}
-			cfg.ProxyUID = usr.Uid
-			cfg.ProxyGID = usr.Gid
 
 			ic := &iptablesConstructor{
-				iptables: builder.NewIptablesBuilder(),
-				cfg:      &cfg,
-				dep:      dep,
+				iptables:   builder.NewIptablesBuilder(),
+				cfg:        &cfg,
+				dep:        dep,
+				enableIPv6: ipv6,
+
+				outboundExcludeCIDRsV4: v4CIDRs,
+				outboundExcludeCIDRsV6: v6CIDRs,
 			}
 
 			ic.run()
@@ -72,18 +108,22 @@ if outbound TCP traffic (say the destination port is 80) is desired to be interc
 	cmd.PersistentFlags().StringVar(&cfg.OutboundPortsInclude, "outbound-ports", "", "comma separated list of outbound ports for which traffic is to be redirected")
 	cmd.PersistentFlags().StringVar(&cfg.InboundPortsExclude, "inbound-exclude-ports", "", "comma separated list of inbound ports to be excluded from forwarding to APISIX, only in effective if value of --inbound-ports option is \"*\"")
 	cmd.PersistentFlags().StringVar(&cfg.OutboundPortsExclude, "outbound-exclude-ports", "", "comma separated list of outbound ports to be excluded from forwarding to APISIX, only in effective if value of --outbound-ports option is \"*\"")
+	cmd.PersistentFlags().StringVar(&cfg.OutboundIPRangesExclude, "outbound-exclude-cidrs", "", "comma separated list of outbound destination CIDRs (e.g. the Kubernetes API server) to be excluded from forwarding to APISIX")
 
 	cmd.PersistentFlags().BoolVar(&cfg.DryRun, "dry-run", false, "dry run mode")
-	cmd.PersistentFlags().StringVar(&proxyUser, "apisix-user", "nobody", "user to run APISIX")
+	cmd.PersistentFlags().StringVar(&proxyUser, "apisix-user", "nobody", "user to run APISIX, its uid and gid are used if neither --proxy-uid nor --proxy-gid is specified")
+	cmd.PersistentFlags().StringVar(&cfg.ProxyUID, "proxy-uid", "", "uid of APISIX, outbound traffic from it won't be redirected, no rule is added for the uid if it's empty")
+	cmd.PersistentFlags().StringVar(&cfg.ProxyGID, "proxy-gid", "", "gid of APISIX, outbound traffic from it won't be redirected, no rule is added for the gid if it's empty")
+	addIPv6Flag(cmd, &enableIPv6)
 
 	return cmd
 }
 
 func (ic *iptablesConstructor) run() {
-	ic.iptables.AppendRuleV4(
+	ic.appendRule(
 		types.RedirectChain, "nat", "-p", "tcp", "-j", "REDIRECT", "--to-ports", ic.cfg.ProxyPort,
 	)
-	ic.iptables.AppendRuleV4(
+	ic.appendRule(
 		types.InboundRedirectChain, "nat", "-p", "tcp",
 		"-j", "REDIRECT", "--to-ports", ic.cfg.InboundCapturePort,
 	)
@@ -99,20 +139,20 @@ func (ic *iptablesConstructor) insertInboundRules() {
 	if ic.cfg.InboundPortsInclude == "" {
 		return
 	}
-	ic.iptables.AppendRuleV4(types.PreRoutingChain, "nat", "-p", "tcp", "-j", types.InboundChain)
+	ic.appendRule(types.PreRoutingChain, "nat", "-p", "tcp", "-j", types.InboundChain)
 
 	if ic.cfg.InboundPortsInclude == "*" {
 		// Makes sure SSH is not redirected
-		ic.iptables.AppendRuleV4(types.InboundChain, "nat", "-p", "tcp", "--dport", "22", "-j", "RETURN")
+		ic.appendRule(types.InboundChain, "nat", "-p", "tcp", "--dport", "22", "-j", "RETURN")
 		if ic.cfg.InboundPortsExclude != "" {
 			for _, port := range split(ic.cfg.InboundPortsExclude) {
-				ic.iptables.AppendRuleV4(types.InboundChain, "nat", "-p", "tcp", "--dport", port, "-j", "RETURN")
+				ic.appendRule(types.InboundChain, "nat", "-p", "tcp", "--dport", port, "-j", "RETURN")
 			}
 		}
-		ic.iptables.AppendRuleV4(types.InboundChain, "nat", "-p", "tcp", "-j", types.InboundRedirectChain)
+		ic.appendRule(types.InboundChain, "nat", "-p", "tcp", "-j", types.InboundRedirectChain)
 	} else {
 		for _, port := range split(ic.cfg.InboundPortsInclude) {
-			ic.iptables.AppendRuleV4(
+			ic.appendRule(
 				types.InboundChain, "nat", "-p", "tcp", "--dport", port, "-j", types.InboundRedirectChain,
 			)
 		}
@@ -123,20 +163,28 @@ func (ic *iptablesConstructor) insertOutboundRules() {
 	if ic.cfg.OutboundPortsInclude == "" {
 		return
 	}
+	for _, cidr := range ic.outboundExcludeCIDRsV4 {
+		ic.iptables.AppendRuleV4(types.OutputChain, "nat", "-d", cidr, "-j", "RETURN")
+	}
+	if ic.enableIPv6 {
+		for _, cidr := range ic.outboundExcludeCIDRsV6 {
+			ic.iptables.AppendRuleV6(types.OutputChain, "nat", "-d", cidr, "-j", "RETURN")
+		}
+	}
 	if ic.cfg.OutboundPortsInclude == "*" {
 		if ic.cfg.OutboundPortsExclude != "" {
 			for _, port := range split(ic.cfg.OutboundPortsExclude) {
-				ic.iptables.AppendRuleV4(
+				ic.appendRule(
 					types.OutputChain, "nat", "-p", "tcp", "--dport", port, "-j", "RETURN",
 				)
 			}
 		}
-		ic.iptables.AppendRuleV4(
+		ic.appendRule(
 			types.OutputChain, "nat", "-p", "tcp", "-j", types.RedirectChain,
 		)
 	} else {
 		for _, port := range split(ic.cfg.OutboundPortsInclude) {
-			ic.iptables.AppendRuleV4(
+			ic.appendRule(
 				types.OutputChain, "nat", "-p", "tcp", "--dport", port, "-j", types.RedirectChain,
 			)
 		}
@@ -144,15 +192,41 @@ func (ic *iptablesConstructor) insertOutboundRules() {
 	}
 }
 
+// insertSkipRules makes sure the outbound traffic from APISIX is not
+// redirected to itself again. All of it is matched by the gid, or by the
+// uid if the gid is unknown.
 func (ic *iptablesConstructor) insertSkipRules() {
-	ic.iptables.AppendRuleV4(types.OutputChain, "nat", "-o", "lo", "!", "-d",
-		"127.0.0.1/32", "-m", "owner", "--uid-owner", ic.cfg.ProxyUID, "-j", "RETURN")
-	ic.iptables.AppendRuleV4(types.OutputChain, "nat", "-m", "owner", "--gid-owner",
-		ic.cfg.ProxyGID, "-j", "RETURN")
+	if ic.cfg.ProxyUID != "" {
+		ic.iptables.AppendRuleV4(types.OutputChain, "nat", "-o", "lo", "!", "-d",
+			"127.0.0.1/32", "-m", "owner", "--uid-owner", ic.cfg.ProxyUID, "-j", "RETURN")
+		if ic.enableIPv6 {
+			ic.iptables.AppendRuleV6(types.OutputChain, "nat", "-o", "lo", "!", "-d",
+				"::1/128", "-m", "owner", "--uid-owner", ic.cfg.ProxyUID, "-j", "RETURN")
+		}
+	}
+	if ic.cfg.ProxyGID != "" {
+		ic.appendRule(types.OutputChain, "nat", "-m", "owner", "--gid-owner",
+			ic.cfg.ProxyGID, "-j", "RETURN")
+	} else if ic.cfg.ProxyUID != "" {
+		ic.appendRule(types.OutputChain, "nat", "-m", "owner", "--uid-owner",
+			ic.cfg.ProxyUID, "-j", "RETURN")
+	}
+}
+
+// appendRule appends the rule to the IPv4 rules, and the IPv6 ones if
+// IPv6 is enabled.
+func (ic *iptablesConstructor) appendRule(chain, table string, params ...string) {
+	ic.iptables.AppendRuleV4(chain, table, params...)
+	if ic.enableIPv6 {
+		ic.iptables.AppendRuleV6(chain, table, params...)
+	}
 }
 
 func (ic *iptablesConstructor) executeCommand() {
 	commands := ic.iptables.BuildV4()
+	if ic.enableIPv6 {
+		commands = append(commands, ic.iptables.BuildV6()...)
+	}
 	for _, cmd := range commands {
 		if len(cmd) > 1 {
 			ic.dep.RunOrFail(cmd[0], cmd[1:]...)
@@ -162,6 +236,24 @@ func (ic *iptablesConstructor) executeCommand() {
 	}
 }
 
+// splitCIDRs splits the comma separated CIDRs by the IP family, error is
+// returned if any of them is not in the CIDR notation.
+func splitCIDRs(s string) ([]string, []string, error) {
+	var v4, v6 []string
+	for _, cidr := range split(s) {
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ip.To4() != nil {
+			v4 = append(v4, cidr)
+		} else {
+			v6 = append(v6, cidr)
+		}
+	}
+	return v4, v6, nil
+}
+
 func split(s string) []string {
 	if s == "" {
 		return nil
@@ -179,3 +271,41 @@ func filterEmpty(strs []string) []string {
 	}
 	return filtered
 }
+
+// addIPv6Flag adds the --enable-ipv6 option to the cmd, the value can be
+// "auto", "true" or "false", and it's "true" if the option is given without
+// any value.
+func addIPv6Flag(cmd *cobra.Command, mode *string) {
+	cmd.PersistentFlags().StringVar(mode, "enable-ipv6", "auto", "whether to handle the ip6tables rules too, can be \"auto\" (enabled if the pod has a global IPv6 address), \"true\" or \"false\"")
+	cmd.PersistentFlags().Lookup("enable-ipv6").NoOptDefVal = "true"
+}
+
+// resolveIPv6Mode resolves the value of the --enable-ipv6 option.
+func resolveIPv6Mode(mode string) (bool, error) {
+	switch mode {
+	case "auto":
+		return _detectIPv6(), nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("bad --enable-ipv6 value %q, it can be \"auto\", \"true\" or \"false\"", mode)
+	}
+}
+
+// hasIPv6Address reports whether any interface has a global unicast IPv6
+// address, i.e. the pod is dual-stack (or IPv6 only).
+func hasIPv6Address() bool {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		ipnet, ok := addr.(*net.IPNet)
+		if ok && ipnet.IP.To4() == nil && ipnet.IP.IsGlobalUnicast() {
+			return true
+		}
+	}
+	return false
+}
@@ -23,6 +23,7 @@ func TestNoCapture(t *testing.T) {
 		"--apisix-port",
 		"9080",
 		"--dry-run",
+		"--enable-ipv6=false",
 		"--apisix-user",
 		"root",
 	})
@@ -61,6 +62,7 @@ func TestCaptureAllInboundTrafficWithExcludePorts(t *testing.T) {
 		"--inbound-exclude-ports",
 		"15010,15011",
 		"--dry-run",
+		"--enable-ipv6=false",
 		"--apisix-user",
 		"root",
 	})
@@ -104,6 +106,7 @@ func TestCaptureSelectedInboundTraffic(t *testing.T) {
 		"--inbound-ports",
 		"80,443,53",
 		"--dry-run",
+		"--enable-ipv6=false",
 		"--apisix-user",
 		"root",
 	})
@@ -146,6 +149,7 @@ func TestCaptureOutboundTraffic(t *testing.T) {
 		"--outbound-ports",
 		"80,443",
 		"--dry-run",
+		"--enable-ipv6=false",
 		"--apisix-user",
 		"root",
 	})
@@ -186,6 +190,7 @@ func TestCaptureOutboundTrafficWithExcludedPorts(t *testing.T) {
 		"--outbound-exclude-ports",
 		"15010",
 		"--dry-run",
+		"--enable-ipv6=false",
 		"--apisix-user",
 		"root",
 	})
@@ -226,6 +231,7 @@ func TestCaptureBothInboundAndOutboundTraffic(t *testing.T) {
 		"--inbound-ports",
 		"*",
 		"--dry-run",
+		"--enable-ipv6=false",
 		"--apisix-user",
 		"root",
 	})
@@ -251,3 +257,205 @@ func TestCaptureBothInboundAndOutboundTraffic(t *testing.T) {
 	actual := strings.Split(strings.TrimSpace(string(data)), "\n")
 	assert.Equal(t, expect, actual)
 }
+
+func TestCaptureTrafficWithIPv6(t *testing.T) {
+	f, err := ioutil.TempFile("./", "iptables.*")
+	assert.Nil(t, err)
+	defer func() {
+		assert.Nil(t, f.Close())
+		assert.Nil(t, os.Remove(f.Name()))
+	}()
+	rawStdout := os.Stdout
+	os.Stdout = f
+	cmd := NewSetupCommand()
+	cmd.SetArgs([]string{
+		"--apisix-port",
+		"9080",
+		"--outbound-ports",
+		"80",
+		"--inbound-ports",
+		"443",
+		"--dry-run",
+		"--enable-ipv6",
+		"--apisix-user",
+		"root",
+	})
+	err = cmd.Execute()
+	os.Stdout = rawStdout
+	assert.Nil(t, err)
+	expect := []string{
+		"iptables -t nat -N APISIX_REDIRECT",
+		"iptables -t nat -N APISIX_INBOUND_REDIRECT",
+		"iptables -t nat -N APISIX_INBOUND",
+		"iptables -t nat -A APISIX_REDIRECT -p tcp -j REDIRECT --to-ports 9080",
+		"iptables -t nat -A APISIX_INBOUND_REDIRECT -p tcp -j REDIRECT --to-ports 9081",
+		"iptables -t nat -A OUTPUT -o lo ! -d 127.0.0.1/32 -m owner --uid-owner 0 -j RETURN",
+		"iptables -t nat -A OUTPUT -m owner --gid-owner 0 -j RETURN",
+		"iptables -t nat -A PREROUTING -p tcp -j APISIX_INBOUND",
+		"iptables -t nat -A APISIX_INBOUND -p tcp --dport 443 -j APISIX_INBOUND_REDIRECT",
+		"iptables -t nat -A OUTPUT -p tcp --dport 80 -j APISIX_REDIRECT",
+		"ip6tables -t nat -N APISIX_REDIRECT",
+		"ip6tables -t nat -N APISIX_INBOUND_REDIRECT",
+		"ip6tables -t nat -N APISIX_INBOUND",
+		"ip6tables -t nat -A APISIX_REDIRECT -p tcp -j REDIRECT --to-ports 9080",
+		"ip6tables -t nat -A APISIX_INBOUND_REDIRECT -p tcp -j REDIRECT --to-ports 9081",
+		"ip6tables -t nat -A OUTPUT -o lo ! -d ::1/128 -m owner --uid-owner 0 -j RETURN",
+		"ip6tables -t nat -A OUTPUT -m owner --gid-owner 0 -j RETURN",
+		"ip6tables -t nat -A PREROUTING -p tcp -j APISIX_INBOUND",
+		"ip6tables -t nat -A APISIX_INBOUND -p tcp --dport 443 -j APISIX_INBOUND_REDIRECT",
+		"ip6tables -t nat -A OUTPUT -p tcp --dport 80 -j APISIX_REDIRECT",
+	}
+	data, err := ioutil.ReadFile(f.Name())
+	assert.Nil(t, err)
+	actual := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, expect, actual)
+}
+
+func TestResolveIPv6Mode(t *testing.T) {
+	rawDetect := _detectIPv6
+	defer func() {
+		_detectIPv6 = rawDetect
+	}()
+
+	_detectIPv6 = func() bool { return true }
+	ipv6, err := resolveIPv6Mode("auto")
+	assert.Nil(t, err)
+	assert.True(t, ipv6)
+	_detectIPv6 = func() bool { return false }
+	ipv6, err = resolveIPv6Mode("auto")
+	assert.Nil(t, err)
+	assert.False(t, ipv6)
+
+	ipv6, err = resolveIPv6Mode("true")
+	assert.Nil(t, err)
+	assert.True(t, ipv6)
+	ipv6, err = resolveIPv6Mode("false")
+	assert.Nil(t, err)
+	assert.False(t, ipv6)
+	_, err = resolveIPv6Mode("yes")
+	assert.NotNil(t, err)
+}
+
+func TestCaptureOutboundTrafficWithExcludedCIDRs(t *testing.T) {
+	f, err := ioutil.TempFile("./", "iptables.*")
+	assert.Nil(t, err)
+	defer func() {
+		assert.Nil(t, f.Close())
+		assert.Nil(t, os.Remove(f.Name()))
+	}()
+	rawStdout := os.Stdout
+	os.Stdout = f
+	cmd := NewSetupCommand()
+	cmd.SetArgs([]string{
+		"--apisix-port",
+		"9080",
+		"--outbound-ports",
+		"*",
+		"--outbound-exclude-cidrs",
+		"10.96.0.1/32,169.254.169.254/32,fd00::1/128",
+		"--dry-run",
+		"--enable-ipv6",
+		"--apisix-user",
+		"root",
+	})
+	err = cmd.Execute()
+	os.Stdout = rawStdout
+	assert.Nil(t, err)
+	expect := []string{
+		"iptables -t nat -N APISIX_REDIRECT",
+		"iptables -t nat -N APISIX_INBOUND_REDIRECT",
+		"iptables -t nat -A APISIX_REDIRECT -p tcp -j REDIRECT --to-ports 9080",
+		"iptables -t nat -A APISIX_INBOUND_REDIRECT -p tcp -j REDIRECT --to-ports 9081",
+		"iptables -t nat -A OUTPUT -o lo ! -d 127.0.0.1/32 -m owner --uid-owner 0 -j RETURN",
+		"iptables -t nat -A OUTPUT -m owner --gid-owner 0 -j RETURN",
+		"iptables -t nat -A OUTPUT -d 10.96.0.1/32 -j RETURN",
+		"iptables -t nat -A OUTPUT -d 169.254.169.254/32 -j RETURN",
+		"iptables -t nat -A OUTPUT -p tcp -j APISIX_REDIRECT",
+		"ip6tables -t nat -N APISIX_REDIRECT",
+		"ip6tables -t nat -N APISIX_INBOUND_REDIRECT",
+		"ip6tables -t nat -A APISIX_REDIRECT -p tcp -j REDIRECT --to-ports 9080",
+		"ip6tables -t nat -A APISIX_INBOUND_REDIRECT -p tcp -j REDIRECT --to-ports 9081",
+		"ip6tables -t nat -A OUTPUT -o lo ! -d ::1/128 -m owner --uid-owner 0 -j RETURN",
+		"ip6tables -t nat -A OUTPUT -m owner --gid-owner 0 -j RETURN",
+		"ip6tables -t nat -A OUTPUT -d fd00::1/128 -j RETURN",
+		"ip6tables -t nat -A OUTPUT -p tcp -j APISIX_REDIRECT",
+	}
+	data, err := ioutil.ReadFile(f.Name())
+	assert.Nil(t, err)
+	actual := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, expect, actual)
+}
+
+func TestSplitCIDRs(t *testing.T) {
+	v4, v6, err := splitCIDRs("10.0.0.0/8,,fd00::/8,192.168.1.1/32")
+	assert.Nil(t, err)
+	assert.Equal(t, v4, []string{"10.0.0.0/8", "192.168.1.1/32"})
+	assert.Equal(t, v6, []string{"fd00::/8"})
+
+	_, _, err = splitCIDRs("10.0.0.0/8,10.0.0.1")
+	assert.NotNil(t, err)
+	_, _, err = splitCIDRs("10.0.0.0/33")
+	assert.NotNil(t, err)
+}
+
+func TestSkipProxyOwnerTraffic(t *testing.T) {
+	head := []string{
+		"iptables -t nat -N APISIX_REDIRECT",
+		"iptables -t nat -N APISIX_INBOUND_REDIRECT",
+		"iptables -t nat -A APISIX_REDIRECT -p tcp -j REDIRECT --to-ports 9080",
+		"iptables -t nat -A APISIX_INBOUND_REDIRECT -p tcp -j REDIRECT --to-ports 9081",
+	}
+	cases := []struct {
+		name   string
+		args   []string
+		expect []string
+	}{
+		{
+			name: "uid and gid",
+			// The APISIX user is not looked up.
+			args: []string{"--proxy-uid", "1337", "--proxy-gid", "1338", "--apisix-user", "no-such-user"},
+			expect: []string{
+				"iptables -t nat -A OUTPUT -o lo ! -d 127.0.0.1/32 -m owner --uid-owner 1337 -j RETURN",
+				"iptables -t nat -A OUTPUT -m owner --gid-owner 1338 -j RETURN",
+			},
+		},
+		{
+			name: "uid only",
+			args: []string{"--proxy-uid", "1337"},
+			expect: []string{
+				"iptables -t nat -A OUTPUT -o lo ! -d 127.0.0.1/32 -m owner --uid-owner 1337 -j RETURN",
+				"iptables -t nat -A OUTPUT -m owner --uid-owner 1337 -j RETURN",
+			},
+		},
+		{
+			name: "gid only",
+			args: []string{"--proxy-gid", "1338"},
+			expect: []string{
+				"iptables -t nat -A OUTPUT -m owner --gid-owner 1338 -j RETURN",
+			},
+		},
+		{
+			name: "none",
+			args: []string{"--apisix-user", ""},
+		},
+	}
+	for _, tc := range cases {
+		f, err := ioutil.TempFile("./", "iptables.*")
+		assert.Nil(t, err)
+		rawStdout := os.Stdout
+		os.Stdout = f
+		cmd := NewSetupCommand()
+		cmd.SetArgs(append([]string{"--dry-run", "--enable-ipv6=false"}, tc.args...))
+		err = cmd.Execute()
+		os.Stdout = rawStdout
+		assert.Nil(t, err, tc.name)
+
+		data, err := ioutil.ReadFile(f.Name())
+		assert.Nil(t, err)
+		actual := strings.Split(strings.TrimSpace(string(data)), "\n")
+		assert.Equal(t, append(head, tc.expect...), actual, tc.name)
+
+		assert.Nil(t, f.Close())
+		assert.Nil(t, os.Remove(f.Name()))
+	}
+}
@@ -0,0 +1,97 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	apisixutil "github.com/api7/apisix-mesh-agent/pkg/apisix"
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner/xds/v3/grpc"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+)
+
+func dief(template string, args ...interface{}) {
+	if !strings.HasSuffix(template, "\n") {
+		template += "\n"
+	}
+	_, _ = fmt.Fprintf(os.Stderr, template, args...)
+	os.Exit(1)
+}
+
+// NewCommand creates the replay command for apisix-mesh-agent.
+func NewCommand() *cobra.Command {
+	var (
+		dir      string
+		realtime bool
+	)
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{
+		Use:   "replay --dir <recorded> [flags]",
+		Short: "Replay recorded xDS DiscoveryResponses through the translation pipeline",
+		Long: `Replay recorded xDS DiscoveryResponses through the translation pipeline.
+
+Each file in the directory should be a DiscoveryResponse in JSON format, files are fed in the
+lexicographical order of their names, the generated APISIX events are printed to the stdout,
+one line per config push. Pass --realtime to honor the inter-arrival timing, which is derived
+from the modification time of files.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if dir == "" {
+				dief("no recorded directory, please specify it through --dir")
+			}
+			cfg.Provisioner = config.XDSV3GRPCProvisioner
+			p, err := grpc.NewXDSReplayProvisioner(cfg, dir, realtime)
+			if err != nil {
+				dief("initialization failure: %s", err)
+			}
+
+			stop := make(chan struct{})
+			defer close(stop)
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- p.Run(stop)
+			}()
+
+			encoder := json.NewEncoder(os.Stdout)
+			for events := range p.Channel() {
+				if err := encoder.Encode(formatEvents(events)); err != nil {
+					dief("failed to print events: %s", err)
+				}
+			}
+			if err := <-errCh; err != nil {
+				dief(err.Error())
+			}
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&dir, "dir", "", "the directory of recorded DiscoveryResponses")
+	cmd.PersistentFlags().BoolVar(&realtime, "realtime", false, "honor the recorded inter-arrival timing")
+	cmd.PersistentFlags().StringVar(&cfg.LogOutput, "log-output", "stderr", "the output file path of error log")
+	cmd.PersistentFlags().StringVar(&cfg.LogLevel, "log-level", "info", "the error log level")
+	cmd.PersistentFlags().StringVar(&cfg.DefaultUpstreamScheme, "default-upstream-scheme", config.DefaultUpstreamScheme, "the scheme of upstreams translated from xds clusters, can be \"http\", \"https\", \"grpc\" or \"grpcs\"")
+	cmd.PersistentFlags().IntVar(&cfg.DefaultUpstreamPort, "default-upstream-port", 0, "the port of endpoints which don't specify the port, it's derived from the default upstream scheme if it's 0")
+	return cmd
+}
+
+type event struct {
+	Type      types.EventType `json:"type"`
+	Object    interface{}     `json:"object,omitempty"`
+	Tombstone interface{}     `json:"tombstone,omitempty"`
+}
+
+// formatEvents formats the events for printing, the private keys are
+// redacted.
+func formatEvents(events []types.Event) []event {
+	output := make([]event, 0, len(events))
+	for _, ev := range events {
+		output = append(output, event{
+			Type:      ev.Type,
+			Object:    apisixutil.Redact(ev.Object),
+			Tombstone: apisixutil.Redact(ev.Tombstone),
+		})
+	}
+	return output
+}
@@ -0,0 +1,243 @@
+package selftest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner/xds/v3/file"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+const (
+	_sampleCluster      = "selftest.default.svc.cluster.local"
+	_sampleEndpointHost = "127.0.0.1"
+	_sampleEndpointPort = 8080
+	_sampleRoutePrefix  = "/selftest/"
+)
+
+// _translateTimeout is the max time to wait for the translation.
+var _translateTimeout = 5 * time.Second
+
+// NewCommand creates the selftest command for apisix-mesh-agent.
+func NewCommand() *cobra.Command {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{
+		Use:   "selftest [flags]",
+		Short: "Run a sample configuration through the translation pipeline and verify the results",
+		Long: `Run a sample configuration through the translation pipeline and verify the results.
+
+A DiscoveryResponse with one cluster, one ClusterLoadAssignment and one RouteConfiguration is
+built in memory and translated by the same pipeline as the provisioners, the generated APISIX
+resources are checked, and PASS or FAIL is printed. Nothing outside the process is required.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if !selftest(os.Stdout, cfg) {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&cfg.LogOutput, "log-output", "stderr", "the output file path of error log")
+	cmd.PersistentFlags().StringVar(&cfg.LogLevel, "log-level", "warn", "the error log level")
+	return cmd
+}
+
+func selftest(w io.Writer, cfg *config.Config) bool {
+	ok := check(w, cfg)
+	if ok {
+		fmt.Fprintln(w, "PASS")
+	} else {
+		fmt.Fprintln(w, "FAIL")
+	}
+	return ok
+}
+
+func check(w io.Writer, cfg *config.Config) bool {
+	fmt.Fprint(w, "translating the sample configuration ... ")
+	events, err := translate(cfg)
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return false
+	}
+	fmt.Fprintf(w, "%d events\n", len(events))
+
+	var (
+		ups   *apisix.Upstream
+		route *apisix.Route
+	)
+	for _, ev := range events {
+		if ev.Type != types.EventAdd {
+			continue
+		}
+		switch obj := ev.Object.(type) {
+		case *apisix.Upstream:
+			if obj.Name == _sampleCluster {
+				ups = obj
+			}
+		case *apisix.Route:
+			if obj.UpstreamId == id.GenID(_sampleCluster) {
+				route = obj
+			}
+		}
+	}
+	passed := true
+	fmt.Fprintf(w, "checking upstream %s ... ", _sampleCluster)
+	if err := checkUpstream(ups); err != nil {
+		fmt.Fprintln(w, err)
+		passed = false
+	} else {
+		fmt.Fprintln(w, "ok")
+	}
+	fmt.Fprintf(w, "checking route to %s ... ", _sampleCluster)
+	if err := checkRoute(route); err != nil {
+		fmt.Fprintln(w, err)
+		passed = false
+	} else {
+		fmt.Fprintln(w, "ok")
+	}
+	return passed
+}
+
+func checkUpstream(ups *apisix.Upstream) error {
+	if ups == nil {
+		return errors.New("not found")
+	}
+	if ups.Id != id.GenID(_sampleCluster) {
+		return fmt.Errorf("unexpected id %s", ups.Id)
+	}
+	if len(ups.Nodes) != 1 || ups.Nodes[0].Host != _sampleEndpointHost || ups.Nodes[0].Port != _sampleEndpointPort {
+		return fmt.Errorf("unexpected nodes %v", ups.Nodes)
+	}
+	return nil
+}
+
+func checkRoute(route *apisix.Route) error {
+	if route == nil {
+		return errors.New("not found")
+	}
+	if len(route.Uris) != 1 || route.Uris[0] != _sampleRoutePrefix+"*" {
+		return fmt.Errorf("unexpected uris %v", route.Uris)
+	}
+	return nil
+}
+
+// translate pushes the sample DiscoveryResponse to the in-memory provisioner
+// and returns the generated events.
+func translate(cfg *config.Config) ([]types.Event, error) {
+	dr, err := newSampleDiscoveryResponse()
+	if err != nil {
+		return nil, err
+	}
+	p, err := file.NewXDSMemoryProvisioner(cfg)
+	if err != nil {
+		return nil, err
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		_ = p.Run(stop)
+	}()
+
+	pushed := make(chan error, 1)
+	go func() {
+		pushed <- p.PushResponse(dr)
+	}()
+	select {
+	case events := <-p.Channel():
+		return events, nil
+	case err := <-pushed:
+		if err == nil {
+			err = errors.New("no events generated")
+		}
+		return nil, err
+	case <-time.After(_translateTimeout):
+		return nil, errors.New("timed out")
+	}
+}
+
+func newSampleDiscoveryResponse() (*discoveryv3.DiscoveryResponse, error) {
+	cluster := &clusterv3.Cluster{
+		Name: _sampleCluster,
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_EDS,
+		},
+		LbPolicy: clusterv3.Cluster_ROUND_ROBIN,
+	}
+	cla := &endpointv3.ClusterLoadAssignment{
+		ClusterName: _sampleCluster,
+		Endpoints: []*endpointv3.LocalityLbEndpoints{
+			{
+				LbEndpoints: []*endpointv3.LbEndpoint{
+					{
+						HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+							Endpoint: &endpointv3.Endpoint{
+								Address: &corev3.Address{
+									Address: &corev3.Address_SocketAddress{
+										SocketAddress: &corev3.SocketAddress{
+											Address: _sampleEndpointHost,
+											PortSpecifier: &corev3.SocketAddress_PortValue{
+												PortValue: _sampleEndpointPort,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	rc := &routev3.RouteConfiguration{
+		Name: "selftest",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name:    "selftest",
+				Domains: []string{"*"},
+				Routes: []*routev3.Route{
+					{
+						Name: "selftest",
+						Match: &routev3.RouteMatch{
+							PathSpecifier: &routev3.RouteMatch_Prefix{
+								Prefix: _sampleRoutePrefix,
+							},
+						},
+						Action: &routev3.Route_Route{
+							Route: &routev3.RouteAction{
+								ClusterSpecifier: &routev3.RouteAction_Cluster{
+									Cluster: _sampleCluster,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	dr := &discoveryv3.DiscoveryResponse{
+		VersionInfo: "selftest",
+	}
+	for _, msg := range []proto.Message{cluster, cla, rc} {
+		res, err := anypb.New(msg)
+		if err != nil {
+			return nil, err
+		}
+		dr.Resources = append(dr.Resources, res)
+	}
+	return dr, nil
+}
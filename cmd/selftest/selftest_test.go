@@ -0,0 +1,28 @@
+package selftest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestSelftest(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	var buffer strings.Builder
+	assert.Equal(t, selftest(&buffer, cfg), true)
+	assert.Contains(t, buffer.String(), "checking upstream selftest.default.svc.cluster.local ... ok\n")
+	assert.Contains(t, buffer.String(), "checking route to selftest.default.svc.cluster.local ... ok\n")
+	assert.True(t, strings.HasSuffix(buffer.String(), "PASS\n"))
+}
+
+func TestCheckUpstreamAndRoute(t *testing.T) {
+	assert.NotNil(t, checkUpstream(nil))
+	assert.NotNil(t, checkUpstream(&apisix.Upstream{Id: "1"}))
+	assert.NotNil(t, checkRoute(nil))
+	assert.NotNil(t, checkRoute(&apisix.Route{Uris: []string{"/"}}))
+	assert.Nil(t, checkRoute(&apisix.Route{Uris: []string{"/selftest/*"}}))
+}
@@ -51,10 +51,13 @@ func NewCommand() *cobra.Command {
 		Use:   "sidecar [flags]",
 		Short: "Launch apisix-mesh-agent as a sidecar process",
 		Run: func(cmd *cobra.Command, args []string) {
-			initializeDefaultLogger(cfg)
+			// Validate the configuration before initializing the logger,
+			// so that all problems (including the bad log level) are
+			// reported at once.
 			if err := cfg.Validate(); err != nil {
 				dief("configuration validation failure: %s", err)
 			}
+			initializeDefaultLogger(cfg)
 			log.Infow("apisix-mesh-agent started")
 			defer log.Info("apisix-mesh-agent exited")
 			log.Info("version:\n", version.String())
@@ -77,15 +80,57 @@ func NewCommand() *cobra.Command {
 		},
 	}
 
-	cmd.PersistentFlags().StringVar(&cfg.LogOutput, "log-output", "stderr", "the output file path of error log")
-	cmd.PersistentFlags().StringVar(&cfg.LogLevel, "log-level", "info", "the error log level")
-	cmd.PersistentFlags().StringVar(&cfg.Provisioner, "provisioner", config.XDSV3FileProvisioner, "the provisioner to use, option can be \"xds-v3-file\", \"xds-v3-grpc\"")
-	cmd.PersistentFlags().StringSliceVar(&cfg.XDSWatchFiles, "xds-watch-files", nil, "file paths watched by xds-v3-file provisioner")
+	AddTranslationFlags(cmd, cfg)
+	cmd.PersistentFlags().StringVar(&cfg.Provisioner, "provisioner", config.XDSV3FileProvisioner, "the provisioner to use, option can be \"xds-v3-file\", \"xds-v3-grpc\", \"xds-v3-configmap\"")
+	cmd.PersistentFlags().DurationVar(&cfg.XDSWatchDebounce, "xds-watch-debounce", config.DefaultXDSWatchDebounce, "the window to coalesce file system notifications of the same file watched by xds-v3-file provisioner, so that a burst of writes only triggers one parse, it's not used if it's 0")
+	cmd.PersistentFlags().IntVar(&cfg.XDSPollInterval, "watch-interval", 0, "the interval (in seconds) to poll the files watched by xds-v3-file provisioner, file system notifications are used if it's 0")
+	cmd.PersistentFlags().BoolVar(&cfg.XDSOneshot, "oneshot", false, "translate and apply the files watched by xds-v3-file provisioner once and exit, instead of watching them")
+	cmd.PersistentFlags().StringVar(&cfg.XDSStateDir, "xds-state-dir", "", "the directory to persist the translated state of the files watched by xds-v3-file provisioner, so that only the changes are emitted after restarts, it's not persisted if it's empty")
+	cmd.PersistentFlags().StringVar(&cfg.XDSConfigMapNamespace, "xds-configmap-namespace", "", "the namespace of the configmap watched by xds-v3-configmap provisioner, the pod namespace will be used if it's empty")
+	cmd.PersistentFlags().StringVar(&cfg.XDSConfigMapName, "xds-configmap-name", "", "the name of the configmap watched by xds-v3-configmap provisioner")
+	cmd.PersistentFlags().StringVar(&cfg.Kubeconfig, "kubeconfig", "", "the kubeconfig file path, in-cluster credentials will be used if it's empty")
 	cmd.PersistentFlags().StringVar(&cfg.GRPCListen, "grpc-listen", config.DefaultGRPCListen, "grpc server listen address")
-	cmd.PersistentFlags().StringVar(&cfg.EtcdKeyPrefix, "etcd-key-prefix", config.DefaultEtcdKeyPrefix, "the key prefix in the mimicking etcd v3 server")
+	cmd.PersistentFlags().BoolVar(&cfg.EnablePprof, "enable-pprof", false, "serve the pprof and expvar endpoints on the metrics server (only to the loopback addresses) for performance debugging, --metrics-listen is required")
+	cmd.PersistentFlags().StringVar(&cfg.MetricsListen, "metrics-listen", "", "the listen address of the metrics server which serves the prometheus metrics on /metrics, it's not launched if it's empty")
+	cmd.PersistentFlags().StringVar(&cfg.ProbeListen, "probe-listen", "", "the listen address of the probe server which serves /healthz and /readyz for the liveness and readiness probes, it's not launched if it's empty")
 	cmd.PersistentFlags().StringVar(&cfg.XDSConfigSource, "xds-config-source", "", "the xds config source address, required if provisioner is \"xds-v3-grpc\"")
+	cmd.PersistentFlags().StringVar(&cfg.XDSClientCert, "xds-client-cert", "", "the client certificate (PEM) to connect to the xds config source over TLS, the connection is plaintext if it's empty, it can be reloaded by POST /reload-certs on the probe server")
+	cmd.PersistentFlags().StringVar(&cfg.XDSClientKey, "xds-client-key", "", "the private key (PEM) of the xds client certificate")
+	cmd.PersistentFlags().StringVar(&cfg.XDSCACert, "xds-ca-cert", "", "the CA certificate (PEM) to verify the xds config source, the system roots are used if it's empty")
+	cmd.PersistentFlags().IntVar(&cfg.MaxWritesPerSecond, "max-writes-per-second", 0, "the max number of events delivered to the downstream per second, events exceeding it are delayed, there is no limit if it's 0")
+	cmd.PersistentFlags().StringVar(&cfg.EventOrder, "event-order", config.DefaultEventOrder, "the order of events in a batch, can be \"default\" (added, deleted, updated) or \"dependency\" (upstreams, plugin configs, routes, deletions last)")
+	cmd.PersistentFlags().BoolVar(&cfg.DegradeOnAdaptorFailure, "degrade-on-adaptor-failure", false, "run in the degraded mode (nothing will be translated) instead of exiting when the xds adaptor cannot be initialized")
+	cmd.PersistentFlags().DurationVar(&cfg.HeartbeatInterval, "heartbeat-interval", 0, "the interval to emit heartbeat events from the provisioner, so that a stalled provisioner can be detected, no heartbeat is emitted if it's 0")
+	cmd.PersistentFlags().StringVar(&cfg.NotifyWebhook, "notify-webhook", "", "the URL which a summary of each applied batch of events is POSTed to, nothing will be notified if it's empty")
+	cmd.PersistentFlags().DurationVar(&cfg.NotifyWebhookTimeout, "notify-webhook-timeout", config.DefaultNotifyWebhookTimeout, "the timeout of each request to the notify webhook")
+	cmd.PersistentFlags().IntVar(&cfg.NotifyWebhookRetries, "notify-webhook-retries", config.DefaultNotifyWebhookRetries, "the number of retries when the request to the notify webhook fails")
 	cmd.PersistentFlags().StringVar(&cfg.RunMode, "run-mode", config.StandaloneMode, "run mode for apisix-mesh-agent, can be \"standalone\" or \"bundle\"")
 	cmd.PersistentFlags().StringVar(&cfg.APISIXBinPath, "apisix-bin-path", config.DefaultAPISIXBinPath, "executable binary file path for Apache APISIX, it's not concerned if run mode is \"standalone\"")
 	cmd.PersistentFlags().StringVar(&cfg.APISIXHomePath, "apisix-home-path", config.DefaultAPISIXHomePath, "home path for Apache APISIX, it's not concerned if run mode is \"standalone\"")
 	return cmd
 }
+
+// AddTranslationFlags registers the flags which affect how the xDS resources
+// are translated, so that the commands translating the resources like the
+// sidecar (e.g. diff-live) share them. The defaults are taken from cfg.
+func AddTranslationFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.PersistentFlags().StringVar(&cfg.LogOutput, "log-output", cfg.LogOutput, "the output file path of error log")
+	cmd.PersistentFlags().StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "the error log level")
+	cmd.PersistentFlags().StringSliceVar(&cfg.XDSWatchFiles, "xds-watch-files", cfg.XDSWatchFiles, "file paths watched by xds-v3-file provisioner")
+	cmd.PersistentFlags().BoolVar(&cfg.XDSPartialUpdates, "xds-partial-updates", cfg.XDSPartialUpdates, "merge the partial updates (marked by the control_plane.identifier \"apisix-mesh-agent/partial-update\") into the last resources of the same file watched by xds-v3-file provisioner")
+	cmd.PersistentFlags().IntVar(&cfg.XDSMaxTrackedFiles, "xds-max-tracked-files", cfg.XDSMaxTrackedFiles, "the max number of files tracked by xds-v3-file provisioner, new files beyond it are rejected, there is no limit if it's 0")
+	cmd.PersistentFlags().IntVar(&cfg.XDSMaxTrackedResources, "xds-max-tracked-resources", cfg.XDSMaxTrackedResources, "the max number of xds resources in the files tracked by xds-v3-file provisioner, contents of files exceeding it are rejected and the last ones are kept, there is no limit if it's 0")
+	cmd.PersistentFlags().StringVar(&cfg.EtcdKeyPrefix, "etcd-key-prefix", cfg.EtcdKeyPrefix, "the key prefix in the mimicking etcd v3 server")
+	cmd.PersistentFlags().StringToStringVar(&cfg.XDSWatchFileTenants, "xds-watch-file-tenants", cfg.XDSWatchFileTenants, "the tenants of the watched xds files, keyed by the watched path, e.g. \"/etc/xds/a=tenant-a\"")
+	cmd.PersistentFlags().StringVar(&cfg.DefaultUpstreamScheme, "default-upstream-scheme", cfg.DefaultUpstreamScheme, "the scheme of upstreams translated from xds clusters, can be \"http\", \"https\", \"grpc\" or \"grpcs\"")
+	cmd.PersistentFlags().IntVar(&cfg.DefaultUpstreamPort, "default-upstream-port", cfg.DefaultUpstreamPort, "the port of endpoints which don't specify the port, it's derived from the default upstream scheme if it's 0")
+	cmd.PersistentFlags().IntVar(&cfg.DNSResolverValid, "dns-resolver-valid", cfg.DNSResolverValid, "the valid time (in seconds) of DNS records resolved by Apache APISIX, the record TTL will be used if it's 0")
+	cmd.PersistentFlags().StringToStringVar(&cfg.ClusterConnectTimeouts, "cluster-connect-timeouts", cfg.ClusterConnectTimeouts, "the connect timeouts for clusters which don't specify it, keyed by the cluster type, e.g. \"strict_dns=10s,eds=5s\"")
+	cmd.PersistentFlags().StringVar(&cfg.FilterPluginMappingFile, "filter-plugin-mapping-file", cfg.FilterPluginMappingFile, "the JSON file which maps Envoy HTTP filters to APISIX plugins, for filters which are not translated natively")
+	cmd.PersistentFlags().IntVar(&cfg.MaxUpstreamNodes, "max-upstream-nodes", cfg.MaxUpstreamNodes, "the max number of nodes in an upstream, healthy and higher-weight nodes are preferred when it's exceeded, there is no limit if it's 0")
+	cmd.PersistentFlags().BoolVar(&cfg.ProvenanceLabels, "provenance-labels", cfg.ProvenanceLabels, "mark the generated routes and upstreams with labels about their provenance")
+	cmd.PersistentFlags().BoolVar(&cfg.SourceHashLabels, "source-hash-labels", cfg.SourceHashLabels, "mark the generated routes and upstreams with the hash of the xds resources they are translated from")
+	cmd.PersistentFlags().StringSliceVar(&cfg.DiffIgnoredFields, "diff-ignored-fields", cfg.DiffIgnoredFields, "fields which changes on them won't generate update events, e.g. \"upstream.labels.timestamp\"")
+	cmd.PersistentFlags().StringSliceVar(&cfg.ProtectedResources, "protected-resources", cfg.ProtectedResources, "names or ids of resources which are managed manually, they won't be added, updated or deleted")
+	cmd.PersistentFlags().BoolVar(&cfg.SharedPluginConfigs, "shared-plugin-configs", cfg.SharedPluginConfigs, "extract plugins shared by multiple routes to plugin_config objects")
+}
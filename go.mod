@@ -1,25 +1,48 @@
 module github.com/api7/apisix-mesh-agent
 
-go 1.16
+go 1.23.0
 
 require (
-	github.com/envoyproxy/go-control-plane v0.9.9-0.20210115003313-31f9241a16e6
-	github.com/envoyproxy/protoc-gen-validate v0.4.1
+	github.com/cncf/xds/go v0.0.0-20250121191232-2f005788dc42
+	github.com/envoyproxy/go-control-plane v0.13.4
+	github.com/envoyproxy/go-control-plane/envoy v1.32.4
+	github.com/envoyproxy/protoc-gen-validate v1.2.1
 	github.com/fsnotify/fsnotify v1.4.9
-	github.com/golang/protobuf v1.4.3
-	github.com/google/uuid v1.2.0
+	github.com/golang/protobuf v1.5.4
+	github.com/google/uuid v1.6.0
 	github.com/grpc-ecosystem/grpc-gateway v1.14.6
+	github.com/prometheus/client_golang v1.22.0
 	github.com/soheilhy/cmux v0.1.4
 	github.com/spf13/cobra v1.1.3
-	github.com/stretchr/testify v1.7.0
+	github.com/stretchr/testify v1.10.0
 	github.com/tmc/grpc-websocket-proxy v0.0.0-20190109142713-0ad062ec5ee5
 	go.etcd.io/etcd/api/v3 v3.5.0-alpha.0
 	go.uber.org/zap v1.16.0
-	golang.org/x/net v0.0.0-20210525063256-abc453219eb5
-	google.golang.org/genproto v0.0.0-20210222152913-aa3ee6e6a81c
-	google.golang.org/grpc v1.36.0
-	google.golang.org/grpc/examples v0.0.0-20210304020650-930c79186c99 // indirect
-	google.golang.org/protobuf v1.25.0
+	golang.org/x/net v0.40.0
+	google.golang.org/genproto v0.0.0-20250603155806-513f23925822
+	google.golang.org/genproto/googleapis/api v0.0.0-20250528174236-200df99c418a
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250528174236-200df99c418a
+	google.golang.org/grpc v1.72.1
+	google.golang.org/protobuf v1.36.6
 	gotest.tools v2.2.0+incompatible
 	istio.io/istio v0.0.0-20210308180034-f6502508b04c
+	k8s.io/api v0.20.4
+	k8s.io/apimachinery v0.20.4
+	k8s.io/client-go v0.20.4
+)
+
+require (
+	cel.dev/expr v0.20.0 // indirect
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_model v0.6.1 // indirect
+	github.com/prometheus/common v0.62.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	github.com/spf13/pflag v1.0.5 // indirect
+	go.uber.org/atomic v1.7.0 // indirect
+	go.uber.org/multierr v1.6.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.25.0 // indirect
+	google.golang.org/grpc/examples v0.0.0-20210304020650-930c79186c99 // indirect
 )
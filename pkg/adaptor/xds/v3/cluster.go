@@ -1,15 +1,39 @@
 package v3
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
 	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"go.uber.org/zap"
 
 	"github.com/api7/apisix-mesh-agent/pkg/id"
 	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
 )
 
+const (
+	_upstreamTLSContextTypeUrl = "type.googleapis.com/envoy.extensions.transport_sockets.tls.v3.UpstreamTlsContext"
+
+	// The defaults of the consecutive failures and the base ejection time
+	// (in seconds) of the Envoy outlier detection.
+	_defaultOutlierDetectionConsecutiveFailures = 5
+	_defaultOutlierDetectionBaseEjectionTime    = 30
+	// _maxHealthCheckCounter is the upper bound of the health check
+	// counters in Apache APISIX.
+	_maxHealthCheckCounter = 254
+)
+
+// ErrUpstreamSNINotSupported means the endpoints in the ClusterLoadAssignment
+// carry hostnames as the SNI, which cannot be set on an APISIX Upstream.
+var ErrUpstreamSNINotSupported = errors.New("upstream SNI is not supported")
+
 func (adaptor *adaptor) TranslateCluster(c *clusterv3.Cluster) (*apisix.Upstream, error) {
 	ups := &apisix.Upstream{
 		Name:  c.Name,
@@ -19,9 +43,46 @@ func (adaptor *adaptor) TranslateCluster(c *clusterv3.Cluster) (*apisix.Upstream
 	if err := adaptor.translateClusterLbPolicy(c, ups); err != nil {
 		return nil, err
 	}
+	adaptor.translateClusterScheme(c, ups)
+	if err := adaptor.translateClusterTLS(c, ups); err != nil {
+		return nil, err
+	}
 	if err := adaptor.translateClusterTimeoutSettings(c, ups); err != nil {
 		return nil, err
 	}
+	adaptor.translateClusterHealthChecks(c, ups)
+	adaptor.translateClusterOutlierDetection(c, ups)
+	adaptor.checkClusterCircuitBreakers(c)
+	if adaptor.sourceHashLabels {
+		ups.Labels = map[string]string{
+			LabelSourceHash: getSourceHash(c),
+		}
+	}
+	if count := c.GetCommonHttpProtocolOptions().GetMaxHeadersCount(); count != nil {
+		// The header limits of upstream responses are global nginx settings
+		// in Apache APISIX.
+		adaptor.logger.Warnw("max_headers_count of cluster cannot be applied",
+			zap.String("cluster_name", c.Name),
+			zap.Uint32("max_headers_count", count.GetValue()),
+		)
+	}
+	if pp := c.GetPreconnectPolicy(); pp != nil {
+		// Connections to upstreams are always established on demand
+		// in Apache APISIX.
+		adaptor.logger.Infow("preconnect_policy of cluster is not supported and ignored",
+			zap.String("cluster_name", c.Name),
+			zap.Any("preconnect_policy", pp),
+		)
+	}
+	if bind := c.GetUpstreamBindConfig().GetSourceAddress(); bind != nil {
+		// Apache APISIX Upstream has no setting to bind the source address,
+		// so source IP based firewall rules might reject the connections.
+		adaptor.logger.Warnw("upstream_bind_config of cluster cannot be applied, source address binding isn't enforced",
+			zap.String("cluster_name", c.Name),
+			zap.String("source_address", bind.GetAddress()),
+			zap.Uint32("source_port", bind.GetPortValue()),
+		)
+	}
 	if err := adaptor.translateClusterLoadAssignments(c, ups); err != nil {
 		if err == ErrRequireFurtherEDS {
 			return ups, err
@@ -30,7 +91,7 @@ func (adaptor *adaptor) TranslateCluster(c *clusterv3.Cluster) (*apisix.Upstream
 	}
 
 	adaptor.logger.Debugw("got upstream after parsing cluster",
-		zap.Any("cluster", c),
+		zap.Any("cluster", RedactCluster(c)),
 	)
 
 	return ups, nil
@@ -45,19 +106,145 @@ func (adaptor *adaptor) translateClusterLbPolicy(c *clusterv3.Cluster, ups *apis
 		// But is doesn't expose configuration items. So LbConfig field
 		// is ignored.
 		ups.Type = "least_conn"
+	case clusterv3.Cluster_RING_HASH, clusterv3.Cluster_MAGLEV:
+		// The hash key is configured in RouteConfiguration, so here we
+		// just use the DefaultHashPolicy, it'll be overwritten once the
+		// route hash policy is found. See HashPolicy for details.
+		ups.Type = "chash"
+		DefaultHashPolicy.PatchUpstream(ups)
+	case clusterv3.Cluster_RANDOM:
+		// Apache APISIX doesn't support Random, the weighted round robin
+		// has the same distribution in the long run.
+		adaptor.logger.Infow("RANDOM load balancer is translated to roundrobin",
+			zap.String("cluster_name", c.Name),
+		)
+		ups.Type = "roundrobin"
 	default:
-		// Apache APISIX doesn't support Random, Manglev. In addition,
-		// also RinghHash (Consistent Hash) is available but the configurations
-		// like key is in RouteConfiguration, so we cannot use it either.
 		adaptor.logger.Warnw("ignore cluster with unsupported load balancer",
 			zap.String("cluster_name", c.Name),
 			zap.String("lb_policy", c.GetLbPolicy().String()),
 		)
 		return ErrFeatureNotSupportedYet
 	}
+	if lsc := c.GetLbSubsetConfig(); lsc != nil {
+		// Endpoints cannot be selected by the metadata in Apache APISIX,
+		// so all of them are candidates.
+		adaptor.logger.Warnw("lb_subset_config of cluster is not supported and ignored",
+			zap.String("cluster_name", c.Name),
+			zap.Any("lb_subset_config", lsc),
+		)
+	}
 	return nil
 }
 
+func (adaptor *adaptor) translateClusterScheme(c *clusterv3.Cluster, ups *apisix.Upstream) {
+	// xDS Cluster doesn't carry the application protocol explicitly,
+	// so the default scheme is used.
+	if ups.Scheme == "" {
+		ups.Scheme = adaptor.defaultScheme
+		adaptor.logger.Debugw("use default scheme for cluster",
+			zap.String("cluster_name", c.Name),
+			zap.String("scheme", ups.Scheme),
+		)
+	}
+}
+
+// translateClusterTLS enables TLS for the upstream if the cluster uses the
+// UpstreamTlsContext transport socket. The upstream TLS settings of Apache
+// APISIX have no SNI, so the sni field is ignored with a warning, rewriting
+// the upstream host instead would change the Host header as well.
+func (adaptor *adaptor) translateClusterTLS(c *clusterv3.Cluster, ups *apisix.Upstream) error {
+	tc := c.GetTransportSocket().GetTypedConfig()
+	if tc == nil || tc.GetTypeUrl() != _upstreamTLSContextTypeUrl {
+		return nil
+	}
+	var ctx tlsv3.UpstreamTlsContext
+	if err := tc.UnmarshalTo(&ctx); err != nil {
+		adaptor.logger.Errorw("failed to unmarshal UpstreamTlsContext",
+			zap.Error(err),
+			zap.String("cluster_name", c.Name),
+		)
+		return err
+	}
+	if ups.Scheme == "grpc" {
+		ups.Scheme = "grpcs"
+	} else {
+		ups.Scheme = "https"
+	}
+	if sni := ctx.GetSni(); sni != "" {
+		adaptor.logger.Warnw("SNI of cluster cannot be applied, the upstream TLS settings have no SNI",
+			zap.String("cluster_name", c.Name),
+			zap.String("sni", sni),
+		)
+	}
+	adaptor.checkClusterALPN(c, &ctx, ups)
+	return adaptor.translateClusterClientCertificate(c, &ctx, ups)
+}
+
+// translateClusterClientCertificate sets the inline TLS certificate of the
+// UpstreamTlsContext as the client certificate of the upstream, the ones
+// from SDS are patched by the provisioners, see GetClusterSecretName.
+// Apache APISIX doesn't verify the upstream certificates, so the validation
+// context is ignored with a warning.
+func (adaptor *adaptor) translateClusterClientCertificate(c *clusterv3.Cluster, ctx *tlsv3.UpstreamTlsContext, ups *apisix.Upstream) error {
+	common := ctx.GetCommonTlsContext()
+	if common.GetValidationContextType() != nil {
+		adaptor.logger.Warnw("validation context of cluster cannot be applied, the upstream certificate isn't verified",
+			zap.String("cluster_name", c.Name),
+		)
+	}
+	certs := common.GetTlsCertificates()
+	if len(certs) == 0 {
+		return nil
+	}
+	if len(certs) > 1 || len(common.GetTlsCertificateSdsSecretConfigs()) > 0 {
+		adaptor.logger.Warnw("multiple client certificates of cluster are not supported, only the first one is used",
+			zap.String("cluster_name", c.Name),
+		)
+	}
+	cert, key, err := readTlsCertificate(certs[0])
+	if err != nil {
+		// The private key is never logged.
+		adaptor.logger.Errorw("failed to read client certificate of cluster",
+			zap.Error(err),
+			zap.String("cluster_name", c.Name),
+		)
+		return err
+	}
+	ups.Tls = &apisix.Upstream_TLS{
+		ClientCert: cert,
+		ClientKey:  key,
+	}
+	return nil
+}
+
+// checkClusterALPN checks the ALPN protocols of the UpstreamTlsContext.
+// Apache APISIX has no ALPN setting for upstreams, the protocol is decided
+// by the scheme: "h2" for grpcs and "http/1.1" for https, so the list is
+// only honored if it contains the protocol of the scheme, a warning is
+// logged otherwise.
+func (adaptor *adaptor) checkClusterALPN(c *clusterv3.Cluster, ctx *tlsv3.UpstreamTlsContext, ups *apisix.Upstream) {
+	protocols := ctx.GetCommonTlsContext().GetAlpnProtocols()
+	if len(protocols) == 0 {
+		return
+	}
+	negotiated := "http/1.1"
+	if ups.Scheme == "grpcs" {
+		negotiated = "h2"
+	}
+	for _, protocol := range protocols {
+		if protocol == negotiated {
+			return
+		}
+	}
+	adaptor.logger.Warnw("ALPN protocols of cluster cannot be applied, Apache APISIX negotiates the protocol by the scheme",
+		zap.String("cluster_name", c.Name),
+		zap.Strings("alpn_protocols", protocols),
+		zap.String("scheme", ups.Scheme),
+		zap.String("negotiated_protocol", negotiated),
+	)
+}
+
 func (adaptor *adaptor) translateClusterTimeoutSettings(c *clusterv3.Cluster, ups *apisix.Upstream) error {
 	if c.GetConnectTimeout() != nil {
 		ups.Timeout = &apisix.Upstream_Timeout{
@@ -65,10 +252,220 @@ func (adaptor *adaptor) translateClusterTimeoutSettings(c *clusterv3.Cluster, up
 			Read:    60,
 			Send:    60,
 		}
+	} else if c.GetClusterType() == nil {
+		// Use the default one of the cluster type, or leave it to the
+		// global default in Apache APISIX.
+		if timeout, ok := adaptor.connectTimeouts[c.GetType()]; ok {
+			ups.Timeout = &apisix.Upstream_Timeout{
+				Connect: timeout,
+				Read:    60,
+				Send:    60,
+			}
+		}
 	}
 	return nil
 }
 
+// translateClusterOutlierDetection translates the outlier detection to the
+// passive health check. Envoy counts the locally originated errors (connect
+// failures, timeouts and resets) as 5xx unless split_external_local_origin_errors
+// is set, in which case they're counted by consecutive_local_origin_failure
+// separately. Apache APISIX always counts them separately (tcp_failures and
+// timeouts), so the thresholds are chosen according to the split.
+//
+// Apache APISIX requires the active health check, unhealthy nodes get no
+// traffic and are brought back only by the probes, so the outlier detection
+// is translated only if the cluster has the health checks, which must be
+// translated before.
+func (adaptor *adaptor) translateClusterOutlierDetection(c *clusterv3.Cluster, ups *apisix.Upstream) {
+	od := c.GetOutlierDetection()
+	if od == nil {
+		return
+	}
+	if ups.Check == nil {
+		adaptor.logger.Warnw("outlier detection of cluster without health checks is not supported and ignored",
+			zap.String("cluster_name", c.Name),
+		)
+		return
+	}
+	httpFailures := getOutlierDetectionCounter(od.GetConsecutive_5Xx())
+	localFailures := httpFailures
+	if od.GetSplitExternalLocalOriginErrors() {
+		localFailures = getOutlierDetectionCounter(od.GetConsecutiveLocalOriginFailure())
+	}
+	statuses := make([]int32, 0, 100)
+	for code := int32(500); code < 600; code++ {
+		statuses = append(statuses, code)
+	}
+
+	ups.Check.Passive = &apisix.PassiveHealthCheck{
+		Type: "http",
+		Unhealthy: &apisix.PassiveHealthCheckUnhealthy{
+			HttpStatuses: statuses,
+			HttpFailures: httpFailures,
+			TcpFailures:  localFailures,
+			Timeouts:     localFailures,
+		},
+	}
+}
+
+// translateClusterHealthChecks translates the first HTTP or TCP health check
+// of the cluster to the active health check, since an APISIX upstream can
+// have only one. Like Envoy, statuses in the expected_statuses (200 by
+// default) are healthy and all the others are unhealthy. The base ejection
+// time of the outlier detection is used as the unhealthy interval if it's not
+// set.
+func (adaptor *adaptor) translateClusterHealthChecks(c *clusterv3.Cluster, ups *apisix.Upstream) {
+	var active *apisix.ActiveHealthCheck
+	for _, hc := range c.GetHealthChecks() {
+		if active != nil {
+			adaptor.logger.Warnw("only the first health check of cluster is supported, the others are ignored",
+				zap.String("cluster_name", c.Name),
+				zap.Any("health_check", hc),
+			)
+			continue
+		}
+		switch {
+		case hc.GetHttpHealthCheck() != nil:
+			active = adaptor.translateHTTPHealthCheck(c.Name, hc.GetHttpHealthCheck())
+			active.Type = "http"
+			if ups.Scheme == "https" {
+				active.Type = "https"
+			}
+		case hc.GetTcpHealthCheck() != nil:
+			if hc.GetTcpHealthCheck().GetSend() != nil || len(hc.GetTcpHealthCheck().GetReceive()) > 0 {
+				adaptor.logger.Warnw("payloads of tcp health check are not supported, only the connectivity is checked",
+					zap.String("cluster_name", c.Name),
+				)
+			}
+			active = &apisix.ActiveHealthCheck{
+				Type:      "tcp",
+				Healthy:   &apisix.ActiveHealthCheckHealthy{},
+				Unhealthy: &apisix.ActiveHealthCheckUnhealthy{},
+			}
+		default:
+			adaptor.logger.Warnw("health check of cluster is not supported and ignored",
+				zap.String("cluster_name", c.Name),
+				zap.Any("health_check", hc),
+			)
+			continue
+		}
+
+		if d := hc.GetTimeout(); d != nil {
+			active.Timeout = d.AsDuration().Seconds()
+		}
+		active.Healthy.Interval = getHealthCheckInterval(hc.GetInterval())
+		active.Unhealthy.Interval = active.Healthy.Interval
+		switch {
+		case hc.GetUnhealthyInterval() != nil:
+			active.Unhealthy.Interval = getHealthCheckInterval(hc.GetUnhealthyInterval())
+		case c.GetOutlierDetection() != nil:
+			// Nodes ejected by the outlier detection are probed again after
+			// the base ejection time, like the ejection expiration in Envoy.
+			active.Unhealthy.Interval = getOutlierDetectionBaseEjectionTime(c.GetOutlierDetection())
+		}
+		active.Healthy.Successes = getHealthCheckCounter(hc.GetHealthyThreshold())
+		failures := getHealthCheckCounter(hc.GetUnhealthyThreshold())
+		active.Unhealthy.TcpFailures = failures
+		active.Unhealthy.Timeouts = failures
+		if active.Type != "tcp" {
+			active.Unhealthy.HttpFailures = failures
+		}
+	}
+	if active == nil {
+		return
+	}
+	ups.Check = &apisix.HealthCheck{
+		Active: active,
+	}
+}
+
+// translateHTTPHealthCheck translates the HTTP health check, the type and
+// the timings are left to the caller.
+func (adaptor *adaptor) translateHTTPHealthCheck(cluster string, hc *corev3.HealthCheck_HttpHealthCheck) *apisix.ActiveHealthCheck {
+	active := &apisix.ActiveHealthCheck{
+		Host:      hc.GetHost(),
+		HttpPath:  hc.GetPath(),
+		Healthy:   &apisix.ActiveHealthCheckHealthy{},
+		Unhealthy: &apisix.ActiveHealthCheckUnhealthy{},
+	}
+	for _, opt := range hc.GetRequestHeadersToAdd() {
+		active.ReqHeaders = append(active.ReqHeaders, fmt.Sprintf("%s: %s", opt.GetHeader().GetKey(), opt.GetHeader().GetValue()))
+	}
+
+	healthy := make(map[int32]struct{})
+	for _, r := range hc.GetExpectedStatuses() {
+		for code := r.GetStart(); code < r.GetEnd(); code++ {
+			if code >= 200 && code <= 599 {
+				healthy[int32(code)] = struct{}{}
+			}
+		}
+	}
+	if len(healthy) == 0 {
+		if len(hc.GetExpectedStatuses()) > 0 {
+			adaptor.logger.Warnw("expected_statuses of health check are out of range, use 200 instead",
+				zap.String("cluster_name", cluster),
+				zap.Any("expected_statuses", hc.GetExpectedStatuses()),
+			)
+		}
+		healthy[200] = struct{}{}
+	}
+	for code := int32(200); code <= 599; code++ {
+		if _, ok := healthy[code]; ok {
+			active.Healthy.HttpStatuses = append(active.Healthy.HttpStatuses, code)
+		} else {
+			active.Unhealthy.HttpStatuses = append(active.Unhealthy.HttpStatuses, code)
+		}
+	}
+	return active
+}
+
+// getHealthCheckInterval returns the interval (in seconds) of health
+// checks, the minimum is 1 second in Apache APISIX.
+func getHealthCheckInterval(d *duration.Duration) int32 {
+	interval := int32(d.AsDuration() / time.Second)
+	if interval < 1 {
+		interval = 1
+	}
+	return interval
+}
+
+// getHealthCheckCounter returns the health check threshold, it's bounded
+// by the limit of Apache APISIX.
+func getHealthCheckCounter(v *wrappers.UInt32Value) int32 {
+	n := v.GetValue()
+	if n < 1 {
+		n = 1
+	} else if n > _maxHealthCheckCounter {
+		n = _maxHealthCheckCounter
+	}
+	return int32(n)
+}
+
+// getOutlierDetectionBaseEjectionTime returns the base ejection time (in
+// seconds) of the outlier detection, the minimum is 1 second in Apache APISIX.
+func getOutlierDetectionBaseEjectionTime(od *clusterv3.OutlierDetection) int32 {
+	if od.GetBaseEjectionTime() == nil {
+		return _defaultOutlierDetectionBaseEjectionTime
+	}
+	return getHealthCheckInterval(od.GetBaseEjectionTime())
+}
+
+// getOutlierDetectionCounter returns the consecutive failures, it's bounded
+// by the limit of Apache APISIX.
+func getOutlierDetectionCounter(v *wrappers.UInt32Value) int32 {
+	if v == nil {
+		return _defaultOutlierDetectionConsecutiveFailures
+	}
+	n := v.GetValue()
+	if n < 1 {
+		n = 1
+	} else if n > _maxHealthCheckCounter {
+		n = _maxHealthCheckCounter
+	}
+	return int32(n)
+}
+
 func (adaptor *adaptor) translateClusterLoadAssignments(c *clusterv3.Cluster, ups *apisix.Upstream) error {
 	if c.GetClusterType() != nil {
 		return ErrFeatureNotSupportedYet
@@ -76,18 +473,187 @@ func (adaptor *adaptor) translateClusterLoadAssignments(c *clusterv3.Cluster, up
 	switch c.GetType() {
 	case clusterv3.Cluster_EDS:
 		return ErrRequireFurtherEDS
+	case clusterv3.Cluster_STRICT_DNS, clusterv3.Cluster_LOGICAL_DNS:
+		adaptor.translateClusterDNSRefreshRate(c)
+		fallthrough
 	default:
 		nodes, err := adaptor.TranslateClusterLoadAssignment(c.GetLoadAssignment())
 		if err != nil {
 			return err
 		}
 		ups.Nodes = nodes
+		if err := CheckUpstreamSNI(ups, c.GetLoadAssignment()); err != nil {
+			adaptor.logger.Warnw("per endpoint SNI cannot be honored",
+				zap.Error(err),
+				zap.String("cluster_name", c.Name),
+			)
+		}
+		return nil
+	}
+}
+
+// CheckUpstreamSNI checks whether the endpoints in the ClusterLoadAssignment
+// carry hostnames, which are used as the SNI by Envoy if the upstream is
+// TLS-enabled. The upstream TLS settings of Apache APISIX have no SNI, so
+// ErrUpstreamSNINotSupported will be given, the upstream is left as it is.
+func CheckUpstreamSNI(ups *apisix.Upstream, la *endpointv3.ClusterLoadAssignment) error {
+	if ups.Scheme != "https" && ups.Scheme != "grpcs" {
+		return nil
+	}
+	for _, eps := range la.GetEndpoints() {
+		for _, ep := range eps.GetLbEndpoints() {
+			if ep.GetEndpoint().GetHostname() != "" {
+				return ErrUpstreamSNINotSupported
+			}
+		}
+	}
+	return nil
+}
+
+// translateClusterDNSRefreshRate checks whether the DNS refresh rate of the
+// cluster can be honored, Apache APISIX re-resolves domain nodes once the
+// records expire, the expiration is decided by the record TTL or the global
+// dns_resolver_valid, it cannot be configured per upstream.
+func (adaptor *adaptor) translateClusterDNSRefreshRate(c *clusterv3.Cluster) bool {
+	if adaptor.dnsResolverValid == 0 {
+		if c.GetRespectDnsTtl() || c.GetDnsRefreshRate() == nil {
+			return true
+		}
+		adaptor.logger.Warnw("APISIX uses the TTL of DNS records, dns_refresh_rate cannot be honored",
+			zap.String("cluster_name", c.Name),
+			zap.Duration("dns_refresh_rate", c.GetDnsRefreshRate().AsDuration()),
+		)
+		return false
+	}
+	if c.GetRespectDnsTtl() {
+		adaptor.logger.Warnw("APISIX overrides the TTL of DNS records, respect_dns_ttl cannot be honored",
+			zap.String("cluster_name", c.Name),
+			zap.Duration("dns_resolver_valid", adaptor.dnsResolverValid),
+		)
+		return false
+	}
+	// Envoy refreshes DNS records every 5 seconds by default.
+	rate := 5 * time.Second
+	if c.GetDnsRefreshRate() != nil {
+		rate = c.GetDnsRefreshRate().AsDuration()
+	}
+	if rate != adaptor.dnsResolverValid {
+		adaptor.logger.Warnw("dns_refresh_rate is different from the dns_resolver_valid of APISIX, it cannot be honored",
+			zap.String("cluster_name", c.Name),
+			zap.Duration("dns_refresh_rate", rate),
+			zap.Duration("dns_resolver_valid", adaptor.dnsResolverValid),
+		)
+		return false
+	}
+	return true
+}
+
+// GetEDSServiceName returns the name that the ClusterLoadAssignment of the
+// cluster uses, it's the service_name in the eds_cluster_config if it's
+// specified, or the cluster name.
+func GetEDSServiceName(c *clusterv3.Cluster) string {
+	if name := c.GetEdsClusterConfig().GetServiceName(); name != "" {
+		return name
+	}
+	return c.GetName()
+}
+
+// GetClusterSecretName returns the name of the SDS secret which carries the
+// client certificate of the cluster, it's empty if the cluster doesn't use
+// the UpstreamTlsContext transport socket, or the certificate is inline.
+// Only the first SDS secret is used, like the inline certificates.
+func GetClusterSecretName(c *clusterv3.Cluster) string {
+	tc := c.GetTransportSocket().GetTypedConfig()
+	if tc == nil || tc.GetTypeUrl() != _upstreamTLSContextTypeUrl {
+		return ""
+	}
+	var ctx tlsv3.UpstreamTlsContext
+	if err := tc.UnmarshalTo(&ctx); err != nil {
+		return ""
+	}
+	common := ctx.GetCommonTlsContext()
+	if len(common.GetTlsCertificates()) > 0 || len(common.GetTlsCertificateSdsSecretConfigs()) == 0 {
+		return ""
+	}
+	return common.GetTlsCertificateSdsSecretConfigs()[0].GetName()
+}
+
+// IsClusterStatsTracked reports whether the cluster opts into the
+// additional stats tracking (track_cluster_stats, or the deprecated
+// track_timeout_budgets), the prometheus plugin should be enabled on
+// routes to such clusters.
+func IsClusterStatsTracked(c *clusterv3.Cluster) bool {
+	ts := c.GetTrackClusterStats()
+	return ts.GetTimeoutBudgets() || ts.GetRequestResponseSizes() || c.GetTrackTimeoutBudgets()
+}
+
+// GetClusterConnectionLimit translates the circuit breaker thresholds (of the
+// DEFAULT priority) of the cluster to the limit-conn plugin, which should be
+// enabled on routes to the cluster. The smaller one of max_connections and
+// max_requests becomes the conn, and the max_pending_requests becomes the
+// burst, since the burst requests are delayed like the pending requests
+// in Envoy. Nil is returned if neither limit is configured.
+func GetClusterConnectionLimit(c *clusterv3.Cluster) *apisix.LimitConn {
+	var threshold *clusterv3.CircuitBreakers_Thresholds
+	for _, t := range c.GetCircuitBreakers().GetThresholds() {
+		if t.GetPriority() == corev3.RoutingPriority_DEFAULT {
+			threshold = t
+			break
+		}
+	}
+	var conn uint32
+	for _, limit := range []*wrappers.UInt32Value{threshold.GetMaxConnections(), threshold.GetMaxRequests()} {
+		if limit != nil && (conn == 0 || limit.GetValue() < conn) {
+			conn = limit.GetValue()
+		}
+	}
+	if conn == 0 {
 		return nil
 	}
+	return &apisix.LimitConn{
+		Conn:             int32(conn),
+		Burst:            int32(threshold.GetMaxPendingRequests().GetValue()),
+		DefaultConnDelay: _defaultConnDelay,
+		Key:              "server_addr",
+		KeyType:          "var",
+		// Requests are rejected with 503 by Envoy once the circuit breaker
+		// overflows.
+		RejectedCode: 503,
+	}
+}
+
+// checkClusterCircuitBreakers logs the circuit breaker settings which cannot be
+// applied, see GetClusterConnectionLimit for the translated ones.
+func (adaptor *adaptor) checkClusterCircuitBreakers(c *clusterv3.Cluster) {
+	for _, t := range c.GetCircuitBreakers().GetThresholds() {
+		if t.GetPriority() != corev3.RoutingPriority_DEFAULT {
+			// There is no priority routing in Apache APISIX.
+			adaptor.logger.Warnw("circuit breaker thresholds of non DEFAULT priority are ignored",
+				zap.String("cluster_name", c.Name),
+				zap.String("priority", t.GetPriority().String()),
+			)
+			continue
+		}
+		if t.GetMaxRetries() != nil || t.GetRetryBudget() != nil {
+			adaptor.logger.Warnw("max_retries and retry_budget of circuit breaker cannot be applied",
+				zap.String("cluster_name", c.Name),
+				zap.Any("threshold", t),
+			)
+		}
+		if t.GetMaxConnections() == nil && t.GetMaxRequests() == nil && t.GetMaxPendingRequests() != nil {
+			adaptor.logger.Warnw("max_pending_requests of circuit breaker is ignored without max_connections or max_requests",
+				zap.String("cluster_name", c.Name),
+				zap.Uint32("max_pending_requests", t.GetMaxPendingRequests().GetValue()),
+			)
+		}
+	}
 }
 
 func (adaptor *adaptor) TranslateClusterLoadAssignment(la *endpointv3.ClusterLoadAssignment) ([]*apisix.Node, error) {
-	var nodes []*apisix.Node
+	var (
+		nodes     []*apisix.Node
+		unhealthy = make(map[*apisix.Node]bool)
+	)
 	for _, eps := range la.GetEndpoints() {
 		var weight int32
 		if eps.GetLoadBalancingWeight() != nil {
@@ -122,6 +688,14 @@ func (adaptor *adaptor) TranslateClusterLoadAssignment(la *endpointv3.ClusterLoa
 						)
 						continue
 					}
+					if node.Port == 0 {
+						node.Port = adaptor.defaultPort
+						adaptor.logger.Warnw("use default port for endpoint without port",
+							zap.String("cluster_name", la.GetClusterName()),
+							zap.Int32("port", node.Port),
+							zap.Any("endpoint", ep),
+						)
+					}
 				default:
 					adaptor.logger.Warnw("ignore endpoint with unsupported address type",
 						zap.Any("endpoint", ep),
@@ -140,8 +714,100 @@ func (adaptor *adaptor) TranslateClusterLoadAssignment(la *endpointv3.ClusterLoa
 			)
 			// Currently Apache APISIX doesn't use the metadata field.
 			// So we don't pass ep.Metadata.
-			nodes = append(nodes, node)
+			epNodes := append([]*apisix.Node{node}, adaptor.translateAdditionalAddresses(la, ep, node)...)
+			nodes = append(nodes, epNodes...)
+			switch ep.GetHealthStatus() {
+			case corev3.HealthStatus_UNKNOWN, corev3.HealthStatus_HEALTHY:
+			default:
+				for _, n := range epNodes {
+					unhealthy[n] = true
+				}
+			}
 		}
 	}
+	if adaptor.maxUpstreamNodes > 0 && len(nodes) > adaptor.maxUpstreamNodes {
+		adaptor.logger.Warnw("too many endpoints, nodes exceeding the limit are dropped",
+			zap.String("cluster_name", la.GetClusterName()),
+			zap.Int("endpoints", len(nodes)),
+			zap.Int("max_upstream_nodes", adaptor.maxUpstreamNodes),
+		)
+		nodes = truncateNodes(nodes, unhealthy, adaptor.maxUpstreamNodes)
+	}
 	return nodes, nil
 }
+
+// translateAdditionalAddresses translates the additional addresses (e.g. the
+// IPv6 address of a dual-stack endpoint) of the endpoint to extra nodes. The
+// weight of the endpoint is split across all its addresses, so that the
+// endpoint doesn't take more traffic than a single-stack one.
+func (adaptor *adaptor) translateAdditionalAddresses(la *endpointv3.ClusterLoadAssignment, ep *endpointv3.LbEndpoint, node *apisix.Node) []*apisix.Node {
+	var nodes []*apisix.Node
+	for _, additional := range ep.GetEndpoint().GetAdditionalAddresses() {
+		addr := additional.GetAddress()
+		sockAddr := addr.GetSocketAddress()
+		if sockAddr == nil || sockAddr.GetProtocol() != corev3.SocketAddress_TCP || sockAddr.GetNamedPort() != "" {
+			adaptor.logger.Warnw("ignore unsupported additional address of endpoint",
+				zap.Any("address", addr),
+				zap.Any("endpoint", ep),
+			)
+			continue
+		}
+		port := int32(sockAddr.GetPortValue())
+		if port == 0 {
+			port = node.Port
+		}
+		nodes = append(nodes, &apisix.Node{
+			Host: sockAddr.GetAddress(),
+			Port: port,
+		})
+	}
+	if len(nodes) > 0 {
+		weights := splitWeight(node.Weight, len(nodes)+1)
+		node.Weight = weights[0]
+		for i, extra := range nodes {
+			extra.Weight = weights[i+1]
+		}
+		adaptor.logger.Debugw("got extra nodes from additional addresses",
+			zap.String("cluster_name", la.GetClusterName()),
+			zap.Any("nodes", nodes),
+		)
+	}
+	return nodes
+}
+
+// splitWeight splits the weight into n shares, the remainder is spread over
+// the first shares. Shares of a positive weight are at least 1, since nodes
+// with zero weight never take traffic.
+func splitWeight(weight int32, n int) []int32 {
+	weights := make([]int32, n)
+	for i := range weights {
+		weights[i] = weight / int32(n)
+		if int32(i) < weight%int32(n) {
+			weights[i]++
+		}
+		if weight > 0 && weights[i] == 0 {
+			weights[i] = 1
+		}
+	}
+	return weights
+}
+
+// truncateNodes keeps the first max nodes after sorting them by the health
+// status (healthy first), the weight (higher first), the host and the port,
+// so the result is stable regardless of the endpoints order.
+func truncateNodes(nodes []*apisix.Node, unhealthy map[*apisix.Node]bool, max int) []*apisix.Node {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if unhealthy[a] != unhealthy[b] {
+			return !unhealthy[a]
+		}
+		if a.Weight != b.Weight {
+			return a.Weight > b.Weight
+		}
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		return a.Port < b.Port
+	})
+	return nodes[:max]
+}
@@ -1,16 +1,24 @@
 package v3
 
 import (
+	"bytes"
 	"testing"
+	"time"
 
 	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/types/known/anypb"
 
+	"github.com/api7/apisix-mesh-agent/pkg/config"
 	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/testutil"
 	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
 )
 
@@ -28,6 +36,20 @@ func TestTranslateClusterLbPolicy(t *testing.T) {
 	assert.Equal(t, ups.Type, "least_conn")
 
 	c.LbPolicy = clusterv3.Cluster_RING_HASH
+	assert.Nil(t, a.translateClusterLbPolicy(c, &ups))
+	assert.Equal(t, ups.Type, "chash")
+	assert.Equal(t, ups.HashOn, "vars")
+	assert.Equal(t, ups.Key, "remote_addr")
+
+	c.LbPolicy = clusterv3.Cluster_MAGLEV
+	assert.Nil(t, a.translateClusterLbPolicy(c, &ups))
+	assert.Equal(t, ups.Type, "chash")
+
+	c.LbPolicy = clusterv3.Cluster_RANDOM
+	assert.Nil(t, a.translateClusterLbPolicy(c, &ups))
+	assert.Equal(t, ups.Type, "roundrobin")
+
+	c.LbPolicy = clusterv3.Cluster_CLUSTER_PROVIDED
 	assert.Equal(t, a.translateClusterLbPolicy(c, &ups), ErrFeatureNotSupportedYet)
 }
 
@@ -45,6 +67,51 @@ func TestTranslateClusterTimeoutSettings(t *testing.T) {
 	assert.Equal(t, ups.Timeout.Connect, float64(10))
 }
 
+func TestTranslateClusterDefaultConnectTimeout(t *testing.T) {
+	cfg := &config.Config{
+		LogLevel:  "info",
+		LogOutput: "stderr",
+		ClusterConnectTimeouts: map[string]string{
+			"strict_dns": "10s",
+			"eds":        "500ms",
+		},
+	}
+	ad, err := NewAdaptor(cfg)
+	assert.Nil(t, err)
+	a := ad.(*adaptor)
+
+	c := &clusterv3.Cluster{
+		Name: "test",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_STRICT_DNS,
+		},
+	}
+	var ups apisix.Upstream
+	assert.Nil(t, a.translateClusterTimeoutSettings(c, &ups))
+	assert.Equal(t, ups.Timeout.Connect, float64(10))
+
+	ups.Timeout = nil
+	c.ClusterDiscoveryType = &clusterv3.Cluster_Type{
+		Type: clusterv3.Cluster_EDS,
+	}
+	assert.Nil(t, a.translateClusterTimeoutSettings(c, &ups))
+	assert.Equal(t, ups.Timeout.Connect, 0.5)
+
+	// Use the global default in APISIX.
+	ups.Timeout = nil
+	c.ClusterDiscoveryType = &clusterv3.Cluster_Type{
+		Type: clusterv3.Cluster_STATIC,
+	}
+	assert.Nil(t, a.translateClusterTimeoutSettings(c, &ups))
+	assert.Nil(t, ups.Timeout)
+
+	cfg.ClusterConnectTimeouts = map[string]string{
+		"redis": "10s",
+	}
+	_, err = NewAdaptor(cfg)
+	assert.Equal(t, err, config.ErrBadClusterConnectTimeout)
+}
+
 func TestTranslateClusterLoadAssignment(t *testing.T) {
 	a := &adaptor{logger: log.DefaultLogger}
 	la := &endpointv3.ClusterLoadAssignment{
@@ -136,3 +203,788 @@ func TestTranslateClusterLoadAssignment(t *testing.T) {
 	assert.Equal(t, nodes[0].Weight, int32(100))
 	assert.Equal(t, nodes[0].Host, "10.0.3.11")
 }
+
+func TestGetEDSServiceName(t *testing.T) {
+	c := &clusterv3.Cluster{
+		Name: "outbound|80||httpbin.default.svc.cluster.local",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_EDS,
+		},
+	}
+	assert.Equal(t, GetEDSServiceName(c), "outbound|80||httpbin.default.svc.cluster.local")
+	c.EdsClusterConfig = &clusterv3.Cluster_EdsClusterConfig{
+		ServiceName: "httpbin.default.svc.cluster.local",
+	}
+	assert.Equal(t, GetEDSServiceName(c), "httpbin.default.svc.cluster.local")
+}
+
+func TestTranslateClusterDefaultSchemeAndPort(t *testing.T) {
+	a := &adaptor{
+		logger:        log.DefaultLogger,
+		defaultScheme: "https",
+		defaultPort:   443,
+	}
+	c := &clusterv3.Cluster{
+		Name:     "test",
+		LbPolicy: clusterv3.Cluster_ROUND_ROBIN,
+		LoadAssignment: &endpointv3.ClusterLoadAssignment{
+			ClusterName: "test",
+			Endpoints: []*endpointv3.LocalityLbEndpoints{
+				{
+					LbEndpoints: []*endpointv3.LbEndpoint{
+						{
+							HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+								Endpoint: &endpointv3.Endpoint{
+									Address: &corev3.Address{
+										Address: &corev3.Address_SocketAddress{
+											SocketAddress: &corev3.SocketAddress{
+												Protocol: corev3.SocketAddress_TCP,
+												Address:  "10.0.3.11",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	ups, err := a.TranslateCluster(c)
+	assert.Nil(t, err)
+	assert.Equal(t, ups.Scheme, "https")
+	assert.Len(t, ups.Nodes, 1)
+	assert.Equal(t, ups.Nodes[0].Host, "10.0.3.11")
+	assert.Equal(t, ups.Nodes[0].Port, int32(443))
+}
+
+func TestTranslateClusterDNSRefreshRate(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	c := &clusterv3.Cluster{
+		Name: "test",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_STRICT_DNS,
+		},
+	}
+	assert.True(t, a.translateClusterDNSRefreshRate(c))
+	c.DnsRefreshRate = &duration.Duration{Seconds: 30}
+	assert.False(t, a.translateClusterDNSRefreshRate(c))
+	c.RespectDnsTtl = true
+	assert.True(t, a.translateClusterDNSRefreshRate(c))
+
+	a.dnsResolverValid = 30 * time.Second
+	assert.False(t, a.translateClusterDNSRefreshRate(c))
+	c.RespectDnsTtl = false
+	assert.True(t, a.translateClusterDNSRefreshRate(c))
+	c.DnsRefreshRate = nil
+	assert.False(t, a.translateClusterDNSRefreshRate(c))
+	a.dnsResolverValid = 5 * time.Second
+	assert.True(t, a.translateClusterDNSRefreshRate(c))
+}
+
+func TestTranslateLogicalDNSClusterRefreshRate(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := log.NewLogger(
+		log.WithLogLevel("warn"),
+		log.WithWriteSyncer(zapcore.AddSync(&buf)),
+	)
+	assert.Nil(t, err)
+	a := &adaptor{logger: logger}
+	c := &clusterv3.Cluster{
+		Name: "test",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_LOGICAL_DNS,
+		},
+		DnsRefreshRate:  &duration.Duration{Seconds: 30},
+		CleanupInterval: &duration.Duration{Seconds: 60},
+		LoadAssignment: &endpointv3.ClusterLoadAssignment{
+			ClusterName: "test",
+			Endpoints: []*endpointv3.LocalityLbEndpoints{
+				{
+					LbEndpoints: []*endpointv3.LbEndpoint{
+						{
+							HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+								Endpoint: &endpointv3.Endpoint{
+									Address: &corev3.Address{
+										Address: &corev3.Address_SocketAddress{
+											SocketAddress: &corev3.SocketAddress{
+												Protocol: corev3.SocketAddress_TCP,
+												Address:  "httpbin.org",
+												PortSpecifier: &corev3.SocketAddress_PortValue{
+													PortValue: 80,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	ups, err := a.TranslateCluster(c)
+	assert.Nil(t, err)
+	assert.Len(t, ups.Nodes, 1)
+	assert.Equal(t, ups.Nodes[0].Host, "httpbin.org")
+	// The record TTL governs the refresh.
+	assert.Contains(t, buf.String(), "APISIX uses the TTL of DNS records, dns_refresh_rate cannot be honored")
+
+	// The refresh rate is honored by the dns_resolver_valid.
+	buf.Reset()
+	a.dnsResolverValid = 30 * time.Second
+	_, err = a.TranslateCluster(c)
+	assert.Nil(t, err)
+	assert.NotContains(t, buf.String(), "dns_refresh_rate")
+}
+
+func TestTranslateClusterTLSWithEndpointHostname(t *testing.T) {
+	a := &adaptor{
+		logger:        log.DefaultLogger,
+		defaultScheme: "http",
+	}
+	tc, err := anypb.New(&tlsv3.UpstreamTlsContext{})
+	assert.Nil(t, err)
+	c := &clusterv3.Cluster{
+		Name:     "test",
+		LbPolicy: clusterv3.Cluster_ROUND_ROBIN,
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_EDS,
+		},
+		TransportSocket: &corev3.TransportSocket{
+			Name: "envoy.transport_sockets.tls",
+			ConfigType: &corev3.TransportSocket_TypedConfig{
+				TypedConfig: tc,
+			},
+		},
+	}
+	ups, err := a.TranslateCluster(c)
+	assert.Equal(t, err, ErrRequireFurtherEDS)
+	assert.Equal(t, ups.Scheme, "https")
+	assert.Equal(t, ups.UpstreamHost, "")
+
+	endpoint := func(host, hostname string) *endpointv3.LbEndpoint {
+		return &endpointv3.LbEndpoint{
+			HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+				Endpoint: &endpointv3.Endpoint{
+					Hostname: hostname,
+					Address: &corev3.Address{
+						Address: &corev3.Address_SocketAddress{
+							SocketAddress: &corev3.SocketAddress{
+								Protocol: corev3.SocketAddress_TCP,
+								Address:  host,
+								PortSpecifier: &corev3.SocketAddress_PortValue{
+									PortValue: 443,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	la := &endpointv3.ClusterLoadAssignment{
+		ClusterName: "test",
+		Endpoints: []*endpointv3.LocalityLbEndpoints{
+			{
+				LbEndpoints: []*endpointv3.LbEndpoint{
+					endpoint("10.0.3.11", "httpbin.org"),
+					endpoint("10.0.3.12", "httpbin.org"),
+				},
+			},
+		},
+	}
+	nodes, err := a.TranslateClusterLoadAssignment(la)
+	assert.Nil(t, err)
+	ups.Nodes = nodes
+	// The hostname cannot be the SNI, the host is never rewritten.
+	assert.Equal(t, CheckUpstreamSNI(ups, la), ErrUpstreamSNINotSupported)
+	assert.Equal(t, ups.PassHost, "")
+	assert.Equal(t, ups.UpstreamHost, "")
+	assert.Len(t, ups.Nodes, 2)
+	assert.Equal(t, ups.Nodes[0].Host, "10.0.3.11")
+
+	// Endpoints without hostnames.
+	la.Endpoints[0].LbEndpoints = []*endpointv3.LbEndpoint{endpoint("10.0.3.11", "")}
+	assert.Nil(t, CheckUpstreamSNI(ups, la))
+
+	// The explicit SNI is ignored with a warning.
+	var buf bytes.Buffer
+	a.logger, err = log.NewLogger(
+		log.WithWriteSyncer(zapcore.AddSync(&buf)),
+	)
+	assert.Nil(t, err)
+	tc, err = anypb.New(&tlsv3.UpstreamTlsContext{Sni: "apisix.apache.org"})
+	assert.Nil(t, err)
+	c.TransportSocket.ConfigType = &corev3.TransportSocket_TypedConfig{
+		TypedConfig: tc,
+	}
+	ups, err = a.TranslateCluster(c)
+	assert.Equal(t, err, ErrRequireFurtherEDS)
+	assert.Equal(t, ups.Scheme, "https")
+	assert.Equal(t, ups.PassHost, "")
+	assert.Equal(t, ups.UpstreamHost, "")
+	assert.Contains(t, buf.String(), "SNI of cluster cannot be applied")
+
+	// Plain text upstreams are not affected.
+	c.TransportSocket = nil
+	ups, err = a.TranslateCluster(c)
+	assert.Equal(t, err, ErrRequireFurtherEDS)
+	assert.Equal(t, ups.Scheme, "http")
+	la.Endpoints[0].LbEndpoints = []*endpointv3.LbEndpoint{endpoint("10.0.3.11", "httpbin.org")}
+	assert.Nil(t, CheckUpstreamSNI(ups, la))
+}
+
+func TestTranslateClusterLoadAssignmentMaxNodes(t *testing.T) {
+	a := &adaptor{
+		logger:           log.DefaultLogger,
+		defaultPort:      80,
+		maxUpstreamNodes: 2,
+	}
+	endpoint := func(host string, weight uint32, status corev3.HealthStatus) *endpointv3.LbEndpoint {
+		return &endpointv3.LbEndpoint{
+			HealthStatus:        status,
+			LoadBalancingWeight: &wrappers.UInt32Value{Value: weight},
+			HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+				Endpoint: &endpointv3.Endpoint{
+					Address: &corev3.Address{
+						Address: &corev3.Address_SocketAddress{
+							SocketAddress: &corev3.SocketAddress{
+								Protocol: corev3.SocketAddress_TCP,
+								Address:  host,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	la := &endpointv3.ClusterLoadAssignment{
+		ClusterName: "test",
+		Endpoints: []*endpointv3.LocalityLbEndpoints{
+			{
+				LbEndpoints: []*endpointv3.LbEndpoint{
+					endpoint("10.0.3.11", 100, corev3.HealthStatus_UNHEALTHY),
+					endpoint("10.0.3.12", 10, corev3.HealthStatus_HEALTHY),
+					endpoint("10.0.3.14", 50, corev3.HealthStatus_UNKNOWN),
+					endpoint("10.0.3.13", 50, corev3.HealthStatus_HEALTHY),
+				},
+			},
+		},
+	}
+	nodes, err := a.TranslateClusterLoadAssignment(la)
+	assert.Nil(t, err)
+	assert.Len(t, nodes, 2)
+	assert.Equal(t, nodes[0].Host, "10.0.3.13")
+	assert.Equal(t, nodes[1].Host, "10.0.3.14")
+
+	// The result doesn't depend on the endpoints order.
+	eps := la.Endpoints[0].LbEndpoints
+	eps[2], eps[3] = eps[3], eps[2]
+	nodes, err = a.TranslateClusterLoadAssignment(la)
+	assert.Nil(t, err)
+	assert.Len(t, nodes, 2)
+	assert.Equal(t, nodes[0].Host, "10.0.3.13")
+	assert.Equal(t, nodes[1].Host, "10.0.3.14")
+
+	a.maxUpstreamNodes = 0
+	nodes, err = a.TranslateClusterLoadAssignment(la)
+	assert.Nil(t, err)
+	assert.Len(t, nodes, 4)
+	assert.Equal(t, nodes[0].Host, "10.0.3.11")
+}
+
+func TestTranslateClusterLoadAssignmentAdditionalAddresses(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	socketAddress := func(host string, port uint32) *corev3.Address {
+		return &corev3.Address{
+			Address: &corev3.Address_SocketAddress{
+				SocketAddress: &corev3.SocketAddress{
+					Protocol: corev3.SocketAddress_TCP,
+					Address:  host,
+					PortSpecifier: &corev3.SocketAddress_PortValue{
+						PortValue: port,
+					},
+				},
+			},
+		}
+	}
+	ep := &endpointv3.Endpoint{
+		Address: socketAddress("10.0.3.11", 8000),
+		AdditionalAddresses: []*endpointv3.Endpoint_AdditionalAddress{
+			{Address: socketAddress("fd00::11", 8000)},
+		},
+	}
+	la := &endpointv3.ClusterLoadAssignment{
+		ClusterName: "test",
+		Endpoints: []*endpointv3.LocalityLbEndpoints{
+			{
+				LbEndpoints: []*endpointv3.LbEndpoint{
+					{
+						HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+							Endpoint: ep,
+						},
+						LoadBalancingWeight: &wrappers.UInt32Value{
+							Value: 50,
+						},
+					},
+				},
+			},
+		},
+	}
+	nodes, err := a.TranslateClusterLoadAssignment(la)
+	assert.Nil(t, err)
+	// The weight is split across the addresses.
+	assert.Equal(t, nodes, []*apisix.Node{
+		{Host: "10.0.3.11", Port: 8000, Weight: 25},
+		{Host: "fd00::11", Port: 8000, Weight: 25},
+	})
+
+	// The remainder is spread over the first addresses.
+	la.Endpoints[0].LbEndpoints[0].LoadBalancingWeight.Value = 5
+	nodes, err = a.TranslateClusterLoadAssignment(la)
+	assert.Nil(t, err)
+	assert.Equal(t, nodes, []*apisix.Node{
+		{Host: "10.0.3.11", Port: 8000, Weight: 3},
+		{Host: "fd00::11", Port: 8000, Weight: 2},
+	})
+
+	// Small weights don't disable any address.
+	la.Endpoints[0].LbEndpoints[0].LoadBalancingWeight.Value = 1
+	nodes, err = a.TranslateClusterLoadAssignment(la)
+	assert.Nil(t, err)
+	assert.Equal(t, nodes, []*apisix.Node{
+		{Host: "10.0.3.11", Port: 8000, Weight: 1},
+		{Host: "fd00::11", Port: 8000, Weight: 1},
+	})
+}
+
+func TestSplitWeight(t *testing.T) {
+	assert.Equal(t, splitWeight(100, 3), []int32{34, 33, 33})
+	assert.Equal(t, splitWeight(2, 3), []int32{1, 1, 1})
+	assert.Equal(t, splitWeight(0, 2), []int32{0, 0})
+}
+
+func TestTranslateClusterUpstreamBindConfig(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := log.NewLogger(
+		log.WithLogLevel("warn"),
+		log.WithWriteSyncer(zapcore.AddSync(&buf)),
+	)
+	assert.Nil(t, err)
+	a := &adaptor{logger: logger}
+	c := &clusterv3.Cluster{
+		Name: "test",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_STATIC,
+		},
+		UpstreamBindConfig: &corev3.BindConfig{
+			SourceAddress: &corev3.SocketAddress{
+				Address: "10.0.3.4",
+			},
+		},
+	}
+	ups, err := a.TranslateCluster(c)
+	assert.Nil(t, err)
+	assert.Equal(t, ups.Name, "test")
+	assert.Contains(t, buf.String(), "source address binding isn't enforced")
+	assert.Contains(t, buf.String(), "10.0.3.4")
+}
+
+func TestTranslateClusterPreconnectPolicy(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := log.NewLogger(
+		log.WithLogLevel("info"),
+		log.WithWriteSyncer(zapcore.AddSync(&buf)),
+	)
+	assert.Nil(t, err)
+	a := &adaptor{logger: logger}
+	c := &clusterv3.Cluster{
+		Name: "test",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_STATIC,
+		},
+		PreconnectPolicy: &clusterv3.Cluster_PreconnectPolicy{
+			PerUpstreamPreconnectRatio: &wrappers.DoubleValue{Value: 1.5},
+		},
+	}
+	_, err = a.TranslateCluster(c)
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), "preconnect_policy of cluster is not supported and ignored")
+}
+
+func TestTranslateClusterWithSourceHashLabels(t *testing.T) {
+	a := &adaptor{
+		logger:           log.DefaultLogger,
+		sourceHashLabels: true,
+	}
+	c := &clusterv3.Cluster{
+		Name: "test",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_STATIC,
+		},
+	}
+	ups, err := a.TranslateCluster(c)
+	assert.Nil(t, err)
+	hash := ups.Labels[LabelSourceHash]
+	assert.Equal(t, hash, getSourceHash(c))
+
+	c.LbPolicy = clusterv3.Cluster_LEAST_REQUEST
+	ups, err = a.TranslateCluster(c)
+	assert.Nil(t, err)
+	assert.NotEqual(t, ups.Labels[LabelSourceHash], hash)
+}
+
+func TestTranslateClusterOutlierDetection(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := log.NewLogger(
+		log.WithLogLevel("warn"),
+		log.WithWriteSyncer(zapcore.AddSync(&buf)),
+	)
+	assert.Nil(t, err)
+	a := &adaptor{logger: logger}
+	c := &clusterv3.Cluster{
+		Name: "test",
+	}
+	var ups apisix.Upstream
+	a.translateClusterOutlierDetection(c, &ups)
+	assert.Nil(t, ups.Check)
+
+	// The outlier detection is ignored without the health checks, since
+	// the active health check is required.
+	c.OutlierDetection = &clusterv3.OutlierDetection{
+		Consecutive_5Xx:               &wrappers.UInt32Value{Value: 3},
+		ConsecutiveLocalOriginFailure: &wrappers.UInt32Value{Value: 1000},
+		BaseEjectionTime:              &duration.Duration{Seconds: 10},
+	}
+	a.translateClusterOutlierDetection(c, &ups)
+	assert.Nil(t, ups.Check)
+	assert.Contains(t, buf.String(), "outlier detection of cluster without health checks is not supported and ignored")
+
+	c.HealthChecks = []*corev3.HealthCheck{
+		{
+			Interval: &duration.Duration{Seconds: 5},
+			HealthChecker: &corev3.HealthCheck_TcpHealthCheck_{
+				TcpHealthCheck: &corev3.HealthCheck_TcpHealthCheck{},
+			},
+		},
+	}
+	a.translateClusterHealthChecks(c, &ups)
+	a.translateClusterOutlierDetection(c, &ups)
+	// The active health check is kept, nodes are probed again after the
+	// base ejection time once they're unhealthy.
+	assert.Equal(t, ups.Check.Active.Type, "tcp")
+	assert.Equal(t, ups.Check.Active.Healthy.Interval, int32(5))
+	assert.Equal(t, ups.Check.Active.Unhealthy.Interval, int32(10))
+	unhealthy := ups.Check.Passive.Unhealthy
+	assert.Len(t, unhealthy.HttpStatuses, 100)
+	// Local origin errors are counted as 5xx without the split.
+	assert.Equal(t, unhealthy.HttpFailures, int32(3))
+	assert.Equal(t, unhealthy.TcpFailures, int32(3))
+	assert.Equal(t, unhealthy.Timeouts, int32(3))
+
+	c.OutlierDetection.SplitExternalLocalOriginErrors = true
+	a.translateClusterOutlierDetection(c, &ups)
+	unhealthy = ups.Check.Passive.Unhealthy
+	assert.Equal(t, unhealthy.HttpFailures, int32(3))
+	assert.Equal(t, unhealthy.TcpFailures, int32(254))
+	assert.Equal(t, unhealthy.Timeouts, int32(254))
+
+	c.OutlierDetection = &clusterv3.OutlierDetection{
+		SplitExternalLocalOriginErrors: true,
+	}
+	a.translateClusterHealthChecks(c, &ups)
+	a.translateClusterOutlierDetection(c, &ups)
+	assert.Equal(t, ups.Check.Active.Unhealthy.Interval, int32(30))
+	unhealthy = ups.Check.Passive.Unhealthy
+	assert.Equal(t, unhealthy.HttpFailures, int32(5))
+	assert.Equal(t, unhealthy.TcpFailures, int32(5))
+
+	// The unhealthy interval of the health check wins.
+	c.HealthChecks[0].UnhealthyInterval = &duration.Duration{Seconds: 2}
+	a.translateClusterHealthChecks(c, &ups)
+	a.translateClusterOutlierDetection(c, &ups)
+	assert.Equal(t, ups.Check.Active.Unhealthy.Interval, int32(2))
+}
+
+func TestTranslateClusterHealthChecks(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	c := &clusterv3.Cluster{
+		Name: "test",
+	}
+	var ups apisix.Upstream
+	a.translateClusterHealthChecks(c, &ups)
+	assert.Nil(t, ups.Check)
+
+	c.HealthChecks = []*corev3.HealthCheck{
+		{
+			Timeout:            &duration.Duration{Nanos: 500000000},
+			Interval:           &duration.Duration{Seconds: 5},
+			UnhealthyInterval:  &duration.Duration{Seconds: 10},
+			HealthyThreshold:   &wrappers.UInt32Value{Value: 2},
+			UnhealthyThreshold: &wrappers.UInt32Value{Value: 3},
+			HealthChecker: &corev3.HealthCheck_HttpHealthCheck_{
+				HttpHealthCheck: &corev3.HealthCheck_HttpHealthCheck{
+					Host: "httpbin.org",
+					Path: "/status",
+					RequestHeadersToAdd: []*corev3.HeaderValueOption{
+						{
+							Header: &corev3.HeaderValue{
+								Key:   "X-Probe",
+								Value: "1",
+							},
+						},
+					},
+					ExpectedStatuses: []*typev3.Int64Range{
+						{Start: 200, End: 205},
+					},
+				},
+			},
+		},
+		{
+			Interval: &duration.Duration{Seconds: 1},
+			HealthChecker: &corev3.HealthCheck_TcpHealthCheck_{
+				TcpHealthCheck: &corev3.HealthCheck_TcpHealthCheck{},
+			},
+		},
+	}
+	ups.Scheme = "https"
+	a.translateClusterHealthChecks(c, &ups)
+	active := ups.Check.Active
+	assert.Nil(t, ups.Check.Passive)
+	assert.Equal(t, active.Type, "https")
+	assert.Equal(t, active.Timeout, 0.5)
+	assert.Equal(t, active.Host, "httpbin.org")
+	assert.Equal(t, active.HttpPath, "/status")
+	assert.Equal(t, active.ReqHeaders, []string{"X-Probe: 1"})
+	assert.Equal(t, active.Healthy.Interval, int32(5))
+	assert.Equal(t, active.Healthy.Successes, int32(2))
+	assert.Equal(t, active.Healthy.HttpStatuses, []int32{200, 201, 202, 203, 204})
+	assert.Equal(t, active.Unhealthy.Interval, int32(10))
+	assert.Len(t, active.Unhealthy.HttpStatuses, 395)
+	assert.Equal(t, active.Unhealthy.HttpFailures, int32(3))
+	assert.Equal(t, active.Unhealthy.TcpFailures, int32(3))
+	assert.Equal(t, active.Unhealthy.Timeouts, int32(3))
+
+	// The passive health check is added by the outlier detection.
+	c.HealthChecks = c.HealthChecks[1:]
+	c.OutlierDetection = &clusterv3.OutlierDetection{}
+	ups = apisix.Upstream{}
+	a.translateClusterHealthChecks(c, &ups)
+	a.translateClusterOutlierDetection(c, &ups)
+	active = ups.Check.Active
+	assert.NotNil(t, ups.Check.Passive)
+	assert.Equal(t, active.Type, "tcp")
+	assert.Equal(t, active.Healthy.Interval, int32(1))
+	assert.Equal(t, active.Healthy.Successes, int32(1))
+	assert.Equal(t, active.Unhealthy.Interval, int32(30))
+	assert.Nil(t, active.Unhealthy.HttpStatuses)
+	assert.Equal(t, active.Unhealthy.HttpFailures, int32(0))
+	assert.Equal(t, active.Unhealthy.TcpFailures, int32(1))
+
+	// gRPC health checks are not supported.
+	c.HealthChecks = []*corev3.HealthCheck{
+		{
+			Interval: &duration.Duration{Seconds: 1},
+			HealthChecker: &corev3.HealthCheck_GrpcHealthCheck_{
+				GrpcHealthCheck: &corev3.HealthCheck_GrpcHealthCheck{},
+			},
+		},
+	}
+	c.OutlierDetection = nil
+	ups = apisix.Upstream{}
+	a.translateClusterHealthChecks(c, &ups)
+	assert.Nil(t, ups.Check)
+}
+
+func TestIsClusterStatsTracked(t *testing.T) {
+	assert.False(t, IsClusterStatsTracked(&clusterv3.Cluster{}))
+	assert.False(t, IsClusterStatsTracked(&clusterv3.Cluster{
+		TrackClusterStats: &clusterv3.TrackClusterStats{},
+	}))
+	assert.True(t, IsClusterStatsTracked(&clusterv3.Cluster{
+		TrackClusterStats: &clusterv3.TrackClusterStats{
+			RequestResponseSizes: true,
+		},
+	}))
+	assert.True(t, IsClusterStatsTracked(&clusterv3.Cluster{
+		TrackTimeoutBudgets: true,
+	}))
+}
+
+func TestGetClusterConnectionLimit(t *testing.T) {
+	assert.Nil(t, GetClusterConnectionLimit(&clusterv3.Cluster{}))
+
+	c := &clusterv3.Cluster{
+		CircuitBreakers: &clusterv3.CircuitBreakers{
+			Thresholds: []*clusterv3.CircuitBreakers_Thresholds{
+				{
+					Priority:       corev3.RoutingPriority_HIGH,
+					MaxConnections: &wrappers.UInt32Value{Value: 10},
+				},
+				{
+					MaxConnections:     &wrappers.UInt32Value{Value: 100},
+					MaxRequests:        &wrappers.UInt32Value{Value: 50},
+					MaxPendingRequests: &wrappers.UInt32Value{Value: 20},
+				},
+			},
+		},
+	}
+	lc := GetClusterConnectionLimit(c)
+	assert.NotNil(t, lc)
+	assert.Equal(t, lc.Conn, int32(50))
+	assert.Equal(t, lc.Burst, int32(20))
+	assert.Equal(t, lc.RejectedCode, int32(503))
+	assert.Nil(t, lc.Validate())
+
+	// Only the pending requests limit.
+	c.CircuitBreakers.Thresholds = c.CircuitBreakers.Thresholds[1:]
+	c.CircuitBreakers.Thresholds[0].MaxConnections = nil
+	c.CircuitBreakers.Thresholds[0].MaxRequests = nil
+	assert.Nil(t, GetClusterConnectionLimit(c))
+}
+
+func TestTranslateClusterTLSWithALPN(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := log.NewLogger(
+		log.WithLogLevel("warn"),
+		log.WithWriteSyncer(zapcore.AddSync(&buf)),
+	)
+	assert.Nil(t, err)
+	tc, err := anypb.New(&tlsv3.UpstreamTlsContext{
+		CommonTlsContext: &tlsv3.CommonTlsContext{
+			AlpnProtocols: []string{"h2"},
+		},
+	})
+	assert.Nil(t, err)
+	c := &clusterv3.Cluster{
+		Name: "test",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_STATIC,
+		},
+		TransportSocket: &corev3.TransportSocket{
+			Name: "envoy.transport_sockets.tls",
+			ConfigType: &corev3.TransportSocket_TypedConfig{
+				TypedConfig: tc,
+			},
+		},
+	}
+
+	// gRPC upstreams negotiate h2.
+	a := &adaptor{logger: logger, defaultScheme: "grpc"}
+	ups, err := a.TranslateCluster(c)
+	assert.Nil(t, err)
+	assert.Equal(t, ups.Scheme, "grpcs")
+	assert.Empty(t, buf.String())
+
+	// HTTP upstreams negotiate http/1.1.
+	a = &adaptor{logger: logger, defaultScheme: "http"}
+	ups, err = a.TranslateCluster(c)
+	assert.Nil(t, err)
+	assert.Equal(t, ups.Scheme, "https")
+	assert.Contains(t, buf.String(), "ALPN protocols of cluster cannot be applied")
+	assert.Contains(t, buf.String(), "http/1.1")
+}
+
+func TestTranslateClusterTLSWithClientCertificate(t *testing.T) {
+	cert, key := testutil.NewCertificate(t, "httpbin.org")
+	tc, err := anypb.New(&tlsv3.UpstreamTlsContext{
+		CommonTlsContext: &tlsv3.CommonTlsContext{
+			TlsCertificates: []*tlsv3.TlsCertificate{
+				{
+					CertificateChain: &corev3.DataSource{
+						Specifier: &corev3.DataSource_InlineString{
+							InlineString: cert,
+						},
+					},
+					PrivateKey: &corev3.DataSource{
+						Specifier: &corev3.DataSource_InlineString{
+							InlineString: key,
+						},
+					},
+				},
+			},
+		},
+	})
+	assert.Nil(t, err)
+	c := &clusterv3.Cluster{
+		Name: "test",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_STATIC,
+		},
+		TransportSocket: &corev3.TransportSocket{
+			Name: "envoy.transport_sockets.tls",
+			ConfigType: &corev3.TransportSocket_TypedConfig{
+				TypedConfig: tc,
+			},
+		},
+	}
+	a := &adaptor{logger: log.DefaultLogger, defaultScheme: "http"}
+	ups, err := a.TranslateCluster(c)
+	assert.Nil(t, err)
+	assert.Equal(t, ups.Scheme, "https")
+	assert.Equal(t, ups.Tls, &apisix.Upstream_TLS{
+		ClientCert: cert,
+		ClientKey:  key,
+	})
+	assert.Nil(t, ups.Tls.Validate())
+	assert.Equal(t, GetClusterSecretName(c), "")
+
+	// Plaintext clusters.
+	c.TransportSocket = nil
+	ups, err = a.TranslateCluster(c)
+	assert.Nil(t, err)
+	assert.Equal(t, ups.Scheme, "http")
+	assert.Nil(t, ups.Tls)
+}
+
+func TestGetClusterSecretName(t *testing.T) {
+	c := &clusterv3.Cluster{Name: "test"}
+	assert.Equal(t, GetClusterSecretName(c), "")
+
+	tc, err := anypb.New(&tlsv3.UpstreamTlsContext{
+		CommonTlsContext: &tlsv3.CommonTlsContext{
+			TlsCertificateSdsSecretConfigs: []*tlsv3.SdsSecretConfig{
+				{Name: "default"},
+				{Name: "backup"},
+			},
+		},
+	})
+	assert.Nil(t, err)
+	c.TransportSocket = &corev3.TransportSocket{
+		Name: "envoy.transport_sockets.tls",
+		ConfigType: &corev3.TransportSocket_TypedConfig{
+			TypedConfig: tc,
+		},
+	}
+	assert.Equal(t, GetClusterSecretName(c), "default")
+}
+
+func TestTranslateClusterMaxHeadersCount(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := log.NewLogger(
+		log.WithLogLevel("warn"),
+		log.WithWriteSyncer(zapcore.AddSync(&buf)),
+	)
+	assert.Nil(t, err)
+	a := &adaptor{logger: logger}
+	c := &clusterv3.Cluster{
+		Name: "test",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_EDS,
+		},
+	}
+	_, err = a.TranslateCluster(c)
+	assert.Equal(t, err, ErrRequireFurtherEDS)
+	assert.NotContains(t, buf.String(), "max_headers_count")
+
+	c.CommonHttpProtocolOptions = &corev3.HttpProtocolOptions{
+		MaxHeadersCount: &wrappers.UInt32Value{Value: 50},
+	}
+	_, err = a.TranslateCluster(c)
+	assert.Equal(t, err, ErrRequireFurtherEDS)
+	assert.Contains(t, buf.String(), "max_headers_count of cluster cannot be applied")
+	assert.Contains(t, buf.String(), `"max_headers_count":50`)
+}
@@ -0,0 +1,148 @@
+package v3
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	apikeyauthv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/api_key_auth/v3"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+const (
+	_apiKeyAuthTypeUrl = "type.googleapis.com/envoy.extensions.filters.http.api_key_auth.v3.ApiKeyAuth"
+	_basicAuthTypeUrl  = "type.googleapis.com/envoy.extensions.filters.http.basic_auth.v3.BasicAuth"
+	// The max length of the client name part in the consumer username, the
+	// whole username shouldn't exceed the limit (100) of APISIX.
+	_maxConsumerClientNameLength = 80
+)
+
+var (
+	_invalidUsernameChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+	errNoAPIKeySource = errors.New("no header or query key source")
+)
+
+// CollectConsumers translates the credentials in the auth HTTP filters of
+// the listener to APISIX consumers, consumers with the same username are
+// only returned once.
+func (adaptor *adaptor) CollectConsumers(l *listenerv3.Listener) ([]*apisix.Consumer, error) {
+	hcms, err := adaptor.getHTTPConnectionManagers(l)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		consumers []*apisix.Consumer
+		usernames = make(map[string]struct{})
+	)
+	for _, hcm := range hcms {
+		for _, f := range hcm.GetHttpFilters() {
+			switch getHTTPFilterTypeUrl(f) {
+			case _apiKeyAuthTypeUrl:
+				cs, err := translateAPIKeyAuthCredentials(f.GetTypedConfig())
+				if err != nil {
+					adaptor.logger.Errorw("failed to translate credentials of api_key_auth filter",
+						zap.Error(err),
+						zap.String("listener", l.GetName()),
+						zap.String("filter", f.GetName()),
+					)
+					continue
+				}
+				for _, c := range cs {
+					if _, ok := usernames[c.Username]; ok {
+						continue
+					}
+					usernames[c.Username] = struct{}{}
+					consumers = append(consumers, c)
+				}
+			case _basicAuthTypeUrl:
+				// Only the SHA hashes of passwords are configured, while the
+				// basic-auth plugin requires the plain passwords.
+				adaptor.logger.Warnw("credentials of basic_auth filter cannot be translated to consumers",
+					zap.String("listener", l.GetName()),
+					zap.String("filter", f.GetName()),
+				)
+			}
+		}
+	}
+	return consumers, nil
+}
+
+// translateAPIKeyAuthCredentials translates the credentials of the api_key_auth
+// filter to consumers with the key-auth plugin.
+func translateAPIKeyAuthCredentials(config *anypb.Any) ([]*apisix.Consumer, error) {
+	var conf apikeyauthv3.ApiKeyAuth
+	if err := unmarshalHTTPFilterConfig(config, &conf); err != nil {
+		return nil, err
+	}
+	consumers := make([]*apisix.Consumer, 0, len(conf.GetCredentials()))
+	for i, c := range conf.GetCredentials() {
+		key := c.GetKey()
+		client := c.GetClient()
+		if key == "" || client == "" {
+			return nil, fmt.Errorf("credential #%d: empty key or client", i)
+		}
+		consumers = append(consumers, &apisix.Consumer{
+			Username: getConsumerUsername(client, key),
+			Desc:     client,
+			Plugins: &apisix.Plugins{
+				KeyAuth: &apisix.KeyAuth{
+					Key: key,
+				},
+			},
+		})
+	}
+	return consumers, nil
+}
+
+// getConsumerUsername generates a stable username for the credential, the
+// client name is kept (as far as possible) for readability.
+func getConsumerUsername(client, key string) string {
+	name := _invalidUsernameChars.ReplaceAllString(client, "_")
+	if len(name) > _maxConsumerClientNameLength {
+		name = name[:_maxConsumerClientNameLength]
+	}
+	return name + "_" + id.GenID(client+"/"+key)
+}
+
+// translateAPIKeyAuth translates the api_key_auth filter to the key-auth
+// plugin on routes. The key-auth plugin reads the key from one header and
+// one query argument, so only the first header and query sources are used.
+func (adaptor *adaptor) translateAPIKeyAuth(config *anypb.Any) (*apisix.KeyAuth, error) {
+	var conf apikeyauthv3.ApiKeyAuth
+	if err := unmarshalHTTPFilterConfig(config, &conf); err != nil {
+		return nil, err
+	}
+	var ka apisix.KeyAuth
+	for _, source := range conf.GetKeySources() {
+		switch {
+		case source.GetHeader() != "" && ka.Header == "":
+			ka.Header = source.GetHeader()
+		case source.GetQuery() != "" && ka.Query == "":
+			ka.Query = source.GetQuery()
+		default:
+			adaptor.logger.Warnw("ignore key source of api_key_auth filter",
+				zap.Stringer("key_source", source),
+			)
+		}
+	}
+	if ka.Header == "" && ka.Query == "" {
+		return nil, errNoAPIKeySource
+	}
+	// The forwarding options are newer than the go-control-plane in use,
+	// so they can only be read from TypedStruct.
+	if isTypedStruct(config) {
+		fields, err := getHTTPFilterConfigFields(config)
+		if err != nil {
+			return nil, err
+		}
+		forwarding, _ := fields["forwarding"].(map[string]interface{})
+		ka.HideCredentials, _ = forwarding["hide_credentials"].(bool)
+	}
+	return &ka, nil
+}
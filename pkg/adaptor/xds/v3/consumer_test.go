@@ -0,0 +1,149 @@
+package v3
+
+import (
+	"testing"
+
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	apikeyauthv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/api_key_auth/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	xdswellknown "github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/set"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func newAPIKeyAuthFilter(t *testing.T) *hcmv3.HttpFilter {
+	return &hcmv3.HttpFilter{
+		Name: "envoy.filters.http.api_key_auth",
+		ConfigType: &hcmv3.HttpFilter_TypedConfig{
+			TypedConfig: newTypedStruct(t, _apiKeyAuthTypeUrl, map[string]interface{}{
+				"credentials": []interface{}{
+					map[string]interface{}{"key": "key1", "client": "team-a"},
+					map[string]interface{}{"key": "key2", "client": "team.b"},
+				},
+				"key_sources": []interface{}{
+					map[string]interface{}{"header": "X-API-KEY"},
+					map[string]interface{}{"query": "api_key"},
+					map[string]interface{}{"cookie": "api_key"},
+				},
+				"forwarding": map[string]interface{}{
+					"hide_credentials": true,
+				},
+			}),
+		},
+	}
+}
+
+func TestCollectConsumers(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	hcm := &hcmv3.HttpConnectionManager{
+		HttpFilters: []*hcmv3.HttpFilter{
+			newAPIKeyAuthFilter(t),
+			// Same credentials in another filter.
+			newAPIKeyAuthFilter(t),
+			{
+				Name: "envoy.filters.http.basic_auth",
+				ConfigType: &hcmv3.HttpFilter_TypedConfig{
+					TypedConfig: newTypedStruct(t, _basicAuthTypeUrl, map[string]interface{}{
+						"users": map[string]interface{}{
+							"inline_string": "user1:{SHA}tESsBmE/yNY3lb6a0L6vVQEZNqw=",
+						},
+					}),
+				},
+			},
+			{
+				Name: "envoy.filters.http.router",
+			},
+		},
+	}
+	var any1 anypb.Any
+	assert.Nil(t, anypb.MarshalFrom(&any1, hcm, proto.MarshalOptions{}))
+	listener := &listenerv3.Listener{
+		Name: "listener1",
+		FilterChains: []*listenerv3.FilterChain{
+			{
+				Filters: []*listenerv3.Filter{
+					{
+						Name: xdswellknown.HTTPConnectionManager,
+						ConfigType: &listenerv3.Filter_TypedConfig{
+							TypedConfig: &any1,
+						},
+					},
+				},
+			},
+		},
+	}
+	consumers, err := a.CollectConsumers(listener)
+	assert.Nil(t, err)
+	assert.Len(t, consumers, 2)
+	assert.Equal(t, consumers[0].Username, getConsumerUsername("team-a", "key1"))
+	assert.Equal(t, consumers[0].Desc, "team-a")
+	assert.Equal(t, consumers[0].Plugins.KeyAuth.Key, "key1")
+	assert.Equal(t, consumers[1].Username, getConsumerUsername("team.b", "key2"))
+	assert.Equal(t, consumers[1].Plugins.KeyAuth.Key, "key2")
+	for _, c := range consumers {
+		assert.Nil(t, c.Validate())
+	}
+
+	// Typed configs are accepted as well.
+	config, err := anypb.New(&apikeyauthv3.ApiKeyAuth{
+		Credentials: []*apikeyauthv3.Credential{
+			{Key: "key3", Client: "team-c"},
+		},
+	})
+	assert.Nil(t, err)
+	consumers, err = translateAPIKeyAuthCredentials(config)
+	assert.Nil(t, err)
+	assert.Len(t, consumers, 1)
+	assert.Equal(t, consumers[0].Username, getConsumerUsername("team-c", "key3"))
+	assert.Equal(t, consumers[0].Plugins.KeyAuth.Key, "key3")
+
+	// Credentials without the key are invalid.
+	_, err = translateAPIKeyAuthCredentials(newTypedStruct(t, _apiKeyAuthTypeUrl, map[string]interface{}{
+		"credentials": []interface{}{
+			map[string]interface{}{"client": "team-a"},
+		},
+	}))
+	assert.NotNil(t, err)
+}
+
+func TestGetConsumerUsername(t *testing.T) {
+	assert.Equal(t, getConsumerUsername("team-a", "key1"), getConsumerUsername("team-a", "key1"))
+	assert.NotEqual(t, getConsumerUsername("team-a", "key1"), getConsumerUsername("team-a", "key2"))
+	assert.Regexp(t, `^team_a_[0-9a-f]+$`, getConsumerUsername("team-a", "key1"))
+
+	long := getConsumerUsername(string(make([]byte, 200)), "key1")
+	assert.LessOrEqual(t, len(long), 100)
+}
+
+func TestPatchRoutesWithAPIKeyAuth(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	routes := []*apisix.Route{
+		{Id: "1"},
+		{Id: "2"},
+	}
+	filters := []*hcmv3.HttpFilter{newAPIKeyAuthFilter(t)}
+	disabled := map[string]set.StringSet{
+		"2": {"envoy.filters.http.api_key_auth": {}},
+	}
+	a.patchRoutesWithHTTPFilters(routes, filters, disabled)
+	assert.Equal(t, routes[0].Plugins.KeyAuth.Header, "X-API-KEY")
+	assert.Equal(t, routes[0].Plugins.KeyAuth.Query, "api_key")
+	assert.True(t, routes[0].Plugins.KeyAuth.HideCredentials)
+	assert.Equal(t, routes[0].Plugins.KeyAuth.Key, "")
+	assert.Equal(t, routes[0].Plugins.KeyAuth.Meta.Priority, int32(_httpFilterPluginPriorityBase))
+	assert.Nil(t, routes[1].Plugins.GetKeyAuth())
+
+	// At least a header or query source is required.
+	_, err := a.translateAPIKeyAuth(newTypedStruct(t, _apiKeyAuthTypeUrl, map[string]interface{}{
+		"key_sources": []interface{}{
+			map[string]interface{}{"cookie": "api_key"},
+		},
+	}))
+	assert.Equal(t, err, errNoAPIKeySource)
+}
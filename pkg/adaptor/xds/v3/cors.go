@@ -0,0 +1,189 @@
+package v3
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"go.uber.org/zap"
+
+	"github.com/api7/apisix-mesh-agent/pkg/set"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+const (
+	_corsTypeUrl = "type.googleapis.com/envoy.extensions.filters.http.cors.v3.Cors"
+	// Filters without typed config are identified by their names.
+	_corsFilterName = "envoy.filters.http.cors"
+
+	// Envoy omits the CORS headers which are not configured, so browsers only
+	// allow the CORS-safelisted methods and headers, while the cors plugin
+	// allows all of them by default.
+	_corsSafelistedMethods         = "GET,HEAD,POST"
+	_corsSafelistedRequestHeaders  = "Accept,Accept-Language,Content-Language,Content-Type"
+	_corsSafelistedResponseHeaders = "Cache-Control,Content-Language,Content-Length,Content-Type,Expires,Last-Modified,Pragma"
+)
+
+// patchRouteWithCors translates the CORS policies of the route and its
+// virtual host to the cors plugin. Like the cors filter of Envoy, each
+// field is taken from the route policy first, then the virtual host one.
+func (adaptor *adaptor) patchRouteWithCors(vhost *routev3.VirtualHost, route *routev3.Route, r *apisix.Route) {
+	var policies []*routev3.CorsPolicy
+	for _, policy := range []*routev3.CorsPolicy{route.GetRoute().GetCors(), vhost.GetCors()} {
+		if policy != nil {
+			policies = append(policies, policy)
+		}
+	}
+	if len(policies) == 0 {
+		return
+	}
+	// The enabling is decided by the first policy, the shadow_enabled only
+	// affects stats, so it's ignored.
+	if enabled := policies[0].GetFilterEnabled(); enabled != nil {
+		fraction := getFractionalPercent(enabled.GetDefaultValue())
+		if fraction <= 0 {
+			return
+		}
+		if fraction < 1 {
+			adaptor.logger.Warnw("partial enabling of CORS policy is not supported, the policy is applied to all requests",
+				zap.String("route", r.Name),
+				zap.Any("filter_enabled", enabled),
+			)
+		}
+	}
+
+	var (
+		cors    apisix.Cors
+		origins []*matcherv3.StringMatcher
+	)
+	for _, policy := range policies {
+		if len(origins) == 0 {
+			origins = policy.GetAllowOriginStringMatch()
+		}
+		if cors.AllowMethods == "" {
+			cors.AllowMethods = policy.GetAllowMethods()
+		}
+		if cors.AllowHeaders == "" {
+			cors.AllowHeaders = policy.GetAllowHeaders()
+		}
+		if cors.ExposeHeaders == "" {
+			cors.ExposeHeaders = policy.GetExposeHeaders()
+		}
+		if cors.MaxAge == 0 && policy.GetMaxAge() != "" {
+			maxAge, err := strconv.ParseInt(policy.GetMaxAge(), 10, 32)
+			if err != nil {
+				adaptor.logger.Warnw("ignore invalid max_age of CORS policy",
+					zap.String("route", r.Name),
+					zap.String("max_age", policy.GetMaxAge()),
+				)
+			} else {
+				cors.MaxAge = int32(maxAge)
+			}
+		}
+	}
+	for _, policy := range policies {
+		if policy.GetAllowCredentials() != nil {
+			cors.AllowCredential = policy.GetAllowCredentials().GetValue()
+			break
+		}
+	}
+	if len(origins) == 0 {
+		// No origin is allowed by Envoy.
+		adaptor.logger.Warnw("ignore CORS policy without allowed origins",
+			zap.String("route", r.Name),
+		)
+		return
+	}
+	cors.AllowOrigins, cors.AllowOriginsByRegex = getCorsOrigins(origins)
+	if cors.AllowMethods == "" {
+		cors.AllowMethods = _corsSafelistedMethods
+	}
+	if cors.AllowHeaders == "" {
+		cors.AllowHeaders = _corsSafelistedRequestHeaders
+	}
+	if cors.ExposeHeaders == "" {
+		cors.ExposeHeaders = _corsSafelistedResponseHeaders
+	}
+
+	if r.Plugins == nil {
+		r.Plugins = &apisix.Plugins{}
+	}
+	r.Plugins.Cors = &cors
+}
+
+// getCorsOrigins translates the origin matchers to the origins of the cors
+// plugin. The allow_origins is ignored by the plugin once the regexes are
+// set, so exact origins are converted to regexes if there are other kinds
+// of matchers.
+func getCorsOrigins(origins []*matcherv3.StringMatcher) (string, []string) {
+	var (
+		exacts  []string
+		regexes []string
+	)
+	for _, origin := range origins {
+		if origin.GetExact() != "" && !origin.GetIgnoreCase() {
+			exacts = append(exacts, origin.GetExact())
+		}
+		regexes = append(regexes, getOriginRegex(origin))
+	}
+	if len(exacts) == len(origins) {
+		return strings.Join(exacts, ","), nil
+	}
+	return "", regexes
+}
+
+// getOriginRegex converts the origin matcher to a regex, unlike the route
+// matchers, the origins are quoted since they always contain dots.
+func getOriginRegex(matcher *matcherv3.StringMatcher) string {
+	var regex string
+	switch pat := matcher.GetMatchPattern().(type) {
+	case *matcherv3.StringMatcher_Exact:
+		regex = "^" + regexp.QuoteMeta(pat.Exact) + "$"
+	case *matcherv3.StringMatcher_Prefix:
+		regex = "^" + regexp.QuoteMeta(pat.Prefix)
+	case *matcherv3.StringMatcher_Suffix:
+		regex = regexp.QuoteMeta(pat.Suffix) + "$"
+	case *matcherv3.StringMatcher_Contains:
+		regex = regexp.QuoteMeta(pat.Contains)
+	case *matcherv3.StringMatcher_SafeRegex:
+		// The safe regex matches the whole origin.
+		regex = "^(?:" + pat.SafeRegex.GetRegex() + ")$"
+	}
+	if matcher.GetIgnoreCase() {
+		regex = "(?i)" + regex
+	}
+	return regex
+}
+
+// dropCorsWithoutFilter removes the cors plugin from routes if the cors
+// filter is absent in the HTTP filters or disabled on the routes, since the
+// CORS policies only take effect with the filter.
+func (adaptor *adaptor) dropCorsWithoutFilter(routes []*apisix.Route, filters []*hcmv3.HttpFilter, disabledFilters map[string]set.StringSet) {
+	var names []string
+	for _, f := range filters {
+		if typeUrl := getHTTPFilterTypeUrl(f); typeUrl == _corsTypeUrl || typeUrl == _corsFilterName {
+			names = append(names, f.GetName())
+		}
+	}
+	for _, r := range routes {
+		if r.GetPlugins().GetCors() == nil {
+			continue
+		}
+		enabled := false
+		for _, name := range names {
+			if _, ok := disabledFilters[r.Id][name]; !ok {
+				enabled = true
+				break
+			}
+		}
+		if !enabled {
+			adaptor.logger.Warnw("ignore CORS policy since the cors filter is absent or disabled",
+				zap.String("route", r.Name),
+			)
+			r.Plugins.Cors = nil
+		}
+	}
+}
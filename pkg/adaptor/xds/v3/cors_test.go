@@ -0,0 +1,141 @@
+package v3
+
+import (
+	"testing"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/set"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func newExactOrigin(origin string) *matcherv3.StringMatcher {
+	return &matcherv3.StringMatcher{
+		MatchPattern: &matcherv3.StringMatcher_Exact{
+			Exact: origin,
+		},
+	}
+}
+
+func TestPatchRouteWithCors(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	vhost := &routev3.VirtualHost{}
+	route := &routev3.Route{}
+	r := &apisix.Route{}
+	a.patchRouteWithCors(vhost, route, r)
+	assert.Nil(t, r.Plugins)
+
+	vhost = &routev3.VirtualHost{
+		Cors: &routev3.CorsPolicy{
+			AllowOriginStringMatch: []*matcherv3.StringMatcher{
+				newExactOrigin("https://a.example.com"),
+				newExactOrigin("https://b.example.com"),
+			},
+			AllowMethods:     "GET,PUT",
+			MaxAge:           "600",
+			AllowCredentials: &wrappers.BoolValue{Value: true},
+		},
+	}
+	route = &routev3.Route{
+		Action: &routev3.Route_Route{
+			Route: &routev3.RouteAction{
+				Cors: &routev3.CorsPolicy{
+					AllowHeaders: "X-Token",
+				},
+			},
+		},
+	}
+	r = &apisix.Route{Name: "route1"}
+	a.patchRouteWithCors(vhost, route, r)
+	assert.Equal(t, r.Plugins.Cors, &apisix.Cors{
+		AllowOrigins:    "https://a.example.com,https://b.example.com",
+		AllowMethods:    "GET,PUT",
+		AllowHeaders:    "X-Token",
+		ExposeHeaders:   _corsSafelistedResponseHeaders,
+		MaxAge:          600,
+		AllowCredential: true,
+	})
+	assert.Nil(t, r.Plugins.Cors.Validate())
+
+	// Regex origins, the exact one is converted.
+	route.GetRoute().Cors.AllowOriginStringMatch = []*matcherv3.StringMatcher{
+		newExactOrigin("https://a.example.com"),
+		{
+			MatchPattern: &matcherv3.StringMatcher_Suffix{
+				Suffix: ".example.org",
+			},
+			IgnoreCase: true,
+		},
+		{
+			MatchPattern: &matcherv3.StringMatcher_SafeRegex{
+				SafeRegex: &matcherv3.RegexMatcher{
+					Regex: `https://[a-z]+\.example\.net`,
+				},
+			},
+		},
+	}
+	r = &apisix.Route{Name: "route1"}
+	a.patchRouteWithCors(vhost, route, r)
+	assert.Equal(t, r.Plugins.Cors.AllowOrigins, "")
+	assert.Equal(t, r.Plugins.Cors.AllowOriginsByRegex, []string{
+		`^https://a\.example\.com$`,
+		`(?i)\.example\.org$`,
+		`^(?:https://[a-z]+\.example\.net)$`,
+	})
+
+	// Disabled by the route policy.
+	route.GetRoute().Cors.EnabledSpecifier = &routev3.CorsPolicy_FilterEnabled{
+		FilterEnabled: &corev3.RuntimeFractionalPercent{
+			DefaultValue: &typev3.FractionalPercent{
+				Numerator: 0,
+			},
+		},
+	}
+	r = &apisix.Route{Name: "route1"}
+	a.patchRouteWithCors(vhost, route, r)
+	assert.Nil(t, r.Plugins)
+
+	// No allowed origins.
+	r = &apisix.Route{Name: "route1"}
+	a.patchRouteWithCors(&routev3.VirtualHost{
+		Cors: &routev3.CorsPolicy{
+			AllowMethods: "GET",
+		},
+	}, &routev3.Route{}, r)
+	assert.Nil(t, r.Plugins)
+}
+
+func TestDropCorsWithoutFilter(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	newRoutes := func() []*apisix.Route {
+		return []*apisix.Route{
+			{Id: "1", Plugins: &apisix.Plugins{Cors: &apisix.Cors{AllowOrigins: "https://a.example.com"}}},
+			{Id: "2", Plugins: &apisix.Plugins{Cors: &apisix.Cors{AllowOrigins: "https://a.example.com"}}},
+		}
+	}
+	disabled := map[string]set.StringSet{
+		"2": {"envoy.filters.http.cors": {}},
+	}
+
+	routes := newRoutes()
+	a.dropCorsWithoutFilter(routes, []*hcmv3.HttpFilter{
+		{Name: "envoy.filters.http.cors"},
+		{Name: "envoy.filters.http.router"},
+	}, disabled)
+	assert.NotNil(t, routes[0].Plugins.Cors)
+	assert.Nil(t, routes[1].Plugins.Cors)
+
+	routes = newRoutes()
+	a.dropCorsWithoutFilter(routes, []*hcmv3.HttpFilter{
+		{Name: "envoy.filters.http.router"},
+	}, nil)
+	assert.Nil(t, routes[0].Plugins.Cors)
+	assert.Nil(t, routes[1].Plugins.Cors)
+}
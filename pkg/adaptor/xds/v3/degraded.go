@@ -0,0 +1,59 @@
+package v3
+
+import (
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// degradedAdaptor is used when the adaptor cannot be initialized, it
+// refuses to translate anything, so that provisioners hold the last
+// state instead of crashing the whole agent.
+type degradedAdaptor struct{}
+
+// IsDegraded checks whether the adaptor runs in the degraded mode.
+func IsDegraded(a Adaptor) bool {
+	_, ok := a.(*degradedAdaptor)
+	return ok
+}
+
+func (d *degradedAdaptor) TranslateRouteConfiguration(_ *routev3.RouteConfiguration, _ *TranslateOptions) ([]*apisix.Route, error) {
+	return nil, ErrAdaptorDegraded
+}
+
+func (d *degradedAdaptor) TranslateCluster(_ *clusterv3.Cluster) (*apisix.Upstream, error) {
+	return nil, ErrAdaptorDegraded
+}
+
+func (d *degradedAdaptor) TranslateClusterLoadAssignment(_ *endpointv3.ClusterLoadAssignment) ([]*apisix.Node, error) {
+	return nil, ErrAdaptorDegraded
+}
+
+func (d *degradedAdaptor) CollectRouteNamesAndConfigs(_ *listenerv3.Listener) ([]string, []*routev3.RouteConfiguration, error) {
+	return nil, nil, ErrAdaptorDegraded
+}
+
+func (d *degradedAdaptor) CollectRouteHTTPFilters(_ *listenerv3.Listener) (map[string][]*hcmv3.HttpFilter, error) {
+	return nil, ErrAdaptorDegraded
+}
+
+func (d *degradedAdaptor) CollectClusterHashPolicies(_ *routev3.RouteConfiguration) map[string]*HashPolicy {
+	return nil
+}
+
+func (d *degradedAdaptor) CollectClusterRetryPolicies(_ *routev3.RouteConfiguration) map[string]*RetryPolicy {
+	return nil
+}
+
+func (d *degradedAdaptor) CollectConsumers(_ *listenerv3.Listener) ([]*apisix.Consumer, error) {
+	return nil, ErrAdaptorDegraded
+}
+
+func (d *degradedAdaptor) TranslateSecret(_ *tlsv3.Secret) (*apisix.Ssl, error) {
+	return nil, ErrAdaptorDegraded
+}
@@ -0,0 +1,34 @@
+package v3
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+)
+
+func TestDegradedAdaptor(t *testing.T) {
+	cfg := &config.Config{
+		LogLevel:  "info",
+		LogOutput: "stderr",
+		ClusterConnectTimeouts: map[string]string{
+			"redis": "10s",
+		},
+	}
+	_, err := NewAdaptor(cfg)
+	assert.Equal(t, err, config.ErrBadClusterConnectTimeout)
+
+	cfg.DegradeOnAdaptorFailure = true
+	a, err := NewAdaptor(cfg)
+	assert.Nil(t, err)
+	assert.True(t, IsDegraded(a))
+	_, err = a.TranslateCluster(&clusterv3.Cluster{Name: "test"})
+	assert.Equal(t, err, ErrAdaptorDegraded)
+
+	cfg.ClusterConnectTimeouts = nil
+	a, err = NewAdaptor(cfg)
+	assert.Nil(t, err)
+	assert.False(t, IsDegraded(a))
+}
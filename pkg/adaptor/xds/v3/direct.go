@@ -0,0 +1,143 @@
+package v3
+
+import (
+	"fmt"
+	"regexp"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"go.uber.org/zap"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+var (
+	// _redirectCodes maps the redirect response codes of Envoy to the
+	// HTTP status codes.
+	_redirectCodes = map[routev3.RedirectAction_RedirectResponseCode]int32{
+		routev3.RedirectAction_MOVED_PERMANENTLY:  301,
+		routev3.RedirectAction_FOUND:              302,
+		routev3.RedirectAction_SEE_OTHER:          303,
+		routev3.RedirectAction_TEMPORARY_REDIRECT: 307,
+		routev3.RedirectAction_PERMANENT_REDIRECT: 308,
+	}
+)
+
+// patchRouteWithDirectResponse uses the fault-injection plugin to respond
+// the status and body of the direct_response action. It reports whether
+// the route should be skipped.
+func (adaptor *adaptor) patchRouteWithDirectResponse(route *routev3.Route, r *apisix.Route) bool {
+	dr := route.GetDirectResponse()
+	if dr == nil {
+		return false
+	}
+	status := int32(dr.GetStatus())
+	if status < 200 || status > 599 {
+		// The fault-injection plugin cannot abort with the informational
+		// status codes.
+		adaptor.logger.Warnw("ignore route with unsupported direct response status",
+			zap.String("route", route.GetName()),
+			zap.Int32("status", status),
+		)
+		return true
+	}
+	abort := &apisix.FaultInjection_Abort{
+		HttpStatus: status,
+	}
+	switch body := dr.GetBody().GetSpecifier().(type) {
+	case *corev3.DataSource_InlineString:
+		abort.Body = body.InlineString
+	case *corev3.DataSource_InlineBytes:
+		abort.Body = string(body.InlineBytes)
+	case *corev3.DataSource_Filename:
+		// The file is on the control plane side (or in the Envoy container),
+		// which is unreachable here.
+		adaptor.logger.Warnw("body file of direct response is not supported, the body will be empty",
+			zap.String("route", route.GetName()),
+			zap.String("filename", body.Filename),
+		)
+	}
+	if r.Plugins == nil {
+		r.Plugins = &apisix.Plugins{}
+	}
+	r.Plugins.FaultInjection = &apisix.FaultInjection{
+		Abort: abort,
+	}
+	return false
+}
+
+// patchRouteWithRedirect translates the redirect action to the redirect
+// plugin. The redirect URI is assembled by the Nginx variables for the
+// parts which are not changed, e.g. redirecting to the host "example.com"
+// is translated to "$scheme://example.com$request_uri". The prefix_rewrite
+// is translated to the regex_uri, which generates a relative URI, so it
+// cannot be used with the scheme, host or port changed, in such a case, the
+// route is skipped. It reports whether the route should be skipped.
+func (adaptor *adaptor) patchRouteWithRedirect(route *routev3.Route, r *apisix.Route) bool {
+	action := route.GetRedirect()
+	if action == nil {
+		return false
+	}
+	redirect := &apisix.Redirect{
+		RetCode: _redirectCodes[action.GetResponseCode()],
+	}
+	if redirect.RetCode == 0 {
+		redirect.RetCode = 301
+	}
+
+	scheme := action.GetSchemeRedirect()
+	if action.GetHttpsRedirect() {
+		scheme = "https"
+	}
+	host := action.GetHostRedirect()
+	port := action.GetPortRedirect()
+	authorityChanged := scheme != "" || host != "" || port != 0
+
+	switch {
+	case action.GetPrefixRewrite() != "":
+		prefix, ok := route.GetMatch().GetPathSpecifier().(*routev3.RouteMatch_Prefix)
+		if !ok || authorityChanged {
+			adaptor.logger.Warnw("ignore route with unsupported prefix rewrite in redirect",
+				zap.String("route", route.GetName()),
+				zap.Any("redirect", action),
+			)
+			return true
+		}
+		redirect.RegexUri = []string{"^" + regexp.QuoteMeta(prefix.Prefix) + "(.*)", action.GetPrefixRewrite() + "$1"}
+		redirect.AppendQueryString = !action.GetStripQuery()
+	case action.GetPathRedirect() == "" && !action.GetStripQuery() && scheme == "https" && host == "" && port == 0:
+		redirect.HttpToHttps = true
+	default:
+		if scheme == "" {
+			scheme = "$scheme"
+		}
+		// The $host doesn't contain the port, while the $http_host is the
+		// Host header as is.
+		authority := "$http_host"
+		if host != "" || port != 0 {
+			if host == "" {
+				host = "$host"
+			}
+			authority = host
+			if port != 0 {
+				authority = fmt.Sprintf("%s:%d", host, port)
+			}
+		}
+		path := action.GetPathRedirect()
+		if path == "" {
+			path = "$request_uri"
+			if action.GetStripQuery() {
+				path = "$uri"
+			}
+		} else {
+			redirect.AppendQueryString = !action.GetStripQuery()
+		}
+		redirect.Uri = fmt.Sprintf("%s://%s%s", scheme, authority, path)
+	}
+
+	if r.Plugins == nil {
+		r.Plugins = &apisix.Plugins{}
+	}
+	r.Plugins.Redirect = redirect
+	return false
+}
@@ -0,0 +1,199 @@
+package v3
+
+import (
+	"testing"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestPatchRouteWithDirectResponse(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	route := &routev3.Route{
+		Name: "route1",
+		Action: &routev3.Route_DirectResponse{
+			DirectResponse: &routev3.DirectResponseAction{
+				Status: 200,
+				Body: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineString{
+						InlineString: "ok",
+					},
+				},
+			},
+		},
+	}
+	r := &apisix.Route{}
+	assert.False(t, a.patchRouteWithDirectResponse(route, r))
+	assert.Equal(t, r.Plugins.FaultInjection, &apisix.FaultInjection{
+		Abort: &apisix.FaultInjection_Abort{
+			HttpStatus: 200,
+			Body:       "ok",
+		},
+	})
+
+	route.GetDirectResponse().Body = &corev3.DataSource{
+		Specifier: &corev3.DataSource_Filename{
+			Filename: "/etc/envoy/404.html",
+		},
+	}
+	route.GetDirectResponse().Status = 404
+	r = &apisix.Route{}
+	assert.False(t, a.patchRouteWithDirectResponse(route, r))
+	assert.Equal(t, r.Plugins.FaultInjection.Abort, &apisix.FaultInjection_Abort{
+		HttpStatus: 404,
+	})
+
+	route.GetDirectResponse().Status = 101
+	assert.True(t, a.patchRouteWithDirectResponse(route, &apisix.Route{}))
+
+	// Not a direct response.
+	r = &apisix.Route{}
+	assert.False(t, a.patchRouteWithDirectResponse(&routev3.Route{}, r))
+	assert.Nil(t, r.Plugins)
+}
+
+func TestPatchRouteWithRedirect(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	prefixMatch := &routev3.RouteMatch{
+		PathSpecifier: &routev3.RouteMatch_Prefix{
+			Prefix: "/old",
+		},
+	}
+	testCases := []struct {
+		name     string
+		match    *routev3.RouteMatch
+		action   *routev3.RedirectAction
+		redirect *apisix.Redirect
+		skip     bool
+	}{
+		{
+			name: "https redirect",
+			action: &routev3.RedirectAction{
+				SchemeRewriteSpecifier: &routev3.RedirectAction_HttpsRedirect{
+					HttpsRedirect: true,
+				},
+			},
+			redirect: &apisix.Redirect{HttpToHttps: true, RetCode: 301},
+		},
+		{
+			name: "host redirect",
+			action: &routev3.RedirectAction{
+				HostRedirect: "example.com",
+				ResponseCode: routev3.RedirectAction_FOUND,
+			},
+			redirect: &apisix.Redirect{Uri: "$scheme://example.com$request_uri", RetCode: 302},
+		},
+		{
+			name: "port redirect without query",
+			action: &routev3.RedirectAction{
+				SchemeRewriteSpecifier: &routev3.RedirectAction_SchemeRedirect{
+					SchemeRedirect: "https",
+				},
+				PortRedirect: 8443,
+				StripQuery:   true,
+			},
+			redirect: &apisix.Redirect{Uri: "https://$host:8443$uri", RetCode: 301},
+		},
+		{
+			name: "path redirect",
+			action: &routev3.RedirectAction{
+				PathRewriteSpecifier: &routev3.RedirectAction_PathRedirect{
+					PathRedirect: "/new",
+				},
+				ResponseCode: routev3.RedirectAction_PERMANENT_REDIRECT,
+			},
+			redirect: &apisix.Redirect{Uri: "$scheme://$http_host/new", RetCode: 308, AppendQueryString: true},
+		},
+		{
+			name:  "prefix rewrite",
+			match: prefixMatch,
+			action: &routev3.RedirectAction{
+				PathRewriteSpecifier: &routev3.RedirectAction_PrefixRewrite{
+					PrefixRewrite: "/new",
+				},
+			},
+			redirect: &apisix.Redirect{RegexUri: []string{"^/old(.*)", "/new$1"}, RetCode: 301, AppendQueryString: true},
+		},
+		{
+			name:  "prefix rewrite with host redirect",
+			match: prefixMatch,
+			action: &routev3.RedirectAction{
+				HostRedirect: "example.com",
+				PathRewriteSpecifier: &routev3.RedirectAction_PrefixRewrite{
+					PrefixRewrite: "/new",
+				},
+			},
+			skip: true,
+		},
+	}
+	for _, tc := range testCases {
+		route := &routev3.Route{
+			Name:  "route1",
+			Match: tc.match,
+			Action: &routev3.Route_Redirect{
+				Redirect: tc.action,
+			},
+		}
+		r := &apisix.Route{}
+		assert.Equal(t, a.patchRouteWithRedirect(route, r), tc.skip, tc.name)
+		if !tc.skip {
+			assert.Equal(t, r.Plugins.Redirect, tc.redirect, tc.name)
+		}
+	}
+}
+
+func TestTranslateVirtualHostWithDirectActions(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	vhost := &routev3.VirtualHost{
+		Name:    "vhost1",
+		Domains: []string{"*"},
+		Routes: []*routev3.Route{
+			{
+				Name: "health",
+				Match: &routev3.RouteMatch{
+					PathSpecifier: &routev3.RouteMatch_Path{
+						Path: "/health",
+					},
+				},
+				Action: &routev3.Route_DirectResponse{
+					DirectResponse: &routev3.DirectResponseAction{
+						Status: 200,
+					},
+				},
+			},
+			{
+				Name: "moved",
+				Match: &routev3.RouteMatch{
+					PathSpecifier: &routev3.RouteMatch_Prefix{
+						Prefix: "/",
+					},
+				},
+				Action: &routev3.Route_Redirect{
+					Redirect: &routev3.RedirectAction{
+						HostRedirect: "example.com",
+					},
+				},
+			},
+			{
+				Name: "filter",
+				Match: &routev3.RouteMatch{
+					PathSpecifier: &routev3.RouteMatch_Prefix{
+						Prefix: "/",
+					},
+				},
+				Action: &routev3.Route_FilterAction{},
+			},
+		},
+	}
+	routes, err := a.translateVirtualHost("rc1", vhost, nil, nil)
+	assert.Nil(t, err)
+	assert.Len(t, routes, 2)
+	assert.Equal(t, routes[0].UpstreamId, "")
+	assert.Equal(t, routes[0].Plugins.FaultInjection.Abort.HttpStatus, int32(200))
+	assert.Equal(t, routes[1].UpstreamId, "")
+	assert.Equal(t, routes[1].Plugins.Redirect.Uri, "$scheme://example.com$request_uri")
+}
@@ -0,0 +1,120 @@
+package v3
+
+import (
+	"fmt"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	healthcheckv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/health_check/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+const (
+	_healthCheckTypeUrl = "type.googleapis.com/envoy.extensions.filters.http.health_check.v3.HealthCheck"
+	// Health check routes should be matched before the normal ones.
+	_healthCheckRoutePriority = _defaultRoutePriority + 1
+	_healthCheckStatus        = 200
+	_healthCheckBody          = "OK"
+)
+
+// translateHealthCheckFilters translates the health_check HTTP filters to
+// APISIX routes which respond to the health check requests directly (by the
+// fault-injection plugin) instead of proxying them. Only the non pass through
+// mode is supported, and since the drain state of Envoy doesn't exist in
+// Apache APISIX, these routes always respond 200.
+func (adaptor *adaptor) translateHealthCheckFilters(prefix string, filters []*hcmv3.HttpFilter, tenant string) []*apisix.Route {
+	var routes []*apisix.Route
+	for _, f := range filters {
+		if getHTTPFilterTypeUrl(f) != _healthCheckTypeUrl {
+			continue
+		}
+		r, err := adaptor.translateHealthCheck(prefix, f, tenant)
+		if err != nil {
+			adaptor.logger.Errorw("failed to translate health_check filter",
+				zap.Error(err),
+				zap.Any("filter", f),
+			)
+			continue
+		}
+		if r != nil {
+			routes = append(routes, r)
+		}
+	}
+	return routes
+}
+
+func (adaptor *adaptor) translateHealthCheck(prefix string, f *hcmv3.HttpFilter, tenant string) (*apisix.Route, error) {
+	var hc healthcheckv3.HealthCheck
+	if err := anypb.UnmarshalTo(f.GetTypedConfig(), &hc, proto.UnmarshalOptions{}); err != nil {
+		return nil, err
+	}
+	if hc.GetPassThroughMode().GetValue() {
+		adaptor.logger.Warnw("ignore health_check filter in pass through mode",
+			zap.String("filter", f.GetName()),
+		)
+		return nil, nil
+	}
+	if len(hc.GetClusterMinHealthyPercentages()) > 0 {
+		adaptor.logger.Warnw("cluster_min_healthy_percentages of health_check filter is not supported",
+			zap.String("filter", f.GetName()),
+		)
+	}
+
+	var (
+		uri     string
+		headers []*routev3.HeaderMatcher
+	)
+	for _, header := range hc.GetHeaders() {
+		if header.GetName() == ":path" && !header.GetInvertMatch() && header.GetExactMatch() != "" {
+			uri = header.GetExactMatch()
+			continue
+		}
+		headers = append(headers, header)
+	}
+	if uri == "" {
+		adaptor.logger.Warnw("ignore health_check filter without exact :path matcher",
+			zap.String("filter", f.GetName()),
+		)
+		return nil, nil
+	}
+	// Reuse the header translation of routes.
+	route := &routev3.Route{
+		Name: f.GetName(),
+		Match: &routev3.RouteMatch{
+			Headers: headers,
+		},
+	}
+	methods, skip := adaptor.getMethods(route)
+	if skip {
+		return nil, nil
+	}
+	vars, skip := adaptor.getHeadersMatchVars(route)
+	if skip {
+		return nil, nil
+	}
+
+	name := fmt.Sprintf("%s#health_check#%s", f.GetName(), prefix)
+	name = TenantScopedName(tenant, name)
+	return &apisix.Route{
+		Name:     name,
+		Priority: _healthCheckRoutePriority,
+		Status:   1,
+		Id:       id.GenID(name),
+		Uris:     []string{uri},
+		Methods:  methods,
+		Vars:     vars,
+		Plugins: &apisix.Plugins{
+			FaultInjection: &apisix.FaultInjection{
+				Abort: &apisix.FaultInjection_Abort{
+					HttpStatus: _healthCheckStatus,
+					Body:       _healthCheckBody,
+				},
+			},
+		},
+	}, nil
+}
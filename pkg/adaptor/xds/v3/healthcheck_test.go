@@ -0,0 +1,93 @@
+package v3
+
+import (
+	"testing"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	healthcheckv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/health_check/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func newHealthCheckFilter(t *testing.T, hc *healthcheckv3.HealthCheck) *hcmv3.HttpFilter {
+	any, err := anypb.New(hc)
+	assert.Nil(t, err)
+	return &hcmv3.HttpFilter{
+		Name: "envoy.filters.http.health_check",
+		ConfigType: &hcmv3.HttpFilter_TypedConfig{
+			TypedConfig: any,
+		},
+	}
+}
+
+func TestTranslateHealthCheckFilters(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	filters := []*hcmv3.HttpFilter{
+		newHealthCheckFilter(t, &healthcheckv3.HealthCheck{
+			PassThroughMode: &wrappers.BoolValue{Value: false},
+			Headers: []*routev3.HeaderMatcher{
+				{
+					Name: ":path",
+					HeaderMatchSpecifier: &routev3.HeaderMatcher_ExactMatch{
+						ExactMatch: "/healthz",
+					},
+				},
+				{
+					Name: "x-envoy-livenessprobe",
+					HeaderMatchSpecifier: &routev3.HeaderMatcher_PresentMatch{
+						PresentMatch: true,
+					},
+				},
+			},
+		}),
+		// Pass through mode is not supported.
+		newHealthCheckFilter(t, &healthcheckv3.HealthCheck{
+			PassThroughMode: &wrappers.BoolValue{Value: true},
+			Headers: []*routev3.HeaderMatcher{
+				{
+					Name: ":path",
+					HeaderMatchSpecifier: &routev3.HeaderMatcher_ExactMatch{
+						ExactMatch: "/ready",
+					},
+				},
+			},
+		}),
+		newTypedHTTPFilter("type.googleapis.com/envoy.extensions.filters.http.fault.v3.HTTPFault"),
+	}
+	rc := &routev3.RouteConfiguration{
+		Name: "rc1",
+	}
+	opts := &TranslateOptions{
+		RouteOriginalDestination: map[string]string{
+			"rc1": "10.0.5.4:8080",
+		},
+		RouteHTTPFilters: map[string][]*hcmv3.HttpFilter{
+			"rc1": filters,
+		},
+	}
+	routes, err := a.TranslateRouteConfiguration(rc, opts)
+	assert.Nil(t, err)
+	assert.Len(t, routes, 1)
+
+	name := "envoy.filters.http.health_check#health_check#rc1"
+	assert.Equal(t, routes[0].Name, name)
+	assert.Equal(t, routes[0].Id, id.GenID(name))
+	assert.Equal(t, routes[0].Priority, int32(_healthCheckRoutePriority))
+	assert.Equal(t, routes[0].Uris, []string{"/healthz"})
+	assert.Empty(t, routes[0].UpstreamId)
+	assert.Equal(t, routes[0].Plugins.FaultInjection.Abort, &apisix.FaultInjection_Abort{
+		HttpStatus: 200,
+		Body:       "OK",
+	})
+	assert.Len(t, routes[0].Vars, 2)
+	assert.Equal(t, routes[0].Vars[1], &apisix.Var{
+		Vars: []string{"connection_original_dst", "==", "10.0.5.4:8080"},
+	})
+}
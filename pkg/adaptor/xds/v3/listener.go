@@ -22,6 +22,49 @@ func (adaptor *adaptor) CollectRouteNamesAndConfigs(l *listenerv3.Listener) ([]s
 		staticConfigs []*routev3.RouteConfiguration
 	)
 
+	hcms, err := adaptor.getHTTPConnectionManagers(l)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, hcm := range hcms {
+		adaptor.checkHTTPHeaderLimits(l, hcm)
+		if hcm.GetRds() != nil {
+			rdsNames = append(rdsNames, hcm.GetRds().GetRouteConfigName())
+		} else if hcm.GetRouteConfig() != nil {
+			// TODO deep copy?
+			staticConfigs = append(staticConfigs, hcm.GetRouteConfig())
+		}
+	}
+	adaptor.logger.Debugw("got route names and config from listener",
+		zap.Strings("route_names", rdsNames),
+		zap.Any("route_configs", staticConfigs),
+		zap.Any("listener", l),
+	)
+	return rdsNames, staticConfigs, nil
+}
+
+func (adaptor *adaptor) CollectRouteHTTPFilters(l *listenerv3.Listener) (map[string][]*hcmv3.HttpFilter, error) {
+	hcms, err := adaptor.getHTTPConnectionManagers(l)
+	if err != nil {
+		return nil, err
+	}
+	filters := make(map[string][]*hcmv3.HttpFilter)
+	for _, hcm := range hcms {
+		var name string
+		if hcm.GetRds() != nil {
+			name = hcm.GetRds().GetRouteConfigName()
+		} else if hcm.GetRouteConfig() != nil {
+			name = hcm.GetRouteConfig().GetName()
+		} else {
+			continue
+		}
+		filters[name] = hcm.GetHttpFilters()
+	}
+	return filters, nil
+}
+
+func (adaptor *adaptor) getHTTPConnectionManagers(l *listenerv3.Listener) ([]*hcmv3.HttpConnectionManager, error) {
+	var hcms []*hcmv3.HttpConnectionManager
 	for _, fc := range l.FilterChains {
 		for _, f := range fc.Filters {
 			if f.Name == xdswellknown.HTTPConnectionManager && f.GetTypedConfig().GetTypeUrl() == _hcmv3 {
@@ -31,21 +74,50 @@ func (adaptor *adaptor) CollectRouteNamesAndConfigs(l *listenerv3.Listener) ([]s
 						zap.Error(err),
 						zap.Any("listener", l),
 					)
-					return nil, nil, err
-				}
-				if hcm.GetRds() != nil {
-					rdsNames = append(rdsNames, hcm.GetRds().GetRouteConfigName())
-				} else if hcm.GetRouteConfig() != nil {
-					// TODO deep copy?
-					staticConfigs = append(staticConfigs, hcm.GetRouteConfig())
+					return nil, err
 				}
+				hcms = append(hcms, &hcm)
 			}
 		}
 	}
-	adaptor.logger.Debugw("got route names and config from listener",
-		zap.Strings("route_names", rdsNames),
-		zap.Any("route_configs", staticConfigs),
-		zap.Any("listener", l),
-	)
-	return rdsNames, staticConfigs, nil
+	// Listeners for the gRPC xDS clients (proxyless) use the api_listener
+	// instead of the filter chains.
+	if al := l.GetApiListener().GetApiListener(); al != nil {
+		if al.GetTypeUrl() != _hcmv3 {
+			adaptor.logger.Warnw("ignore api_listener which is not a HttpConnectionManager",
+				zap.String("listener", l.GetName()),
+				zap.String("type_url", al.GetTypeUrl()),
+			)
+			return hcms, nil
+		}
+		var hcm hcmv3.HttpConnectionManager
+		if err := anypb.UnmarshalTo(al, &hcm, proto.UnmarshalOptions{}); err != nil {
+			adaptor.logger.Errorw("failed to unmarshal HttpConnectionManager config in api_listener",
+				zap.Error(err),
+				zap.String("listener", l.GetName()),
+			)
+			return nil, err
+		}
+		hcms = append(hcms, &hcm)
+	}
+	return hcms, nil
+}
+
+// checkHTTPHeaderLimits logs the header count and size limits of the
+// HttpConnectionManager, they cannot be applied since the equivalents
+// in Apache APISIX are the global nginx settings (like the
+// large_client_header_buffers directive).
+func (adaptor *adaptor) checkHTTPHeaderLimits(l *listenerv3.Listener, hcm *hcmv3.HttpConnectionManager) {
+	if count := hcm.GetCommonHttpProtocolOptions().GetMaxHeadersCount(); count != nil {
+		adaptor.logger.Warnw("max_headers_count of HttpConnectionManager cannot be applied",
+			zap.String("listener", l.GetName()),
+			zap.Uint32("max_headers_count", count.GetValue()),
+		)
+	}
+	if size := hcm.GetMaxRequestHeadersKb(); size != nil {
+		adaptor.logger.Warnw("max_request_headers_kb of HttpConnectionManager cannot be applied",
+			zap.String("listener", l.GetName()),
+			zap.Uint32("max_request_headers_kb", size.GetValue()),
+		)
+	}
 }
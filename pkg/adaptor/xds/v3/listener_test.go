@@ -1,13 +1,17 @@
 package v3
 
 import (
+	"bytes"
 	"testing"
 
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	xdswellknown "github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
@@ -94,3 +98,136 @@ func TestCollectRouteNamesAndConfigs(t *testing.T) {
 	assert.Len(t, staticConfigs[0].VirtualHosts, 1)
 	assert.Equal(t, staticConfigs[0].VirtualHosts[0].Name, "v1")
 }
+
+func TestCollectRouteHTTPFilters(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	var (
+		any1 anypb.Any
+		any2 anypb.Any
+	)
+	f1 := &hcmv3.HttpConnectionManager{
+		RouteSpecifier: &hcmv3.HttpConnectionManager_Rds{
+			Rds: &hcmv3.Rds{
+				RouteConfigName: "route1",
+			},
+		},
+		HttpFilters: []*hcmv3.HttpFilter{
+			{
+				Name: "envoy.filters.http.jwt_authn",
+				ConfigType: &hcmv3.HttpFilter_TypedConfig{
+					TypedConfig: &anypb.Any{
+						TypeUrl: "type.googleapis.com/envoy.extensions.filters.http.jwt_authn.v3.JwtAuthentication",
+					},
+				},
+			},
+			{
+				Name: "envoy.filters.http.router",
+			},
+		},
+	}
+	f2 := &hcmv3.HttpConnectionManager{
+		RouteSpecifier: &hcmv3.HttpConnectionManager_RouteConfig{
+			RouteConfig: &routev3.RouteConfiguration{
+				Name: "route2",
+			},
+		},
+	}
+	assert.Nil(t, anypb.MarshalFrom(&any1, f1, proto.MarshalOptions{}))
+	assert.Nil(t, anypb.MarshalFrom(&any2, f2, proto.MarshalOptions{}))
+
+	listener := &listenerv3.Listener{
+		Name: "listener1",
+		FilterChains: []*listenerv3.FilterChain{
+			{
+				Filters: []*listenerv3.Filter{
+					{
+						Name: xdswellknown.HTTPConnectionManager,
+						ConfigType: &listenerv3.Filter_TypedConfig{
+							TypedConfig: &any1,
+						},
+					},
+					{
+						Name: xdswellknown.HTTPConnectionManager,
+						ConfigType: &listenerv3.Filter_TypedConfig{
+							TypedConfig: &any2,
+						},
+					},
+				},
+			},
+		},
+	}
+	filters, err := a.CollectRouteHTTPFilters(listener)
+	assert.Nil(t, err)
+	assert.Len(t, filters, 2)
+	assert.Len(t, filters["route1"], 2)
+	assert.Equal(t, getHTTPFilterTypeUrl(filters["route1"][0]), "type.googleapis.com/envoy.extensions.filters.http.jwt_authn.v3.JwtAuthentication")
+	assert.Equal(t, getHTTPFilterTypeUrl(filters["route1"][1]), "envoy.filters.http.router")
+	assert.Nil(t, filters["route2"])
+}
+
+func TestCollectRouteNamesAndConfigsFromAPIListener(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	hcm, err := anypb.New(&hcmv3.HttpConnectionManager{
+		RouteSpecifier: &hcmv3.HttpConnectionManager_Rds{
+			Rds: &hcmv3.Rds{
+				RouteConfigName: "grpc-route",
+			},
+		},
+		HttpFilters: []*hcmv3.HttpFilter{
+			{
+				Name: "envoy.filters.http.router",
+			},
+		},
+	})
+	assert.Nil(t, err)
+	listener := &listenerv3.Listener{
+		Name: "httpbin.default.svc.cluster.local:8080",
+		ApiListener: &listenerv3.ApiListener{
+			ApiListener: hcm,
+		},
+	}
+	rdsNames, staticConfigs, err := a.CollectRouteNamesAndConfigs(listener)
+	assert.Nil(t, err)
+	assert.Equal(t, rdsNames, []string{"grpc-route"})
+	assert.Len(t, staticConfigs, 0)
+	filters, err := a.CollectRouteHTTPFilters(listener)
+	assert.Nil(t, err)
+	assert.Len(t, filters["grpc-route"], 1)
+
+	// Not a HttpConnectionManager.
+	listener.ApiListener.ApiListener = &anypb.Any{
+		TypeUrl: "type.googleapis.com/envoy.extensions.filters.network.tcp_proxy.v3.TcpProxy",
+	}
+	rdsNames, staticConfigs, err = a.CollectRouteNamesAndConfigs(listener)
+	assert.Nil(t, err)
+	assert.Len(t, rdsNames, 0)
+	assert.Len(t, staticConfigs, 0)
+}
+
+func TestCheckHTTPHeaderLimits(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := log.NewLogger(
+		log.WithLogLevel("warn"),
+		log.WithWriteSyncer(zapcore.AddSync(&buf)),
+	)
+	assert.Nil(t, err)
+	a := &adaptor{logger: logger}
+	l := &listenerv3.Listener{Name: "listener1"}
+
+	a.checkHTTPHeaderLimits(l, &hcmv3.HttpConnectionManager{})
+	assert.Equal(t, buf.String(), "")
+
+	a.checkHTTPHeaderLimits(l, &hcmv3.HttpConnectionManager{
+		CommonHttpProtocolOptions: &corev3.HttpProtocolOptions{
+			MaxHeadersCount: &wrappers.UInt32Value{Value: 50},
+		},
+		MaxRequestHeadersKb: &wrappers.UInt32Value{Value: 96},
+	})
+	assert.Contains(t, buf.String(), "max_headers_count of HttpConnectionManager cannot be applied")
+	assert.Contains(t, buf.String(), `"max_headers_count":50`)
+	assert.Contains(t, buf.String(), "max_request_headers_kb of HttpConnectionManager cannot be applied")
+	assert.Contains(t, buf.String(), `"max_request_headers_kb":96`)
+	assert.Contains(t, buf.String(), "listener1")
+}
@@ -0,0 +1,268 @@
+package v3
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	udpatypev1 "github.com/cncf/xds/go/udpa/type/v1"
+	xdstypev3 "github.com/cncf/xds/go/xds/type/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	_udpaTypedStructTypeUrl = "type.googleapis.com/udpa.type.v1.TypedStruct"
+	_xdsTypedStructTypeUrl  = "type.googleapis.com/xds.type.v3.TypedStruct"
+)
+
+var (
+	// _pluginNameRegex is the pattern of the APISIX plugin names.
+	_pluginNameRegex = regexp.MustCompile(`^[a-z0-9-]+$`)
+	// _typedPlugins are the plugins which have their own fields in the
+	// apisix.Plugins, they cannot be the target of mappings.
+	_typedPlugins = map[string]struct{}{
+		"cors":             {},
+		"fault-injection":  {},
+		"key-auth":         {},
+		"limit-conn":       {},
+		"limit-req":        {},
+		"prometheus":       {},
+		"proxy-rewrite":    {},
+		"redirect":         {},
+		"response-rewrite": {},
+		"traffic-split":    {},
+	}
+)
+
+// FilterPluginMapping describes how an Envoy HTTP filter is translated to
+// an APISIX plugin, mappings are loaded from the filter plugin mapping file,
+// they take precedence over the native translations.
+type FilterPluginMapping struct {
+	// TypeUrl is the type url of the HTTP filter config, it's the inner
+	// one for configs in TypedStruct.
+	TypeUrl string `json:"type_url"`
+	// Plugin is the name of the APISIX plugin.
+	Plugin string `json:"plugin"`
+	// Config is the static part of the plugin config, the mapped fields
+	// are merged into it.
+	Config map[string]interface{} `json:"config,omitempty"`
+	// Fields map the fields of the filter config to the plugin config.
+	Fields []FieldMapping `json:"fields,omitempty"`
+}
+
+// FieldMapping maps a field of the filter config (in the JSON format) to
+// the plugin config, fields are referred by dot separated paths like
+// "token_bucket.max_tokens".
+type FieldMapping struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// LoadFilterPluginMappings loads the filter plugin mappings (a JSON array)
+// from the file and validates them.
+func LoadFilterPluginMappings(filename string) ([]*FilterPluginMapping, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var mappings []*FilterPluginMapping
+	if err := json.Unmarshal(data, &mappings); err != nil {
+		return nil, err
+	}
+	if err := validateFilterPluginMappings(mappings); err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+func validateFilterPluginMappings(mappings []*FilterPluginMapping) error {
+	typeUrls := make(map[string]struct{}, len(mappings))
+	for i, m := range mappings {
+		if m == nil || m.TypeUrl == "" {
+			return fmt.Errorf("mapping #%d: type_url is required", i)
+		}
+		if _, ok := typeUrls[m.TypeUrl]; ok {
+			return fmt.Errorf("mapping #%d: duplicated type_url %s", i, m.TypeUrl)
+		}
+		typeUrls[m.TypeUrl] = struct{}{}
+		if !_pluginNameRegex.MatchString(m.Plugin) {
+			return fmt.Errorf("mapping #%d: invalid plugin name \"%s\"", i, m.Plugin)
+		}
+		if _, ok := _typedPlugins[m.Plugin]; ok {
+			return fmt.Errorf("mapping #%d: plugin %s is translated natively", i, m.Plugin)
+		}
+		targets := make(map[string]struct{}, len(m.Fields))
+		for j, f := range m.Fields {
+			if !isValidFieldPath(f.From) || !isValidFieldPath(f.To) {
+				return fmt.Errorf("mapping #%d: field #%d: invalid path", i, j)
+			}
+			if _, ok := targets[f.To]; ok {
+				return fmt.Errorf("mapping #%d: field #%d: duplicated target %s", i, j, f.To)
+			}
+			targets[f.To] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func isValidFieldPath(path string) bool {
+	if path == "" {
+		return false
+	}
+	for _, seg := range strings.Split(path, ".") {
+		if seg == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// getHTTPFilterPlugin returns the APISIX plugin name of the HTTP filter
+// with the type url, mappings take precedence over the native ones.
+func (adaptor *adaptor) getHTTPFilterPlugin(typeUrl string) (string, bool) {
+	if m, ok := adaptor.filterPluginMappings[typeUrl]; ok {
+		return m.Plugin, true
+	}
+	plugin, ok := _httpFilterPlugins[typeUrl]
+	return plugin, ok
+}
+
+// translateMappedHTTPFilter translates the HTTP filter config to the plugin
+// config (in JSON) according to the mapping, fields which don't exist in
+// the filter config are skipped.
+func translateMappedHTTPFilter(m *FilterPluginMapping, config *anypb.Any) (string, error) {
+	filter, err := getHTTPFilterConfigFields(config)
+	if err != nil {
+		return "", err
+	}
+	// Clone the static config so it's not shared by translations.
+	var conf map[string]interface{}
+	data, err := json.Marshal(m.Config)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(data, &conf); err != nil {
+		return "", err
+	}
+	if conf == nil {
+		conf = make(map[string]interface{})
+	}
+	for _, f := range m.Fields {
+		value, ok := getFieldByPath(filter, f.From)
+		if !ok {
+			continue
+		}
+		setFieldByPath(conf, f.To, value)
+	}
+	data, err = json.Marshal(conf)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// getHTTPFilterConfigFields decodes the HTTP filter config to the JSON
+// object, configs in TypedStruct are decoded from the struct directly,
+// others are decoded by protojson, which requires the type to be known.
+func getHTTPFilterConfigFields(config *anypb.Any) (map[string]interface{}, error) {
+	if config == nil {
+		return map[string]interface{}{}, nil
+	}
+	if isTypedStruct(config) {
+		_, value, err := unmarshalTypedStruct(config)
+		if err != nil {
+			return nil, err
+		}
+		return value.AsMap(), nil
+	}
+	msg, err := config.UnmarshalNew()
+	if err != nil {
+		return nil, err
+	}
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// unmarshalHTTPFilterConfig decodes the HTTP filter config to the typed
+// message, configs in TypedStruct are converted through the JSON format.
+// Fields unknown to the message are ignored.
+func unmarshalHTTPFilterConfig(config *anypb.Any, msg proto.Message) error {
+	if !isTypedStruct(config) {
+		return anypb.UnmarshalTo(config, msg, proto.UnmarshalOptions{DiscardUnknown: true})
+	}
+	_, value, err := unmarshalTypedStruct(config)
+	if err != nil {
+		return err
+	}
+	data, err := protojson.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, msg)
+}
+
+func isTypedStruct(config *anypb.Any) bool {
+	return config.GetTypeUrl() == _udpaTypedStructTypeUrl || config.GetTypeUrl() == _xdsTypedStructTypeUrl
+}
+
+// unmarshalTypedStruct returns the type url and the value inside the
+// TypedStruct, both the udpa and the xds ones are accepted.
+func unmarshalTypedStruct(config *anypb.Any) (string, *structpb.Struct, error) {
+	if config.GetTypeUrl() == _udpaTypedStructTypeUrl {
+		var ts udpatypev1.TypedStruct
+		if err := anypb.UnmarshalTo(config, &ts, proto.UnmarshalOptions{}); err != nil {
+			return "", nil, err
+		}
+		return ts.GetTypeUrl(), ts.GetValue(), nil
+	}
+	var ts xdstypev3.TypedStruct
+	if err := anypb.UnmarshalTo(config, &ts, proto.UnmarshalOptions{}); err != nil {
+		return "", nil, err
+	}
+	return ts.GetTypeUrl(), ts.GetValue(), nil
+}
+
+// getTypedStructTypeUrl returns the type url inside the TypedStruct.
+func getTypedStructTypeUrl(config *anypb.Any) string {
+	typeUrl, _, _ := unmarshalTypedStruct(config)
+	return typeUrl
+}
+
+func getFieldByPath(obj map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = obj
+	for _, seg := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[seg]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func setFieldByPath(obj map[string]interface{}, path string, value interface{}) {
+	segs := strings.Split(path, ".")
+	for _, seg := range segs[:len(segs)-1] {
+		next, ok := obj[seg].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			obj[seg] = next
+		}
+		obj = next
+	}
+	obj[segs[len(segs)-1]] = value
+}
@@ -0,0 +1,173 @@
+package v3
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	udpatypev1 "github.com/cncf/xds/go/udpa/type/v1"
+	bufferv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/buffer/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func newTypedStruct(t *testing.T, typeUrl string, value map[string]interface{}) *anypb.Any {
+	s, err := structpb.NewStruct(value)
+	assert.Nil(t, err)
+	config, err := anypb.New(&udpatypev1.TypedStruct{
+		TypeUrl: typeUrl,
+		Value:   s,
+	})
+	assert.Nil(t, err)
+	return config
+}
+
+func TestLoadFilterPluginMappings(t *testing.T) {
+	f, err := ioutil.TempFile("", "mappings-*.json")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`[
+  {
+    "type_url": "type.googleapis.com/envoy.extensions.filters.http.buffer.v3.Buffer",
+    "plugin": "client-control",
+    "fields": [
+      {"from": "max_request_bytes", "to": "max_body_size"}
+    ]
+  }
+]`)
+	assert.Nil(t, err)
+	assert.Nil(t, f.Close())
+
+	mappings, err := LoadFilterPluginMappings(f.Name())
+	assert.Nil(t, err)
+	assert.Len(t, mappings, 1)
+	assert.Equal(t, mappings[0].Plugin, "client-control")
+	assert.Equal(t, mappings[0].Fields, []FieldMapping{
+		{From: "max_request_bytes", To: "max_body_size"},
+	})
+
+	_, err = LoadFilterPluginMappings(f.Name() + ".404")
+	assert.NotNil(t, err)
+
+	bad := [][]*FilterPluginMapping{
+		{{Plugin: "echo"}},
+		{{TypeUrl: "a", Plugin: "echo"}, {TypeUrl: "a", Plugin: "echo"}},
+		{{TypeUrl: "a", Plugin: "Bad Name"}},
+		{{TypeUrl: "a", Plugin: "limit-conn"}},
+		{{TypeUrl: "a", Plugin: "cors"}},
+		{{TypeUrl: "a", Plugin: "echo", Fields: []FieldMapping{{From: "a..b", To: "c"}}}},
+		{{TypeUrl: "a", Plugin: "echo", Fields: []FieldMapping{{From: "a", To: "c"}, {From: "b", To: "c"}}}},
+	}
+	for _, mappings := range bad {
+		assert.NotNil(t, validateFilterPluginMappings(mappings))
+	}
+}
+
+func TestTranslateMappedHTTPFilter(t *testing.T) {
+	m := &FilterPluginMapping{
+		TypeUrl: "type.googleapis.com/envoy.extensions.filters.http.buffer.v3.Buffer",
+		Plugin:  "client-control",
+		Config: map[string]interface{}{
+			"static": true,
+		},
+		Fields: []FieldMapping{
+			{From: "max_request_bytes", To: "max_body_size"},
+			{From: "not_exist", To: "ignored"},
+		},
+	}
+	config, err := anypb.New(&bufferv3.Buffer{
+		MaxRequestBytes: &wrappers.UInt32Value{Value: 1024},
+	})
+	assert.Nil(t, err)
+	conf, err := translateMappedHTTPFilter(m, config)
+	assert.Nil(t, err)
+	assert.JSONEq(t, conf, `{"static":true,"max_body_size":1024}`)
+	// The static config is not modified.
+	assert.Equal(t, m.Config, map[string]interface{}{"static": true})
+
+	m = &FilterPluginMapping{
+		TypeUrl: "type.googleapis.com/envoy.extensions.filters.http.wasm.v3.Wasm",
+		Plugin:  "echo",
+		Fields: []FieldMapping{
+			{From: "config.name", To: "body"},
+			{From: "config.vm_config.runtime", To: "headers.X-Runtime"},
+		},
+	}
+	config = newTypedStruct(t, m.TypeUrl, map[string]interface{}{
+		"config": map[string]interface{}{
+			"name": "hello",
+			"vm_config": map[string]interface{}{
+				"runtime": "envoy.wasm.runtime.v8",
+			},
+		},
+	})
+	conf, err = translateMappedHTTPFilter(m, config)
+	assert.Nil(t, err)
+	assert.JSONEq(t, conf, `{"body":"hello","headers":{"X-Runtime":"envoy.wasm.runtime.v8"}}`)
+
+	// The type is unknown and it's not in TypedStruct.
+	_, err = translateMappedHTTPFilter(m, &anypb.Any{TypeUrl: "type.googleapis.com/example.Unknown"})
+	assert.NotNil(t, err)
+}
+
+func TestPatchRoutesWithMappedHTTPFilters(t *testing.T) {
+	wasm := "type.googleapis.com/envoy.extensions.filters.http.wasm.v3.Wasm"
+	a := &adaptor{
+		logger: log.DefaultLogger,
+		filterPluginMappings: map[string]*FilterPluginMapping{
+			wasm: {
+				TypeUrl: wasm,
+				Plugin:  "echo",
+				Fields: []FieldMapping{
+					{From: "body", To: "body"},
+				},
+			},
+		},
+	}
+	filters := []*hcmv3.HttpFilter{
+		{
+			Name: "envoy.filters.http.wasm",
+			ConfigType: &hcmv3.HttpFilter_TypedConfig{
+				TypedConfig: newTypedStruct(t, wasm, map[string]interface{}{
+					"body": "hello",
+				}),
+			},
+		},
+		newTypedHTTPFilter("type.googleapis.com/envoy.extensions.filters.http.fault.v3.HTTPFault"),
+		{
+			Name: "envoy.filters.http.router",
+		},
+	}
+	assert.Equal(t, getHTTPFilterTypeUrl(filters[0]), wasm)
+	assert.Equal(t, a.getHTTPFilterPluginPriorities(filters), map[string]int32{
+		"echo":            20000,
+		"fault-injection": 19900,
+	})
+
+	routes := []*apisix.Route{
+		{
+			Id: "1",
+			Plugins: &apisix.Plugins{
+				FaultInjection: &apisix.FaultInjection{
+					Abort: &apisix.FaultInjection_Abort{
+						HttpStatus: 503,
+					},
+				},
+			},
+		},
+	}
+	a.patchRoutesWithHTTPFilters(routes, filters, nil)
+	data, err := json.Marshal(routes[0].Plugins)
+	assert.Nil(t, err)
+	assert.JSONEq(t, string(data), `{
+  "fault-injection": {"abort": {"http_status": 503}, "_meta": {"priority": 19900}},
+  "echo": {"body": "hello", "_meta": {"priority": 20000}}
+}`)
+}
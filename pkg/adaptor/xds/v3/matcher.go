@@ -0,0 +1,301 @@
+package v3
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	xdsmatcherv3 "github.com/cncf/xds/go/xds/type/matcher/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/set"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+const (
+	_headerMatchInputTypeUrl = "type.googleapis.com/envoy.type.matcher.v3.HttpRequestHeaderMatchInput"
+	_routeTypeUrl            = "type.googleapis.com/envoy.config.route.v3.Route"
+
+	// _matcherRouteName is the name prefix of the routes without names
+	// in the matcher.
+	_matcherRouteName = "matcher"
+)
+
+// errUnsupportedMatcher means the matcher (or a part of it) cannot be
+// translated.
+var errUnsupportedMatcher = errors.New("unsupported matcher")
+
+// translateVirtualHostMatcher translates the matcher (the unified matcher API)
+// of the VirtualHost to APISIX routes. Only the matcher trees and matcher lists
+// on request headers (":path" included) with the Route actions are supported.
+// The matcher is evaluated in order by Envoy, so routes get descending
+// priorities in the order they're collected, routes from the on_no_match are
+// collected after the others in the same matcher.
+func (adaptor *adaptor) translateVirtualHostMatcher(prefix string, vhost *routev3.VirtualHost, opts *TranslateOptions, disabledFilters map[string]set.StringSet) ([]*apisix.Route, error) {
+	if vhost.GetMatcher() == nil {
+		return nil, nil
+	}
+	var collected []*routev3.Route
+	if err := adaptor.collectMatcherRoutes(vhost.GetMatcher(), &routev3.RouteMatch{}, _matcherRouteName, &collected); err != nil {
+		return nil, err
+	}
+
+	var routes []*apisix.Route
+	priority := int32(_defaultRoutePriority)
+	for _, route := range collected {
+		// Reuse the translation of the classic routes list, one by one
+		// so the priorities can be decided.
+		mvhost := &routev3.VirtualHost{
+			Name:                 vhost.GetName(),
+			Domains:              vhost.GetDomains(),
+			Routes:               []*routev3.Route{route},
+			TypedPerFilterConfig: vhost.GetTypedPerFilterConfig(),
+		}
+		translated, err := adaptor.translateVirtualHost(prefix, mvhost, opts, disabledFilters)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range translated {
+			// The "allow_any" route keeps the lowest priority.
+			if r.Priority == _defaultRoutePriority {
+				r.Priority = priority
+			}
+		}
+		// Priorities stay above the lowest one of the "allow_any" route.
+		if priority > 1 {
+			priority--
+		}
+		routes = append(routes, translated...)
+	}
+	return routes, nil
+}
+
+// collectMatcherRoutes collects the routes of the matcher in the order they're
+// evaluated, name is the position of the matcher, which is used to name the
+// routes without names.
+func (adaptor *adaptor) collectMatcherRoutes(m *xdsmatcherv3.Matcher, match *routev3.RouteMatch, name string, routes *[]*routev3.Route) error {
+	switch {
+	case m.GetMatcherList() != nil:
+		for i, fm := range m.GetMatcherList().GetMatchers() {
+			pm, err := adaptor.translatePredicate(fm.GetPredicate(), match)
+			if err != nil {
+				if err == errUnsupportedMatcher {
+					continue
+				}
+				return err
+			}
+			if err := adaptor.collectOnMatchRoutes(fm.GetOnMatch(), pm, fmt.Sprintf("%s[%d]", name, i), routes); err != nil {
+				return err
+			}
+		}
+	case m.GetMatcherTree() != nil:
+		if err := adaptor.collectMatcherTreeRoutes(m.GetMatcherTree(), match, name, routes); err != nil {
+			return err
+		}
+	}
+	if m.GetOnNoMatch() != nil {
+		return adaptor.collectOnMatchRoutes(m.GetOnNoMatch(), match, name+".on_no_match", routes)
+	}
+	return nil
+}
+
+func (adaptor *adaptor) collectMatcherTreeRoutes(tree *xdsmatcherv3.Matcher_MatcherTree, match *routev3.RouteMatch, name string, routes *[]*routev3.Route) error {
+	header, err := getHeaderMatchInput(tree.GetInput().GetTypedConfig())
+	if err != nil {
+		if err == errUnsupportedMatcher {
+			adaptor.logger.Warnw("ignore matcher tree with unsupported input")
+			return nil
+		}
+		return err
+	}
+	kind := "exact"
+	mm := tree.GetExactMatchMap()
+	if mm == nil {
+		kind = "prefix"
+		mm = tree.GetPrefixMatchMap()
+	}
+	if mm == nil {
+		adaptor.logger.Warnw("ignore matcher tree with custom match",
+			zap.String("header", header),
+		)
+		return nil
+	}
+	// Keep the generated routes in a stable order.
+	keys := make([]string, 0, len(mm.GetMap()))
+	for key := range mm.GetMap() {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		em, err := adaptor.patchMatch(match, header, kind, key)
+		if err != nil {
+			if err == errUnsupportedMatcher {
+				continue
+			}
+			return err
+		}
+		if err := adaptor.collectOnMatchRoutes(mm.GetMap()[key], em, fmt.Sprintf("%s[%q]", name, key), routes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (adaptor *adaptor) collectOnMatchRoutes(om *xdsmatcherv3.Matcher_OnMatch, match *routev3.RouteMatch, name string, routes *[]*routev3.Route) error {
+	if om.GetMatcher() != nil {
+		return adaptor.collectMatcherRoutes(om.GetMatcher(), match, name, routes)
+	}
+	if om.GetAction() == nil {
+		return nil
+	}
+	config := om.GetAction().GetTypedConfig()
+	if config.GetTypeUrl() != _routeTypeUrl {
+		adaptor.logger.Warnw("ignore matcher with unsupported action",
+			zap.String("type_url", config.GetTypeUrl()),
+		)
+		return nil
+	}
+	var route routev3.Route
+	if err := anypb.UnmarshalTo(config, &route, proto.UnmarshalOptions{}); err != nil {
+		return err
+	}
+	// Routes without names are named by their positions in the matcher,
+	// or they'll get the same APISIX route id.
+	if route.Name == "" {
+		route.Name = name
+	}
+	// The match of the route action is not used by the matcher API.
+	route.Match = proto.Clone(match).(*routev3.RouteMatch)
+	if route.Match.PathSpecifier == nil {
+		route.Match.PathSpecifier = &routev3.RouteMatch_Prefix{
+			Prefix: "/",
+		}
+	}
+	*routes = append(*routes, &route)
+	return nil
+}
+
+// translatePredicate patches the predicate to a copy of match, only the
+// single predicates and the and_matcher of them are supported.
+func (adaptor *adaptor) translatePredicate(pred *xdsmatcherv3.Matcher_MatcherList_Predicate, match *routev3.RouteMatch) (*routev3.RouteMatch, error) {
+	if single := pred.GetSinglePredicate(); single != nil {
+		header, err := getHeaderMatchInput(single.GetInput().GetTypedConfig())
+		if err != nil {
+			if err == errUnsupportedMatcher {
+				adaptor.logger.Warnw("ignore predicate with unsupported input")
+			}
+			return nil, err
+		}
+		if single.GetValueMatch() == nil {
+			adaptor.logger.Warnw("ignore predicate with custom match",
+				zap.String("header", header),
+			)
+			return nil, errUnsupportedMatcher
+		}
+		kind, value, err := getStringMatcher(single.GetValueMatch())
+		if err != nil {
+			adaptor.logger.Warnw("ignore predicate with unsupported string matcher",
+				zap.String("header", header),
+			)
+			return nil, err
+		}
+		return adaptor.patchMatch(match, header, kind, value)
+	}
+	if and := pred.GetAndMatcher(); and != nil {
+		var err error
+		for _, p := range and.GetPredicate() {
+			match, err = adaptor.translatePredicate(p, match)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return match, nil
+	}
+	adaptor.logger.Warnw("ignore unsupported predicate, only single predicates and and_matcher are supported")
+	return nil, errUnsupportedMatcher
+}
+
+// patchMatch returns a copy of match with the header constraint, the ":path"
+// header is translated to the path specifier.
+func (adaptor *adaptor) patchMatch(match *routev3.RouteMatch, header, kind, value string) (*routev3.RouteMatch, error) {
+	m := proto.Clone(match).(*routev3.RouteMatch)
+	if header == ":path" {
+		if m.PathSpecifier != nil {
+			adaptor.logger.Warnw("ignore matcher with multiple :path constraints",
+				zap.String("kind", kind),
+				zap.String("value", value),
+			)
+			return nil, errUnsupportedMatcher
+		}
+		switch kind {
+		case "exact":
+			m.PathSpecifier = &routev3.RouteMatch_Path{Path: value}
+		case "prefix":
+			m.PathSpecifier = &routev3.RouteMatch_Prefix{Prefix: value}
+		default:
+			adaptor.logger.Warnw("ignore matcher with unsupported :path match",
+				zap.String("kind", kind),
+				zap.String("value", value),
+			)
+			return nil, errUnsupportedMatcher
+		}
+		return m, nil
+	}
+	hm := &routev3.HeaderMatcher{Name: header}
+	switch kind {
+	case "exact":
+		hm.HeaderMatchSpecifier = &routev3.HeaderMatcher_ExactMatch{ExactMatch: value}
+	case "prefix":
+		hm.HeaderMatchSpecifier = &routev3.HeaderMatcher_PrefixMatch{PrefixMatch: value}
+	case "suffix":
+		hm.HeaderMatchSpecifier = &routev3.HeaderMatcher_SuffixMatch{SuffixMatch: value}
+	case "contains":
+		hm.HeaderMatchSpecifier = &routev3.HeaderMatcher_ContainsMatch{ContainsMatch: value}
+	case "safe_regex":
+		hm.HeaderMatchSpecifier = &routev3.HeaderMatcher_SafeRegexMatch{
+			SafeRegexMatch: &matcherv3.RegexMatcher{Regex: value},
+		}
+	}
+	m.Headers = append(m.Headers, hm)
+	return m, nil
+}
+
+// getHeaderMatchInput returns the header name of the HttpRequestHeaderMatchInput.
+func getHeaderMatchInput(config *anypb.Any) (string, error) {
+	if config.GetTypeUrl() != _headerMatchInputTypeUrl {
+		return "", errUnsupportedMatcher
+	}
+	var input matcherv3.HttpRequestHeaderMatchInput
+	if err := anypb.UnmarshalTo(config, &input, proto.UnmarshalOptions{}); err != nil {
+		return "", err
+	}
+	if input.GetHeaderName() == "" {
+		return "", errUnsupportedMatcher
+	}
+	return input.GetHeaderName(), nil
+}
+
+// getStringMatcher returns the kind and the value of the StringMatcher,
+// case insensitive and custom matchers are not supported.
+func getStringMatcher(sm *xdsmatcherv3.StringMatcher) (string, string, error) {
+	if sm.GetIgnoreCase() {
+		return "", "", errUnsupportedMatcher
+	}
+	switch pattern := sm.GetMatchPattern().(type) {
+	case *xdsmatcherv3.StringMatcher_Exact:
+		return "exact", pattern.Exact, nil
+	case *xdsmatcherv3.StringMatcher_Prefix:
+		return "prefix", pattern.Prefix, nil
+	case *xdsmatcherv3.StringMatcher_Suffix:
+		return "suffix", pattern.Suffix, nil
+	case *xdsmatcherv3.StringMatcher_Contains:
+		return "contains", pattern.Contains, nil
+	case *xdsmatcherv3.StringMatcher_SafeRegex:
+		return "safe_regex", pattern.SafeRegex.GetRegex(), nil
+	}
+	return "", "", errUnsupportedMatcher
+}
@@ -0,0 +1,223 @@
+package v3
+
+import (
+	"testing"
+
+	xdscorev3 "github.com/cncf/xds/go/xds/core/v3"
+	xdsmatcherv3 "github.com/cncf/xds/go/xds/type/matcher/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func newHeaderMatchInput(t *testing.T, header string) *xdscorev3.TypedExtensionConfig {
+	config, err := anypb.New(&matcherv3.HttpRequestHeaderMatchInput{
+		HeaderName: header,
+	})
+	assert.Nil(t, err)
+	return &xdscorev3.TypedExtensionConfig{
+		Name:        "header",
+		TypedConfig: config,
+	}
+}
+
+func newRouteOnMatch(t *testing.T, name, cluster string) *xdsmatcherv3.Matcher_OnMatch {
+	config, err := anypb.New(&routev3.Route{
+		Name: name,
+		Action: &routev3.Route_Route{
+			Route: &routev3.RouteAction{
+				ClusterSpecifier: &routev3.RouteAction_Cluster{
+					Cluster: cluster,
+				},
+			},
+		},
+	})
+	assert.Nil(t, err)
+	return &xdsmatcherv3.Matcher_OnMatch{
+		OnMatch: &xdsmatcherv3.Matcher_OnMatch_Action{
+			Action: &xdscorev3.TypedExtensionConfig{
+				Name:        "route",
+				TypedConfig: config,
+			},
+		},
+	}
+}
+
+func newHeaderFieldMatcher(t *testing.T, header, value string, om *xdsmatcherv3.Matcher_OnMatch) *xdsmatcherv3.Matcher_MatcherList_FieldMatcher {
+	return &xdsmatcherv3.Matcher_MatcherList_FieldMatcher{
+		Predicate: &xdsmatcherv3.Matcher_MatcherList_Predicate{
+			MatchType: &xdsmatcherv3.Matcher_MatcherList_Predicate_SinglePredicate_{
+				SinglePredicate: &xdsmatcherv3.Matcher_MatcherList_Predicate_SinglePredicate{
+					Input: newHeaderMatchInput(t, header),
+					Matcher: &xdsmatcherv3.Matcher_MatcherList_Predicate_SinglePredicate_ValueMatch{
+						ValueMatch: &xdsmatcherv3.StringMatcher{
+							MatchPattern: &xdsmatcherv3.StringMatcher_Exact{
+								Exact: value,
+							},
+						},
+					},
+				},
+			},
+		},
+		OnMatch: om,
+	}
+}
+
+func TestTranslateVirtualHostMatcher(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	// matcher_tree:
+	//   input: :path
+	//   exact_match_map:
+	//     /get: route "get" -> httpbin
+	// on_no_match:
+	//   matcher_list:
+	//   - predicate: x-canary == "true"
+	//     on_match: route "canary" -> canary
+	//   - predicate: or_matcher (unsupported)
+	//     on_match: route "ignored" -> httpbin
+	canary := newHeaderFieldMatcher(t, "x-canary", "true", newRouteOnMatch(t, "canary", "canary"))
+	ignored := &xdsmatcherv3.Matcher_MatcherList_FieldMatcher{
+		Predicate: &xdsmatcherv3.Matcher_MatcherList_Predicate{
+			MatchType: &xdsmatcherv3.Matcher_MatcherList_Predicate_OrMatcher{
+				OrMatcher: &xdsmatcherv3.Matcher_MatcherList_Predicate_PredicateList{},
+			},
+		},
+		OnMatch: newRouteOnMatch(t, "ignored", "httpbin"),
+	}
+	matcher := &xdsmatcherv3.Matcher{
+		MatcherType: &xdsmatcherv3.Matcher_MatcherTree_{
+			MatcherTree: &xdsmatcherv3.Matcher_MatcherTree{
+				Input: newHeaderMatchInput(t, ":path"),
+				TreeType: &xdsmatcherv3.Matcher_MatcherTree_ExactMatchMap{
+					ExactMatchMap: &xdsmatcherv3.Matcher_MatcherTree_MatchMap{
+						Map: map[string]*xdsmatcherv3.Matcher_OnMatch{
+							"/get": newRouteOnMatch(t, "get", "httpbin"),
+						},
+					},
+				},
+			},
+		},
+		OnNoMatch: &xdsmatcherv3.Matcher_OnMatch{
+			OnMatch: &xdsmatcherv3.Matcher_OnMatch_Matcher{
+				Matcher: &xdsmatcherv3.Matcher{
+					MatcherType: &xdsmatcherv3.Matcher_MatcherList_{
+						MatcherList: &xdsmatcherv3.Matcher_MatcherList{
+							Matchers: []*xdsmatcherv3.Matcher_MatcherList_FieldMatcher{canary, ignored},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	vhost := &routev3.VirtualHost{
+		Name:    "vhost1",
+		Domains: []string{"*"},
+		Matcher: matcher,
+	}
+	rc := &routev3.RouteConfiguration{
+		Name:         "rc1",
+		VirtualHosts: []*routev3.VirtualHost{vhost},
+	}
+	routes, err := a.TranslateRouteConfiguration(rc, nil)
+	assert.Nil(t, err)
+	assert.Len(t, routes, 2)
+
+	assert.Equal(t, routes[0].Name, "get#vhost1#rc1")
+	assert.Equal(t, routes[0].Uris, []string{"/get"})
+	assert.Equal(t, routes[0].UpstreamId, id.GenID("httpbin"))
+	assert.Equal(t, routes[0].Priority, int32(_defaultRoutePriority))
+
+	assert.Equal(t, routes[1].Name, "canary#vhost1#rc1")
+	assert.Equal(t, routes[1].Uris, []string{"/*"})
+	assert.Equal(t, routes[1].UpstreamId, id.GenID("canary"))
+	assert.Equal(t, routes[1].Vars, []*apisix.Var{
+		{Vars: []string{"http_x_canary", "~~", "^true$"}},
+	})
+	// Routes from on_no_match have lower priorities.
+	assert.Equal(t, routes[1].Priority, int32(_defaultRoutePriority-1))
+}
+
+func TestTranslateVirtualHostMatcherWithUnnamedRoutes(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	// matcher_list:
+	// - predicate: x-version == "v1"
+	//   on_match: route -> v1
+	// - predicate: x-version == "v2"
+	//   on_match:
+	//     matcher_tree:
+	//       input: :path
+	//       prefix_match_map:
+	//         /api: route -> v2
+	// on_no_match: route -> httpbin
+	matcher := &xdsmatcherv3.Matcher{
+		MatcherType: &xdsmatcherv3.Matcher_MatcherList_{
+			MatcherList: &xdsmatcherv3.Matcher_MatcherList{
+				Matchers: []*xdsmatcherv3.Matcher_MatcherList_FieldMatcher{
+					newHeaderFieldMatcher(t, "x-version", "v1", newRouteOnMatch(t, "", "v1")),
+					newHeaderFieldMatcher(t, "x-version", "v2", &xdsmatcherv3.Matcher_OnMatch{
+						OnMatch: &xdsmatcherv3.Matcher_OnMatch_Matcher{
+							Matcher: &xdsmatcherv3.Matcher{
+								MatcherType: &xdsmatcherv3.Matcher_MatcherTree_{
+									MatcherTree: &xdsmatcherv3.Matcher_MatcherTree{
+										Input: newHeaderMatchInput(t, ":path"),
+										TreeType: &xdsmatcherv3.Matcher_MatcherTree_PrefixMatchMap{
+											PrefixMatchMap: &xdsmatcherv3.Matcher_MatcherTree_MatchMap{
+												Map: map[string]*xdsmatcherv3.Matcher_OnMatch{
+													"/api": newRouteOnMatch(t, "", "v2"),
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					}),
+				},
+			},
+		},
+		OnNoMatch: newRouteOnMatch(t, "", "httpbin"),
+	}
+	rc := &routev3.RouteConfiguration{
+		Name: "rc1",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name:    "vhost1",
+				Domains: []string{"*"},
+				Matcher: matcher,
+			},
+		},
+	}
+	routes, err := a.TranslateRouteConfiguration(rc, nil)
+	assert.Nil(t, err)
+	assert.Len(t, routes, 3)
+
+	// Routes are named by their positions, so they don't overwrite each
+	// other, and the priorities follow the matching order.
+	assert.Equal(t, routes[0].Name, "matcher[0]#vhost1#rc1")
+	assert.Equal(t, routes[0].UpstreamId, id.GenID("v1"))
+	assert.Equal(t, routes[0].Uris, []string{"/*"})
+	assert.Equal(t, routes[0].Priority, int32(_defaultRoutePriority))
+
+	assert.Equal(t, routes[1].Name, `matcher[1]["/api"]#vhost1#rc1`)
+	assert.Equal(t, routes[1].UpstreamId, id.GenID("v2"))
+	assert.Equal(t, routes[1].Uris, []string{"/api*"})
+	assert.Equal(t, routes[1].Priority, int32(_defaultRoutePriority-1))
+
+	assert.Equal(t, routes[2].Name, "matcher.on_no_match#vhost1#rc1")
+	assert.Equal(t, routes[2].UpstreamId, id.GenID("httpbin"))
+	assert.Equal(t, routes[2].Priority, int32(_defaultRoutePriority-2))
+
+	ids := map[string]struct{}{}
+	for _, r := range routes {
+		ids[r.Id] = struct{}{}
+	}
+	assert.Len(t, ids, 3)
+}
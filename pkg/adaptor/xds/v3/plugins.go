@@ -0,0 +1,626 @@
+package v3
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	adaptiveconcurrencyv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/adaptive_concurrency/v3"
+	extauthzv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
+	faultv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
+	jwtauthnv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
+	localratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
+	ratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/set"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+const (
+	// The priority of the plugin translated from the first HTTP filter,
+	// it's higher than the default priorities of most APISIX plugins.
+	_httpFilterPluginPriorityBase = 20000
+	// The priority gap between plugins translated from adjacent HTTP filters.
+	_httpFilterPluginPriorityStep = 100
+
+	_adaptiveConcurrencyTypeUrl = "type.googleapis.com/envoy.extensions.filters.http.adaptive_concurrency.v3.AdaptiveConcurrency"
+	_faultTypeUrl               = "type.googleapis.com/envoy.extensions.filters.http.fault.v3.HTTPFault"
+	_rateLimitTypeUrl           = "type.googleapis.com/envoy.extensions.filters.http.ratelimit.v3.RateLimit"
+	_localRateLimitTypeUrl      = "type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit"
+	_filterConfigTypeUrl        = "type.googleapis.com/envoy.config.route.v3.FilterConfig"
+	_extAuthzPerRouteTypeUrl    = "type.googleapis.com/envoy.extensions.filters.http.ext_authz.v3.ExtAuthzPerRoute"
+	_jwtAuthnPerRouteTypeUrl    = "type.googleapis.com/envoy.extensions.filters.http.jwt_authn.v3.PerRouteConfig"
+	// The default max_concurrency_limit of the adaptive_concurrency filter.
+	_defaultMaxConcurrencyLimit = 1000
+	// The limit-conn plugin requires the processing latency of a typical
+	// request, it's only used to delay the burst requests, since the burst
+	// is always zero, this value is not important.
+	_defaultConnDelay = 0.1
+	// The default status code of the local_ratelimit filter.
+	_defaultLocalRateLimitStatus = 429
+)
+
+var (
+	// _httpFilterPlugins maps the type url of Envoy HTTP filters to the
+	// equivalent APISIX plugin names.
+	_httpFilterPlugins = map[string]string{
+		"type.googleapis.com/envoy.extensions.filters.http.fault.v3.HTTPFault":                          "fault-injection",
+		"type.googleapis.com/envoy.extensions.filters.http.cors.v3.Cors":                                "cors",
+		"type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit":           "limit-req",
+		"type.googleapis.com/envoy.extensions.filters.http.ext_authz.v3.ExtAuthz":                       "forward-auth",
+		"type.googleapis.com/envoy.extensions.filters.http.jwt_authn.v3.JwtAuthentication":              "jwt-auth",
+		"type.googleapis.com/envoy.extensions.filters.http.adaptive_concurrency.v3.AdaptiveConcurrency": "limit-conn",
+		"type.googleapis.com/envoy.extensions.filters.http.api_key_auth.v3.ApiKeyAuth":                  "key-auth",
+	}
+)
+
+// getHTTPFilterPluginPriorities calculates the plugin priorities according to
+// the HTTP filter order, the plugin translated from the first filter has the
+// highest priority, so that the plugins run in the same order as the filters.
+// Filters which cannot be translated to APISIX plugins are skipped.
+func (adaptor *adaptor) getHTTPFilterPluginPriorities(filters []*hcmv3.HttpFilter) map[string]int32 {
+	priorities := make(map[string]int32)
+	for _, f := range filters {
+		plugin, ok := adaptor.getHTTPFilterPlugin(getHTTPFilterTypeUrl(f))
+		if !ok {
+			continue
+		}
+		if _, ok := priorities[plugin]; ok {
+			continue
+		}
+		priorities[plugin] = int32(_httpFilterPluginPriorityBase - len(priorities)*_httpFilterPluginPriorityStep)
+	}
+	return priorities
+}
+
+// getHTTPFilterTypeUrl returns the type url of the HTTP filter, filters
+// without typed config are identified by their names, and the type url
+// inside is used for configs in TypedStruct.
+func getHTTPFilterTypeUrl(f *hcmv3.HttpFilter) string {
+	if f.GetTypedConfig() != nil {
+		if isTypedStruct(f.GetTypedConfig()) {
+			return getTypedStructTypeUrl(f.GetTypedConfig())
+		}
+		return f.GetTypedConfig().GetTypeUrl()
+	}
+	return f.GetName()
+}
+
+// patchRoutesWithHTTPFilters translates the HTTP filters to plugins and
+// patches them to the routes, plugins won't be patched to routes which
+// disable the corresponding filters, see getDisabledHTTPFilters.
+func (adaptor *adaptor) patchRoutesWithHTTPFilters(routes []*apisix.Route, filters []*hcmv3.HttpFilter, disabledFilters map[string]set.StringSet) {
+	for _, f := range filters {
+		typeUrl := getHTTPFilterTypeUrl(f)
+		if m, ok := adaptor.filterPluginMappings[typeUrl]; ok {
+			conf, err := translateMappedHTTPFilter(m, f.GetTypedConfig())
+			if err != nil {
+				adaptor.logger.Errorw("failed to translate HTTP filter by the mapping",
+					zap.Error(err),
+					zap.String("plugin", m.Plugin),
+					zap.Any("filter", f),
+				)
+				continue
+			}
+			for _, r := range routes {
+				if _, ok := disabledFilters[r.Id][f.GetName()]; ok {
+					continue
+				}
+				if r.Plugins == nil {
+					r.Plugins = &apisix.Plugins{}
+				}
+				if r.Plugins.Custom == nil {
+					r.Plugins.Custom = make(map[string]string)
+				}
+				r.Plugins.Custom[m.Plugin] = conf
+			}
+			continue
+		}
+		switch typeUrl {
+		case _adaptiveConcurrencyTypeUrl:
+			lc, err := adaptor.translateAdaptiveConcurrency(f.GetTypedConfig())
+			if err != nil {
+				adaptor.logger.Errorw("failed to translate adaptive_concurrency filter",
+					zap.Error(err),
+					zap.Any("filter", f),
+				)
+				continue
+			}
+			for _, r := range routes {
+				if _, ok := disabledFilters[r.Id][f.GetName()]; ok {
+					continue
+				}
+				if r.Plugins == nil {
+					r.Plugins = &apisix.Plugins{}
+				}
+				r.Plugins.LimitConn = proto.Clone(lc).(*apisix.LimitConn)
+			}
+		case _faultTypeUrl:
+			fi, err := adaptor.translateFault(f.GetTypedConfig())
+			if err != nil {
+				adaptor.logger.Errorw("failed to translate fault filter",
+					zap.Error(err),
+					zap.Any("filter", f),
+				)
+				continue
+			}
+			if fi == nil {
+				continue
+			}
+			for _, r := range routes {
+				if _, ok := disabledFilters[r.Id][f.GetName()]; ok {
+					continue
+				}
+				if r.Plugins == nil {
+					r.Plugins = &apisix.Plugins{}
+				}
+				// Routes which override the filter, or respond directly
+				// (by the plugin too) are kept.
+				if r.Plugins.FaultInjection == nil {
+					r.Plugins.FaultInjection = proto.Clone(fi).(*apisix.FaultInjection)
+				}
+			}
+		case _localRateLimitTypeUrl:
+			// The filter level config applies to routes which don't
+			// override it, see patchRouteWithPerFilterConfigs.
+			if adaptor.isHTTPFilterDisabled(f.GetName(), f.GetTypedConfig()) {
+				continue
+			}
+			lr, err := adaptor.translateLocalRateLimit(f.GetTypedConfig())
+			if err != nil {
+				adaptor.logger.Errorw("failed to translate local_ratelimit filter",
+					zap.Error(err),
+					zap.Any("filter", f),
+				)
+				continue
+			}
+			for _, r := range routes {
+				if _, ok := disabledFilters[r.Id][f.GetName()]; ok {
+					continue
+				}
+				if r.Plugins == nil {
+					r.Plugins = &apisix.Plugins{}
+				}
+				if r.Plugins.LimitReq == nil {
+					r.Plugins.LimitReq = proto.Clone(lr).(*apisix.LimitReq)
+				}
+			}
+		case _apiKeyAuthTypeUrl:
+			ka, err := adaptor.translateAPIKeyAuth(f.GetTypedConfig())
+			if err != nil {
+				adaptor.logger.Errorw("failed to translate api_key_auth filter",
+					zap.Error(err),
+					zap.Any("filter", f),
+				)
+				continue
+			}
+			for _, r := range routes {
+				if _, ok := disabledFilters[r.Id][f.GetName()]; ok {
+					continue
+				}
+				if r.Plugins == nil {
+					r.Plugins = &apisix.Plugins{}
+				}
+				r.Plugins.KeyAuth = proto.Clone(ka).(*apisix.KeyAuth)
+			}
+		case _rateLimitTypeUrl:
+			if err := adaptor.checkGlobalRateLimit(f.GetTypedConfig()); err != nil {
+				adaptor.logger.Errorw("failed to parse ratelimit filter",
+					zap.Error(err),
+					zap.Any("filter", f),
+				)
+			}
+		}
+	}
+	patchRoutesWithPluginPriorities(routes, adaptor.getHTTPFilterPluginPriorities(filters))
+}
+
+// getPerFilterConfigs returns the typed_per_filter_config which takes effect
+// on the route, configs of the virtual host are inherited unless the route
+// configures the same filter.
+func getPerFilterConfigs(vhost *routev3.VirtualHost, route *routev3.Route) map[string]*anypb.Any {
+	configs := make(map[string]*anypb.Any, len(vhost.GetTypedPerFilterConfig())+len(route.GetTypedPerFilterConfig()))
+	for name, config := range vhost.GetTypedPerFilterConfig() {
+		configs[name] = config
+	}
+	for name, config := range route.GetTypedPerFilterConfig() {
+		configs[name] = config
+	}
+	return configs
+}
+
+// getDisabledHTTPFilters returns names of the HTTP filters which are disabled
+// by the typed_per_filter_config of the route (or the virtual host if the route
+// doesn't configure the filter).
+func (adaptor *adaptor) getDisabledHTTPFilters(vhost *routev3.VirtualHost, route *routev3.Route) set.StringSet {
+	disabled := set.StringSet{}
+	for name, config := range getPerFilterConfigs(vhost, route) {
+		if adaptor.isHTTPFilterDisabled(name, config) {
+			disabled.Add(name)
+		}
+	}
+	return disabled
+}
+
+// patchRouteWithPerFilterConfigs translates the per filter configs (see
+// getPerFilterConfigs) to plugins, they take precedence over the filter
+// level configs patched by patchRoutesWithHTTPFilters.
+func (adaptor *adaptor) patchRouteWithPerFilterConfigs(r *apisix.Route, configs map[string]*anypb.Any) {
+	for name, config := range configs {
+		switch config.GetTypeUrl() {
+		case _localRateLimitTypeUrl:
+			if adaptor.isHTTPFilterDisabled(name, config) {
+				continue
+			}
+			lr, err := adaptor.translateLocalRateLimit(config)
+			if err != nil {
+				adaptor.logger.Errorw("failed to translate per filter config of local_ratelimit",
+					zap.Error(err),
+					zap.String("route", r.Name),
+					zap.String("filter", name),
+				)
+				continue
+			}
+			if r.Plugins == nil {
+				r.Plugins = &apisix.Plugins{}
+			}
+			r.Plugins.LimitReq = lr
+		case _faultTypeUrl:
+			// The fault-injection plugin is used by the direct responses
+			// already, which take precedence.
+			if r.GetPlugins().GetFaultInjection() != nil {
+				continue
+			}
+			fi, err := adaptor.translateFault(config)
+			if err != nil {
+				adaptor.logger.Errorw("failed to translate per filter config of fault",
+					zap.Error(err),
+					zap.String("route", r.Name),
+					zap.String("filter", name),
+				)
+				continue
+			}
+			if fi == nil {
+				continue
+			}
+			if r.Plugins == nil {
+				r.Plugins = &apisix.Plugins{}
+			}
+			r.Plugins.FaultInjection = fi
+		}
+	}
+}
+
+// isHTTPFilterDisabled checks whether the per filter config disables the filter,
+// it can be the generic FilterConfig or the filter specific per route config.
+func (adaptor *adaptor) isHTTPFilterDisabled(name string, config *anypb.Any) bool {
+	var err error
+	switch config.GetTypeUrl() {
+	case _filterConfigTypeUrl:
+		var fc routev3.FilterConfig
+		if err = anypb.UnmarshalTo(config, &fc, proto.UnmarshalOptions{}); err == nil {
+			return fc.GetDisabled()
+		}
+	case _extAuthzPerRouteTypeUrl:
+		var perRoute extauthzv3.ExtAuthzPerRoute
+		if err = anypb.UnmarshalTo(config, &perRoute, proto.UnmarshalOptions{}); err == nil {
+			return perRoute.GetDisabled()
+		}
+	case _jwtAuthnPerRouteTypeUrl:
+		var perRoute jwtauthnv3.PerRouteConfig
+		if err = anypb.UnmarshalTo(config, &perRoute, proto.UnmarshalOptions{}); err == nil {
+			return perRoute.GetDisabled()
+		}
+	case _localRateLimitTypeUrl:
+		// The local_ratelimit filter is disabled (or in the shadow mode)
+		// unless both filter_enabled and filter_enforced are configured.
+		var lrl localratelimitv3.LocalRateLimit
+		if err = anypb.UnmarshalTo(config, &lrl, proto.UnmarshalOptions{}); err == nil {
+			return getFractionalPercent(lrl.GetFilterEnabled().GetDefaultValue()) == 0 ||
+				getFractionalPercent(lrl.GetFilterEnforced().GetDefaultValue()) == 0
+		}
+	}
+	if err != nil {
+		adaptor.logger.Errorw("failed to parse per filter config",
+			zap.Error(err),
+			zap.String("filter", name),
+			zap.String("type_url", config.GetTypeUrl()),
+		)
+	}
+	return false
+}
+
+// translateAdaptiveConcurrency translates the adaptive_concurrency filter to
+// the limit-conn plugin. The concurrency limit of APISIX is static, so the
+// max_concurrency_limit is used, requests exceeding it will be rejected
+// directly (with 503) just like Envoy.
+func (adaptor *adaptor) translateAdaptiveConcurrency(config *anypb.Any) (*apisix.LimitConn, error) {
+	var ac adaptiveconcurrencyv3.AdaptiveConcurrency
+	if err := anypb.UnmarshalTo(config, &ac, proto.UnmarshalOptions{}); err != nil {
+		return nil, err
+	}
+	conn := uint32(_defaultMaxConcurrencyLimit)
+	gc := ac.GetGradientControllerConfig()
+	if limit := gc.GetConcurrencyLimitParams().GetMaxConcurrencyLimit(); limit != nil && limit.GetValue() > 0 {
+		conn = limit.GetValue()
+	}
+	if gc.GetSampleAggregatePercentile() != nil || gc.GetMinRttCalcParams() != nil ||
+		gc.GetConcurrencyLimitParams().GetConcurrencyUpdateInterval() != nil {
+		adaptor.logger.Warnw("the gradient controller of adaptive_concurrency cannot be replicated, only the max concurrency limit is honored",
+			zap.Any("gradient_controller_config", gc),
+		)
+	}
+	if ac.GetEnabled() != nil {
+		adaptor.logger.Warnw("the runtime feature flag of adaptive_concurrency is not supported, the limit is always enabled",
+			zap.Any("enabled", ac.GetEnabled()),
+		)
+	}
+	return &apisix.LimitConn{
+		Conn:             int32(conn),
+		Burst:            0,
+		DefaultConnDelay: _defaultConnDelay,
+		Key:              "server_addr",
+		KeyType:          "var",
+		RejectedCode:     503,
+	}, nil
+}
+
+// translateLocalRateLimit translates the local_ratelimit filter to the
+// limit-req plugin, the token bucket is approximated by the leaky bucket,
+// the fill rate is the request rate, and the max tokens can be consumed
+// without delay as the burst. The limit is shared by all clients, just like
+// the token bucket of Envoy.
+func (adaptor *adaptor) translateLocalRateLimit(config *anypb.Any) (*apisix.LimitReq, error) {
+	var lrl localratelimitv3.LocalRateLimit
+	if err := anypb.UnmarshalTo(config, &lrl, proto.UnmarshalOptions{}); err != nil {
+		return nil, err
+	}
+	tb := lrl.GetTokenBucket()
+	if tb == nil {
+		return nil, fmt.Errorf("token_bucket is required")
+	}
+	interval := tb.GetFillInterval().AsDuration()
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid fill_interval %s", interval)
+	}
+	tokens := uint32(1)
+	if tb.GetTokensPerFill() != nil {
+		tokens = tb.GetTokensPerFill().GetValue()
+	}
+	if tokens == 0 {
+		return nil, fmt.Errorf("tokens_per_fill should be greater than zero")
+	}
+	if getFractionalPercent(lrl.GetFilterEnabled().GetDefaultValue()) < 1 ||
+		getFractionalPercent(lrl.GetFilterEnforced().GetDefaultValue()) < 1 {
+		adaptor.logger.Warnw("partial enabling of local_ratelimit is not supported, the limit is applied to all requests",
+			zap.Any("filter_enabled", lrl.GetFilterEnabled()),
+			zap.Any("filter_enforced", lrl.GetFilterEnforced()),
+		)
+	}
+	status := int32(_defaultLocalRateLimitStatus)
+	if code := lrl.GetStatus().GetCode(); code != 0 {
+		status = int32(code)
+	}
+	return &apisix.LimitReq{
+		Rate:         float64(tokens) / interval.Seconds(),
+		Burst:        float64(tb.GetMaxTokens()),
+		Key:          "server_addr",
+		KeyType:      "var",
+		RejectedCode: status,
+		Nodelay:      true,
+	}, nil
+}
+
+// translateFault translates the fault filter to the fault-injection plugin,
+// only the fixed delays and the HTTP aborts are supported. Nil is returned
+// if neither the delay nor the abort takes effect.
+func (adaptor *adaptor) translateFault(config *anypb.Any) (*apisix.FaultInjection, error) {
+	var fault faultv3.HTTPFault
+	if err := anypb.UnmarshalTo(config, &fault, proto.UnmarshalOptions{}); err != nil {
+		return nil, err
+	}
+	if len(fault.GetHeaders()) > 0 || fault.GetUpstreamCluster() != "" || len(fault.GetDownstreamNodes()) > 0 {
+		adaptor.logger.Warnw("request matching of fault filter is not supported, faults are injected to all requests",
+			zap.Any("headers", fault.GetHeaders()),
+			zap.String("upstream_cluster", fault.GetUpstreamCluster()),
+			zap.Strings("downstream_nodes", fault.GetDownstreamNodes()),
+		)
+	}
+	if fault.GetResponseRateLimit() != nil {
+		adaptor.logger.Warnw("response_rate_limit of fault filter is not supported",
+			zap.Any("response_rate_limit", fault.GetResponseRateLimit()),
+		)
+	}
+
+	var fi apisix.FaultInjection
+	if delay := fault.GetDelay(); delay != nil {
+		if delay.GetFixedDelay() == nil {
+			adaptor.logger.Warnw("only the fixed delay of fault filter is supported",
+				zap.Any("delay", delay),
+			)
+		} else if d := delay.GetFixedDelay().AsDuration().Seconds(); d > 0 {
+			if percentage := adaptor.getFaultPercentage(delay.GetPercentage()); percentage > 0 {
+				fi.Delay = &apisix.FaultInjection_Delay{
+					Duration:   d,
+					Percentage: percentage,
+				}
+			}
+		}
+	}
+	if abort := fault.GetAbort(); abort != nil {
+		if abort.GetHttpStatus() == 0 {
+			adaptor.logger.Warnw("only the HTTP status abort of fault filter is supported",
+				zap.Any("abort", abort),
+			)
+		} else if percentage := adaptor.getFaultPercentage(abort.GetPercentage()); percentage > 0 {
+			fi.Abort = &apisix.FaultInjection_Abort{
+				HttpStatus: int32(abort.GetHttpStatus()),
+				Percentage: percentage,
+			}
+		}
+	}
+	if fi.Delay == nil && fi.Abort == nil {
+		return nil, nil
+	}
+	return &fi, nil
+}
+
+// getFaultPercentage converts the percentage of faults to the integer one of
+// the fault-injection plugin, faults are never injected if it's zero (which
+// is the default of Envoy), while the plugin injects them to all requests
+// without the percentage.
+func (adaptor *adaptor) getFaultPercentage(p *typev3.FractionalPercent) int32 {
+	fraction := getFractionalPercent(p)
+	if fraction <= 0 {
+		return 0
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	percentage := int32(math.Round(fraction * 100))
+	if percentage == 0 {
+		adaptor.logger.Warnw("percentage of fault is rounded up to 1%",
+			zap.Any("percentage", p),
+		)
+		percentage = 1
+	}
+	return percentage
+}
+
+// getFractionalPercent returns the fraction (from 0 to 1) represented by
+// the FractionalPercent.
+func getFractionalPercent(p *typev3.FractionalPercent) float64 {
+	var denominator float64
+	switch p.GetDenominator() {
+	case typev3.FractionalPercent_TEN_THOUSAND:
+		denominator = 10000
+	case typev3.FractionalPercent_MILLION:
+		denominator = 1000000
+	default:
+		denominator = 100
+	}
+	return float64(p.GetNumerator()) / denominator
+}
+
+// checkGlobalRateLimit checks the ratelimit filter, the global rate limiting
+// relies on an external rate limit service (RLS), while there is no APISIX
+// plugin which can talk to it (limit-count only shares counters by Redis),
+// so it cannot be translated and requests won't be limited by APISIX.
+func (adaptor *adaptor) checkGlobalRateLimit(config *anypb.Any) error {
+	var rl ratelimitv3.RateLimit
+	if err := anypb.UnmarshalTo(config, &rl, proto.UnmarshalOptions{}); err != nil {
+		return err
+	}
+	var service string
+	grpc := rl.GetRateLimitService().GetGrpcService()
+	if cluster := grpc.GetEnvoyGrpc().GetClusterName(); cluster != "" {
+		service = cluster
+	} else {
+		service = grpc.GetGoogleGrpc().GetTargetUri()
+	}
+	adaptor.logger.Warnw("global rate limiting by the rate limit service is not supported by APISIX, requests won't be limited",
+		zap.String("domain", rl.GetDomain()),
+		zap.String("rate_limit_service", service),
+		zap.Bool("failure_mode_deny", rl.GetFailureModeDeny()),
+	)
+	return nil
+}
+
+// getRateLimitDescriptors describes the descriptor actions of rate limits,
+// it's used for logging since they cannot be translated.
+func getRateLimitDescriptors(rls []*routev3.RateLimit) []string {
+	var descriptors []string
+	for _, rl := range rls {
+		for _, action := range rl.GetActions() {
+			var desc string
+			switch a := action.GetActionSpecifier().(type) {
+			case *routev3.RateLimit_Action_SourceCluster_:
+				desc = "source_cluster"
+			case *routev3.RateLimit_Action_DestinationCluster_:
+				desc = "destination_cluster"
+			case *routev3.RateLimit_Action_RequestHeaders_:
+				desc = fmt.Sprintf("request_headers(%s=%s)", a.RequestHeaders.GetDescriptorKey(), a.RequestHeaders.GetHeaderName())
+			case *routev3.RateLimit_Action_RemoteAddress_:
+				desc = "remote_address"
+			case *routev3.RateLimit_Action_GenericKey_:
+				desc = fmt.Sprintf("generic_key(%s)", a.GenericKey.GetDescriptorValue())
+			case *routev3.RateLimit_Action_HeaderValueMatch_:
+				desc = fmt.Sprintf("header_value_match(%s)", a.HeaderValueMatch.GetDescriptorValue())
+			default:
+				desc = "unknown"
+			}
+			descriptors = append(descriptors, desc)
+		}
+	}
+	return descriptors
+}
+
+// patchRoutesWithPluginPriorities sets the priorities of plugins on routes,
+// plugins which are not in the priorities map keep their default priorities.
+func patchRoutesWithPluginPriorities(routes []*apisix.Route, priorities map[string]int32) {
+	if len(priorities) == 0 {
+		return
+	}
+	for _, r := range routes {
+		if r.Plugins == nil {
+			continue
+		}
+		if r.Plugins.FaultInjection != nil {
+			if priority, ok := priorities["fault-injection"]; ok {
+				r.Plugins.FaultInjection.Meta = &apisix.PluginMeta{
+					Priority: priority,
+				}
+			}
+		}
+		if r.Plugins.LimitConn != nil {
+			if priority, ok := priorities["limit-conn"]; ok {
+				r.Plugins.LimitConn.Meta = &apisix.PluginMeta{
+					Priority: priority,
+				}
+			}
+		}
+		if r.Plugins.LimitReq != nil {
+			if priority, ok := priorities["limit-req"]; ok {
+				r.Plugins.LimitReq.Meta = &apisix.PluginMeta{
+					Priority: priority,
+				}
+			}
+		}
+		if r.Plugins.KeyAuth != nil {
+			if priority, ok := priorities["key-auth"]; ok {
+				r.Plugins.KeyAuth.Meta = &apisix.PluginMeta{
+					Priority: priority,
+				}
+			}
+		}
+		if r.Plugins.Cors != nil {
+			if priority, ok := priorities["cors"]; ok {
+				r.Plugins.Cors.Meta = &apisix.PluginMeta{
+					Priority: priority,
+				}
+			}
+		}
+		for name, conf := range r.Plugins.Custom {
+			priority, ok := priorities[name]
+			if !ok {
+				continue
+			}
+			var obj map[string]interface{}
+			if err := json.Unmarshal([]byte(conf), &obj); err != nil {
+				continue
+			}
+			obj["_meta"] = &apisix.PluginMeta{
+				Priority: priority,
+			}
+			if data, err := json.Marshal(obj); err == nil {
+				r.Plugins.Custom[name] = string(data)
+			}
+		}
+	}
+}
@@ -0,0 +1,616 @@
+package v3
+
+import (
+	"testing"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	ratelimitconfv3 "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	faultcommonv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/common/fault/v3"
+	adaptiveconcurrencyv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/adaptive_concurrency/v3"
+	extauthzv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
+	faultv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
+	localratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
+	ratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func newTypedHTTPFilter(typeUrl string) *hcmv3.HttpFilter {
+	return &hcmv3.HttpFilter{
+		ConfigType: &hcmv3.HttpFilter_TypedConfig{
+			TypedConfig: &anypb.Any{
+				TypeUrl: typeUrl,
+			},
+		},
+	}
+}
+
+func TestGetHTTPFilterPluginPriorities(t *testing.T) {
+	filters := []*hcmv3.HttpFilter{
+		newTypedHTTPFilter("type.googleapis.com/envoy.extensions.filters.http.jwt_authn.v3.JwtAuthentication"),
+		newTypedHTTPFilter("type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit"),
+		newTypedHTTPFilter("type.googleapis.com/envoy.extensions.filters.http.fault.v3.HTTPFault"),
+		newTypedHTTPFilter("type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit"),
+		{
+			Name: "envoy.filters.http.router",
+		},
+	}
+	a := &adaptor{logger: log.DefaultLogger}
+	priorities := a.getHTTPFilterPluginPriorities(filters)
+	assert.Equal(t, priorities, map[string]int32{
+		"jwt-auth":        20000,
+		"limit-req":       19900,
+		"fault-injection": 19800,
+	})
+	assert.Greater(t, priorities["jwt-auth"], priorities["limit-req"])
+}
+
+func TestPatchRoutesWithPluginPriorities(t *testing.T) {
+	routes := []*apisix.Route{
+		{
+			Name: "1",
+			Plugins: &apisix.Plugins{
+				FaultInjection: &apisix.FaultInjection{
+					Abort: &apisix.FaultInjection_Abort{
+						HttpStatus: 503,
+					},
+				},
+			},
+		},
+		{
+			Name: "2",
+		},
+	}
+	patchRoutesWithPluginPriorities(routes, map[string]int32{
+		"cors": 20000,
+	})
+	assert.Nil(t, routes[0].Plugins.FaultInjection.Meta)
+
+	patchRoutesWithPluginPriorities(routes, map[string]int32{
+		"cors":            20000,
+		"fault-injection": 19900,
+	})
+	assert.Equal(t, routes[0].Plugins.FaultInjection.Meta.Priority, int32(19900))
+	assert.Nil(t, routes[1].Plugins)
+}
+
+func TestPatchRoutesWithAdaptiveConcurrency(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	ac, err := anypb.New(&adaptiveconcurrencyv3.AdaptiveConcurrency{
+		ConcurrencyControllerConfig: &adaptiveconcurrencyv3.AdaptiveConcurrency_GradientControllerConfig{
+			GradientControllerConfig: &adaptiveconcurrencyv3.GradientControllerConfig{
+				ConcurrencyLimitParams: &adaptiveconcurrencyv3.GradientControllerConfig_ConcurrencyLimitCalculationParams{
+					MaxConcurrencyLimit: &wrappers.UInt32Value{
+						Value: 200,
+					},
+				},
+			},
+		},
+		Enabled: &corev3.RuntimeFeatureFlag{
+			RuntimeKey: "adaptive_concurrency.enabled",
+		},
+	})
+	assert.Nil(t, err)
+	filters := []*hcmv3.HttpFilter{
+		newTypedHTTPFilter("type.googleapis.com/envoy.extensions.filters.http.fault.v3.HTTPFault"),
+		{
+			Name: "envoy.filters.http.adaptive_concurrency",
+			ConfigType: &hcmv3.HttpFilter_TypedConfig{
+				TypedConfig: ac,
+			},
+		},
+		{
+			Name: "envoy.filters.http.router",
+		},
+	}
+	routes := []*apisix.Route{
+		{
+			Name: "1",
+		},
+		{
+			Name: "2",
+			Plugins: &apisix.Plugins{
+				FaultInjection: &apisix.FaultInjection{
+					Abort: &apisix.FaultInjection_Abort{
+						HttpStatus: 503,
+					},
+				},
+			},
+		},
+	}
+	a.patchRoutesWithHTTPFilters(routes, filters, nil)
+	for _, r := range routes {
+		lc := r.Plugins.LimitConn
+		assert.NotNil(t, lc)
+		assert.Equal(t, lc.Conn, int32(200))
+		assert.Equal(t, lc.Burst, int32(0))
+		assert.Equal(t, lc.DefaultConnDelay, float64(_defaultConnDelay))
+		assert.Equal(t, lc.Key, "server_addr")
+		assert.Equal(t, lc.KeyType, "var")
+		assert.Equal(t, lc.RejectedCode, int32(503))
+		assert.Equal(t, lc.Meta.Priority, int32(19900))
+		assert.Nil(t, lc.Validate())
+	}
+	assert.Equal(t, routes[1].Plugins.FaultInjection.Meta.Priority, int32(20000))
+	// Routes should not share the same plugin object.
+	assert.NotSame(t, routes[0].Plugins.LimitConn, routes[1].Plugins.LimitConn)
+
+	// The default max_concurrency_limit is used if not set.
+	ac, err = anypb.New(&adaptiveconcurrencyv3.AdaptiveConcurrency{})
+	assert.Nil(t, err)
+	lc, err := a.translateAdaptiveConcurrency(ac)
+	assert.Nil(t, err)
+	assert.Equal(t, lc.Conn, int32(_defaultMaxConcurrencyLimit))
+}
+
+func TestPatchRoutesWithGlobalRateLimit(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	rl, err := anypb.New(&ratelimitv3.RateLimit{
+		Domain: "mesh",
+		RateLimitService: &ratelimitconfv3.RateLimitServiceConfig{
+			GrpcService: &corev3.GrpcService{
+				TargetSpecifier: &corev3.GrpcService_EnvoyGrpc_{
+					EnvoyGrpc: &corev3.GrpcService_EnvoyGrpc{
+						ClusterName: "rls",
+					},
+				},
+			},
+		},
+	})
+	assert.Nil(t, err)
+	routes := []*apisix.Route{
+		{
+			Name: "1",
+		},
+	}
+	a.patchRoutesWithHTTPFilters(routes, []*hcmv3.HttpFilter{
+		{
+			Name: "envoy.filters.http.ratelimit",
+			ConfigType: &hcmv3.HttpFilter_TypedConfig{
+				TypedConfig: rl,
+			},
+		},
+	}, nil)
+	// No equivalent plugin.
+	assert.Nil(t, routes[0].Plugins)
+	assert.Nil(t, a.checkGlobalRateLimit(rl))
+	assert.NotNil(t, a.checkGlobalRateLimit(&anypb.Any{
+		TypeUrl: _rateLimitTypeUrl,
+		Value:   []byte("bad"),
+	}))
+}
+
+func TestGetRateLimitDescriptors(t *testing.T) {
+	rls := []*routev3.RateLimit{
+		{
+			Actions: []*routev3.RateLimit_Action{
+				{
+					ActionSpecifier: &routev3.RateLimit_Action_RemoteAddress_{
+						RemoteAddress: &routev3.RateLimit_Action_RemoteAddress{},
+					},
+				},
+				{
+					ActionSpecifier: &routev3.RateLimit_Action_RequestHeaders_{
+						RequestHeaders: &routev3.RateLimit_Action_RequestHeaders{
+							HeaderName:    "x-user",
+							DescriptorKey: "user",
+						},
+					},
+				},
+			},
+		},
+		{
+			Actions: []*routev3.RateLimit_Action{
+				{
+					ActionSpecifier: &routev3.RateLimit_Action_GenericKey_{
+						GenericKey: &routev3.RateLimit_Action_GenericKey{
+							DescriptorValue: "slowpath",
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, getRateLimitDescriptors(rls), []string{
+		"remote_address",
+		"request_headers(user=x-user)",
+		"generic_key(slowpath)",
+	})
+}
+
+func TestPatchRoutesWithDisabledHTTPFilters(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	ac, err := anypb.New(&adaptiveconcurrencyv3.AdaptiveConcurrency{})
+	assert.Nil(t, err)
+	filters := []*hcmv3.HttpFilter{
+		{
+			Name: "envoy.filters.http.ext_authz",
+			ConfigType: &hcmv3.HttpFilter_TypedConfig{
+				TypedConfig: &anypb.Any{
+					TypeUrl: "type.googleapis.com/envoy.extensions.filters.http.ext_authz.v3.ExtAuthz",
+				},
+			},
+		},
+		{
+			Name: "envoy.filters.http.adaptive_concurrency",
+			ConfigType: &hcmv3.HttpFilter_TypedConfig{
+				TypedConfig: ac,
+			},
+		},
+		{
+			Name: "envoy.filters.http.router",
+		},
+	}
+
+	authzDisabled, err := anypb.New(&extauthzv3.ExtAuthzPerRoute{
+		Override: &extauthzv3.ExtAuthzPerRoute_Disabled{
+			Disabled: true,
+		},
+	})
+	assert.Nil(t, err)
+	filterDisabled, err := anypb.New(&routev3.FilterConfig{
+		Disabled: true,
+	})
+	assert.Nil(t, err)
+	newRoute := func(name string, perFilterConfig map[string]*anypb.Any) *routev3.Route {
+		return &routev3.Route{
+			Name: name,
+			Match: &routev3.RouteMatch{
+				PathSpecifier: &routev3.RouteMatch_Prefix{
+					Prefix: "/" + name,
+				},
+			},
+			Action: &routev3.Route_Route{
+				Route: &routev3.RouteAction{
+					ClusterSpecifier: &routev3.RouteAction_Cluster{
+						Cluster: "httpbin",
+					},
+				},
+			},
+			TypedPerFilterConfig: perFilterConfig,
+		}
+	}
+	rc := &routev3.RouteConfiguration{
+		Name: "rc1",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name: "vhost1",
+				TypedPerFilterConfig: map[string]*anypb.Any{
+					"envoy.filters.http.adaptive_concurrency": filterDisabled,
+				},
+				Routes: []*routev3.Route{
+					newRoute("public", map[string]*anypb.Any{
+						"envoy.filters.http.ext_authz": authzDisabled,
+					}),
+					newRoute("private", map[string]*anypb.Any{
+						// Overrides the one on the virtual host.
+						"envoy.filters.http.adaptive_concurrency": {
+							TypeUrl: _filterConfigTypeUrl,
+						},
+					}),
+				},
+			},
+		},
+	}
+	disabled := a.getDisabledHTTPFilters(rc.VirtualHosts[0], rc.VirtualHosts[0].Routes[0])
+	assert.Len(t, disabled, 2)
+	assert.Contains(t, disabled, "envoy.filters.http.ext_authz")
+	assert.Contains(t, disabled, "envoy.filters.http.adaptive_concurrency")
+	disabled = a.getDisabledHTTPFilters(rc.VirtualHosts[0], rc.VirtualHosts[0].Routes[1])
+	assert.Len(t, disabled, 0)
+
+	routes, err := a.TranslateRouteConfiguration(rc, &TranslateOptions{
+		RouteHTTPFilters: map[string][]*hcmv3.HttpFilter{
+			"rc1": filters,
+		},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, routes, 2)
+	assert.Nil(t, routes[0].Plugins)
+	assert.NotNil(t, routes[1].Plugins.LimitConn)
+
+	assert.False(t, a.isHTTPFilterDisabled("envoy.filters.http.ext_authz", &anypb.Any{
+		TypeUrl: _extAuthzPerRouteTypeUrl,
+		Value:   []byte("bad"),
+	}))
+}
+
+func newLocalRateLimit(t *testing.T, tokens uint32, enabled bool) *anypb.Any {
+	lrl := &localratelimitv3.LocalRateLimit{
+		StatPrefix: "http_local_rate_limiter",
+		TokenBucket: &typev3.TokenBucket{
+			MaxTokens:     tokens,
+			TokensPerFill: &wrappers.UInt32Value{Value: tokens},
+			FillInterval:  &duration.Duration{Seconds: 1},
+		},
+	}
+	if enabled {
+		percent := &corev3.RuntimeFractionalPercent{
+			DefaultValue: &typev3.FractionalPercent{
+				Numerator:   100,
+				Denominator: typev3.FractionalPercent_HUNDRED,
+			},
+		}
+		lrl.FilterEnabled = percent
+		lrl.FilterEnforced = percent
+	}
+	config, err := anypb.New(lrl)
+	assert.Nil(t, err)
+	return config
+}
+
+func TestPatchRoutesWithInheritedPerFilterConfigs(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	filters := []*hcmv3.HttpFilter{
+		{
+			Name: "envoy.filters.http.local_ratelimit",
+			ConfigType: &hcmv3.HttpFilter_TypedConfig{
+				// Disabled unless it's configured per route.
+				TypedConfig: newLocalRateLimit(t, 1000, false),
+			},
+		},
+		{
+			Name: "envoy.filters.http.router",
+		},
+	}
+	newRoute := func(name string, perFilterConfig map[string]*anypb.Any) *routev3.Route {
+		return &routev3.Route{
+			Name: name,
+			Match: &routev3.RouteMatch{
+				PathSpecifier: &routev3.RouteMatch_Prefix{
+					Prefix: "/" + name,
+				},
+			},
+			Action: &routev3.Route_Route{
+				Route: &routev3.RouteAction{
+					ClusterSpecifier: &routev3.RouteAction_Cluster{
+						Cluster: "httpbin",
+					},
+				},
+			},
+			TypedPerFilterConfig: perFilterConfig,
+		}
+	}
+	rc := &routev3.RouteConfiguration{
+		Name: "rc1",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name: "vhost1",
+				TypedPerFilterConfig: map[string]*anypb.Any{
+					"envoy.filters.http.local_ratelimit": newLocalRateLimit(t, 10, true),
+				},
+				Routes: []*routev3.Route{
+					newRoute("inherited", nil),
+					newRoute("overridden", map[string]*anypb.Any{
+						"envoy.filters.http.local_ratelimit": newLocalRateLimit(t, 100, true),
+					}),
+					newRoute("disabled", map[string]*anypb.Any{
+						"envoy.filters.http.local_ratelimit": newLocalRateLimit(t, 100, false),
+					}),
+				},
+			},
+		},
+	}
+	routes, err := a.TranslateRouteConfiguration(rc, &TranslateOptions{
+		RouteHTTPFilters: map[string][]*hcmv3.HttpFilter{
+			"rc1": filters,
+		},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, routes, 3)
+	assert.Equal(t, routes[0].Plugins.LimitReq, &apisix.LimitReq{
+		Rate:         10,
+		Burst:        10,
+		Key:          "server_addr",
+		KeyType:      "var",
+		RejectedCode: 429,
+		Nodelay:      true,
+		Meta: &apisix.PluginMeta{
+			Priority: 20000,
+		},
+	})
+	assert.Equal(t, routes[1].Plugins.LimitReq.Rate, float64(100))
+	assert.Nil(t, routes[2].Plugins)
+}
+
+func TestTranslateLocalRateLimit(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	lr, err := a.translateLocalRateLimit(newLocalRateLimit(t, 5, true))
+	assert.Nil(t, err)
+	assert.Equal(t, lr.Rate, float64(5))
+	assert.Equal(t, lr.Burst, float64(5))
+
+	config, err := anypb.New(&localratelimitv3.LocalRateLimit{
+		StatPrefix: "http_local_rate_limiter",
+		Status: &typev3.HttpStatus{
+			Code: typev3.StatusCode_ServiceUnavailable,
+		},
+		TokenBucket: &typev3.TokenBucket{
+			MaxTokens:    20,
+			FillInterval: &duration.Duration{Seconds: 2},
+		},
+	})
+	assert.Nil(t, err)
+	lr, err = a.translateLocalRateLimit(config)
+	assert.Nil(t, err)
+	assert.Equal(t, lr.Rate, 0.5)
+	assert.Equal(t, lr.Burst, float64(20))
+	assert.Equal(t, lr.RejectedCode, int32(503))
+
+	config, err = anypb.New(&localratelimitv3.LocalRateLimit{
+		StatPrefix: "http_local_rate_limiter",
+	})
+	assert.Nil(t, err)
+	_, err = a.translateLocalRateLimit(config)
+	assert.NotNil(t, err)
+}
+
+func newFault(t *testing.T, status uint32, delay int64, percentage uint32) *anypb.Any {
+	fault := &faultv3.HTTPFault{}
+	if status > 0 {
+		fault.Abort = &faultv3.FaultAbort{
+			ErrorType: &faultv3.FaultAbort_HttpStatus{
+				HttpStatus: status,
+			},
+			Percentage: &typev3.FractionalPercent{
+				Numerator: percentage,
+			},
+		}
+	}
+	if delay > 0 {
+		fault.Delay = &faultcommonv3.FaultDelay{
+			FaultDelaySecifier: &faultcommonv3.FaultDelay_FixedDelay{
+				FixedDelay: &duration.Duration{Seconds: delay},
+			},
+			Percentage: &typev3.FractionalPercent{
+				Numerator: percentage,
+			},
+		}
+	}
+	config, err := anypb.New(fault)
+	assert.Nil(t, err)
+	return config
+}
+
+func TestTranslateFault(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	fi, err := a.translateFault(newFault(t, 503, 2, 50))
+	assert.Nil(t, err)
+	assert.Equal(t, fi.Abort, &apisix.FaultInjection_Abort{
+		HttpStatus: 503,
+		Percentage: 50,
+	})
+	assert.Equal(t, fi.Delay, &apisix.FaultInjection_Delay{
+		Duration:   2,
+		Percentage: 50,
+	})
+	assert.Nil(t, fi.Validate())
+
+	// Faults are never injected without the percentage.
+	fi, err = a.translateFault(newFault(t, 503, 2, 0))
+	assert.Nil(t, err)
+	assert.Nil(t, fi)
+
+	config, err := anypb.New(&faultv3.HTTPFault{
+		Abort: &faultv3.FaultAbort{
+			ErrorType: &faultv3.FaultAbort_HttpStatus{
+				HttpStatus: 500,
+			},
+			Percentage: &typev3.FractionalPercent{
+				Numerator:   1,
+				Denominator: typev3.FractionalPercent_TEN_THOUSAND,
+			},
+		},
+	})
+	assert.Nil(t, err)
+	fi, err = a.translateFault(config)
+	assert.Nil(t, err)
+	assert.Equal(t, fi.Abort.Percentage, int32(1))
+	assert.Nil(t, fi.Delay)
+
+	// gRPC aborts are not supported.
+	config, err = anypb.New(&faultv3.HTTPFault{
+		Abort: &faultv3.FaultAbort{
+			ErrorType: &faultv3.FaultAbort_GrpcStatus{
+				GrpcStatus: 14,
+			},
+			Percentage: &typev3.FractionalPercent{
+				Numerator: 100,
+			},
+		},
+	})
+	assert.Nil(t, err)
+	fi, err = a.translateFault(config)
+	assert.Nil(t, err)
+	assert.Nil(t, fi)
+}
+
+func TestPatchRoutesWithFault(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	filters := []*hcmv3.HttpFilter{
+		{
+			Name: "envoy.filters.http.fault",
+			ConfigType: &hcmv3.HttpFilter_TypedConfig{
+				TypedConfig: newFault(t, 503, 0, 10),
+			},
+		},
+		{
+			Name: "envoy.filters.http.router",
+		},
+	}
+	newRoute := func(name string, perFilterConfig map[string]*anypb.Any) *routev3.Route {
+		return &routev3.Route{
+			Name: name,
+			Match: &routev3.RouteMatch{
+				PathSpecifier: &routev3.RouteMatch_Prefix{
+					Prefix: "/" + name,
+				},
+			},
+			Action: &routev3.Route_Route{
+				Route: &routev3.RouteAction{
+					ClusterSpecifier: &routev3.RouteAction_Cluster{
+						Cluster: "httpbin",
+					},
+				},
+			},
+			TypedPerFilterConfig: perFilterConfig,
+		}
+	}
+	direct := newRoute("direct", nil)
+	direct.Action = &routev3.Route_DirectResponse{
+		DirectResponse: &routev3.DirectResponseAction{
+			Status: 200,
+		},
+	}
+	rc := &routev3.RouteConfiguration{
+		Name: "rc1",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name: "vhost1",
+				Routes: []*routev3.Route{
+					newRoute("default", nil),
+					newRoute("overridden", map[string]*anypb.Any{
+						"envoy.filters.http.fault": newFault(t, 0, 3, 100),
+					}),
+					direct,
+				},
+			},
+		},
+	}
+	routes, err := a.TranslateRouteConfiguration(rc, &TranslateOptions{
+		RouteHTTPFilters: map[string][]*hcmv3.HttpFilter{
+			"rc1": filters,
+		},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, routes, 3)
+	assert.True(t, proto.Equal(routes[0].Plugins.FaultInjection, &apisix.FaultInjection{
+		Abort: &apisix.FaultInjection_Abort{
+			HttpStatus: 503,
+			Percentage: 10,
+		},
+		Meta: &apisix.PluginMeta{
+			Priority: 20000,
+		},
+	}))
+	assert.Nil(t, routes[1].Plugins.FaultInjection.Abort)
+	assert.Equal(t, routes[1].Plugins.FaultInjection.Delay.Duration, float64(3))
+	assert.Equal(t, routes[1].Plugins.FaultInjection.Delay.Percentage, int32(100))
+	assert.Equal(t, routes[2].Plugins.FaultInjection.Abort.HttpStatus, int32(200))
+}
@@ -0,0 +1,77 @@
+package v3
+
+import (
+	"fmt"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// GetResourceName returns the name of the xDS resource, it's the cluster
+// name for ClusterLoadAssignments.
+func GetResourceName(res *anypb.Any) (string, error) {
+	msg, err := res.UnmarshalNew()
+	if err != nil {
+		return "", err
+	}
+	switch obj := msg.(type) {
+	case *clusterv3.Cluster:
+		return obj.GetName(), nil
+	case *endpointv3.ClusterLoadAssignment:
+		return obj.GetClusterName(), nil
+	case *routev3.RouteConfiguration:
+		return obj.GetName(), nil
+	case *listenerv3.Listener:
+		return obj.GetName(), nil
+	case *tlsv3.Secret:
+		return obj.GetName(), nil
+	default:
+		return "", fmt.Errorf("unsupported resource type %s", res.GetTypeUrl())
+	}
+}
+
+// DedupResources drops the resources which have the same type url and name
+// as a later one in the list (e.g. a control plane bug puts two clusters
+// with the same name in a DiscoveryResponse), so that only the last
+// occurrence is kept and the translation is deterministic. Resources which
+// cannot be named are kept as is. The names of the dropped resources are
+// returned, keyed by the type url.
+func DedupResources(resources []*anypb.Any) ([]*anypb.Any, map[string][]string) {
+	type key struct {
+		typeUrl string
+		name    string
+	}
+	var (
+		seen    = make(map[key]struct{}, len(resources))
+		dropped = make(map[int]struct{})
+		dups    = make(map[string][]string)
+	)
+	for i := len(resources) - 1; i >= 0; i-- {
+		res := resources[i]
+		name, err := GetResourceName(res)
+		if err != nil {
+			continue
+		}
+		k := key{res.GetTypeUrl(), name}
+		if _, ok := seen[k]; ok {
+			dropped[i] = struct{}{}
+			dups[k.typeUrl] = append(dups[k.typeUrl], name)
+			continue
+		}
+		seen[k] = struct{}{}
+	}
+	if len(dropped) == 0 {
+		return resources, nil
+	}
+	kept := make([]*anypb.Any, 0, len(resources)-len(dropped))
+	for i, res := range resources {
+		if _, ok := dropped[i]; !ok {
+			kept = append(kept, res)
+		}
+	}
+	return kept, dups
+}
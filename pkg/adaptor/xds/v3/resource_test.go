@@ -0,0 +1,51 @@
+package v3
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/testutil"
+)
+
+func TestGetResourceName(t *testing.T) {
+	name, err := GetResourceName(testutil.NewAny(t, &clusterv3.Cluster{Name: "a"}))
+	assert.Nil(t, err)
+	assert.Equal(t, name, "a")
+	name, err = GetResourceName(testutil.NewAny(t, &endpointv3.ClusterLoadAssignment{ClusterName: "b"}))
+	assert.Nil(t, err)
+	assert.Equal(t, name, "b")
+	name, err = GetResourceName(testutil.NewAny(t, &tlsv3.Secret{Name: "d"}))
+	assert.Nil(t, err)
+	assert.Equal(t, name, "d")
+
+	_, err = GetResourceName(testutil.NewAny(t, &routev3.Route{Name: "c"}))
+	assert.NotNil(t, err)
+}
+
+func TestDedupResources(t *testing.T) {
+	resources := []*anypb.Any{
+		testutil.NewAny(t, &clusterv3.Cluster{Name: "a", LbPolicy: clusterv3.Cluster_ROUND_ROBIN}),
+		testutil.NewAny(t, &clusterv3.Cluster{Name: "b"}),
+		// Same name but different types.
+		testutil.NewAny(t, &endpointv3.ClusterLoadAssignment{ClusterName: "a"}),
+		testutil.NewAny(t, &clusterv3.Cluster{Name: "a", LbPolicy: clusterv3.Cluster_LEAST_REQUEST}),
+		// Resources which cannot be named are kept.
+		testutil.NewAny(t, &routev3.Route{}),
+		testutil.NewAny(t, &routev3.Route{}),
+	}
+	kept, dups := DedupResources(resources)
+	assert.Equal(t, kept, []*anypb.Any{resources[1], resources[2], resources[3], resources[4], resources[5]})
+	assert.Equal(t, dups, map[string][]string{
+		resources[0].GetTypeUrl(): {"a"},
+	})
+
+	kept, dups = DedupResources(kept[:3])
+	assert.Len(t, kept, 3)
+	assert.Nil(t, dups)
+}
@@ -0,0 +1,239 @@
+package v3
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"go.uber.org/zap"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+var (
+	// _substitutionGroupRegex is the pattern of the capture group references
+	// (like "\1") in the substitution of the Envoy regex_rewrite.
+	_substitutionGroupRegex = regexp.MustCompile(`\\(\d)`)
+)
+
+// headerMutations are the header manipulations merged from the route and
+// its virtual host, header names are in lower case.
+type headerMutations struct {
+	add    map[string][]string
+	set    map[string]string
+	remove []string
+}
+
+// patchRouteWithHeaderMutations translates the request and response headers
+// manipulations of the route and its virtual host to the proxy-rewrite and
+// response-rewrite plugins.
+func (adaptor *adaptor) patchRouteWithHeaderMutations(vhost *routev3.VirtualHost, route *routev3.Route, r *apisix.Route) {
+	req := adaptor.mergeHeaderMutations(r.Name,
+		[][]*corev3.HeaderValueOption{route.GetRequestHeadersToAdd(), vhost.GetRequestHeadersToAdd()},
+		[][]string{route.GetRequestHeadersToRemove(), vhost.GetRequestHeadersToRemove()},
+	)
+	resp := adaptor.mergeHeaderMutations(r.Name,
+		[][]*corev3.HeaderValueOption{route.GetResponseHeadersToAdd(), vhost.GetResponseHeadersToAdd()},
+		[][]string{route.GetResponseHeadersToRemove(), vhost.GetResponseHeadersToRemove()},
+	)
+	if req == nil && resp == nil {
+		return
+	}
+	if r.Plugins == nil {
+		r.Plugins = &apisix.Plugins{}
+	}
+	if req != nil {
+		headers := &apisix.ProxyRewrite_Headers{
+			Set:    req.set,
+			Remove: req.remove,
+		}
+		for name, values := range req.add {
+			if headers.Add == nil {
+				headers.Add = make(map[string]string)
+			}
+			// The proxy-rewrite plugin appends one value for each header,
+			// values of the same header are combined as per RFC 7230.
+			headers.Add[name] = strings.Join(values, ", ")
+		}
+		if r.Plugins.ProxyRewrite == nil {
+			r.Plugins.ProxyRewrite = &apisix.ProxyRewrite{}
+		}
+		r.Plugins.ProxyRewrite.Headers = headers
+	}
+	if resp != nil {
+		headers := &apisix.ResponseRewrite_Headers{
+			Set:    resp.set,
+			Remove: resp.remove,
+		}
+		names := make([]string, 0, len(resp.add))
+		for name := range resp.add {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, value := range resp.add[name] {
+				headers.Add = append(headers.Add, fmt.Sprintf("%s: %s", name, value))
+			}
+		}
+		r.Plugins.ResponseRewrite = &apisix.ResponseRewrite{
+			Headers: headers,
+		}
+	}
+}
+
+// mergeHeaderMutations merges the headers to add and remove, the route
+// level ones come first. Like Envoy, the enclosing (virtual host) level is
+// applied after the route level, so it wins when the header is overwritten
+// by both, and headers are removed before the new ones are added, so headers
+// which are both removed and added are overwritten instead. Values with the
+// command operators (like "%DOWNSTREAM_REMOTE_ADDRESS%") are not supported
+// and skipped. It returns nil if there is no manipulation.
+func (adaptor *adaptor) mergeHeaderMutations(route string, toAdd [][]*corev3.HeaderValueOption, toRemove [][]string) *headerMutations {
+	m := &headerMutations{
+		add: make(map[string][]string),
+		set: make(map[string]string),
+	}
+	for _, opts := range toAdd {
+		for _, opt := range opts {
+			name := strings.ToLower(opt.GetHeader().GetKey())
+			value := opt.GetHeader().GetValue()
+			if name == "" {
+				continue
+			}
+			if strings.Contains(value, "%") {
+				adaptor.logger.Warnw("header value with command operators is not supported, the header is ignored",
+					zap.String("route", route),
+					zap.String("header", name),
+					zap.String("value", value),
+				)
+				continue
+			}
+			// Headers are appended by default.
+			if opt.GetAppend() == nil || opt.GetAppend().GetValue() {
+				if v, ok := m.set[name]; ok {
+					m.set[name] = v + ", " + value
+				} else {
+					m.add[name] = append(m.add[name], value)
+				}
+				continue
+			}
+			delete(m.add, name)
+			m.set[name] = value
+		}
+	}
+	removed := make(map[string]struct{})
+	for _, names := range toRemove {
+		for _, name := range names {
+			name = strings.ToLower(name)
+			if _, ok := removed[name]; ok {
+				continue
+			}
+			removed[name] = struct{}{}
+			if values, ok := m.add[name]; ok {
+				delete(m.add, name)
+				m.set[name] = strings.Join(values, ", ")
+				continue
+			}
+			if _, ok := m.set[name]; ok {
+				continue
+			}
+			m.remove = append(m.remove, name)
+		}
+	}
+	if len(m.add) == 0 && len(m.set) == 0 && len(m.remove) == 0 {
+		return nil
+	}
+	if len(m.add) == 0 {
+		m.add = nil
+	}
+	if len(m.set) == 0 {
+		m.set = nil
+	}
+	return m
+}
+
+// patchRouteWithPathRewrite translates the prefix_rewrite and regex_rewrite
+// of the route to the uri and regex_uri of the proxy-rewrite plugin. The
+// matched prefix is swapped by the prefix_rewrite as is, e.g. "/api/foo" is
+// rewritten to "//foo" if the prefix "/api" is rewritten to "/", like Envoy.
+func (adaptor *adaptor) patchRouteWithPathRewrite(route *routev3.Route, r *apisix.Route) {
+	action := route.GetRoute()
+	var (
+		uri      string
+		regexUri []string
+	)
+	if prefix := action.GetPrefixRewrite(); prefix != "" {
+		switch match := route.GetMatch().GetPathSpecifier().(type) {
+		case *routev3.RouteMatch_Path:
+			uri = prefix
+		case *routev3.RouteMatch_Prefix:
+			regexUri = []string{"^" + regexp.QuoteMeta(match.Prefix) + "(.*)", prefix + "$1"}
+		default:
+			adaptor.logger.Warnw("prefix_rewrite of route is ignored since the route doesn't match by path or prefix",
+				zap.String("route", r.Name),
+				zap.String("prefix_rewrite", prefix),
+			)
+			return
+		}
+	} else if rr := action.GetRegexRewrite(); rr != nil {
+		pattern := rr.GetPattern().GetRegex()
+		if pattern == "" {
+			return
+		}
+		if !isAnchoredAtBeginning(pattern) {
+			// Envoy replaces every match of the pattern, while the regex_uri
+			// (ngx.re.sub) replaces only the first one.
+			adaptor.logger.Warnw("regex_rewrite of route may match more than once, only the first match will be rewritten",
+				zap.String("route", r.Name),
+				zap.String("pattern", pattern),
+			)
+		}
+		// Apache APISIX refers to the capture groups by "$1" instead of "\1".
+		regexUri = []string{pattern, _substitutionGroupRegex.ReplaceAllString(rr.GetSubstitution(), "$$${1}")}
+	} else {
+		return
+	}
+	if r.Plugins == nil {
+		r.Plugins = &apisix.Plugins{}
+	}
+	if r.Plugins.ProxyRewrite == nil {
+		r.Plugins.ProxyRewrite = &apisix.ProxyRewrite{}
+	}
+	r.Plugins.ProxyRewrite.Uri = uri
+	r.Plugins.ProxyRewrite.RegexUri = regexUri
+}
+
+// isAnchoredAtBeginning reports whether the pattern only matches at the
+// beginning of the text, so it matches once at most. Invalid patterns are
+// not anchored.
+func isAnchoredAtBeginning(pattern string) bool {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return false
+	}
+	return isRegexpAnchoredAtBeginning(re.Simplify())
+}
+
+func isRegexpAnchoredAtBeginning(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpBeginText:
+		return true
+	case syntax.OpCapture:
+		return isRegexpAnchoredAtBeginning(re.Sub[0])
+	case syntax.OpConcat:
+		return len(re.Sub) > 0 && isRegexpAnchoredAtBeginning(re.Sub[0])
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if !isRegexpAnchoredAtBeginning(sub) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,191 @@
+package v3
+
+import (
+	"bytes"
+	"testing"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func newHeaderValueOption(key, value string, appendValue *bool) *corev3.HeaderValueOption {
+	opt := &corev3.HeaderValueOption{
+		Header: &corev3.HeaderValue{
+			Key:   key,
+			Value: value,
+		},
+	}
+	if appendValue != nil {
+		opt.Append = &wrappers.BoolValue{Value: *appendValue}
+	}
+	return opt
+}
+
+func TestPatchRouteWithHeaderMutations(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	vhost := &routev3.VirtualHost{}
+	route := &routev3.Route{}
+	r := &apisix.Route{}
+	a.patchRouteWithHeaderMutations(vhost, route, r)
+	assert.Nil(t, r.Plugins)
+
+	overwrite := false
+	vhost = &routev3.VirtualHost{
+		RequestHeadersToAdd: []*corev3.HeaderValueOption{
+			newHeaderValueOption("X-Env", "prod", &overwrite),
+			newHeaderValueOption("X-Tag", "vhost", nil),
+		},
+		RequestHeadersToRemove:  []string{"X-Internal"},
+		ResponseHeadersToRemove: []string{"Server"},
+	}
+	route = &routev3.Route{
+		RequestHeadersToAdd: []*corev3.HeaderValueOption{
+			newHeaderValueOption("X-Env", "test", &overwrite),
+			newHeaderValueOption("X-Tag", "route", nil),
+			newHeaderValueOption("X-Client", "%DOWNSTREAM_REMOTE_ADDRESS%", nil),
+			newHeaderValueOption("X-Version", "v1", nil),
+		},
+		// It's removed before adding, so it's overwritten.
+		RequestHeadersToRemove: []string{"x-version"},
+		ResponseHeadersToAdd: []*corev3.HeaderValueOption{
+			newHeaderValueOption("Cache-Control", "no-cache", nil),
+			newHeaderValueOption("Cache-Control", "no-store", nil),
+			newHeaderValueOption("X-Powered-By", "apisix", &overwrite),
+		},
+	}
+	r = &apisix.Route{Name: "route1"}
+	a.patchRouteWithHeaderMutations(vhost, route, r)
+	assert.Equal(t, r.Plugins.ProxyRewrite.Headers, &apisix.ProxyRewrite_Headers{
+		Add: map[string]string{
+			"x-tag": "route, vhost",
+		},
+		Set: map[string]string{
+			// The virtual host level is applied after the route level.
+			"x-env":     "prod",
+			"x-version": "v1",
+		},
+		Remove: []string{"x-internal"},
+	})
+	assert.Equal(t, r.Plugins.ResponseRewrite.Headers, &apisix.ResponseRewrite_Headers{
+		Add: []string{"cache-control: no-cache", "cache-control: no-store"},
+		Set: map[string]string{
+			"x-powered-by": "apisix",
+		},
+		Remove: []string{"server"},
+	})
+}
+
+func TestPatchRouteWithPathRewrite(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	route := &routev3.Route{
+		Match: &routev3.RouteMatch{
+			PathSpecifier: &routev3.RouteMatch_Prefix{
+				Prefix: "/api",
+			},
+		},
+		Action: &routev3.Route_Route{
+			Route: &routev3.RouteAction{},
+		},
+	}
+	r := &apisix.Route{}
+	a.patchRouteWithPathRewrite(route, r)
+	assert.Nil(t, r.Plugins)
+
+	route.GetRoute().PrefixRewrite = "/"
+	a.patchRouteWithPathRewrite(route, r)
+	assert.Equal(t, r.Plugins.ProxyRewrite, &apisix.ProxyRewrite{
+		RegexUri: []string{"^/api(.*)", "/$1"},
+	})
+
+	route.Match.PathSpecifier = &routev3.RouteMatch_Path{
+		Path: "/api.json",
+	}
+	route.GetRoute().PrefixRewrite = "/v2/api.json"
+	r = &apisix.Route{}
+	a.patchRouteWithPathRewrite(route, r)
+	assert.Equal(t, r.Plugins.ProxyRewrite, &apisix.ProxyRewrite{
+		Uri: "/v2/api.json",
+	})
+
+	route.Match.PathSpecifier = &routev3.RouteMatch_ConnectMatcher_{
+		ConnectMatcher: &routev3.RouteMatch_ConnectMatcher{},
+	}
+	r = &apisix.Route{}
+	a.patchRouteWithPathRewrite(route, r)
+	assert.Nil(t, r.Plugins)
+
+	route.GetRoute().PrefixRewrite = ""
+	route.GetRoute().RegexRewrite = &matcherv3.RegexMatchAndSubstitute{
+		Pattern: &matcherv3.RegexMatcher{
+			Regex: "^/service/([^/]+)(/.*)$",
+		},
+		Substitution: "\\2/instance/\\1",
+	}
+	// The header manipulations are kept.
+	r = &apisix.Route{
+		Plugins: &apisix.Plugins{
+			ProxyRewrite: &apisix.ProxyRewrite{
+				Headers: &apisix.ProxyRewrite_Headers{
+					Remove: []string{"x-internal"},
+				},
+			},
+		},
+	}
+	a.patchRouteWithPathRewrite(route, r)
+	assert.Equal(t, r.Plugins.ProxyRewrite, &apisix.ProxyRewrite{
+		Headers: &apisix.ProxyRewrite_Headers{
+			Remove: []string{"x-internal"},
+		},
+		RegexUri: []string{"^/service/([^/]+)(/.*)$", "$2/instance/$1"},
+	})
+}
+
+func TestPatchRouteWithRegexRewriteMatchingMoreThanOnce(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := log.NewLogger(
+		log.WithLogLevel("warn"),
+		log.WithWriteSyncer(zapcore.AddSync(&buf)),
+	)
+	assert.Nil(t, err)
+	a := &adaptor{logger: logger}
+	route := &routev3.Route{
+		Action: &routev3.Route_Route{
+			Route: &routev3.RouteAction{
+				RegexRewrite: &matcherv3.RegexMatchAndSubstitute{
+					Pattern: &matcherv3.RegexMatcher{
+						Regex: "^/service/([^/]+)(/.*)$",
+					},
+					Substitution: "\\2/instance/\\1",
+				},
+			},
+		},
+	}
+	r := &apisix.Route{Name: "route1"}
+	a.patchRouteWithPathRewrite(route, r)
+	assert.Equal(t, buf.String(), "")
+
+	route.GetRoute().RegexRewrite.Pattern.Regex = "-"
+	r = &apisix.Route{Name: "route1"}
+	a.patchRouteWithPathRewrite(route, r)
+	// The rewrite is still translated.
+	assert.Equal(t, r.Plugins.ProxyRewrite.RegexUri, []string{"-", "$2/instance/$1"})
+	assert.Contains(t, buf.String(), "regex_rewrite of route may match more than once")
+}
+
+func TestIsAnchoredAtBeginning(t *testing.T) {
+	assert.True(t, isAnchoredAtBeginning("^/api"))
+	assert.True(t, isAnchoredAtBeginning("\\A/api"))
+	assert.True(t, isAnchoredAtBeginning("(^/v1)/(.*)"))
+	assert.True(t, isAnchoredAtBeginning("^/v1|^/v2"))
+	assert.False(t, isAnchoredAtBeginning("/api"))
+	assert.False(t, isAnchoredAtBeginning("^/v1|/v2"))
+	assert.False(t, isAnchoredAtBeginning("(?m)^/api"))
+	assert.False(t, isAnchoredAtBeginning("["))
+}
@@ -2,11 +2,15 @@ package v3
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/api7/apisix-mesh-agent/pkg/id"
 	"github.com/api7/apisix-mesh-agent/pkg/set"
@@ -15,12 +19,52 @@ import (
 
 const (
 	_defaultRoutePriority = 999
+
+	// MaintenanceMetadataKey is the key in the route filter_metadata, routes
+	// with the "enabled" field set to true will respond the "status" (503 by
+	// default) and "body" directly instead of proxying the requests.
+	MaintenanceMetadataKey = "apisix.maintenance"
+
+	_defaultMaintenanceStatus = 503
+
+	// LabelUnsupported is the label key which marks the routes with
+	// features that cannot be translated, the value is the feature name.
+	LabelUnsupported = "xds-unsupported"
+	// LabelSourceHash is the label key which carries the hash of the xDS
+	// resource that the resource is translated from.
+	LabelSourceHash = "xds-source-hash"
+)
+
+var (
+	// _routeHostRegex is the pattern of the APISIX route hosts.
+	_routeHostRegex = regexp.MustCompile(`^\*?[0-9a-zA-Z-._]+$`)
+	// _httpMethods are the HTTP methods supported by the APISIX route methods field.
+	_httpMethods = map[string]struct{}{
+		"GET":     {},
+		"POST":    {},
+		"PUT":     {},
+		"DELETE":  {},
+		"PATCH":   {},
+		"HEAD":    {},
+		"OPTIONS": {},
+		"CONNECT": {},
+		"TRACE":   {},
+	}
+	// _retriableConditions are the retry_on conditions which cover the
+	// connection failures or timeouts, which are retried by Apache APISIX.
+	_retriableConditions = map[string]struct{}{
+		"5xx":             {},
+		"gateway-error":   {},
+		"connect-failure": {},
+		"reset":           {},
+	}
 )
 
 func (adaptor *adaptor) TranslateRouteConfiguration(r *routev3.RouteConfiguration, opts *TranslateOptions) ([]*apisix.Route, error) {
 	var routes []*apisix.Route
+	disabledFilters := make(map[string]set.StringSet)
 	for _, vhost := range r.GetVirtualHosts() {
-		partial, err := adaptor.translateVirtualHost(r.Name, vhost, opts)
+		partial, err := adaptor.translateVirtualHost(r.Name, vhost, opts, disabledFilters)
 		if err != nil {
 			adaptor.logger.Errorw("failed to translate VirtualHost",
 				zap.Error(err),
@@ -28,10 +72,28 @@ func (adaptor *adaptor) TranslateRouteConfiguration(r *routev3.RouteConfiguratio
 			return nil, err
 		}
 		routes = append(routes, partial...)
+
+		partial, err = adaptor.translateVirtualHostMatcher(r.Name, vhost, opts, disabledFilters)
+		if err != nil {
+			adaptor.logger.Errorw("failed to translate the matcher of VirtualHost",
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		routes = append(routes, partial...)
+	}
+	if opts != nil && opts.RouteHTTPFilters != nil {
+		if filters, ok := opts.RouteHTTPFilters[r.Name]; ok {
+			adaptor.patchRoutesWithHTTPFilters(routes, filters, disabledFilters)
+			adaptor.dropCorsWithoutFilter(routes, filters, disabledFilters)
+			// Health check routes respond directly, so other filters
+			// are not patched to them.
+			routes = append(routes, adaptor.translateHealthCheckFilters(r.Name, filters, opts.Tenant)...)
+		}
 	}
 	if opts != nil && opts.RouteOriginalDestination != nil {
 		origDst, ok := opts.RouteOriginalDestination[r.Name]
-		if ok {
+		if ok && origDst != "" {
 			patchRoutesWithOriginalDestination(routes, origDst)
 		}
 	}
@@ -39,7 +101,10 @@ func (adaptor *adaptor) TranslateRouteConfiguration(r *routev3.RouteConfiguratio
 	return routes, nil
 }
 
-func (adaptor *adaptor) translateVirtualHost(prefix string, vhost *routev3.VirtualHost, opts *TranslateOptions) ([]*apisix.Route, error) {
+// translateVirtualHost translates the routes in the VirtualHost, names of the HTTP
+// filters which are disabled on the routes will be collected to disabledFilters
+// (keyed by the APISIX route id) if it's not nil.
+func (adaptor *adaptor) translateVirtualHost(prefix string, vhost *routev3.VirtualHost, opts *TranslateOptions, disabledFilters map[string]set.StringSet) ([]*apisix.Route, error) {
 	if prefix == "" {
 		prefix = "<anon>"
 	}
@@ -57,19 +122,41 @@ func (adaptor *adaptor) translateVirtualHost(prefix string, vhost *routev3.Virtu
 			continue
 		}
 
-		cluster, skip := adaptor.getClusterName(route)
-		if skip {
-			continue
+		var cluster string
+		switch route.GetAction().(type) {
+		case *routev3.Route_DirectResponse, *routev3.Route_Redirect:
+			// These routes respond directly, there is no upstream.
+		default:
+			var skip bool
+			cluster, skip = adaptor.getClusterName(route)
+			if skip {
+				continue
+			}
 		}
 		uri, skip := adaptor.getURL(route)
 		if skip {
 			continue
 		}
+		if rls := route.GetRoute().GetRateLimits(); len(rls) > 0 {
+			adaptor.logger.Warnw("rate limits of route are ignored since the global rate limiting is not supported",
+				zap.String("route", route.GetName()),
+				zap.Strings("descriptors", getRateLimitDescriptors(rls)),
+			)
+		}
 
 		name := route.Name
 		if name == "" {
 			name = "<anon>"
 		}
+
+		if mps := route.GetRoute().GetRequestMirrorPolicies(); len(mps) > 0 {
+			// There is no mirror translation yet, so whether mirrored
+			// requests are traced (trace_sampled) cannot be carried either.
+			adaptor.logger.Warnw("request mirror policies of route are not supported and ignored, mirrored requests and their trace sampling are not configured",
+				zap.String("route", route.GetName()),
+				zap.Any("request_mirror_policies", mps),
+			)
+		}
 		priority := _defaultRoutePriority
 		// This is for istio.
 		// use the default and lowest priority for the "allow_any" route.
@@ -81,12 +168,24 @@ func (adaptor *adaptor) translateVirtualHost(prefix string, vhost *routev3.Virtu
 		if skip {
 			continue
 		}
+		methods, skip := adaptor.getMethods(route)
+		if skip {
+			continue
+		}
 		vars, skip := adaptor.getHeadersMatchVars(route)
 		if skip {
 			continue
 		}
 		vars = append(vars, queryVars...)
 		name = fmt.Sprintf("%s#%s#%s", name, vhost.GetName(), prefix)
+		var tenant string
+		if opts != nil && opts.Tenant != "" {
+			tenant = opts.Tenant
+			name = TenantScopedName(tenant, name)
+			if cluster != "" {
+				cluster = TenantScopedName(tenant, cluster)
+			}
+		}
 		hosts := set.StringSet{}
 		for _, domain := range vhost.Domains {
 			if domain == "*" {
@@ -101,15 +200,48 @@ func (adaptor *adaptor) translateVirtualHost(prefix string, vhost *routev3.Virtu
 				hosts.Add(domain)
 			}
 		}
+		// Sort the hosts so the translation is stable, or the route is
+		// updated every time it's translated.
+		vhostHosts := hosts.Strings()
+		sort.Strings(vhostHosts)
+		routeHosts, skip := adaptor.getHosts(route, vhostHosts)
+		if skip {
+			continue
+		}
 		r := &apisix.Route{
-			Name:       name,
-			Priority:   int32(priority),
-			Status:     1,
-			Id:         id.GenID(name),
-			Hosts:      hosts.Strings(),
-			Uris:       []string{uri},
-			UpstreamId: id.GenID(cluster),
-			Vars:       vars,
+			Name:     name,
+			Priority: int32(priority),
+			Status:   1,
+			Id:       id.GenID(name),
+			Hosts:    routeHosts,
+			Uris:     []string{uri},
+			Methods:  methods,
+			Vars:     vars,
+		}
+		if cluster != "" {
+			r.UpstreamId = id.GenID(cluster)
+		}
+		if adaptor.patchRouteWithDirectResponse(route, r) || adaptor.patchRouteWithRedirect(route, r) {
+			continue
+		}
+		adaptor.patchRouteWithMaintenance(route, r)
+		adaptor.patchRouteWithInternalRedirect(route, r)
+		adaptor.patchRouteWithTimeout(route, r)
+		adaptor.patchRouteWithHeaderMutations(vhost, route, r)
+		adaptor.patchRouteWithPathRewrite(route, r)
+		adaptor.patchRouteWithCors(vhost, route, r)
+		adaptor.patchRouteWithPerFilterConfigs(r, getPerFilterConfigs(vhost, route))
+		if adaptor.sourceHashLabels {
+			if r.Labels == nil {
+				r.Labels = make(map[string]string)
+			}
+			r.Labels[LabelSourceHash] = getSourceHash(route)
+		}
+		adaptor.patchRouteWithWeightedClusters(route, r, tenant)
+		if disabledFilters != nil {
+			if disabled := adaptor.getDisabledHTTPFilters(vhost, route); len(disabled) > 0 {
+				disabledFilters[r.Id] = disabled
+			}
 		}
 		routes = append(routes, r)
 	}
@@ -124,14 +256,36 @@ func (adaptor *adaptor) getClusterName(route *routev3.Route) (string, bool) {
 		)
 		return "", true
 	}
-	cluster, ok := action.Route.GetClusterSpecifier().(*routev3.RouteAction_Cluster)
-	if !ok {
+	switch spec := action.Route.GetClusterSpecifier().(type) {
+	case *routev3.RouteAction_Cluster:
+		return spec.Cluster, false
+	case *routev3.RouteAction_WeightedClusters:
+		// The first cluster is used as the upstream of the route, and the
+		// traffic will be split by the traffic-split plugin, see
+		// patchRouteWithWeightedClusters for the details.
+		clusters := spec.WeightedClusters.GetClusters()
+		if len(clusters) == 0 {
+			adaptor.logger.Warnw("ignore route with empty weighted clusters",
+				zap.String("route", route.GetName()),
+			)
+			return "", true
+		}
+		return clusters[0].GetName(), false
+	case *routev3.RouteAction_ClusterHeader:
+		// The upstream of APISIX route is decided statically, while the
+		// candidate clusters are unknown here, so the route is skipped
+		// instead of generating a route without the correct upstream.
+		adaptor.logger.Warnw("ignore route with dynamic cluster selection (cluster_header), it's not supported",
+			zap.String("route", route.GetName()),
+			zap.String("cluster_header", spec.ClusterHeader),
+		)
+		return "", true
+	default:
 		adaptor.logger.Warnw("ignore route with unexpected cluster specifier",
 			zap.Any("route", route),
 		)
 		return "", true
 	}
-	return cluster.Cluster, false
 }
 
 func (adaptor *adaptor) getURL(route *routev3.Route) (string, bool) {
@@ -141,6 +295,19 @@ func (adaptor *adaptor) getURL(route *routev3.Route) (string, bool) {
 		uri = route.GetMatch().GetPathSpecifier().(*routev3.RouteMatch_Path).Path
 	case *routev3.RouteMatch_Prefix:
 		uri = route.GetMatch().GetPathSpecifier().(*routev3.RouteMatch_Prefix).Prefix + "*"
+	case *routev3.RouteMatch_ConnectMatcher_:
+		// The target of CONNECT requests is the authority instead of
+		// a path, so any uri is matched, and the method is restricted
+		// by getMethods.
+		if isConnectTerminated(route) {
+			// Apache APISIX cannot terminate the CONNECT and proxy
+			// the payload to the upstream.
+			adaptor.logger.Warnw("ignore route which terminates CONNECT requests, it's not supported",
+				zap.String("route", route.GetName()),
+			)
+			return "", true
+		}
+		uri = "/*"
 	default:
 		adaptor.logger.Warnw("ignore route with unexpected path specifier",
 			zap.Any("route", route),
@@ -179,6 +346,14 @@ func (adaptor *adaptor) getHeadersMatchVars(route *routev3.Route) ([]*apisix.Var
 	// for the translation details.
 	var vars []*apisix.Var
 	for _, header := range route.GetMatch().GetHeaders() {
+		if getMethodMatchValues(header) != nil {
+			// Already translated to the route methods.
+			continue
+		}
+		if getAuthorityMatchHost(header) != "" {
+			// Already translated to the route hosts.
+			continue
+		}
 		var (
 			expr  apisix.Var
 			name  string
@@ -224,6 +399,486 @@ func (adaptor *adaptor) getHeadersMatchVars(route *routev3.Route) ([]*apisix.Var
 	return vars, false
 }
 
+// getMethods translates the ":method" header matchers to the APISIX route
+// methods, only the non-inverted exact and regex (alternation of methods,
+// like "^(GET|POST)$") matchers are supported, others are still translated
+// to vars by getHeadersMatchVars.
+func (adaptor *adaptor) getMethods(route *routev3.Route) ([]string, bool) {
+	var methods []string
+	if route.GetMatch().GetConnectMatcher() != nil {
+		methods = []string{"CONNECT"}
+	}
+	for _, header := range route.GetMatch().GetHeaders() {
+		values := getMethodMatchValues(header)
+		if values == nil {
+			continue
+		}
+		if methods == nil {
+			methods = values
+			continue
+		}
+		// All header matchers should be matched, so just keep the
+		// intersection.
+		var intersection []string
+		for _, m := range methods {
+			for _, v := range values {
+				if m == v {
+					intersection = append(intersection, m)
+					break
+				}
+			}
+		}
+		if len(intersection) == 0 {
+			adaptor.logger.Warnw("ignore route with conflicting method matchers",
+				zap.Any("route", route),
+			)
+			return nil, true
+		}
+		methods = intersection
+	}
+	return methods, false
+}
+
+// isConnectTerminated reports whether the route terminates the CONNECT
+// requests (i.e. the CONNECT upgrade has the connect_config), instead of
+// forwarding them to the upstream.
+func isConnectTerminated(route *routev3.Route) bool {
+	for _, uc := range route.GetRoute().GetUpgradeConfigs() {
+		if strings.EqualFold(uc.GetUpgradeType(), "CONNECT") && uc.GetConnectConfig() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getMethodMatchValues returns the HTTP methods that the header matcher
+// accepts, nil will be returned if the matcher is not a ":method" one or
+// it cannot be represented as a list of methods.
+func getMethodMatchValues(header *routev3.HeaderMatcher) []string {
+	if header.GetName() != ":method" || header.GetInvertMatch() {
+		return nil
+	}
+	var candidates []string
+	switch spec := header.GetHeaderMatchSpecifier().(type) {
+	case *routev3.HeaderMatcher_ExactMatch:
+		candidates = []string{spec.ExactMatch}
+	case *routev3.HeaderMatcher_SafeRegexMatch:
+		regex := spec.SafeRegexMatch.GetRegex()
+		regex = strings.TrimSuffix(strings.TrimPrefix(regex, "^"), "$")
+		if strings.HasPrefix(regex, "(") && strings.HasSuffix(regex, ")") {
+			regex = strings.TrimPrefix(regex[1:len(regex)-1], "?:")
+		}
+		candidates = strings.Split(regex, "|")
+	default:
+		return nil
+	}
+	methods := set.StringSet{}
+	var values []string
+	for _, c := range candidates {
+		if _, ok := _httpMethods[c]; !ok {
+			return nil
+		}
+		if _, ok := methods[c]; !ok {
+			methods.Add(c)
+			values = append(values, c)
+		}
+	}
+	return values
+}
+
+// getHosts translates the ":authority" header matchers to the APISIX route
+// hosts, and intersects them with the hosts from the virtual host domains
+// (empty means any host). Only the non-inverted exact matchers (without port)
+// and the suffix matchers starting with "." (i.e. wildcard hosts) are supported,
+// others are still translated to vars by getHeadersMatchVars.
+func (adaptor *adaptor) getHosts(route *routev3.Route, vhostHosts []string) ([]string, bool) {
+	hosts := vhostHosts
+	for _, header := range route.GetMatch().GetHeaders() {
+		host := getAuthorityMatchHost(header)
+		if host == "" {
+			continue
+		}
+		if len(hosts) == 0 {
+			hosts = []string{host}
+			continue
+		}
+		intersection := intersectHosts(hosts, host)
+		if len(intersection) == 0 {
+			adaptor.logger.Warnw("ignore route with authority matchers conflicting with the virtual host domains",
+				zap.Any("route", route),
+				zap.Strings("hosts", hosts),
+				zap.String("authority", host),
+			)
+			return nil, true
+		}
+		hosts = intersection
+	}
+	return hosts, false
+}
+
+// getAuthorityMatchHost returns the host (maybe a wildcard one) that the
+// header matcher accepts, empty string will be returned if the matcher is not
+// a ":authority" one or it cannot be represented as an APISIX route host.
+func getAuthorityMatchHost(header *routev3.HeaderMatcher) string {
+	if header.GetName() != ":authority" || header.GetInvertMatch() {
+		return ""
+	}
+	var host string
+	switch spec := header.GetHeaderMatchSpecifier().(type) {
+	case *routev3.HeaderMatcher_ExactMatch:
+		host = spec.ExactMatch
+	case *routev3.HeaderMatcher_SuffixMatch:
+		if !strings.HasPrefix(spec.SuffixMatch, ".") {
+			return ""
+		}
+		host = "*" + spec.SuffixMatch
+	default:
+		return ""
+	}
+	// The port is not a part of APISIX route hosts.
+	if !_routeHostRegex.MatchString(host) {
+		return ""
+	}
+	return host
+}
+
+// intersectHosts returns the hosts which are matched by both one of hosts
+// and host, the more specific one is kept if a wildcard host matches another.
+func intersectHosts(hosts []string, host string) []string {
+	var intersection []string
+	seen := set.StringSet{}
+	for _, h := range hosts {
+		var matched string
+		if h == host || matchWildcardHost(h, host) {
+			matched = host
+		} else if matchWildcardHost(host, h) {
+			matched = h
+		} else {
+			continue
+		}
+		if _, ok := seen[matched]; !ok {
+			seen.Add(matched)
+			intersection = append(intersection, matched)
+		}
+	}
+	return intersection
+}
+
+// matchWildcardHost checks whether the wildcard host pattern (like
+// "*.apache.org") matches the host.
+func matchWildcardHost(pattern, host string) bool {
+	if !strings.HasPrefix(pattern, "*") {
+		return false
+	}
+	return len(host) > len(pattern)-1 && strings.HasSuffix(host, pattern[1:])
+}
+
+func (adaptor *adaptor) CollectClusterHashPolicies(r *routev3.RouteConfiguration) map[string]*HashPolicy {
+	policies := make(map[string]*HashPolicy)
+	for _, vhost := range r.GetVirtualHosts() {
+		for _, route := range vhost.GetRoutes() {
+			action := route.GetRoute()
+			if action == nil || len(action.GetHashPolicy()) == 0 {
+				continue
+			}
+			cluster, ok := action.GetClusterSpecifier().(*routev3.RouteAction_Cluster)
+			if !ok {
+				continue
+			}
+			hp := adaptor.translateHashPolicy(action.GetHashPolicy())
+			if hp == nil {
+				continue
+			}
+			if old, ok := policies[cluster.Cluster]; ok {
+				if *old != *hp {
+					// Apache APISIX configures the hash policy on upstream,
+					// so routes which share the same cluster cannot have
+					// different hash policies.
+					adaptor.logger.Warnw("ignore conflicting hash policy for the same cluster",
+						zap.String("cluster", cluster.Cluster),
+						zap.String("route", route.GetName()),
+						zap.Any("hash_policy", hp),
+						zap.Any("effective_hash_policy", old),
+					)
+				}
+				continue
+			}
+			policies[cluster.Cluster] = hp
+		}
+	}
+	return policies
+}
+
+// translateHashPolicy picks the effective hash policy since APISIX only
+// supports a single hash key. Envoy computes hashes in the policy order and
+// stops at the first terminal policy which generates a hash, so the first
+// terminal policy is chosen, or the last one if none of them is terminal.
+// Policies which cannot be translated are ignored.
+func (adaptor *adaptor) translateHashPolicy(policies []*routev3.RouteAction_HashPolicy) *HashPolicy {
+	var effective *HashPolicy
+	for _, policy := range policies {
+		var hp HashPolicy
+		switch spec := policy.GetPolicySpecifier().(type) {
+		case *routev3.RouteAction_HashPolicy_Header_:
+			hp.HashOn = "header"
+			hp.Key = strings.ToLower(spec.Header.GetHeaderName())
+		case *routev3.RouteAction_HashPolicy_Cookie_:
+			hp.HashOn = "cookie"
+			hp.Key = spec.Cookie.GetName()
+		case *routev3.RouteAction_HashPolicy_QueryParameter_:
+			hp.HashOn = "vars"
+			hp.Key = "arg_" + spec.QueryParameter.GetName()
+		case *routev3.RouteAction_HashPolicy_ConnectionProperties_:
+			if !spec.ConnectionProperties.GetSourceIp() {
+				continue
+			}
+			hp.HashOn = "vars"
+			hp.Key = "remote_addr"
+		default:
+			adaptor.logger.Warnw("ignore unsupported hash policy",
+				zap.Any("hash_policy", policy),
+			)
+			continue
+		}
+		effective = &hp
+		if policy.GetTerminal() {
+			break
+		}
+	}
+	return effective
+}
+
+// CollectClusterRetryPolicies collects the retry policies of routes, the
+// retry_policy of a route takes precedence over the one of its virtual host,
+// like Envoy does.
+func (adaptor *adaptor) CollectClusterRetryPolicies(r *routev3.RouteConfiguration) map[string]*RetryPolicy {
+	policies := make(map[string]*RetryPolicy)
+	for _, vhost := range r.GetVirtualHosts() {
+		for _, route := range vhost.GetRoutes() {
+			action := route.GetRoute()
+			if action == nil {
+				continue
+			}
+			cluster, ok := action.GetClusterSpecifier().(*routev3.RouteAction_Cluster)
+			if !ok {
+				continue
+			}
+			policy := action.GetRetryPolicy()
+			if policy == nil {
+				policy = vhost.GetRetryPolicy()
+			}
+			if policy == nil {
+				continue
+			}
+			rp := adaptor.translateRetryPolicy(route, policy)
+			if rp == nil {
+				continue
+			}
+			if old, ok := policies[cluster.Cluster]; ok {
+				if *old != *rp {
+					// Apache APISIX configures the retries on upstream,
+					// so routes which share the same cluster cannot have
+					// different retry policies.
+					adaptor.logger.Warnw("ignore conflicting retry policy for the same cluster",
+						zap.String("cluster", cluster.Cluster),
+						zap.String("route", route.GetName()),
+						zap.Any("retry_policy", rp),
+						zap.Any("effective_retry_policy", old),
+					)
+				}
+				continue
+			}
+			policies[cluster.Cluster] = rp
+		}
+	}
+	return policies
+}
+
+// translateRetryPolicy translates the retry policy. Apache APISIX retries
+// on the connection failures and timeouts only, so policies without any
+// of the retry_on conditions covering them are ignored. The per_try_timeout
+// is translated to the retry_timeout, which bounds the time of retries.
+func (adaptor *adaptor) translateRetryPolicy(route *routev3.Route, policy *routev3.RetryPolicy) *RetryPolicy {
+	var retriable bool
+	for _, cond := range strings.Split(policy.GetRetryOn(), ",") {
+		if _, ok := _retriableConditions[strings.TrimSpace(cond)]; ok {
+			retriable = true
+			break
+		}
+	}
+	if !retriable {
+		adaptor.logger.Warnw("ignore retry policy without conditions supported by Apache APISIX",
+			zap.String("route", route.GetName()),
+			zap.String("retry_on", policy.GetRetryOn()),
+		)
+		return nil
+	}
+	// Envoy retries once if the num_retries is absent.
+	rp := &RetryPolicy{
+		Retries: 1,
+	}
+	if policy.GetNumRetries() != nil {
+		rp.Retries = int32(policy.GetNumRetries().GetValue())
+	}
+	if policy.GetPerTryTimeout() != nil {
+		rp.RetryTimeout = policy.GetPerTryTimeout().AsDuration().Seconds()
+	}
+	return rp
+}
+
+// getSourceHash returns the hash of the xDS resource, it's marshalled
+// deterministically so the hash is stable across translations, and it
+// only changes when the resource changes.
+func getSourceHash(res proto.Message) string {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(res)
+	if err != nil {
+		return ""
+	}
+	return id.GenID(string(data))
+}
+
+// patchRouteWithMaintenance uses the fault-injection plugin to abort the
+// requests if the route is under maintenance, see MaintenanceMetadataKey
+// for the details.
+func (adaptor *adaptor) patchRouteWithMaintenance(route *routev3.Route, r *apisix.Route) {
+	md, ok := route.GetMetadata().GetFilterMetadata()[MaintenanceMetadataKey]
+	if !ok || !md.GetFields()["enabled"].GetBoolValue() {
+		return
+	}
+	abort := &apisix.FaultInjection_Abort{
+		HttpStatus: _defaultMaintenanceStatus,
+		Body:       md.GetFields()["body"].GetStringValue(),
+	}
+	if status := int32(md.GetFields()["status"].GetNumberValue()); status >= 200 && status <= 599 {
+		abort.HttpStatus = status
+	}
+	if r.Plugins == nil {
+		r.Plugins = &apisix.Plugins{}
+	}
+	r.Plugins.FaultInjection = &apisix.FaultInjection{
+		Abort: abort,
+	}
+	adaptor.logger.Infow("route is under maintenance",
+		zap.String("route", r.Name),
+		zap.Int32("status", abort.HttpStatus),
+	)
+}
+
+// patchRouteWithInternalRedirect marks the route with the LabelUnsupported
+// label if it follows redirects internally, since Apache APISIX has no such
+// capability, the 3xx responses are passed to the client as is, requests
+// without redirection are not affected.
+func (adaptor *adaptor) patchRouteWithInternalRedirect(route *routev3.Route, r *apisix.Route) {
+	action := route.GetRoute()
+	if action.GetInternalRedirectPolicy() == nil &&
+		action.GetInternalRedirectAction() != routev3.RouteAction_HANDLE_INTERNAL_REDIRECT {
+		return
+	}
+	adaptor.logger.Warnw("internal redirect policy of route is not supported, redirects are passed to the client",
+		zap.String("route", r.Name),
+		zap.Any("internal_redirect_policy", action.GetInternalRedirectPolicy()),
+	)
+	if r.Labels == nil {
+		r.Labels = make(map[string]string)
+	}
+	r.Labels[LabelUnsupported] = "internal_redirect"
+}
+
+// patchRouteWithTimeout bounds the total request time of the route by its
+// timeout and max_stream_duration, the shorter one (which is not zero) wins.
+// Apache APISIX has no total timeout, so the bound is applied to connecting,
+// sending and reading respectively, which is the closest approximation.
+// Routes without an explicit timeout are not affected, so that the timeout
+// settings of upstreams still take effect.
+func (adaptor *adaptor) patchRouteWithTimeout(route *routev3.Route, r *apisix.Route) {
+	action := route.GetRoute()
+	var bound time.Duration
+	if action.GetTimeout() != nil {
+		bound = action.GetTimeout().AsDuration()
+	}
+	if msd := action.GetMaxStreamDuration(); msd != nil {
+		if msd.GetMaxStreamDuration() != nil {
+			if d := msd.GetMaxStreamDuration().AsDuration(); d > 0 && (bound <= 0 || d < bound) {
+				bound = d
+			}
+		}
+		if msd.GetGrpcTimeoutHeaderMax() != nil || msd.GetGrpcTimeoutHeaderOffset() != nil {
+			adaptor.logger.Warnw("grpc-timeout header of route is not supported and ignored",
+				zap.String("route", r.Name),
+				zap.Any("max_stream_duration", msd),
+			)
+		}
+	}
+	if bound <= 0 {
+		return
+	}
+	r.Timeout = &apisix.Route_Timeout{
+		Connect: bound.Seconds(),
+		Send:    bound.Seconds(),
+		Read:    bound.Seconds(),
+	}
+}
+
+// patchRouteWithWeightedClusters uses the traffic-split plugin to split the
+// traffic to the weighted clusters. Weights in the traffic-split plugin are
+// relative, so they're normalized against the total_weight (100 by default)
+// by dividing their greatest common divisor, e.g. 300 and 700 (with the
+// total_weight 1000) are translated to 3 and 7.
+func (adaptor *adaptor) patchRouteWithWeightedClusters(route *routev3.Route, r *apisix.Route, tenant string) {
+	wc := route.GetRoute().GetWeightedClusters()
+	if wc == nil {
+		return
+	}
+	total := uint32(100)
+	if wc.GetTotalWeight() != nil {
+		total = wc.GetTotalWeight().GetValue()
+	}
+	var sum uint32
+	divisor := total
+	for _, c := range wc.GetClusters() {
+		sum += c.GetWeight().GetValue()
+		divisor = gcd(divisor, c.GetWeight().GetValue())
+	}
+	if sum != total {
+		adaptor.logger.Warnw("weights of clusters don't sum up to the total weight",
+			zap.String("route", r.Name),
+			zap.Uint32("total_weight", total),
+			zap.Uint32("sum", sum),
+		)
+	}
+	if divisor == 0 {
+		divisor = 1
+	}
+	if prefix := wc.GetRuntimeKeyPrefix(); prefix != "" {
+		// Apache APISIX cannot consult the Envoy runtime, the static
+		// weights are used as the baseline.
+		adaptor.logger.Warnw("runtime overrides of cluster weights are not supported, static weights are used",
+			zap.String("route", r.Name),
+			zap.String("runtime_key_prefix", prefix),
+		)
+	}
+	rule := &apisix.TrafficSplit_Rule{}
+	for _, c := range wc.GetClusters() {
+		rule.WeightedUpstreams = append(rule.WeightedUpstreams, &apisix.TrafficSplit_WeightedUpstream{
+			UpstreamId: id.GenID(TenantScopedName(tenant, c.GetName())),
+			Weight:     int32(c.GetWeight().GetValue() / divisor),
+		})
+	}
+	if r.Plugins == nil {
+		r.Plugins = &apisix.Plugins{}
+	}
+	r.Plugins.TrafficSplit = &apisix.TrafficSplit{
+		Rules: []*apisix.TrafficSplit_Rule{rule},
+	}
+}
+
+func gcd(a, b uint32) uint32 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
 func getStringMatchValue(matcher *matcherv3.StringMatcher) string {
 	pattern := matcher.MatchPattern
 	switch pat := pattern.(type) {
@@ -1,13 +1,20 @@
 package v3
 
 import (
+	"bytes"
 	"sort"
+	"strings"
 	"testing"
 
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/types/known/structpb"
 
+	apisixutil "github.com/api7/apisix-mesh-agent/pkg/apisix"
 	"github.com/api7/apisix-mesh-agent/pkg/id"
 	"github.com/api7/apisix-mesh-agent/pkg/log"
 	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
@@ -68,7 +75,7 @@ func TestGetHeadersMatchVars(t *testing.T) {
 				{
 					Name: ":authority",
 					HeaderMatchSpecifier: &routev3.HeaderMatcher_ExactMatch{
-						ExactMatch: "apisix.apache.org",
+						ExactMatch: "apisix.apache.org:8080",
 					},
 				},
 				{
@@ -102,7 +109,7 @@ func TestGetHeadersMatchVars(t *testing.T) {
 		Vars: []string{"request_method", "~~", "POST"},
 	})
 	assert.Equal(t, vars[1], &apisix.Var{
-		Vars: []string{"http_host", "~~", "^apisix.apache.org$"},
+		Vars: []string{"http_host", "~~", "^apisix.apache.org:8080$"},
 	})
 	assert.Equal(t, vars[2], &apisix.Var{
 		Vars: []string{"http_accept_ranges", "!", "~~", "^bytes"},
@@ -115,6 +122,133 @@ func TestGetHeadersMatchVars(t *testing.T) {
 	})
 }
 
+func TestGetHosts(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	route := &routev3.Route{
+		Match: &routev3.RouteMatch{
+			Headers: []*routev3.HeaderMatcher{
+				{
+					Name: ":authority",
+					HeaderMatchSpecifier: &routev3.HeaderMatcher_SuffixMatch{
+						SuffixMatch: ".apache.org",
+					},
+				},
+			},
+		},
+	}
+	hosts, skip := a.getHosts(route, nil)
+	assert.Equal(t, skip, false)
+	assert.Equal(t, hosts, []string{"*.apache.org"})
+
+	hosts, skip = a.getHosts(route, []string{"apisix.apache.org", "*.org", "api7.ai"})
+	assert.Equal(t, skip, false)
+	assert.Equal(t, hosts, []string{"apisix.apache.org", "*.apache.org"})
+
+	route.Match.Headers = append(route.Match.Headers, &routev3.HeaderMatcher{
+		Name: ":authority",
+		HeaderMatchSpecifier: &routev3.HeaderMatcher_ExactMatch{
+			ExactMatch: "apisix.apache.org",
+		},
+	})
+	hosts, skip = a.getHosts(route, []string{"*.org"})
+	assert.Equal(t, skip, false)
+	assert.Equal(t, hosts, []string{"apisix.apache.org"})
+
+	hosts, skip = a.getHosts(route, []string{"api7.ai"})
+	assert.Equal(t, skip, true)
+	assert.Nil(t, hosts)
+
+	// Inverted matchers and matchers with port are kept as vars.
+	route.Match.Headers = []*routev3.HeaderMatcher{
+		{
+			Name: ":authority",
+			HeaderMatchSpecifier: &routev3.HeaderMatcher_ExactMatch{
+				ExactMatch: "apisix.apache.org",
+			},
+			InvertMatch: true,
+		},
+		{
+			Name: ":authority",
+			HeaderMatchSpecifier: &routev3.HeaderMatcher_ExactMatch{
+				ExactMatch: "apisix.apache.org:8080",
+			},
+		},
+	}
+	hosts, skip = a.getHosts(route, []string{"api7.ai"})
+	assert.Equal(t, skip, false)
+	assert.Equal(t, hosts, []string{"api7.ai"})
+	vars, skip := a.getHeadersMatchVars(route)
+	assert.Equal(t, skip, false)
+	assert.Len(t, vars, 2)
+}
+
+func TestGetMethods(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+
+	route := &routev3.Route{
+		Match: &routev3.RouteMatch{
+			Headers: []*routev3.HeaderMatcher{
+				{
+					Name: ":method",
+					HeaderMatchSpecifier: &routev3.HeaderMatcher_SafeRegexMatch{
+						SafeRegexMatch: &matcherv3.RegexMatcher{
+							Regex: "^(GET|POST|PUT|GET)$",
+						},
+					},
+				},
+				{
+					Name: ":method",
+					HeaderMatchSpecifier: &routev3.HeaderMatcher_SafeRegexMatch{
+						SafeRegexMatch: &matcherv3.RegexMatcher{
+							Regex: "PUT|GET",
+						},
+					},
+				},
+				{
+					Name: ":method",
+					HeaderMatchSpecifier: &routev3.HeaderMatcher_ExactMatch{
+						ExactMatch: "DELETE",
+					},
+					InvertMatch: true,
+				},
+				{
+					Name: ":method",
+					HeaderMatchSpecifier: &routev3.HeaderMatcher_SafeRegexMatch{
+						SafeRegexMatch: &matcherv3.RegexMatcher{
+							Regex: "P.*",
+						},
+					},
+				},
+			},
+		},
+	}
+	methods, skip := a.getMethods(route)
+	assert.Equal(t, skip, false)
+	assert.Equal(t, methods, []string{"GET", "PUT"})
+
+	// Untranslatable method matchers are still kept as vars.
+	vars, skip := a.getHeadersMatchVars(route)
+	assert.Equal(t, skip, false)
+	assert.Equal(t, vars, []*apisix.Var{
+		{
+			Vars: []string{"request_method", "!", "~~", "^DELETE$"},
+		},
+		{
+			Vars: []string{"request_method", "~~", "P.*"},
+		},
+	})
+
+	route.Match.Headers = append(route.Match.Headers, &routev3.HeaderMatcher{
+		Name: ":method",
+		HeaderMatchSpecifier: &routev3.HeaderMatcher_ExactMatch{
+			ExactMatch: "HEAD",
+		},
+	})
+	_, skip = a.getMethods(route)
+	assert.Equal(t, skip, true)
+}
+
 func TestGetParametersMatchVars(t *testing.T) {
 	a := &adaptor{logger: log.DefaultLogger}
 
@@ -203,6 +337,62 @@ func TestGetURL(t *testing.T) {
 	assert.Equal(t, skip, true)
 }
 
+func TestTranslateConnectRoute(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	route := &routev3.Route{
+		Name: "tunnel",
+		Match: &routev3.RouteMatch{
+			PathSpecifier: &routev3.RouteMatch_ConnectMatcher_{
+				ConnectMatcher: &routev3.RouteMatch_ConnectMatcher{},
+			},
+		},
+		Action: &routev3.Route_Route{
+			Route: &routev3.RouteAction{
+				ClusterSpecifier: &routev3.RouteAction_Cluster{
+					Cluster: "proxy.default.svc.cluster.local",
+				},
+				UpgradeConfigs: []*routev3.RouteAction_UpgradeConfig{
+					{
+						UpgradeType: "CONNECT",
+					},
+				},
+			},
+		},
+	}
+	vhost := &routev3.VirtualHost{
+		Name:    "vhost1",
+		Domains: []string{"*"},
+		Routes:  []*routev3.Route{route},
+	}
+	routes, err := a.translateVirtualHost("rc1", vhost, nil, nil)
+	assert.Nil(t, err)
+	assert.Len(t, routes, 1)
+	assert.Equal(t, routes[0].Uris, []string{"/*"})
+	assert.Equal(t, routes[0].Methods, []string{"CONNECT"})
+	assert.Equal(t, routes[0].UpstreamId, id.GenID("proxy.default.svc.cluster.local"))
+
+	// Conflicting with the method matchers.
+	route.Match.Headers = []*routev3.HeaderMatcher{
+		{
+			Name: ":method",
+			HeaderMatchSpecifier: &routev3.HeaderMatcher_ExactMatch{
+				ExactMatch: "GET",
+			},
+		},
+	}
+	_, skip := a.getMethods(route)
+	assert.Equal(t, skip, true)
+	route.Match.Headers = nil
+
+	// CONNECT requests are terminated by Envoy.
+	route.GetRoute().UpgradeConfigs[0].ConnectConfig = &routev3.RouteAction_UpgradeConfig_ConnectConfig{}
+	_, skip = a.getURL(route)
+	assert.Equal(t, skip, true)
+	routes, err = a.translateVirtualHost("rc1", vhost, nil, nil)
+	assert.Nil(t, err)
+	assert.Len(t, routes, 0)
+}
+
 func TestGetClusterName(t *testing.T) {
 	a := &adaptor{logger: log.DefaultLogger}
 	route := &routev3.Route{
@@ -301,7 +491,7 @@ func TestTranslateVirtualHost(t *testing.T) {
 			},
 		},
 	}
-	routes, err := a.translateVirtualHost("test", vhost, nil)
+	routes, err := a.translateVirtualHost("test", vhost, nil, nil)
 	assert.Nil(t, err)
 	assert.Len(t, routes, 1)
 	assert.Equal(t, routes[0].Name, "route1#test#test")
@@ -322,6 +512,15 @@ func TestTranslateVirtualHost(t *testing.T) {
 			Vars: []string{"request_method", "~~", "POST"},
 		},
 	})
+
+	routes, err = a.translateVirtualHost("test", vhost, &TranslateOptions{
+		Tenant: "tenant-a",
+	}, nil)
+	assert.Nil(t, err)
+	assert.Len(t, routes, 1)
+	assert.Equal(t, routes[0].Name, "tenant-a/route1#test#test")
+	assert.Equal(t, routes[0].Id, id.GenID(routes[0].Name))
+	assert.Equal(t, routes[0].UpstreamId, id.GenID("tenant-a/kubernetes.default.svc.cluster.local"))
 }
 
 func TestPatchRoutesWithOriginalDestination(t *testing.T) {
@@ -342,3 +541,481 @@ func TestPatchRoutesWithOriginalDestination(t *testing.T) {
 		},
 	})
 }
+
+func TestTranslateHashPolicy(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	header := &routev3.RouteAction_HashPolicy{
+		PolicySpecifier: &routev3.RouteAction_HashPolicy_Header_{
+			Header: &routev3.RouteAction_HashPolicy_Header{
+				HeaderName: "X-User",
+			},
+		},
+	}
+	cookie := &routev3.RouteAction_HashPolicy{
+		PolicySpecifier: &routev3.RouteAction_HashPolicy_Cookie_{
+			Cookie: &routev3.RouteAction_HashPolicy_Cookie{
+				Name: "session",
+			},
+		},
+		Terminal: true,
+	}
+	query := &routev3.RouteAction_HashPolicy{
+		PolicySpecifier: &routev3.RouteAction_HashPolicy_QueryParameter_{
+			QueryParameter: &routev3.RouteAction_HashPolicy_QueryParameter{
+				Name: "id",
+			},
+		},
+	}
+	sourceIP := &routev3.RouteAction_HashPolicy{
+		PolicySpecifier: &routev3.RouteAction_HashPolicy_ConnectionProperties_{
+			ConnectionProperties: &routev3.RouteAction_HashPolicy_ConnectionProperties{
+				SourceIp: true,
+			},
+		},
+		Terminal: true,
+	}
+	filterState := &routev3.RouteAction_HashPolicy{
+		PolicySpecifier: &routev3.RouteAction_HashPolicy_FilterState_{
+			FilterState: &routev3.RouteAction_HashPolicy_FilterState{
+				Key: "foo",
+			},
+		},
+		Terminal: true,
+	}
+
+	// The first terminal policy wins.
+	hp := a.translateHashPolicy([]*routev3.RouteAction_HashPolicy{header, cookie, sourceIP, query})
+	assert.Equal(t, hp, &HashPolicy{HashOn: "cookie", Key: "session"})
+
+	// The last policy wins if none of them is terminal.
+	hp = a.translateHashPolicy([]*routev3.RouteAction_HashPolicy{query, header})
+	assert.Equal(t, hp, &HashPolicy{HashOn: "header", Key: "x-user"})
+
+	// Untranslatable terminal policy is ignored.
+	hp = a.translateHashPolicy([]*routev3.RouteAction_HashPolicy{query, filterState})
+	assert.Equal(t, hp, &HashPolicy{HashOn: "vars", Key: "arg_id"})
+
+	hp = a.translateHashPolicy([]*routev3.RouteAction_HashPolicy{sourceIP, header})
+	assert.Equal(t, hp, &HashPolicy{HashOn: "vars", Key: "remote_addr"})
+
+	assert.Nil(t, a.translateHashPolicy([]*routev3.RouteAction_HashPolicy{filterState}))
+}
+
+func TestCollectClusterHashPolicies(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	rc := &routev3.RouteConfiguration{
+		Name: "rc1",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name: "vhost1",
+				Routes: []*routev3.Route{
+					{
+						Name: "route1",
+						Action: &routev3.Route_Route{
+							Route: &routev3.RouteAction{
+								ClusterSpecifier: &routev3.RouteAction_Cluster{
+									Cluster: "httpbin",
+								},
+								HashPolicy: []*routev3.RouteAction_HashPolicy{
+									{
+										PolicySpecifier: &routev3.RouteAction_HashPolicy_Header_{
+											Header: &routev3.RouteAction_HashPolicy_Header{
+												HeaderName: "x-user",
+											},
+										},
+										Terminal: true,
+									},
+									{
+										PolicySpecifier: &routev3.RouteAction_HashPolicy_Cookie_{
+											Cookie: &routev3.RouteAction_HashPolicy_Cookie{
+												Name: "session",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					{
+						Name: "route2",
+						Action: &routev3.Route_Route{
+							Route: &routev3.RouteAction{
+								ClusterSpecifier: &routev3.RouteAction_Cluster{
+									Cluster: "httpbin",
+								},
+								HashPolicy: []*routev3.RouteAction_HashPolicy{
+									{
+										PolicySpecifier: &routev3.RouteAction_HashPolicy_Cookie_{
+											Cookie: &routev3.RouteAction_HashPolicy_Cookie{
+												Name: "session",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					{
+						Name: "route3",
+						Action: &routev3.Route_Route{
+							Route: &routev3.RouteAction{
+								ClusterSpecifier: &routev3.RouteAction_Cluster{
+									Cluster: "nginx",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	policies := a.CollectClusterHashPolicies(rc)
+	assert.Len(t, policies, 1)
+	assert.Equal(t, policies["httpbin"], &HashPolicy{HashOn: "header", Key: "x-user"})
+
+	ups := &apisix.Upstream{Type: "roundrobin"}
+	assert.Equal(t, policies["httpbin"].PatchUpstream(ups), false)
+	assert.Equal(t, ups.Key, "")
+	ups.Type = "chash"
+	assert.Equal(t, policies["httpbin"].PatchUpstream(ups), true)
+	assert.Equal(t, ups.HashOn, "header")
+	assert.Equal(t, ups.Key, "x-user")
+}
+
+func TestCollectClusterRetryPolicies(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	newRoute := func(name, cluster string, policy *routev3.RetryPolicy) *routev3.Route {
+		return &routev3.Route{
+			Name: name,
+			Action: &routev3.Route_Route{
+				Route: &routev3.RouteAction{
+					ClusterSpecifier: &routev3.RouteAction_Cluster{
+						Cluster: cluster,
+					},
+					RetryPolicy: policy,
+				},
+			},
+		}
+	}
+	rc := &routev3.RouteConfiguration{
+		Name: "rc1",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name: "vhost1",
+				RetryPolicy: &routev3.RetryPolicy{
+					RetryOn:    "connect-failure",
+					NumRetries: &wrappers.UInt32Value{Value: 5},
+				},
+				Routes: []*routev3.Route{
+					// The route wins.
+					newRoute("route1", "httpbin", &routev3.RetryPolicy{
+						RetryOn:       "5xx,retriable-4xx",
+						NumRetries:    &wrappers.UInt32Value{Value: 3},
+						PerTryTimeout: &duration.Duration{Seconds: 2},
+					}),
+					// Conflicting with route1.
+					newRoute("route2", "httpbin", &routev3.RetryPolicy{
+						RetryOn: "reset",
+					}),
+					// Inherited from the virtual host.
+					newRoute("route3", "nginx", nil),
+					// APISIX doesn't retry on 4xx.
+					newRoute("route4", "redis", &routev3.RetryPolicy{
+						RetryOn: "retriable-4xx",
+					}),
+				},
+			},
+			{
+				Name: "vhost2",
+				Routes: []*routev3.Route{
+					newRoute("route5", "mysql", &routev3.RetryPolicy{
+						RetryOn: "gateway-error",
+					}),
+					newRoute("route6", "mongo", nil),
+				},
+			},
+		},
+	}
+	policies := a.CollectClusterRetryPolicies(rc)
+	assert.Equal(t, policies, map[string]*RetryPolicy{
+		"httpbin": {Retries: 3, RetryTimeout: 2},
+		"nginx":   {Retries: 5},
+		"mysql":   {Retries: 1},
+	})
+
+	ups := &apisix.Upstream{}
+	policies["httpbin"].PatchUpstream(ups)
+	assert.Equal(t, ups.Retries, int32(3))
+	assert.Equal(t, ups.RetryTimeout, float64(2))
+}
+
+func TestPatchRouteWithMaintenance(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	route := &routev3.Route{
+		Name: "route1",
+	}
+	r := &apisix.Route{}
+	a.patchRouteWithMaintenance(route, r)
+	assert.Nil(t, r.Plugins)
+
+	md, err := structpb.NewStruct(map[string]interface{}{
+		"enabled": false,
+	})
+	assert.Nil(t, err)
+	route.Metadata = &corev3.Metadata{
+		FilterMetadata: map[string]*structpb.Struct{
+			MaintenanceMetadataKey: md,
+		},
+	}
+	a.patchRouteWithMaintenance(route, r)
+	assert.Nil(t, r.Plugins)
+
+	md.Fields["enabled"] = structpb.NewBoolValue(true)
+	a.patchRouteWithMaintenance(route, r)
+	assert.Equal(t, r.Plugins.FaultInjection.Abort.HttpStatus, int32(503))
+	assert.Equal(t, r.Plugins.FaultInjection.Abort.Body, "")
+
+	md.Fields["status"] = structpb.NewNumberValue(502)
+	md.Fields["body"] = structpb.NewStringValue("under maintenance")
+	a.patchRouteWithMaintenance(route, r)
+	assert.Equal(t, r.Plugins.FaultInjection.Abort.HttpStatus, int32(502))
+	assert.Equal(t, r.Plugins.FaultInjection.Abort.Body, "under maintenance")
+}
+
+func TestPatchRouteWithInternalRedirect(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	route := &routev3.Route{
+		Name: "route1",
+		Action: &routev3.Route_Route{
+			Route: &routev3.RouteAction{
+				ClusterSpecifier: &routev3.RouteAction_Cluster{
+					Cluster: "httpbin",
+				},
+			},
+		},
+	}
+	r := &apisix.Route{}
+	a.patchRouteWithInternalRedirect(route, r)
+	assert.Nil(t, r.Labels)
+
+	route.GetRoute().InternalRedirectPolicy = &routev3.InternalRedirectPolicy{
+		MaxInternalRedirects: &wrappers.UInt32Value{Value: 3},
+	}
+	a.patchRouteWithInternalRedirect(route, r)
+	assert.Equal(t, r.Labels, map[string]string{
+		LabelUnsupported: "internal_redirect",
+	})
+}
+
+func TestPatchRouteWithTimeout(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	route := &routev3.Route{
+		Name: "route1",
+		Action: &routev3.Route_Route{
+			Route: &routev3.RouteAction{
+				ClusterSpecifier: &routev3.RouteAction_Cluster{
+					Cluster: "httpbin",
+				},
+			},
+		},
+	}
+	r := &apisix.Route{}
+	a.patchRouteWithTimeout(route, r)
+	assert.Nil(t, r.Timeout)
+
+	route.GetRoute().Timeout = &duration.Duration{Seconds: 10}
+	a.patchRouteWithTimeout(route, r)
+	assert.Equal(t, r.Timeout, &apisix.Route_Timeout{Connect: 10, Send: 10, Read: 10})
+
+	// max_stream_duration is shorter than the timeout.
+	route.GetRoute().MaxStreamDuration = &routev3.RouteAction_MaxStreamDuration{
+		MaxStreamDuration: &duration.Duration{Seconds: 3},
+	}
+	a.patchRouteWithTimeout(route, r)
+	assert.Equal(t, r.Timeout, &apisix.Route_Timeout{Connect: 3, Send: 3, Read: 3})
+
+	// The timeout is disabled.
+	route.GetRoute().Timeout = &duration.Duration{}
+	route.GetRoute().MaxStreamDuration.MaxStreamDuration = &duration.Duration{Seconds: 30}
+	a.patchRouteWithTimeout(route, r)
+	assert.Equal(t, r.Timeout, &apisix.Route_Timeout{Connect: 30, Send: 30, Read: 30})
+}
+
+func TestPatchRouteWithWeightedClusters(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	route := &routev3.Route{
+		Name: "route1",
+		Action: &routev3.Route_Route{
+			Route: &routev3.RouteAction{
+				ClusterSpecifier: &routev3.RouteAction_WeightedClusters{
+					WeightedClusters: &routev3.WeightedCluster{
+						TotalWeight: &wrappers.UInt32Value{Value: 1000},
+						Clusters: []*routev3.WeightedCluster_ClusterWeight{
+							{
+								Name:   "reviews-v1",
+								Weight: &wrappers.UInt32Value{Value: 300},
+							},
+							{
+								Name:   "reviews-v2",
+								Weight: &wrappers.UInt32Value{Value: 700},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	cluster, skip := a.getClusterName(route)
+	assert.Equal(t, skip, false)
+	assert.Equal(t, cluster, "reviews-v1")
+
+	r := &apisix.Route{}
+	a.patchRouteWithWeightedClusters(route, r, "")
+	assert.Len(t, r.Plugins.TrafficSplit.Rules, 1)
+	ups := r.Plugins.TrafficSplit.Rules[0].WeightedUpstreams
+	assert.Len(t, ups, 2)
+	assert.Equal(t, ups[0].UpstreamId, id.GenID("reviews-v1"))
+	assert.Equal(t, ups[1].UpstreamId, id.GenID("reviews-v2"))
+	// 30% and 70%.
+	assert.Equal(t, float64(ups[0].Weight)/float64(ups[0].Weight+ups[1].Weight), 0.3)
+	assert.Equal(t, float64(ups[1].Weight)/float64(ups[0].Weight+ups[1].Weight), 0.7)
+
+	// The default total weight is 100, upstreams are scoped by the tenant.
+	wc := route.GetRoute().GetWeightedClusters()
+	wc.TotalWeight = nil
+	wc.Clusters[0].Weight.Value = 25
+	wc.Clusters[1].Weight.Value = 75
+	r = &apisix.Route{}
+	a.patchRouteWithWeightedClusters(route, r, "tenant-a")
+	ups = r.Plugins.TrafficSplit.Rules[0].WeightedUpstreams
+	assert.Equal(t, ups[0].UpstreamId, id.GenID("tenant-a/reviews-v1"))
+	assert.Equal(t, ups[0].Weight, int32(1))
+	assert.Equal(t, ups[1].Weight, int32(3))
+
+	// The runtime overrides are ignored, static weights are used.
+	wc.RuntimeKeyPrefix = "routing.traffic_split.reviews"
+	r = &apisix.Route{}
+	a.patchRouteWithWeightedClusters(route, r, "")
+	ups = r.Plugins.TrafficSplit.Rules[0].WeightedUpstreams
+	assert.Equal(t, ups[0].Weight, int32(1))
+	assert.Equal(t, ups[1].Weight, int32(3))
+
+	route.Action = &routev3.Route_Route{
+		Route: &routev3.RouteAction{
+			ClusterSpecifier: &routev3.RouteAction_Cluster{
+				Cluster: "reviews",
+			},
+		},
+	}
+	r = &apisix.Route{}
+	a.patchRouteWithWeightedClusters(route, r, "")
+	assert.Nil(t, r.Plugins)
+}
+
+func TestTranslateRouteConfigurationWithSourceHashLabels(t *testing.T) {
+	a := &adaptor{
+		logger:           log.DefaultLogger,
+		sourceHashLabels: true,
+	}
+	newRouteConfiguration := func(prefix string) *routev3.RouteConfiguration {
+		return &routev3.RouteConfiguration{
+			Name: "rc1",
+			VirtualHosts: []*routev3.VirtualHost{
+				{
+					Name:    "vhost1",
+					Domains: []string{"*"},
+					Routes: []*routev3.Route{
+						{
+							Name: "route1",
+							Match: &routev3.RouteMatch{
+								PathSpecifier: &routev3.RouteMatch_Prefix{
+									Prefix: prefix,
+								},
+							},
+							Action: &routev3.Route_Route{
+								Route: &routev3.RouteAction{
+									ClusterSpecifier: &routev3.RouteAction_Cluster{
+										Cluster: "httpbin",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	routes, err := a.TranslateRouteConfiguration(newRouteConfiguration("/foo"), &TranslateOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, routes, 1)
+	hash := routes[0].Labels[LabelSourceHash]
+	assert.NotEqual(t, hash, "")
+
+	// Same resources are hashed to the same value, so no updates.
+	routes2, err := a.TranslateRouteConfiguration(newRouteConfiguration("/foo"), &TranslateOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, routes2[0].Labels[LabelSourceHash], hash)
+	_, _, updated := apisixutil.CompareRoutes(routes, routes2)
+	assert.Len(t, updated, 0)
+
+	routes2, err = a.TranslateRouteConfiguration(newRouteConfiguration("/bar"), &TranslateOptions{})
+	assert.Nil(t, err)
+	assert.NotEqual(t, routes2[0].Labels[LabelSourceHash], hash)
+}
+
+func TestTranslateRouteConfigurationWithRequestMirrorPolicies(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := log.NewLogger(
+		log.WithLogLevel("warn"),
+		log.WithWriteSyncer(zapcore.AddSync(&buf)),
+	)
+	assert.Nil(t, err)
+	a := &adaptor{logger: logger}
+
+	newRouteConfiguration := func(traceSampled bool) *routev3.RouteConfiguration {
+		return &routev3.RouteConfiguration{
+			Name: "rc1",
+			VirtualHosts: []*routev3.VirtualHost{
+				{
+					Name:    "vhost1",
+					Domains: []string{"*"},
+					Routes: []*routev3.Route{
+						{
+							Name: "route1",
+							Match: &routev3.RouteMatch{
+								PathSpecifier: &routev3.RouteMatch_Prefix{
+									Prefix: "/foo",
+								},
+							},
+							Action: &routev3.Route_Route{
+								Route: &routev3.RouteAction{
+									ClusterSpecifier: &routev3.RouteAction_Cluster{
+										Cluster: "httpbin",
+									},
+									RequestMirrorPolicies: []*routev3.RouteAction_RequestMirrorPolicy{
+										{
+											Cluster:      "httpbin-shadow",
+											TraceSampled: &wrappers.BoolValue{Value: traceSampled},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	const warning = "request mirror policies of route are not supported"
+
+	routes, err := a.TranslateRouteConfiguration(newRouteConfiguration(true), &TranslateOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, routes, 1)
+	assert.Equal(t, strings.Count(buf.String(), warning), 1)
+
+	// The warning is logged on every translation, like the one of the
+	// ignored rate limits.
+	_, err = a.TranslateRouteConfiguration(newRouteConfiguration(true), &TranslateOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, strings.Count(buf.String(), warning), 2)
+}
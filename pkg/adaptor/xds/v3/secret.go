@@ -0,0 +1,173 @@
+package v3
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	apisixutil "github.com/api7/apisix-mesh-agent/pkg/apisix"
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+var (
+	errNoCertificate    = errors.New("no certificate in PEM")
+	errNoTlsCertificate = errors.New("no tls_certificate in secret")
+)
+
+// TranslateSecret translates the Secret with tls_certificate to an APISIX
+// Ssl, the SNIs are the DNS names of the certificate. Other kinds of
+// secrets (like the validation_context) cannot be translated, nil is
+// returned for them.
+func (adaptor *adaptor) TranslateSecret(s *tlsv3.Secret) (*apisix.Ssl, error) {
+	tc := s.GetTlsCertificate()
+	if tc == nil {
+		adaptor.logger.Debugw("ignore secret without tls_certificate",
+			zap.String("secret", s.GetName()),
+		)
+		return nil, nil
+	}
+	cert, key, err := readTlsCertificate(tc)
+	if err != nil {
+		if err == ErrFeatureNotSupportedYet {
+			adaptor.logger.Warnw("encrypted private key and private key provider are not supported",
+				zap.String("secret", s.GetName()),
+			)
+		}
+		return nil, err
+	}
+	snis, err := getCertificateDNSNames(cert)
+	if err != nil {
+		return nil, fmt.Errorf("certificate_chain: %s", err)
+	}
+	return &apisix.Ssl{
+		Id:   id.GenID(s.GetName()),
+		Cert: cert,
+		Key:  key,
+		Snis: snis,
+	}, nil
+}
+
+// readTlsCertificate reads the certificate chain and the private key of
+// the TlsCertificate, the encrypted private key and the private key provider
+// are not supported.
+func readTlsCertificate(tc *tlsv3.TlsCertificate) (string, string, error) {
+	if tc.GetPassword() != nil || tc.GetPrivateKeyProvider() != nil {
+		return "", "", ErrFeatureNotSupportedYet
+	}
+	cert, err := readDataSource(tc.GetCertificateChain())
+	if err != nil {
+		return "", "", fmt.Errorf("certificate_chain: %s", err)
+	}
+	key, err := readDataSource(tc.GetPrivateKey())
+	if err != nil {
+		return "", "", fmt.Errorf("private_key: %s", err)
+	}
+	return cert, key, nil
+}
+
+// PatchUpstreamWithSecret sets the TLS certificate of the SDS secret as the
+// client certificate of the upstream.
+func PatchUpstreamWithSecret(ups *apisix.Upstream, s *tlsv3.Secret) error {
+	tc := s.GetTlsCertificate()
+	if tc == nil {
+		return errNoTlsCertificate
+	}
+	cert, key, err := readTlsCertificate(tc)
+	if err != nil {
+		return err
+	}
+	ups.Tls = &apisix.Upstream_TLS{
+		ClientCert: cert,
+		ClientKey:  key,
+	}
+	return nil
+}
+
+// RedactCluster returns the cluster with the inline private keys (and
+// passwords) of its UpstreamTlsContext transport sockets replaced, so that
+// it can be logged. The cluster is cloned before changing.
+func RedactCluster(c *clusterv3.Cluster) *clusterv3.Cluster {
+	redacted := proto.Clone(c).(*clusterv3.Cluster)
+	redactTransportSocket(redacted.GetTransportSocket())
+	for _, m := range redacted.GetTransportSocketMatches() {
+		redactTransportSocket(m.GetTransportSocket())
+	}
+	return redacted
+}
+
+func redactTransportSocket(ts *corev3.TransportSocket) {
+	tc := ts.GetTypedConfig()
+	if tc == nil || tc.GetTypeUrl() != _upstreamTLSContextTypeUrl {
+		return
+	}
+	var ctx tlsv3.UpstreamTlsContext
+	if err := tc.UnmarshalTo(&ctx); err != nil {
+		// Nothing can be told from a broken config, drop it.
+		tc.Value = nil
+		return
+	}
+	for _, cert := range ctx.GetCommonTlsContext().GetTlsCertificates() {
+		cert.PrivateKey = redactDataSource(cert.GetPrivateKey())
+		cert.Password = redactDataSource(cert.GetPassword())
+	}
+	if err := anypb.MarshalFrom(tc, &ctx, proto.MarshalOptions{}); err != nil {
+		tc.Value = nil
+	}
+}
+
+// redactDataSource replaces the inline data, the file names are kept.
+func redactDataSource(ds *corev3.DataSource) *corev3.DataSource {
+	switch ds.GetSpecifier().(type) {
+	case *corev3.DataSource_InlineString, *corev3.DataSource_InlineBytes:
+		return &corev3.DataSource{
+			Specifier: &corev3.DataSource_InlineString{
+				InlineString: apisixutil.RedactedValue,
+			},
+		}
+	default:
+		return ds
+	}
+}
+
+// readDataSource reads the content of the DataSource, the file is read
+// only once, later changes of it won't be noticed.
+func readDataSource(ds *corev3.DataSource) (string, error) {
+	switch spec := ds.GetSpecifier().(type) {
+	case *corev3.DataSource_InlineString:
+		return spec.InlineString, nil
+	case *corev3.DataSource_InlineBytes:
+		return string(spec.InlineBytes), nil
+	case *corev3.DataSource_Filename:
+		data, err := ioutil.ReadFile(spec.Filename)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	default:
+		return "", errors.New("empty data source")
+	}
+}
+
+// getCertificateDNSNames returns the DNS names of the leaf certificate
+// in the PEM chain.
+func getCertificateDNSNames(chain string) ([]string, error) {
+	block, _ := pem.Decode([]byte(chain))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errNoCertificate
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return cert.DNSNames, nil
+}
@@ -0,0 +1,172 @@
+package v3
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	apisixutil "github.com/api7/apisix-mesh-agent/pkg/apisix"
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/testutil"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestTranslateSecret(t *testing.T) {
+	a := &adaptor{logger: log.DefaultLogger}
+	cert, key := testutil.NewCertificate(t, "httpbin.org", "*.httpbin.org")
+
+	ssl, err := a.TranslateSecret(&tlsv3.Secret{
+		Name: "default",
+		Type: &tlsv3.Secret_TlsCertificate{
+			TlsCertificate: &tlsv3.TlsCertificate{
+				CertificateChain: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineString{
+						InlineString: cert,
+					},
+				},
+				PrivateKey: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineBytes{
+						InlineBytes: []byte(key),
+					},
+				},
+			},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, ssl, &apisix.Ssl{
+		Id:   id.GenID("default"),
+		Cert: cert,
+		Key:  key,
+		Snis: []string{"httpbin.org", "*.httpbin.org"},
+	})
+	assert.Nil(t, ssl.Validate())
+
+	// Validation context cannot be translated.
+	ssl, err = a.TranslateSecret(&tlsv3.Secret{
+		Name: "ROOTCA",
+		Type: &tlsv3.Secret_ValidationContext{
+			ValidationContext: &tlsv3.CertificateValidationContext{},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, ssl)
+
+	// Encrypted private key.
+	_, err = a.TranslateSecret(&tlsv3.Secret{
+		Name: "default",
+		Type: &tlsv3.Secret_TlsCertificate{
+			TlsCertificate: &tlsv3.TlsCertificate{
+				Password: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineString{
+						InlineString: "123456",
+					},
+				},
+			},
+		},
+	})
+	assert.Equal(t, err, ErrFeatureNotSupportedYet)
+
+	// Bad certificate.
+	_, err = a.TranslateSecret(&tlsv3.Secret{
+		Name: "default",
+		Type: &tlsv3.Secret_TlsCertificate{
+			TlsCertificate: &tlsv3.TlsCertificate{
+				CertificateChain: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineString{
+						InlineString: "bad cert",
+					},
+				},
+				PrivateKey: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineString{
+						InlineString: key,
+					},
+				},
+			},
+		},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestPatchUpstreamWithSecret(t *testing.T) {
+	cert, key := testutil.NewCertificate(t, "httpbin.org")
+	ups := &apisix.Upstream{Name: "httpbin"}
+	err := PatchUpstreamWithSecret(ups, &tlsv3.Secret{
+		Name: "ROOTCA",
+		Type: &tlsv3.Secret_ValidationContext{
+			ValidationContext: &tlsv3.CertificateValidationContext{},
+		},
+	})
+	assert.Equal(t, err, errNoTlsCertificate)
+	assert.Nil(t, ups.Tls)
+
+	err = PatchUpstreamWithSecret(ups, &tlsv3.Secret{
+		Name: "default",
+		Type: &tlsv3.Secret_TlsCertificate{
+			TlsCertificate: &tlsv3.TlsCertificate{
+				CertificateChain: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineString{
+						InlineString: cert,
+					},
+				},
+				PrivateKey: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineString{
+						InlineString: key,
+					},
+				},
+			},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, ups.Tls, &apisix.Upstream_TLS{
+		ClientCert: cert,
+		ClientKey:  key,
+	})
+}
+
+func TestRedactCluster(t *testing.T) {
+	ctx, err := anypb.New(&tlsv3.UpstreamTlsContext{
+		CommonTlsContext: &tlsv3.CommonTlsContext{
+			TlsCertificates: []*tlsv3.TlsCertificate{
+				{
+					CertificateChain: &corev3.DataSource{
+						Specifier: &corev3.DataSource_InlineString{InlineString: "cert"},
+					},
+					PrivateKey: &corev3.DataSource{
+						Specifier: &corev3.DataSource_InlineBytes{InlineBytes: []byte("key")},
+					},
+				},
+				{
+					PrivateKey: &corev3.DataSource{
+						Specifier: &corev3.DataSource_Filename{Filename: "/etc/certs/key.pem"},
+					},
+				},
+			},
+		},
+	})
+	assert.Nil(t, err)
+	c := &clusterv3.Cluster{
+		Name: "httpbin.default.svc.cluster.local",
+		TransportSocket: &corev3.TransportSocket{
+			Name:       "envoy.transport_sockets.tls",
+			ConfigType: &corev3.TransportSocket_TypedConfig{TypedConfig: ctx},
+		},
+	}
+
+	redacted := RedactCluster(c)
+	var redactedCtx tlsv3.UpstreamTlsContext
+	assert.Nil(t, redacted.GetTransportSocket().GetTypedConfig().UnmarshalTo(&redactedCtx))
+	certs := redactedCtx.GetCommonTlsContext().GetTlsCertificates()
+	assert.Equal(t, certs[0].GetCertificateChain().GetInlineString(), "cert")
+	assert.Equal(t, certs[0].GetPrivateKey().GetInlineString(), apisixutil.RedactedValue)
+	assert.Equal(t, certs[1].GetPrivateKey().GetFilename(), "/etc/certs/key.pem")
+
+	// The original cluster is untouched.
+	var origCtx tlsv3.UpstreamTlsContext
+	assert.Nil(t, c.GetTransportSocket().GetTypedConfig().UnmarshalTo(&origCtx))
+	assert.Equal(t, string(origCtx.GetCommonTlsContext().GetTlsCertificates()[0].GetPrivateKey().GetInlineBytes()), "key")
+}
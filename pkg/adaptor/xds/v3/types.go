@@ -2,11 +2,16 @@ package v3
 
 import (
 	"errors"
+	"strings"
+	"time"
 
 	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"go.uber.org/zap"
 
 	"github.com/api7/apisix-mesh-agent/pkg/config"
 	"github.com/api7/apisix-mesh-agent/pkg/log"
@@ -22,6 +27,16 @@ var (
 	// ErrFeatureNotSupportedYet means a non-supported feature exists in the
 	// xDS resource so the Adaptor goes ahead.
 	ErrFeatureNotSupportedYet = errors.New("feature not supported yet")
+	// ErrAdaptorDegraded means the Adaptor failed to initialize and runs in
+	// the degraded mode, nothing can be translated.
+	ErrAdaptorDegraded = errors.New("adaptor degraded")
+
+	// DefaultHashPolicy is used when the cluster uses the consistent hashing
+	// load balancer but no hash policy was specified by routes.
+	DefaultHashPolicy = HashPolicy{
+		HashOn: "vars",
+		Key:    "remote_addr",
+	}
 )
 
 // Adaptor translates xDS resources like Route, Cluster
@@ -40,6 +55,63 @@ type Adaptor interface {
 	// CollectRouteNamesAndConfigs collects Rds route names and static route configurations
 	// from listener.
 	CollectRouteNamesAndConfigs(*listenerv3.Listener) ([]string, []*routev3.RouteConfiguration, error)
+	// CollectRouteHTTPFilters collects the HTTP filters of the HTTP connection managers
+	// in the listener, the returned map is keyed by the RouteConfiguration name, and values
+	// are the filters (in the filter chain order).
+	CollectRouteHTTPFilters(*listenerv3.Listener) (map[string][]*hcmv3.HttpFilter, error)
+	// CollectClusterHashPolicies collects the hash policies of routes in the
+	// RouteConfiguration, the returned map is keyed by the cluster name.
+	CollectClusterHashPolicies(*routev3.RouteConfiguration) map[string]*HashPolicy
+	// CollectClusterRetryPolicies collects the retry policies of routes in the
+	// RouteConfiguration, the returned map is keyed by the cluster name.
+	CollectClusterRetryPolicies(*routev3.RouteConfiguration) map[string]*RetryPolicy
+	// CollectConsumers collects the credentials in the auth HTTP filters of the
+	// listener and translates them to APISIX Consumers.
+	CollectConsumers(*listenerv3.Listener) ([]*apisix.Consumer, error)
+	// TranslateSecret translates a Secret with the TLS certificate to an APISIX
+	// Ssl, nil is returned for other kinds of secrets.
+	TranslateSecret(*tlsv3.Secret) (*apisix.Ssl, error)
+}
+
+// HashPolicy is the consistent hashing setting of an APISIX Upstream.
+// In xDS, the hash policy is configured on routes, while Apache APISIX
+// configures it on the upstream, so it should be collected from the
+// RouteConfiguration and patched to the Upstream of the target cluster.
+type HashPolicy struct {
+	// HashOn is the "hash_on" field of APISIX Upstream.
+	HashOn string
+	// Key is the "key" field of APISIX Upstream.
+	Key string
+}
+
+// PatchUpstream patches the hash policy to the upstream, it only takes effect
+// when the upstream uses the consistent hashing load balancer, which obeys the
+// xDS specification, the hash policy will be ignored by non hash-based load
+// balancers.
+func (hp *HashPolicy) PatchUpstream(ups *apisix.Upstream) bool {
+	if ups.Type != "chash" {
+		return false
+	}
+	ups.HashOn = hp.HashOn
+	ups.Key = hp.Key
+	return true
+}
+
+// RetryPolicy is the retry setting of an APISIX Upstream. Like HashPolicy,
+// it's configured on routes (or virtual hosts) in xDS, so it should be
+// collected from the RouteConfiguration and patched to the Upstream of the
+// target cluster.
+type RetryPolicy struct {
+	// Retries is the "retries" field of APISIX Upstream.
+	Retries int32
+	// RetryTimeout is the "retry_timeout" field of APISIX Upstream.
+	RetryTimeout float64
+}
+
+// PatchUpstream patches the retry policy to the upstream.
+func (rp *RetryPolicy) PatchUpstream(ups *apisix.Upstream) {
+	ups.Retries = rp.Retries
+	ups.RetryTimeout = rp.RetryTimeout
 }
 
 // TranslateOptions contains some options to customize the translate process.
@@ -52,15 +124,69 @@ type TranslateOptions struct {
 	// explicitly while there is no listener configuration on APISIX, so this is necessary
 	// to avoid the cross-listener-use of routes.
 	// An extra `vars` expression will be added only if the listener address can be found here.
+	// The address is empty for listeners without addresses (api_listener).
 	RouteOriginalDestination map[string]string
+	// RouteHTTPFilters is a map which key is the name of RouteConfiguration and value
+	// is the HTTP filters (in the filter chain order) in the HTTP connection manager which
+	// uses this route. Some filters will be translated to plugins on the routes, and
+	// priorities of these plugins will be set to keep the same execution order.
+	RouteHTTPFilters map[string][]*hcmv3.HttpFilter
+	// Tenant scopes the names of routes and the clusters they refer to,
+	// see TenantScopedName for details.
+	Tenant string
+}
+
+// TenantScopedName scopes the name of resources by the tenant, so that
+// resources with the same name in different tenants are isolated.
+// The name is unchanged if the tenant is empty.
+func TenantScopedName(tenant, name string) string {
+	if tenant == "" {
+		return name
+	}
+	return tenant + "/" + name
 }
 
 type adaptor struct {
 	logger *log.Logger
+	// defaultScheme and defaultPort are used when the
+	// cluster or endpoint doesn't specify them.
+	defaultScheme string
+	defaultPort   int32
+	// dnsResolverValid is the valid time of DNS records in APISIX,
+	// the record TTL is used if it's 0.
+	dnsResolverValid time.Duration
+	// connectTimeouts are the connect timeouts (in seconds) for clusters
+	// which don't specify it, keyed by the cluster type.
+	connectTimeouts map[clusterv3.Cluster_DiscoveryType]float64
+	// maxUpstreamNodes is the max number of nodes in an upstream,
+	// there is no limit if it's 0.
+	maxUpstreamNodes int
+	// filterPluginMappings are the mappings from HTTP filters to plugins,
+	// keyed by the type url of filters.
+	filterPluginMappings map[string]*FilterPluginMapping
+	// sourceHashLabels marks the translated resources with the
+	// LabelSourceHash label.
+	sourceHashLabels bool
 }
 
-// NewAdaptor creates a XDS based adaptor.
+// NewAdaptor creates a XDS based adaptor. If the creation fails and
+// cfg.DegradeOnAdaptorFailure is true, a degraded adaptor which refuses
+// to translate anything will be returned instead of the error.
 func NewAdaptor(cfg *config.Config) (Adaptor, error) {
+	a, err := newAdaptor(cfg)
+	if err != nil {
+		if !cfg.DegradeOnAdaptorFailure {
+			return nil, err
+		}
+		log.Errorw("failed to create xds v3 adaptor, running in the degraded mode, no resources will be translated",
+			zap.Error(err),
+		)
+		return &degradedAdaptor{}, nil
+	}
+	return a, nil
+}
+
+func newAdaptor(cfg *config.Config) (Adaptor, error) {
 	logger, err := log.NewLogger(
 		log.WithOutputFile(cfg.LogOutput),
 		log.WithLogLevel(cfg.LogLevel),
@@ -69,7 +195,50 @@ func NewAdaptor(cfg *config.Config) (Adaptor, error) {
 	if err != nil {
 		return nil, err
 	}
+	scheme := cfg.DefaultUpstreamScheme
+	if scheme == "" {
+		scheme = config.DefaultUpstreamScheme
+	}
+	port := int32(cfg.DefaultUpstreamPort)
+	if port == 0 {
+		if scheme == "https" || scheme == "grpcs" {
+			port = 443
+		} else {
+			port = 80
+		}
+	}
+	connectTimeouts := make(map[clusterv3.Cluster_DiscoveryType]float64, len(cfg.ClusterConnectTimeouts))
+	for typ, timeout := range cfg.ClusterConnectTimeouts {
+		value, ok := clusterv3.Cluster_DiscoveryType_value[strings.ToUpper(typ)]
+		if !ok {
+			return nil, config.ErrBadClusterConnectTimeout
+		}
+		d, err := time.ParseDuration(timeout)
+		if err != nil || d <= 0 {
+			return nil, config.ErrBadClusterConnectTimeout
+		}
+		connectTimeouts[clusterv3.Cluster_DiscoveryType(value)] = d.Seconds()
+	}
+	filterPluginMappings := make(map[string]*FilterPluginMapping)
+	if cfg.FilterPluginMappingFile != "" {
+		mappings, err := LoadFilterPluginMappings(cfg.FilterPluginMappingFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range mappings {
+			filterPluginMappings[m.TypeUrl] = m
+		}
+	}
 	return &adaptor{
-		logger: logger,
+		logger:        logger,
+		defaultScheme: scheme,
+		defaultPort:   port,
+
+		dnsResolverValid: time.Duration(cfg.DNSResolverValid) * time.Second,
+		connectTimeouts:  connectTimeouts,
+		maxUpstreamNodes: cfg.MaxUpstreamNodes,
+
+		filterPluginMappings: filterPluginMappings,
+		sourceHashLabels:     cfg.SourceHashLabels,
 	}, nil
 }
@@ -0,0 +1,41 @@
+package apisix
+
+import (
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// CompareConsumers diffs two apisix.Consumer arrays and finds the new adds,
+// updates and deleted ones, consumers are identified by their usernames.
+// Note it stands on the first apisix.Consumer array's point of view. Changes
+// only on the ignoredFields are not treated as updates, like CompareRoutes.
+func CompareConsumers(c1, c2 []*apisix.Consumer, ignoredFields ...string) (added, deleted, updated []*apisix.Consumer) {
+	if c1 == nil {
+		return c2, nil, nil
+	}
+	if c2 == nil {
+		return nil, c1, nil
+	}
+	c1Map := make(map[string]*apisix.Consumer)
+	c2Map := make(map[string]*apisix.Consumer)
+	for _, c := range c1 {
+		c1Map[c.GetUsername()] = c
+	}
+	for _, c := range c2 {
+		c2Map[c.GetUsername()] = c
+	}
+	for _, c := range c2 {
+		if _, ok := c1Map[c.GetUsername()]; !ok {
+			added = append(added, c)
+		}
+	}
+	for _, co := range c1 {
+		if cn, ok := c2Map[co.GetUsername()]; !ok {
+			deleted = append(deleted, co)
+		} else {
+			if !EqualIgnoringFields(co, cn, ignoredFields) {
+				updated = append(updated, cn)
+			}
+		}
+	}
+	return
+}
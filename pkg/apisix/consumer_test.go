@@ -0,0 +1,44 @@
+package apisix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestCompareConsumers(t *testing.T) {
+	c1 := []*apisix.Consumer{
+		{
+			Username: "alice",
+		},
+		{
+			Username: "bob",
+		},
+	}
+
+	added, deleted, updated := CompareConsumers(c1, nil)
+	assert.Nil(t, added)
+	assert.Nil(t, updated)
+	assert.Equal(t, deleted, c1)
+
+	added, deleted, updated = CompareConsumers(nil, c1)
+	assert.Equal(t, added, c1)
+	assert.Nil(t, updated)
+	assert.Nil(t, deleted)
+
+	c2 := []*apisix.Consumer{
+		{
+			Username: "bob",
+			Desc:     "bob from the mesh",
+		},
+		{
+			Username: "carol",
+		},
+	}
+	added, deleted, updated = CompareConsumers(c1, c2)
+	assert.Equal(t, added, c2[1:])
+	assert.Equal(t, deleted, c1[:1])
+	assert.Equal(t, updated, c2[:1])
+}
@@ -0,0 +1,58 @@
+package apisix
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EqualIgnoringFields reports whether m1 and m2 are equal after the ignored
+// fields are cleared. Fields are specified by the dot separated paths of the
+// proto field names, e.g. "desc", "timeout.connect", for map fields, the last
+// segment can also be a key, e.g. "labels.timestamp"; for repeated message
+// fields, the rest paths apply to all elements, e.g. "nodes.metadata".
+// m1 and m2 are not modified.
+func EqualIgnoringFields(m1, m2 proto.Message, ignoredFields []string) bool {
+	if len(ignoredFields) == 0 {
+		return proto.Equal(m1, m2)
+	}
+	m1 = proto.Clone(m1)
+	m2 = proto.Clone(m2)
+	for _, field := range ignoredFields {
+		path := strings.Split(field, ".")
+		clearField(m1.ProtoReflect(), path)
+		clearField(m2.ProtoReflect(), path)
+	}
+	return proto.Equal(m1, m2)
+}
+
+func clearField(m protoreflect.Message, path []string) {
+	fd := m.Descriptor().Fields().ByName(protoreflect.Name(path[0]))
+	if fd == nil {
+		return
+	}
+	if len(path) == 1 {
+		m.Clear(fd)
+		return
+	}
+	if !m.Has(fd) {
+		return
+	}
+	switch {
+	case fd.IsMap():
+		if len(path) == 2 && fd.MapKey().Kind() == protoreflect.StringKind {
+			m.Mutable(fd).Map().Clear(protoreflect.ValueOfString(path[1]).MapKey())
+		}
+	case fd.IsList():
+		if fd.Message() == nil {
+			return
+		}
+		list := m.Mutable(fd).List()
+		for i := 0; i < list.Len(); i++ {
+			clearField(list.Get(i).Message(), path[1:])
+		}
+	case fd.Message() != nil:
+		clearField(m.Mutable(fd).Message(), path[1:])
+	}
+}
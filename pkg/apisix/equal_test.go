@@ -0,0 +1,62 @@
+package apisix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestEqualIgnoringFields(t *testing.T) {
+	u1 := &apisix.Upstream{
+		Id:   "1",
+		Desc: "pushed at 1",
+		Labels: map[string]string{
+			"timestamp": "1",
+			"app":       "httpbin",
+		},
+		Timeout: &apisix.Upstream_Timeout{
+			Connect: 1,
+		},
+		Nodes: []*apisix.Node{
+			{
+				Host: "10.0.3.11",
+				Port: 80,
+			},
+		},
+	}
+	u2 := &apisix.Upstream{
+		Id:   "1",
+		Desc: "pushed at 2",
+		Labels: map[string]string{
+			"timestamp": "2",
+			"app":       "httpbin",
+		},
+		Timeout: &apisix.Upstream_Timeout{
+			Connect: 2,
+		},
+		Nodes: []*apisix.Node{
+			{
+				Host: "10.0.3.11",
+				Port: 80,
+			},
+		},
+	}
+	assert.False(t, EqualIgnoringFields(u1, u2, nil))
+	assert.False(t, EqualIgnoringFields(u1, u2, []string{"desc", "labels.timestamp"}))
+	assert.True(t, EqualIgnoringFields(u1, u2, []string{"desc", "labels.timestamp", "timeout.connect"}))
+	assert.True(t, EqualIgnoringFields(u1, u2, []string{"desc", "labels", "timeout", "unknown"}))
+
+	// The original objects should be intact.
+	assert.Equal(t, u1.Desc, "pushed at 1")
+	assert.Equal(t, u2.Labels["timestamp"], "2")
+
+	u2.Labels["app"] = "httpbin2"
+	assert.False(t, EqualIgnoringFields(u1, u2, []string{"desc", "labels.timestamp", "timeout.connect"}))
+
+	u2.Labels["app"] = "httpbin"
+	u2.Nodes[0].Weight = 100
+	assert.False(t, EqualIgnoringFields(u1, u2, []string{"desc", "labels.timestamp", "timeout.connect"}))
+	assert.True(t, EqualIgnoringFields(u1, u2, []string{"desc", "labels.timestamp", "timeout.connect", "nodes.weight"}))
+}
@@ -0,0 +1,41 @@
+package apisix
+
+import (
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// ComparePluginConfigs diffs two apisix.PluginConfig arrays and finds the new adds,
+// updates and deleted ones. Note it stands on the first apisix.PluginConfig array's
+// point of view. Changes only on the ignoredFields are not treated as updates,
+// like CompareRoutes.
+func ComparePluginConfigs(p1, p2 []*apisix.PluginConfig, ignoredFields ...string) (added, deleted, updated []*apisix.PluginConfig) {
+	if p1 == nil {
+		return p2, nil, nil
+	}
+	if p2 == nil {
+		return nil, p1, nil
+	}
+	p1Map := make(map[string]*apisix.PluginConfig)
+	p2Map := make(map[string]*apisix.PluginConfig)
+	for _, p := range p1 {
+		p1Map[p.GetId()] = p
+	}
+	for _, p := range p2 {
+		p2Map[p.GetId()] = p
+	}
+	for _, p := range p2 {
+		if _, ok := p1Map[p.GetId()]; !ok {
+			added = append(added, p)
+		}
+	}
+	for _, po := range p1 {
+		if pn, ok := p2Map[po.GetId()]; !ok {
+			deleted = append(deleted, po)
+		} else {
+			if !EqualIgnoringFields(po, pn, ignoredFields) {
+				updated = append(updated, pn)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,44 @@
+package apisix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestComparePluginConfigs(t *testing.T) {
+	p1 := []*apisix.PluginConfig{
+		{
+			Id: "1",
+		},
+		{
+			Id: "2",
+		},
+	}
+
+	added, deleted, updated := ComparePluginConfigs(p1, nil)
+	assert.Nil(t, added)
+	assert.Nil(t, updated)
+	assert.Equal(t, deleted, p1)
+
+	added, deleted, updated = ComparePluginConfigs(nil, p1)
+	assert.Equal(t, added, p1)
+	assert.Nil(t, updated)
+	assert.Nil(t, deleted)
+
+	p2 := []*apisix.PluginConfig{
+		{
+			Id:   "2",
+			Desc: "shared by 3 routes",
+		},
+		{
+			Id: "3",
+		},
+	}
+	added, deleted, updated = ComparePluginConfigs(p1, p2)
+	assert.Equal(t, added, p2[1:])
+	assert.Equal(t, deleted, p1[:1])
+	assert.Equal(t, updated, p2[:1])
+}
@@ -0,0 +1,47 @@
+package apisix
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// RedactedValue replaces the private keys when the resources are printed
+// or logged.
+const RedactedValue = "<redacted>"
+
+// Redact returns the APISIX resource with the private keys (of the Ssl and
+// the upstream client certificate) replaced by RedactedValue, the resource
+// is cloned before changing, so it's safe to redact the shared ones. Other
+// objects are returned as they are.
+func Redact(obj interface{}) interface{} {
+	switch o := obj.(type) {
+	case *apisix.Ssl:
+		if o.GetKey() != "" {
+			o = proto.Clone(o).(*apisix.Ssl)
+			o.Key = RedactedValue
+		}
+		return o
+	case *apisix.Upstream:
+		if o.GetTls().GetClientKey() != "" {
+			o = proto.Clone(o).(*apisix.Upstream)
+			o.Tls.ClientKey = RedactedValue
+		}
+		return o
+	default:
+		return obj
+	}
+}
+
+// RedactEvents redacts the objects and tombstones of the events, see Redact
+// for the details.
+func RedactEvents(events []types.Event) []types.Event {
+	redacted := make([]types.Event, 0, len(events))
+	for _, ev := range events {
+		ev.Object = Redact(ev.Object)
+		ev.Tombstone = Redact(ev.Tombstone)
+		redacted = append(redacted, ev)
+	}
+	return redacted
+}
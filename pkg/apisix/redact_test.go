@@ -0,0 +1,44 @@
+package apisix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestRedact(t *testing.T) {
+	ssl := &apisix.Ssl{Id: "1", Cert: "cert", Key: "key"}
+	ups := &apisix.Upstream{
+		Id: "2",
+		Tls: &apisix.Upstream_TLS{
+			ClientCert: "cert",
+			ClientKey:  "key",
+		},
+	}
+	route := &apisix.Route{Id: "3"}
+
+	events := RedactEvents([]types.Event{
+		{Type: types.EventAdd, Object: ssl},
+		{Type: types.EventDelete, Tombstone: ups},
+		{Type: types.EventUpdate, Object: route},
+	})
+	assert.Len(t, events, 3)
+
+	redactedSsl := events[0].Object.(*apisix.Ssl)
+	assert.Equal(t, redactedSsl.Key, RedactedValue)
+	assert.Equal(t, redactedSsl.Cert, "cert")
+	redactedUps := events[1].Tombstone.(*apisix.Upstream)
+	assert.Equal(t, redactedUps.Tls.ClientKey, RedactedValue)
+	assert.Equal(t, redactedUps.Tls.ClientCert, "cert")
+	assert.Equal(t, events[2].Object, route)
+
+	// The original ones are untouched.
+	assert.Equal(t, ssl.Key, "key")
+	assert.Equal(t, ups.Tls.ClientKey, "key")
+
+	assert.Nil(t, Redact(nil))
+	assert.Equal(t, Redact(&apisix.Upstream{Id: "4"}), &apisix.Upstream{Id: "4"})
+}
@@ -1,15 +1,14 @@
 package apisix
 
 import (
-	"google.golang.org/protobuf/proto"
-
 	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
 )
 
 // CompareRoutes diffs two apisix.Route array and finds the new adds, updates
 // and deleted ones. Note it stands on the first apisix.Route array's point
-// of view.
-func CompareRoutes(r1, r2 []*apisix.Route) (added, deleted, updated []*apisix.Route) {
+// of view. Changes only on the ignoredFields (see EqualIgnoringFields for the
+// path format) are not treated as updates.
+func CompareRoutes(r1, r2 []*apisix.Route, ignoredFields ...string) (added, deleted, updated []*apisix.Route) {
 	if r1 == nil {
 		return r2, nil, nil
 	}
@@ -34,7 +33,7 @@ func CompareRoutes(r1, r2 []*apisix.Route) (added, deleted, updated []*apisix.Ro
 		if rn, ok := r2Map[ro.Id]; !ok {
 			deleted = append(deleted, ro)
 		} else {
-			if !proto.Equal(ro, rn) {
+			if !EqualIgnoringFields(ro, rn, ignoredFields) {
 				updated = append(updated, rn)
 			}
 		}
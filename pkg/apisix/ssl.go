@@ -0,0 +1,41 @@
+package apisix
+
+import (
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// CompareSsls diffs two apisix.Ssl arrays and finds the new adds, updates
+// and deleted ones. Note it stands on the first apisix.Ssl array's point of
+// view. Changes only on the ignoredFields are not treated as updates, like
+// CompareRoutes.
+func CompareSsls(s1, s2 []*apisix.Ssl, ignoredFields ...string) (added, deleted, updated []*apisix.Ssl) {
+	if s1 == nil {
+		return s2, nil, nil
+	}
+	if s2 == nil {
+		return nil, s1, nil
+	}
+	s1Map := make(map[string]*apisix.Ssl)
+	s2Map := make(map[string]*apisix.Ssl)
+	for _, s := range s1 {
+		s1Map[s.GetId()] = s
+	}
+	for _, s := range s2 {
+		s2Map[s.GetId()] = s
+	}
+	for _, s := range s2 {
+		if _, ok := s1Map[s.GetId()]; !ok {
+			added = append(added, s)
+		}
+	}
+	for _, so := range s1 {
+		if sn, ok := s2Map[so.GetId()]; !ok {
+			deleted = append(deleted, so)
+		} else {
+			if !EqualIgnoringFields(so, sn, ignoredFields) {
+				updated = append(updated, sn)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,44 @@
+package apisix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestCompareSsls(t *testing.T) {
+	s1 := []*apisix.Ssl{
+		{
+			Id: "1",
+		},
+		{
+			Id: "2",
+		},
+	}
+
+	added, deleted, updated := CompareSsls(s1, nil)
+	assert.Nil(t, added)
+	assert.Nil(t, updated)
+	assert.Equal(t, deleted, s1)
+
+	added, deleted, updated = CompareSsls(nil, s1)
+	assert.Equal(t, added, s1)
+	assert.Nil(t, updated)
+	assert.Nil(t, deleted)
+
+	s2 := []*apisix.Ssl{
+		{
+			Id:   "2",
+			Snis: []string{"httpbin.org"},
+		},
+		{
+			Id: "3",
+		},
+	}
+	added, deleted, updated = CompareSsls(s1, s2)
+	assert.Equal(t, added, s2[1:])
+	assert.Equal(t, deleted, s1[:1])
+	assert.Equal(t, updated, s2[:1])
+}
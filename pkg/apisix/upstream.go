@@ -1,15 +1,14 @@
 package apisix
 
 import (
-	"google.golang.org/protobuf/proto"
-
 	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
 )
 
 // CompareUpstreams diffs two apisix.Upstreams array and finds the new adds, updates
 // and deleted ones. Note it stands on the first apisix.Upstream array's point
-// of view.
-func CompareUpstreams(u1, u2 []*apisix.Upstream) (added, deleted, updated []*apisix.Upstream) {
+// of view. Changes only on the ignoredFields (see EqualIgnoringFields for the
+// path format) are not treated as updates.
+func CompareUpstreams(u1, u2 []*apisix.Upstream, ignoredFields ...string) (added, deleted, updated []*apisix.Upstream) {
 	if u1 == nil {
 		return u2, nil, nil
 	}
@@ -33,7 +32,7 @@ func CompareUpstreams(u1, u2 []*apisix.Upstream) (added, deleted, updated []*api
 		if un, ok := u2Map[uo.GetId()]; !ok {
 			deleted = append(deleted, uo)
 		} else {
-			if !proto.Equal(uo, un) {
+			if !EqualIgnoringFields(uo, un, ignoredFields) {
 				updated = append(updated, un)
 			}
 		}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+type consumer struct {
+	mu sync.RWMutex
+	// TODO optimize the store if the performance of map
+	// is unbearable.
+	store map[string]*apisix.Consumer
+}
+
+func newConsumer() Consumer {
+	return &consumer{
+		store: make(map[string]*apisix.Consumer),
+	}
+}
+
+func (r *consumer) Get(username string) (*apisix.Consumer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	obj, ok := r.store[username]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+	// Never return the original one to avoid race conditions.
+	return proto.Clone(obj).(*apisix.Consumer), nil
+}
+
+func (r *consumer) List() ([]*apisix.Consumer, error) {
+	var objs []*apisix.Consumer
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, obj := range r.store {
+		objs = append(objs, proto.Clone(obj).(*apisix.Consumer))
+	}
+	return objs, nil
+}
+
+func (r *consumer) Insert(obj *apisix.Consumer) error {
+	obj = proto.Clone(obj).(*apisix.Consumer)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store[obj.Username] = obj
+	return nil
+}
+
+func (r *consumer) Delete(username string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.store[username]
+	if !ok {
+		return ErrObjectNotFound
+	}
+	delete(r.store, username)
+	return nil
+}
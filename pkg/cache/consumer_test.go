@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestConsumer(t *testing.T) {
+	r := newConsumer()
+	assert.NotNil(t, r)
+
+	// Not found
+	obj, err := r.Get("alice")
+	assert.Nil(t, obj)
+	assert.Equal(t, err, ErrObjectNotFound)
+	assert.Equal(t, r.Delete("alice"), ErrObjectNotFound)
+
+	c1 := &apisix.Consumer{
+		Username: "alice",
+	}
+	assert.Nil(t, r.Insert(c1))
+
+	obj, err = r.Get("alice")
+	assert.Nil(t, err)
+	assert.Equal(t, obj.Username, "alice")
+
+	// Update
+	obj.Desc = "Vivian"
+	assert.Nil(t, r.Insert(obj))
+	obj, err = r.Get("alice")
+	assert.Nil(t, err)
+	assert.Equal(t, obj.Username, "alice")
+	assert.Equal(t, obj.GetDesc(), "Vivian")
+
+	// Delete
+	assert.Nil(t, r.Delete("alice"))
+	assert.Equal(t, r.Delete("alice"), ErrObjectNotFound)
+	obj, err = r.Get("alice")
+	assert.Nil(t, obj)
+	assert.Error(t, err, ErrObjectNotFound)
+}
+
+func TestConsumerList(t *testing.T) {
+	objs := []*apisix.Consumer{
+		{
+			Username: "alice",
+		},
+		{
+			Username: "bob",
+		},
+		{
+			Username: "carol",
+		},
+	}
+	r := newConsumer()
+	assert.NotNil(t, r)
+	for _, obj := range objs {
+		assert.Nil(t, r.Insert(obj))
+	}
+	list, err := r.List()
+	assert.Nil(t, err)
+	assert.Len(t, list, 3)
+
+	var usernames []string
+	for _, elem := range list {
+		usernames = append(usernames, elem.GetUsername())
+	}
+	sort.Strings(usernames)
+	assert.Equal(t, usernames, []string{"alice", "bob", "carol"})
+}
+
+func TestConsumerObjectClone(t *testing.T) {
+	c1 := &apisix.Consumer{
+		Username: "alice",
+	}
+	r := newConsumer()
+	assert.NotNil(t, r)
+	assert.Nil(t, r.Insert(c1))
+
+	obj, err := r.Get("alice")
+	assert.Nil(t, err)
+
+	obj.Desc = "alex"
+	obj, err = r.Get("alice")
+	assert.Nil(t, err)
+	assert.Equal(t, obj.Desc, "")
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+type pluginConfig struct {
+	mu sync.RWMutex
+	// TODO optimize the store if the performance of map
+	// is unbearable.
+	store map[string]*apisix.PluginConfig
+}
+
+func newPluginConfig() PluginConfig {
+	return &pluginConfig{
+		store: make(map[string]*apisix.PluginConfig),
+	}
+}
+
+func (r *pluginConfig) Get(id string) (*apisix.PluginConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	obj, ok := r.store[id]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+	// Never return the original one to avoid race conditions.
+	return proto.Clone(obj).(*apisix.PluginConfig), nil
+}
+
+func (r *pluginConfig) List() ([]*apisix.PluginConfig, error) {
+	var objs []*apisix.PluginConfig
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, obj := range r.store {
+		objs = append(objs, proto.Clone(obj).(*apisix.PluginConfig))
+	}
+	return objs, nil
+}
+
+func (r *pluginConfig) Insert(obj *apisix.PluginConfig) error {
+	obj = proto.Clone(obj).(*apisix.PluginConfig)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store[obj.Id] = obj
+	return nil
+}
+
+func (r *pluginConfig) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.store[id]
+	if !ok {
+		return ErrObjectNotFound
+	}
+	delete(r.store, id)
+	return nil
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestPluginConfig(t *testing.T) {
+	r := newPluginConfig()
+	assert.NotNil(t, r)
+
+	// Not found
+	obj, err := r.Get("1")
+	assert.Nil(t, obj)
+	assert.Equal(t, err, ErrObjectNotFound)
+	assert.Equal(t, r.Delete("1"), ErrObjectNotFound)
+
+	pc1 := &apisix.PluginConfig{
+		Id: "1",
+	}
+	assert.Nil(t, r.Insert(pc1))
+
+	obj, err = r.Get("1")
+	assert.Nil(t, err)
+	assert.Equal(t, obj.Id, "1")
+
+	// Update
+	obj.Desc = "Vivian"
+	assert.Nil(t, r.Insert(obj))
+	obj, err = r.Get("1")
+	assert.Nil(t, err)
+	assert.Equal(t, obj.Id, "1")
+	assert.Equal(t, obj.GetDesc(), "Vivian")
+
+	// Delete
+	assert.Nil(t, r.Delete("1"))
+	assert.Equal(t, r.Delete("1"), ErrObjectNotFound)
+	obj, err = r.Get("1")
+	assert.Nil(t, obj)
+	assert.Error(t, err, ErrObjectNotFound)
+}
+
+func TestPluginConfigList(t *testing.T) {
+	objs := []*apisix.PluginConfig{
+		{
+			Id: "1",
+		},
+		{
+			Id: "2",
+		},
+		{
+			Id: "3",
+		},
+	}
+	r := newPluginConfig()
+	assert.NotNil(t, r)
+	for _, obj := range objs {
+		assert.Nil(t, r.Insert(obj))
+	}
+	list, err := r.List()
+	assert.Nil(t, err)
+	assert.Len(t, list, 3)
+
+	var ids []string
+	for _, elem := range list {
+		ids = append(ids, elem.GetId())
+	}
+	sort.Strings(ids)
+	assert.Equal(t, ids[0], "1")
+	assert.Equal(t, ids[1], "2")
+	assert.Equal(t, ids[2], "3")
+}
+
+func TestPluginConfigObjectClone(t *testing.T) {
+	pc1 := &apisix.PluginConfig{
+		Id: "1",
+	}
+	r := newPluginConfig()
+	assert.NotNil(t, r)
+	assert.Nil(t, r.Insert(pc1))
+
+	obj, err := r.Get("1")
+	assert.Nil(t, err)
+
+	obj.Desc = "alex"
+	obj, err = r.Get("1")
+	assert.Nil(t, err)
+	assert.Equal(t, obj.Desc, "")
+}
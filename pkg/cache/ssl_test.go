@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestSsl(t *testing.T) {
+	r := newSsl()
+	assert.NotNil(t, r)
+
+	// Not found
+	obj, err := r.Get("1")
+	assert.Nil(t, obj)
+	assert.Equal(t, err, ErrObjectNotFound)
+	assert.Equal(t, r.Delete("1"), ErrObjectNotFound)
+
+	s1 := &apisix.Ssl{
+		Id: "1",
+	}
+	assert.Nil(t, r.Insert(s1))
+
+	obj, err = r.Get("1")
+	assert.Nil(t, err)
+	assert.Equal(t, obj.Id, "1")
+
+	// Update
+	obj.Snis = []string{"httpbin.org"}
+	assert.Nil(t, r.Insert(obj))
+	obj, err = r.Get("1")
+	assert.Nil(t, err)
+	assert.Equal(t, obj.Id, "1")
+	assert.Equal(t, obj.GetSnis(), []string{"httpbin.org"})
+
+	// Delete
+	assert.Nil(t, r.Delete("1"))
+	assert.Equal(t, r.Delete("1"), ErrObjectNotFound)
+	obj, err = r.Get("1")
+	assert.Nil(t, obj)
+	assert.Error(t, err, ErrObjectNotFound)
+}
+
+func TestSslList(t *testing.T) {
+	objs := []*apisix.Ssl{
+		{
+			Id: "1",
+		},
+		{
+			Id: "2",
+		},
+		{
+			Id: "3",
+		},
+	}
+	r := newSsl()
+	assert.NotNil(t, r)
+	for _, obj := range objs {
+		assert.Nil(t, r.Insert(obj))
+	}
+	list, err := r.List()
+	assert.Nil(t, err)
+	assert.Len(t, list, 3)
+
+	var ids []string
+	for _, elem := range list {
+		ids = append(ids, elem.GetId())
+	}
+	sort.Strings(ids)
+	assert.Equal(t, ids, []string{"1", "2", "3"})
+}
+
+func TestSslObjectClone(t *testing.T) {
+	s1 := &apisix.Ssl{
+		Id: "1",
+	}
+	r := newSsl()
+	assert.NotNil(t, r)
+	assert.Nil(t, r.Insert(s1))
+
+	obj, err := r.Get("1")
+	assert.Nil(t, err)
+
+	obj.Snis = []string{"httpbin.org"}
+	obj, err = r.Get("1")
+	assert.Nil(t, err)
+	assert.Nil(t, obj.Snis)
+}
@@ -18,6 +18,12 @@ type Cache interface {
 	Route() Route
 	// Upstream returns the upstream exclusive cache object.
 	Upstream() Upstream
+	// PluginConfig returns the plugin config exclusive cache object.
+	PluginConfig() PluginConfig
+	// Consumer returns the consumer exclusive cache object.
+	Consumer() Consumer
+	// Ssl returns the ssl exclusive cache object.
+	Ssl() Ssl
 }
 
 // Route defines the exclusive behaviors for apisix.Route.
@@ -48,16 +54,64 @@ type Upstream interface {
 	Delete(string) error
 }
 
+// PluginConfig defines the exclusive behaviors for apisix.PluginConfig.
+type PluginConfig interface {
+	// Get the apisix.PluginConfig by its id. In case of the object not found,
+	// ErrObjectNotFound is given.
+	Get(string) (*apisix.PluginConfig, error)
+	// List lists all apisix.PluginConfig.
+	List() ([]*apisix.PluginConfig, error)
+	// Insert creates or updates an apisix.PluginConfig object, indexed by its id.
+	Insert(*apisix.PluginConfig) error
+	// Delete deletes the apisix.PluginConfig object by the id. In case of object not
+	// exist, ErrObjectNotFound is given.
+	Delete(string) error
+}
+
+// Consumer defines the exclusive behaviors for apisix.Consumer.
+type Consumer interface {
+	// Get the apisix.Consumer by its username. In case of the object not found,
+	// ErrObjectNotFound is given.
+	Get(string) (*apisix.Consumer, error)
+	// List lists all apisix.Consumer.
+	List() ([]*apisix.Consumer, error)
+	// Insert creates or updates an apisix.Consumer object, indexed by its username.
+	Insert(*apisix.Consumer) error
+	// Delete deletes the apisix.Consumer object by the username. In case of object
+	// not exist, ErrObjectNotFound is given.
+	Delete(string) error
+}
+
+// Ssl defines the exclusive behaviors for apisix.Ssl.
+type Ssl interface {
+	// Get the apisix.Ssl by its id. In case of the object not found,
+	// ErrObjectNotFound is given.
+	Get(string) (*apisix.Ssl, error)
+	// List lists all apisix.Ssl.
+	List() ([]*apisix.Ssl, error)
+	// Insert creates or updates an apisix.Ssl object, indexed by its id.
+	Insert(*apisix.Ssl) error
+	// Delete deletes the apisix.Ssl object by the id. In case of object not
+	// exist, ErrObjectNotFound is given.
+	Delete(string) error
+}
+
 type cache struct {
-	route    Route
-	upstream Upstream
+	route        Route
+	upstream     Upstream
+	pluginConfig PluginConfig
+	consumer     Consumer
+	ssl          Ssl
 }
 
 // NewInMemoryCache creates a Cache object which stores all data in memory.
 func NewInMemoryCache() Cache {
 	return &cache{
-		route:    newRoute(),
-		upstream: newUpstream(),
+		route:        newRoute(),
+		upstream:     newUpstream(),
+		pluginConfig: newPluginConfig(),
+		consumer:     newConsumer(),
+		ssl:          newSsl(),
 	}
 }
 
@@ -68,3 +122,15 @@ func (c *cache) Route() Route {
 func (c *cache) Upstream() Upstream {
 	return c.upstream
 }
+
+func (c *cache) PluginConfig() PluginConfig {
+	return c.pluginConfig
+}
+
+func (c *cache) Consumer() Consumer {
+	return c.consumer
+}
+
+func (c *cache) Ssl() Ssl {
+	return c.ssl
+}
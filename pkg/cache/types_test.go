@@ -18,9 +18,21 @@ func TestInMemoryCache(t *testing.T) {
 	r := &apisix.Route{
 		Id: "1",
 	}
+	pc := &apisix.PluginConfig{
+		Id: "1",
+	}
+	co := &apisix.Consumer{
+		Username: "alice",
+	}
+	ssl := &apisix.Ssl{
+		Id: "1",
+	}
 
 	assert.Nil(t, c.Route().Insert(r))
 	assert.Nil(t, c.Upstream().Insert(ups))
+	assert.Nil(t, c.PluginConfig().Insert(pc))
+	assert.Nil(t, c.Consumer().Insert(co))
+	assert.Nil(t, c.Ssl().Insert(ssl))
 
 	rr, err := c.Route().Get("1")
 	assert.Nil(t, err)
@@ -29,4 +41,16 @@ func TestInMemoryCache(t *testing.T) {
 	uu, err := c.Upstream().Get("1")
 	assert.Nil(t, err)
 	assert.Equal(t, uu.GetId(), "1")
+
+	pp, err := c.PluginConfig().Get("1")
+	assert.Nil(t, err)
+	assert.Equal(t, pp.GetId(), "1")
+
+	cc, err := c.Consumer().Get("alice")
+	assert.Nil(t, err)
+	assert.Equal(t, cc.GetUsername(), "alice")
+
+	ss, err := c.Ssl().Get("1")
+	assert.Nil(t, err)
+	assert.Equal(t, ss.GetId(), "1")
 }
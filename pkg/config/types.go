@@ -2,10 +2,13 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"net"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -15,30 +18,127 @@ const (
 	XDSV3FileProvisioner = "xds-v3-file"
 	// XDSV3GRPCProvisioner means to use the xds v3 grpc provisioner.
 	XDSV3GRPCProvisioner = "xds-v3-grpc"
+	// XDSV3ConfigMapProvisioner means to use the xds v3 configmap provisioner.
+	XDSV3ConfigMapProvisioner = "xds-v3-configmap"
 
 	// StandaloneMode means run apisix-mesh-agent standalone.
 	StandaloneMode = "standalone"
 	// BundleMode means run apisix-mesh-agent and apisix.
 	BundleMode = "bundle"
+	// DefaultEventOrder orders events in a batch by their types, the added
+	// ones first, then the deleted ones and the updated ones.
+	DefaultEventOrder = "default"
+	// DependencyEventOrder orders events in a batch by the dependencies of
+	// resources, upstreams are added (or updated) before the plugin configs
+	// and the routes referring to them, and all deletions come last, in
+	// the reverse order.
+	DependencyEventOrder = "dependency"
 	// DefaultAPISIXHomePath is the default home path for Apache APISIX.
 	DefaultAPISIXHomePath = "/usr/local/apisix"
 	// DefaultAPISIXBinPath is the default binary path for Apache APISIX.
 	DefaultAPISIXBinPath = "/usr/local/bin/apisix"
+	// DefaultUpstreamScheme is the default scheme for upstreams translated
+	// from xDS clusters.
+	DefaultUpstreamScheme = "http"
 )
 
 var (
 	// ErrUnknownProvisioner means user specified an unknown provisioner.
 	ErrUnknownProvisioner = errors.New("unknown provisioner")
+	// ErrBadXDSWatchFile means the xds watch file doesn't exist or it's
+	// not readable.
+	ErrBadXDSWatchFile = errors.New("bad xds watch file")
+	// ErrConflictingProvisionerOptions means options of different provisioners
+	// are specified, e.g. --xds-watch-files with the xds-v3-grpc provisioner,
+	// or --xds-config-source with the xds-v3-configmap provisioner.
+	ErrConflictingProvisionerOptions = errors.New("conflicting provisioner options, --xds-watch-files is only for the xds-v3-file provisioner and --xds-config-source is only for the xds-v3-grpc provisioner")
+	// ErrBadLogLevel means the log level is unknown.
+	ErrBadLogLevel = errors.New("bad log level, it can be \"debug\", \"info\", \"warn\", \"error\", \"panic\" or \"fatal\"")
+	// ErrBadRunMode means the run mode is unsupported.
+	ErrBadRunMode = errors.New("bad run mode, it can be \"standalone\" or \"bundle\"")
 	// ErrBadGRPCListen means the grpc listen address is invalid.
 	ErrBadGRPCListen = errors.New("bad grpc listen address")
+	// ErrBadMetricsListen means the metrics listen address is invalid.
+	ErrBadMetricsListen = errors.New("bad metrics listen address")
+	// ErrBadProbeListen means the probe listen address is invalid.
+	ErrBadProbeListen = errors.New("bad probe listen address")
+	// ErrBadXDSStateDir means the xds state dir exists but it's not a
+	// directory, or it cannot be accessed.
+	ErrBadXDSStateDir = errors.New("bad xds state dir")
 	// ErrEmptyXDSConfigSource means the XDS config source is empty.
 	ErrEmptyXDSConfigSource = errors.New("empty xds config source, --xds-config-source option is required")
+	// ErrBadXDSClientCerts means the client certificate, private key or the
+	// CA certificate to the XDS config source is missing or unreadable.
+	ErrBadXDSClientCerts = errors.New("bad xds client certificates, --xds-client-cert and --xds-client-key should be set together, --xds-ca-cert requires them")
+	// ErrEmptyXDSConfigMapName means the XDS ConfigMap name is empty.
+	ErrEmptyXDSConfigMapName = errors.New("empty xds configmap name, --xds-configmap-name option is required")
+	// ErrBadDefaultUpstreamScheme means the default upstream scheme is invalid.
+	ErrBadDefaultUpstreamScheme = errors.New("bad default upstream scheme")
+	// ErrBadDefaultUpstreamPort means the default upstream port is invalid.
+	ErrBadDefaultUpstreamPort = errors.New("bad default upstream port")
+	// ErrBadDNSResolverValid means the DNS resolver valid is invalid.
+	ErrBadDNSResolverValid = errors.New("bad dns resolver valid")
+	// ErrBadEtcdKeyPrefix means the etcd key prefix is invalid, it
+	// should start with "/" and shouldn't end with "/".
+	ErrBadEtcdKeyPrefix = errors.New("bad etcd key prefix")
+	// ErrBadMaxUpstreamNodes means the max upstream nodes is invalid.
+	ErrBadMaxUpstreamNodes = errors.New("bad max upstream nodes")
+	// ErrBadMaxWritesPerSecond means the max writes per second is invalid.
+	ErrBadMaxWritesPerSecond = errors.New("bad max writes per second")
+	// ErrBadXDSPollInterval means the xds poll interval is invalid.
+	ErrBadXDSPollInterval = errors.New("bad xds poll interval")
+	// ErrBadXDSWatchDebounce means the xds watch debounce is invalid.
+	ErrBadXDSWatchDebounce = errors.New("bad xds watch debounce")
+	// ErrBadXDSMaxTrackedFiles means the xds max tracked files is invalid.
+	ErrBadXDSMaxTrackedFiles = errors.New("bad xds max tracked files")
+	// ErrBadXDSMaxTrackedResources means the xds max tracked resources is invalid.
+	ErrBadXDSMaxTrackedResources = errors.New("bad xds max tracked resources")
+	// ErrOneshotNotSupported means the oneshot mode is used with the
+	// provisioner other than xds-v3-file.
+	ErrOneshotNotSupported = errors.New("oneshot mode is only supported by the xds-v3-file provisioner")
+	// ErrBadXDSWatchFileTenant means the watched path is unknown or the
+	// tenant is invalid.
+	ErrBadXDSWatchFileTenant = errors.New("bad xds watch file tenant")
+	// ErrBadDiffIgnoredField means the field in the diff ignored fields is invalid.
+	ErrBadDiffIgnoredField = errors.New("bad diff ignored field")
+	// ErrBadClusterConnectTimeout means the cluster type or the timeout
+	// in the cluster connect timeouts is invalid.
+	ErrBadClusterConnectTimeout = errors.New("bad cluster connect timeout")
+	// ErrBadEventOrder means the event order is unknown.
+	ErrBadEventOrder = errors.New("bad event order")
+	// ErrBadHeartbeatInterval means the heartbeat interval is invalid.
+	ErrBadHeartbeatInterval = errors.New("bad heartbeat interval")
+	// ErrBadNotifyWebhook means the notify webhook is not a http(s) URL.
+	ErrBadNotifyWebhook = errors.New("bad notify webhook")
+	// ErrBadNotifyWebhookTimeout means the notify webhook timeout is invalid.
+	ErrBadNotifyWebhookTimeout = errors.New("bad notify webhook timeout")
+	// ErrBadNotifyWebhookRetries means the notify webhook retries is invalid.
+	ErrBadNotifyWebhookRetries = errors.New("bad notify webhook retries")
+	// ErrPprofWithoutMetricsListen means pprof is enabled without the
+	// metrics server, which serves the pprof endpoints.
+	ErrPprofWithoutMetricsListen = errors.New("pprof is served by the metrics server, --metrics-listen option is required")
 
 	// DefaultGRPCListen is the default gRPC server listen address.
 	DefaultGRPCListen = "127.0.0.1:2379"
 	// DefaultEtcdKeyPrefix is the default key prefix in the mimicking
 	// etcd v3 server.
 	DefaultEtcdKeyPrefix = "/apisix"
+	// DefaultXDSWatchDebounce is the default window to coalesce the file
+	// system notifications of the same file.
+	DefaultXDSWatchDebounce = 100 * time.Millisecond
+	// DefaultClusterConnectTimeouts is the default connect timeouts for
+	// clusters which don't specify it, keyed by the cluster type. DNS
+	// clusters get a longer timeout since the name resolution might be slow.
+	DefaultClusterConnectTimeouts = map[string]string{
+		"strict_dns":  "10s",
+		"logical_dns": "10s",
+	}
+	// DefaultNotifyWebhookTimeout is the default timeout of each request
+	// to the notify webhook.
+	DefaultNotifyWebhookTimeout = 5 * time.Second
+	// DefaultNotifyWebhookRetries is the default number of retries when
+	// the request to the notify webhook fails.
+	DefaultNotifyWebhookRetries = 3
 )
 
 // RunningContext contains data which can be decided only when running.
@@ -59,11 +159,58 @@ type Config struct {
 	// The destination of logs.
 	LogOutput string `json:"log_output" yaml:"log_output"`
 	// The Provisioner to use.
-	// Value can be "xds-v3-file", "xds-v3-grpc".
+	// Value can be "xds-v3-file", "xds-v3-grpc", "xds-v3-configmap".
 	Provisioner string `json:"provisioner" yaml:"provisioner"`
 	// The watched xds files, only valid if the Provisioner is "xds-v3-file"
 	XDSWatchFiles   []string `json:"xds_watch_files" yaml:"xds_watch_files"`
 	XDSConfigSource string   `json:"xds_config_source" yaml:"xds_config_source"`
+	// The client certificate, private key and the CA certificate (PEM
+	// files) to connect to the XDS config source over TLS, only valid if
+	// the Provisioner is "xds-v3-grpc". The connection is plaintext if the
+	// certificate is empty, and the system roots are used if the CA is.
+	XDSClientCert string `json:"xds_client_cert" yaml:"xds_client_cert"`
+	XDSClientKey  string `json:"xds_client_key" yaml:"xds_client_key"`
+	XDSCACert     string `json:"xds_ca_cert" yaml:"xds_ca_cert"`
+	// The interval (in seconds) to poll the watched files, it's used when
+	// the file system notifications (inotify) cannot be trusted, changes
+	// are detected by the modification time and the size of files.
+	// Notifications are used if it's 0.
+	XDSPollInterval int `json:"xds_poll_interval" yaml:"xds_poll_interval"`
+	// The window to coalesce the file system notifications of the same
+	// file, so that a burst of writes only triggers one parse, it's not
+	// used if it's 0.
+	XDSWatchDebounce time.Duration `json:"xds_watch_debounce" yaml:"xds_watch_debounce"`
+	// The max number of files tracked by the xds-v3-file provisioner, and
+	// the max number of xDS resources in them, contents of files exceeding
+	// the limits are rejected (the last ones are kept). There is no limit
+	// if it's 0.
+	XDSMaxTrackedFiles     int `json:"xds_max_tracked_files" yaml:"xds_max_tracked_files"`
+	XDSMaxTrackedResources int `json:"xds_max_tracked_resources" yaml:"xds_max_tracked_resources"`
+	// Whether to merge the partial updates (DiscoveryResponses marked by the
+	// control_plane.identifier "apisix-mesh-agent/partial-update") into the
+	// last resources of the same file, instead of replacing them.
+	XDSPartialUpdates bool `json:"xds_partial_updates" yaml:"xds_partial_updates"`
+	// Whether the xds-v3-file provisioner exits once the events of the
+	// watched files are delivered, instead of watching them, so that
+	// the files can be translated and applied once in a job.
+	XDSOneshot bool `json:"xds_oneshot" yaml:"xds_oneshot"`
+	// The directory to persist the translated state of the files watched
+	// by the xds-v3-file provisioner, so that only the changes since the
+	// last state are emitted after restarts. It's not persisted if it's
+	// empty.
+	XDSStateDir string `json:"xds_state_dir" yaml:"xds_state_dir"`
+	// The tenants of the watched xds files, keyed by the watched path.
+	// Resources from different tenants are isolated, names of them will
+	// be scoped by the tenant.
+	XDSWatchFileTenants map[string]string `json:"xds_watch_file_tenants" yaml:"xds_watch_file_tenants"`
+	// The namespace and name of the watched ConfigMap, only valid if the
+	// Provisioner is "xds-v3-configmap". The namespace of the resident pod
+	// will be used if the namespace is empty.
+	XDSConfigMapNamespace string `json:"xds_configmap_namespace" yaml:"xds_configmap_namespace"`
+	XDSConfigMapName      string `json:"xds_configmap_name" yaml:"xds_configmap_name"`
+	// The kubeconfig file path, the in-cluster credentials will be used
+	// if it's empty.
+	Kubeconfig string `json:"kubeconfig" yaml:"kubeconfig"`
 	// The grpc listen address
 	GRPCListen string `json:"grpc_listen" yaml:"grpc_listen"`
 	// The key prefix in the mimicking etcd v3 server.
@@ -74,6 +221,90 @@ type Config struct {
 	// correct apisix home path and bin path should be configured.
 	// And when you shutdown apisix-mesh-agent, APISIX will also be closed.
 	RunMode string `json:"run_mode" yaml:"run_mode"`
+	// The scheme used by upstreams translated from xDS clusters, value
+	// can be "http", "https", "grpc" and "grpcs".
+	DefaultUpstreamScheme string `json:"default_upstream_scheme" yaml:"default_upstream_scheme"`
+	// The port used by endpoints which don't specify the port, it's derived
+	// from the DefaultUpstreamScheme if it's 0, i.e. 443 for "https" and
+	// "grpcs", 80 for others.
+	DefaultUpstreamPort int `json:"default_upstream_port" yaml:"default_upstream_port"`
+	// The valid time (in seconds) of the DNS records resolved by Apache APISIX,
+	// it overrides the TTL of records, the TTL will be used if it's 0.
+	// Note it's a global setting in Apache APISIX, so the dns_refresh_rate
+	// in xDS clusters can only be honored if they're same to it.
+	DNSResolverValid int `json:"dns_resolver_valid" yaml:"dns_resolver_valid"`
+	// The connect timeouts (in the duration format like "10s") for clusters
+	// which don't specify it, keyed by the cluster type, which can be "static",
+	// "strict_dns", "logical_dns", "eds" and "original_dst". The global default
+	// in Apache APISIX will be used if the cluster type is not here.
+	ClusterConnectTimeouts map[string]string `json:"cluster_connect_timeouts" yaml:"cluster_connect_timeouts"`
+	// The max number of nodes in an upstream, nodes exceeding it will be
+	// dropped (healthy and higher-weight nodes are preferred), there is no
+	// limit if it's 0.
+	MaxUpstreamNodes int `json:"max_upstream_nodes" yaml:"max_upstream_nodes"`
+	// The JSON file which maps Envoy HTTP filters (by their type urls) to
+	// APISIX plugins, so that filters which are not translated natively
+	// can be covered, see docs/how-it-works.md for the format.
+	FilterPluginMappingFile string `json:"filter_plugin_mapping_file" yaml:"filter_plugin_mapping_file"`
+	// The max number of events (writes) delivered to the downstream per
+	// second, events exceeding it are delayed (but never dropped) to smooth
+	// the bursts, there is no limit if it's 0.
+	MaxWritesPerSecond int `json:"max_writes_per_second" yaml:"max_writes_per_second"`
+	// Whether to mark the generated routes and upstreams with labels
+	// about their provenance, like "managed-by", "source-file" and "xds-type".
+	ProvenanceLabels bool `json:"provenance_labels" yaml:"provenance_labels"`
+	// Whether to mark the generated routes and upstreams with the hash of
+	// the xDS resources they are translated from (the "xds-source-hash"
+	// label), so that out-of-band modifications can be detected cheaply.
+	SourceHashLabels bool `json:"source_hash_labels" yaml:"source_hash_labels"`
+	// The fields which should be ignored when comparing the old and new
+	// resources, changes only on these fields won't generate update events.
+	// Each field is the dot separated path of proto field names, prefixed
+	// by the resource kind ("route", "upstream", "plugin_config", "consumer"
+	// or "ssl"), e.g. "upstream.labels.timestamp".
+	DiffIgnoredFields []string `json:"diff_ignored_fields" yaml:"diff_ignored_fields"`
+	// The order of events in a batch, value can be "default" (added, deleted
+	// and then updated) and "dependency" (upstreams, plugin configs and
+	// then routes are added or updated, deletions come last in the reverse
+	// order), so that routes never refer to missing upstreams.
+	EventOrder string `json:"event_order" yaml:"event_order"`
+	// Whether to extract the plugin sets shared by multiple routes to
+	// plugin_config objects, routes will refer to them instead of embedding
+	// the plugins.
+	SharedPluginConfigs bool `json:"shared_plugin_configs" yaml:"shared_plugin_configs"`
+	// The names or ids of resources which are managed manually, no events
+	// will be generated for them even if they appear in (or disappear from)
+	// the xDS resources.
+	ProtectedResources []string `json:"protected_resources" yaml:"protected_resources"`
+	// Whether to serve the pprof endpoints (/debug/pprof/) and the expvar
+	// endpoint (/debug/vars) on the metrics server, so that profiles can
+	// be captured at runtime, they're only served to the loopback
+	// addresses.
+	EnablePprof bool `json:"enable_pprof" yaml:"enable_pprof"`
+	// The listen address of the metrics server, which serves the metrics
+	// in the Prometheus text format on /metrics, the metrics server is not
+	// launched if it's empty.
+	MetricsListen string `json:"metrics_listen" yaml:"metrics_listen"`
+	// The listen address of the probe server, which serves /healthz (the
+	// liveness) and /readyz (the readiness of the provisioner), the probe
+	// server is not launched if it's empty.
+	ProbeListen string `json:"probe_listen" yaml:"probe_listen"`
+	// Whether to run in the degraded mode (nothing will be translated) instead
+	// of exiting when the xDS adaptor cannot be initialized.
+	DegradeOnAdaptorFailure bool `json:"degrade_on_adaptor_failure" yaml:"degrade_on_adaptor_failure"`
+	// The interval to emit the heartbeat events on the channel of the
+	// provisioner, so that the consumers can detect a stalled provisioner,
+	// no heartbeat is emitted if it's 0.
+	HeartbeatInterval time.Duration `json:"heartbeat_interval" yaml:"heartbeat_interval"`
+	// The URL which a summary of each applied batch of events is POSTed to,
+	// so that the changes can be audited by external systems, nothing will
+	// be notified if it's empty.
+	NotifyWebhook string `json:"notify_webhook" yaml:"notify_webhook"`
+	// The timeout of each request to the notify webhook.
+	NotifyWebhookTimeout time.Duration `json:"notify_webhook_timeout" yaml:"notify_webhook_timeout"`
+	// The number of retries when the request to the notify webhook fails,
+	// the notification is dropped once they're exhausted.
+	NotifyWebhookRetries int `json:"notify_webhook_retries" yaml:"notify_webhook_retries"`
 	// The home path of Apache APISIX.
 	APISIXHomePath string `json:"apisix_home_path" yaml:"apisix_home_path"`
 	// The executable binary path of Apache APISIX.
@@ -97,38 +328,268 @@ func NewDefaultConfig() *Config {
 		APISIXHomePath: DefaultAPISIXHomePath,
 		APISIXBinPath:  DefaultAPISIXBinPath,
 		RunMode:        StandaloneMode,
+		EventOrder:     DefaultEventOrder,
+
+		XDSWatchDebounce: DefaultXDSWatchDebounce,
+
+		NotifyWebhookTimeout: DefaultNotifyWebhookTimeout,
+		NotifyWebhookRetries: DefaultNotifyWebhookRetries,
+
+		DefaultUpstreamScheme:  DefaultUpstreamScheme,
+		ClusterConnectTimeouts: copyStringMap(DefaultClusterConnectTimeouts),
 
 		RunningContext: getRunningContext(),
 	}
 }
 
-// Validate validates the config object.
+// Validate validates the config object, all problems are reported at once,
+// the error is a ValidationErrors if there are more than one.
 func (cfg *Config) Validate() error {
+	var errs ValidationErrors
 	if cfg.Provisioner == "" {
-		return errors.New("unspecified provisioner")
-	}
-	if cfg.Provisioner != XDSV3FileProvisioner && cfg.Provisioner != XDSV3GRPCProvisioner {
-		return ErrUnknownProvisioner
+		errs = append(errs, errors.New("unspecified provisioner"))
+	} else if cfg.Provisioner != XDSV3FileProvisioner && cfg.Provisioner != XDSV3GRPCProvisioner &&
+		cfg.Provisioner != XDSV3ConfigMapProvisioner {
+		errs = append(errs, ErrUnknownProvisioner)
 	}
 	if cfg.Provisioner == XDSV3GRPCProvisioner && cfg.XDSConfigSource == "" {
-		return ErrEmptyXDSConfigSource
+		errs = append(errs, ErrEmptyXDSConfigSource)
 	}
-	ip, port, err := net.SplitHostPort(cfg.GRPCListen)
-	if err != nil {
-		return ErrBadGRPCListen
+	if (cfg.XDSClientCert == "") != (cfg.XDSClientKey == "") || (cfg.XDSCACert != "" && cfg.XDSClientCert == "") {
+		errs = append(errs, ErrBadXDSClientCerts)
+	} else {
+		for _, file := range []string{cfg.XDSClientCert, cfg.XDSClientKey, cfg.XDSCACert} {
+			if file == "" {
+				continue
+			}
+			if err := checkReadable(file); err != nil {
+				errs = append(errs, fmt.Errorf("%w: %s", ErrBadXDSClientCerts, err))
+			}
+		}
+	}
+	if cfg.Provisioner == XDSV3ConfigMapProvisioner {
+		if cfg.XDSConfigMapName == "" {
+			errs = append(errs, ErrEmptyXDSConfigMapName)
+		}
+		if cfg.XDSConfigSource != "" {
+			errs = append(errs, ErrConflictingProvisionerOptions)
+		}
+	}
+	if cfg.Provisioner == XDSV3FileProvisioner {
+		if cfg.XDSConfigSource != "" {
+			errs = append(errs, ErrConflictingProvisionerOptions)
+		}
+		for _, file := range cfg.XDSWatchFiles {
+			if err := checkReadable(file); err != nil {
+				errs = append(errs, fmt.Errorf("%w: %s", ErrBadXDSWatchFile, err))
+			}
+		}
+	} else if len(cfg.XDSWatchFiles) > 0 {
+		errs = append(errs, ErrConflictingProvisionerOptions)
+	}
+	switch cfg.LogLevel {
+	case "debug", "info", "warn", "error", "panic", "fatal":
+	default:
+		errs = append(errs, ErrBadLogLevel)
+	}
+	switch cfg.RunMode {
+	case StandaloneMode, BundleMode:
+	default:
+		errs = append(errs, ErrBadRunMode)
+	}
+	switch cfg.DefaultUpstreamScheme {
+	case "", "http", "https", "grpc", "grpcs":
+	default:
+		errs = append(errs, ErrBadDefaultUpstreamScheme)
+	}
+	if cfg.DefaultUpstreamPort < 0 || cfg.DefaultUpstreamPort > 65535 {
+		errs = append(errs, ErrBadDefaultUpstreamPort)
+	}
+	if cfg.DNSResolverValid < 0 {
+		errs = append(errs, ErrBadDNSResolverValid)
+	}
+	if cfg.XDSPollInterval < 0 {
+		errs = append(errs, ErrBadXDSPollInterval)
+	}
+	if cfg.XDSWatchDebounce < 0 {
+		errs = append(errs, ErrBadXDSWatchDebounce)
+	}
+	if cfg.XDSMaxTrackedFiles < 0 {
+		errs = append(errs, ErrBadXDSMaxTrackedFiles)
+	}
+	if cfg.XDSMaxTrackedResources < 0 {
+		errs = append(errs, ErrBadXDSMaxTrackedResources)
+	}
+	if cfg.XDSOneshot && cfg.Provisioner != XDSV3FileProvisioner {
+		errs = append(errs, ErrOneshotNotSupported)
+	}
+	if !strings.HasPrefix(cfg.EtcdKeyPrefix, "/") || strings.HasSuffix(cfg.EtcdKeyPrefix, "/") {
+		errs = append(errs, ErrBadEtcdKeyPrefix)
+	}
+	if cfg.MaxUpstreamNodes < 0 {
+		errs = append(errs, ErrBadMaxUpstreamNodes)
+	}
+	if cfg.MaxWritesPerSecond < 0 {
+		errs = append(errs, ErrBadMaxWritesPerSecond)
+	}
+	for path, tenant := range cfg.XDSWatchFileTenants {
+		var found bool
+		for _, file := range cfg.XDSWatchFiles {
+			if file == path {
+				found = true
+				break
+			}
+		}
+		if !found || tenant == "" || strings.Contains(tenant, "/") {
+			errs = append(errs, ErrBadXDSWatchFileTenant)
+			break
+		}
+	}
+	for _, field := range cfg.DiffIgnoredFields {
+		if !validDiffIgnoredField(field) {
+			errs = append(errs, ErrBadDiffIgnoredField)
+			break
+		}
+	}
+	switch cfg.EventOrder {
+	case "", DefaultEventOrder, DependencyEventOrder:
+	default:
+		errs = append(errs, ErrBadEventOrder)
+	}
+	if cfg.HeartbeatInterval < 0 {
+		errs = append(errs, ErrBadHeartbeatInterval)
+	}
+	if cfg.NotifyWebhook != "" {
+		u, err := url.Parse(cfg.NotifyWebhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, ErrBadNotifyWebhook)
+		}
+	}
+	if cfg.NotifyWebhookTimeout < 0 {
+		errs = append(errs, ErrBadNotifyWebhookTimeout)
+	}
+	if cfg.NotifyWebhookRetries < 0 {
+		errs = append(errs, ErrBadNotifyWebhookRetries)
+	}
+	for typ, timeout := range cfg.ClusterConnectTimeouts {
+		var valid bool
+		switch typ {
+		case "static", "strict_dns", "logical_dns", "eds", "original_dst":
+			d, err := time.ParseDuration(timeout)
+			valid = err == nil && d > 0
+		}
+		if !valid {
+			errs = append(errs, ErrBadClusterConnectTimeout)
+			break
+		}
+	}
+	if !validListenAddress(cfg.GRPCListen) {
+		errs = append(errs, ErrBadGRPCListen)
+	}
+	if cfg.MetricsListen != "" && !validListenAddress(cfg.MetricsListen) {
+		errs = append(errs, ErrBadMetricsListen)
+	}
+	if cfg.EnablePprof && cfg.MetricsListen == "" {
+		errs = append(errs, ErrPprofWithoutMetricsListen)
+	}
+	if cfg.ProbeListen != "" && !validListenAddress(cfg.ProbeListen) {
+		errs = append(errs, ErrBadProbeListen)
+	}
+	if cfg.XDSStateDir != "" {
+		if err := checkStateDir(cfg.XDSStateDir); err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrBadXDSStateDir, err))
+		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errs
+	}
+}
+
+// ValidationErrors is the errors found by Validate.
+type ValidationErrors []error
+
+func (errs ValidationErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d errors occurred:", len(errs))
+	for _, err := range errs {
+		b.WriteString("\n\t* ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Is reports whether any of the errors matches the target, so that it
+// can be checked by errors.Is.
+func (errs ValidationErrors) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
 	}
+	return false
+}
 
-	if net.ParseIP(ip) == nil {
-		return ErrBadGRPCListen
+// validListenAddress checks whether the addr is in the form "<ip>:<port>".
+func validListenAddress(addr string) bool {
+	ip, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(ip) == nil {
+		return false
 	}
 	pnum, err := strconv.Atoi(port)
-	if err != nil || pnum < 1 || pnum > 65535 {
-		return ErrBadGRPCListen
+	return err == nil && pnum >= 1 && pnum <= 65535
+}
+
+// validDiffIgnoredField checks whether the field is prefixed by a known
+// resource kind, see DiffIgnoredFields.
+func validDiffIgnoredField(field string) bool {
+	for _, kind := range []string{"route.", "upstream.", "plugin_config.", "consumer.", "ssl."} {
+		if strings.HasPrefix(field, kind) && len(field) > len(kind) {
+			return true
+		}
 	}
+	return false
+}
 
+// checkReadable checks whether the path (file or directory) exists and
+// can be read.
+func checkReadable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// checkStateDir checks whether the path is a directory, it's fine if
+// the path doesn't exist as it'll be created.
+func checkStateDir(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", path)
+	}
 	return nil
 }
 
+func copyStringMap(m map[string]string) map[string]string {
+	cm := make(map[string]string, len(m))
+	for k, v := range m {
+		cm[k] = v
+	}
+	return cm
+}
+
 func getRunningContext() *RunningContext {
 	namespace := "default"
 	if value := os.Getenv("POD_NAMESPACE"); value != "" {
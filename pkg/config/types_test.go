@@ -2,9 +2,12 @@ package config
 
 import (
 	"errors"
+	"io/ioutil"
 	"net"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -19,6 +22,9 @@ func TestNewDefaultConfig(t *testing.T) {
 	assert.Equal(t, cfg.APISIXHomePath, DefaultAPISIXHomePath)
 	assert.Equal(t, cfg.APISIXBinPath, DefaultAPISIXBinPath)
 	assert.Equal(t, cfg.RunMode, StandaloneMode)
+	assert.Equal(t, cfg.DefaultUpstreamScheme, DefaultUpstreamScheme)
+	assert.Equal(t, cfg.DefaultUpstreamPort, 0)
+	assert.Equal(t, cfg.ClusterConnectTimeouts, DefaultClusterConnectTimeouts)
 }
 
 func TestConfigValidate(t *testing.T) {
@@ -37,8 +43,183 @@ func TestConfigValidate(t *testing.T) {
 	cfg.GRPCListen = "hello"
 	assert.Equal(t, cfg.Validate(), ErrBadGRPCListen)
 
+	// All problems are reported.
 	cfg.Provisioner = "xds-v3-grpc"
-	assert.Equal(t, cfg.Validate(), ErrEmptyXDSConfigSource)
+	err := cfg.Validate()
+	assert.True(t, errors.Is(err, ErrEmptyXDSConfigSource))
+	assert.True(t, errors.Is(err, ErrBadGRPCListen))
+	assert.False(t, errors.Is(err, ErrBadEtcdKeyPrefix))
+	assert.Equal(t, err.Error(), "2 errors occurred:\n\t* "+ErrEmptyXDSConfigSource.Error()+"\n\t* "+ErrBadGRPCListen.Error())
+
+	cfg = NewDefaultConfig()
+	cfg.Provisioner = "xds-v3-configmap"
+	assert.Equal(t, cfg.Validate(), ErrEmptyXDSConfigMapName)
+	cfg.XDSConfigMapName = "xds"
+	assert.Nil(t, cfg.Validate())
+	cfg.XDSConfigSource = "grpc://127.0.0.1:15010"
+	assert.Equal(t, cfg.Validate(), ErrConflictingProvisionerOptions)
+
+	cfg = NewDefaultConfig()
+	cfg.DefaultUpstreamScheme = "tcp"
+	assert.Equal(t, cfg.Validate(), ErrBadDefaultUpstreamScheme)
+	cfg.DefaultUpstreamScheme = "https"
+	cfg.DefaultUpstreamPort = 65536
+	assert.Equal(t, cfg.Validate(), ErrBadDefaultUpstreamPort)
+	cfg.DefaultUpstreamPort = 8443
+	assert.Nil(t, cfg.Validate())
+
+	cfg.DNSResolverValid = -1
+	assert.Equal(t, cfg.Validate(), ErrBadDNSResolverValid)
+	cfg.DNSResolverValid = 30
+	assert.Nil(t, cfg.Validate())
+
+	cfg.XDSPollInterval = -1
+	assert.Equal(t, cfg.Validate(), ErrBadXDSPollInterval)
+	cfg.XDSPollInterval = 5
+	assert.Nil(t, cfg.Validate())
+
+	cfg.XDSMaxTrackedFiles = -1
+	assert.Equal(t, cfg.Validate(), ErrBadXDSMaxTrackedFiles)
+	cfg.XDSMaxTrackedFiles = 100
+	cfg.XDSMaxTrackedResources = -1
+	assert.Equal(t, cfg.Validate(), ErrBadXDSMaxTrackedResources)
+	cfg.XDSMaxTrackedResources = 10000
+	assert.Nil(t, cfg.Validate())
+
+	cfg.XDSOneshot = true
+	assert.Nil(t, cfg.Validate())
+	cfg.Provisioner = "xds-v3-configmap"
+	cfg.XDSConfigMapName = "xds"
+	assert.Equal(t, cfg.Validate(), ErrOneshotNotSupported)
+	cfg.Provisioner = "xds-v3-file"
+	cfg.XDSOneshot = false
+
+	cfg.EtcdKeyPrefix = "apisix"
+	assert.Equal(t, cfg.Validate(), ErrBadEtcdKeyPrefix)
+	cfg.EtcdKeyPrefix = "/apisix/"
+	assert.Equal(t, cfg.Validate(), ErrBadEtcdKeyPrefix)
+	cfg.EtcdKeyPrefix = "/apisix-gateway-a"
+	assert.Nil(t, cfg.Validate())
+
+	cfg.MaxUpstreamNodes = -1
+	assert.Equal(t, cfg.Validate(), ErrBadMaxUpstreamNodes)
+	cfg.MaxUpstreamNodes = 1000
+	assert.Nil(t, cfg.Validate())
+
+	cfg.MaxWritesPerSecond = -1
+	assert.Equal(t, cfg.Validate(), ErrBadMaxWritesPerSecond)
+	cfg.MaxWritesPerSecond = 100
+	assert.Nil(t, cfg.Validate())
+
+	cfg.ClusterConnectTimeouts = map[string]string{"redis": "10s"}
+	assert.Equal(t, cfg.Validate(), ErrBadClusterConnectTimeout)
+	cfg.ClusterConnectTimeouts = map[string]string{"eds": "10"}
+	assert.Equal(t, cfg.Validate(), ErrBadClusterConnectTimeout)
+	cfg.ClusterConnectTimeouts = map[string]string{"eds": "-1s"}
+	assert.Equal(t, cfg.Validate(), ErrBadClusterConnectTimeout)
+	cfg.ClusterConnectTimeouts = map[string]string{"eds": "500ms"}
+	assert.Nil(t, cfg.Validate())
+
+	cfg.DiffIgnoredFields = []string{"labels.timestamp"}
+	assert.Equal(t, cfg.Validate(), ErrBadDiffIgnoredField)
+	cfg.DiffIgnoredFields = []string{"ssl."}
+	assert.Equal(t, cfg.Validate(), ErrBadDiffIgnoredField)
+	cfg.DiffIgnoredFields = []string{"upstream.labels.timestamp", "route.desc", "plugin_config.desc", "consumer.desc", "ssl.labels.timestamp"}
+	assert.Nil(t, cfg.Validate())
+
+	cfg.XDSWatchDebounce = -time.Second
+	assert.Equal(t, cfg.Validate(), ErrBadXDSWatchDebounce)
+	cfg.XDSWatchDebounce = 0
+	assert.Nil(t, cfg.Validate())
+
+	cfg.EventOrder = "random"
+	assert.Equal(t, cfg.Validate(), ErrBadEventOrder)
+	cfg.EventOrder = DependencyEventOrder
+	assert.Nil(t, cfg.Validate())
+
+	cfg.HeartbeatInterval = -time.Second
+	assert.Equal(t, cfg.Validate(), ErrBadHeartbeatInterval)
+	cfg.HeartbeatInterval = 10 * time.Second
+	assert.Nil(t, cfg.Validate())
+
+	cfg.NotifyWebhook = "ftp://audit.example.com/changes"
+	assert.Equal(t, cfg.Validate(), ErrBadNotifyWebhook)
+	cfg.NotifyWebhook = "audit.example.com"
+	assert.Equal(t, cfg.Validate(), ErrBadNotifyWebhook)
+	cfg.NotifyWebhook = "https://audit.example.com/changes"
+	assert.Nil(t, cfg.Validate())
+	cfg.NotifyWebhookTimeout = -time.Second
+	assert.Equal(t, cfg.Validate(), ErrBadNotifyWebhookTimeout)
+	cfg.NotifyWebhookTimeout = time.Second
+	cfg.NotifyWebhookRetries = -1
+	assert.Equal(t, cfg.Validate(), ErrBadNotifyWebhookRetries)
+	cfg.NotifyWebhookRetries = 0
+	assert.Nil(t, cfg.Validate())
+
+	cfg.EnablePprof = true
+	assert.Equal(t, cfg.Validate(), ErrPprofWithoutMetricsListen)
+	cfg.MetricsListen = "localhost:9090"
+	assert.Equal(t, cfg.Validate(), ErrBadMetricsListen)
+	cfg.MetricsListen = "127.0.0.1:9090"
+	cfg.ProbeListen = "127.0.0.1"
+	assert.Equal(t, cfg.Validate(), ErrBadProbeListen)
+	cfg.ProbeListen = "127.0.0.1:9091"
+	assert.Nil(t, cfg.Validate())
+
+	dir, err := ioutil.TempDir("", "xds")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	assert.Nil(t, os.Mkdir(a, 0755))
+	assert.Nil(t, os.Mkdir(b, 0755))
+	cfg.XDSWatchFiles = []string{a, b}
+	cfg.XDSWatchFileTenants = map[string]string{filepath.Join(dir, "c"): "tenant-c"}
+	assert.Equal(t, cfg.Validate(), ErrBadXDSWatchFileTenant)
+	cfg.XDSWatchFileTenants = map[string]string{a: "tenant/a"}
+	assert.Equal(t, cfg.Validate(), ErrBadXDSWatchFileTenant)
+	cfg.XDSWatchFileTenants = map[string]string{a: "tenant-a"}
+	assert.Nil(t, cfg.Validate())
+
+	cfg.XDSStateDir = filepath.Join(dir, "state")
+	assert.Nil(t, cfg.Validate())
+	f := filepath.Join(dir, "file")
+	assert.Nil(t, ioutil.WriteFile(f, nil, 0644))
+	cfg.XDSStateDir = f
+	assert.True(t, errors.Is(cfg.Validate(), ErrBadXDSStateDir))
+	cfg.XDSStateDir = b
+	assert.Nil(t, cfg.Validate())
+
+	cfg.XDSWatchFiles = []string{a, filepath.Join(dir, "missing")}
+	cfg.XDSWatchFileTenants = nil
+	assert.True(t, errors.Is(cfg.Validate(), ErrBadXDSWatchFile))
+	cfg.XDSWatchFiles = []string{a}
+	cfg.XDSConfigSource = "grpc://127.0.0.1:15010"
+	assert.Equal(t, cfg.Validate(), ErrConflictingProvisionerOptions)
+	cfg.Provisioner = XDSV3GRPCProvisioner
+	assert.Equal(t, cfg.Validate(), ErrConflictingProvisionerOptions)
+	cfg.XDSWatchFiles = nil
+	assert.Nil(t, cfg.Validate())
+
+	cfg.XDSClientCert = a
+	assert.Equal(t, cfg.Validate(), ErrBadXDSClientCerts)
+	cfg.XDSClientKey = filepath.Join(dir, "missing")
+	assert.True(t, errors.Is(cfg.Validate(), ErrBadXDSClientCerts))
+	cfg.XDSClientKey = a
+	cfg.XDSCACert = a
+	assert.Nil(t, cfg.Validate())
+	cfg.XDSClientCert = ""
+	cfg.XDSClientKey = ""
+	assert.Equal(t, cfg.Validate(), ErrBadXDSClientCerts)
+	cfg.XDSCACert = ""
+
+	cfg.LogLevel = "verbose"
+	assert.Equal(t, cfg.Validate(), ErrBadLogLevel)
+	cfg.LogLevel = "warn"
+	cfg.RunMode = "cluster"
+	assert.Equal(t, cfg.Validate(), ErrBadRunMode)
+	cfg.RunMode = BundleMode
+	assert.Nil(t, cfg.Validate())
 }
 
 func TestGetRunningContext(t *testing.T) {
@@ -16,7 +16,10 @@ func (e *etcdV3) checkRangeRequestConformance(r *etcdserverpb.RangeRequest) erro
 	randEnd := string(r.RangeEnd)
 	if !(r.RangeEnd == nil ||
 		(key == e.keyPrefix+"/routes" && randEnd == e.keyPrefix+"/routet") ||
-		(key == e.keyPrefix+"/upstreams" && randEnd == e.keyPrefix+"/upstreamt")) {
+		(key == e.keyPrefix+"/upstreams" && randEnd == e.keyPrefix+"/upstreamt") ||
+		(key == e.keyPrefix+"/plugin_configs" && randEnd == e.keyPrefix+"/plugin_configt") ||
+		(key == e.keyPrefix+"/consumers" && randEnd == e.keyPrefix+"/consumert") ||
+		(key == e.keyPrefix+"/ssl" && randEnd == e.keyPrefix+"/ssm")) {
 
 		log.Warnw("RangeRequest with unsupported key and range_end combination",
 			zap.String("key", string(r.Key)),
@@ -63,7 +66,10 @@ func (e *etcdV3) checkWatchRequestConformance(r *etcdserverpb.WatchRequest) erro
 			return rpctypes.ErrEmptyKey
 		}
 		if !((key == e.keyPrefix+"/routes" && rangeEnd == e.keyPrefix+"/routet") ||
-			(key == e.keyPrefix+"/upstreams" && rangeEnd == e.keyPrefix+"/upstreamt")) {
+			(key == e.keyPrefix+"/upstreams" && rangeEnd == e.keyPrefix+"/upstreamt") ||
+			(key == e.keyPrefix+"/plugin_configs" && rangeEnd == e.keyPrefix+"/plugin_configt") ||
+			(key == e.keyPrefix+"/consumers" && rangeEnd == e.keyPrefix+"/consumert") ||
+			(key == e.keyPrefix+"/ssl" && rangeEnd == e.keyPrefix+"/ssm")) {
 
 			log.Warnw("WatchCreateRequest with unsupported key and range_end combination",
 				zap.String("key", string(wr.CreateRequest.Key)),
@@ -129,6 +129,7 @@ func (e *etcdV3) Serve(listener net.Listener) error {
 			),
 		)
 		mux.HandleFunc("/version", e.version)
+		mux.HandleFunc("/readyz", e.readyz)
 		e.httpSrv = &http.Server{
 			Handler: mux,
 		}
@@ -181,6 +182,27 @@ func (e *etcdV3) PushEvents(events []types.Event) {
 	}
 }
 
+// resourceKey returns the key of the resource under the key prefix, it's
+// same to the key in the etcd used by Apache APISIX, so that APISIX
+// instances with different key prefixes can be targeted. Empty string is
+// returned for unknown resources.
+func (e *etcdV3) resourceKey(obj interface{}) string {
+	switch o := obj.(type) {
+	case *apisix.Route:
+		return e.keyPrefix + "/routes/" + o.Id
+	case *apisix.Upstream:
+		return e.keyPrefix + "/upstreams/" + o.Id
+	case *apisix.PluginConfig:
+		return e.keyPrefix + "/plugin_configs/" + o.Id
+	case *apisix.Consumer:
+		return e.keyPrefix + "/consumers/" + o.Username
+	case *apisix.Ssl:
+		return e.keyPrefix + "/ssl/" + o.Id
+	default:
+		return ""
+	}
+}
+
 func (e *etcdV3) pushEvent(ev *types.Event) {
 	e.logger.Debugw("receive event",
 		zap.Any("event", ev),
@@ -198,12 +220,8 @@ func (e *etcdV3) pushEvent(ev *types.Event) {
 		evType = mvccpb.PUT
 	}
 
-	switch o := obj.(type) {
-	case *apisix.Route:
-		name = e.keyPrefix + "/routes/" + o.Id
-	case *apisix.Upstream:
-		name = e.keyPrefix + "/upstreams/" + o.Id
-	default:
+	name = e.resourceKey(obj)
+	if name == "" {
 		// ignore other resources for now.
 		return
 	}
@@ -269,6 +287,42 @@ func (e *etcdV3) pushEvent(ev *types.Event) {
 					},
 				})
 			}
+		case *apisix.PluginConfig:
+			for id := range ws.pluginConfig {
+				resps = append(resps, &etcdserverpb.WatchResponse{
+					Header: &etcdserverpb.ResponseHeader{
+						Revision: e.revisioner.Revision(),
+					},
+					WatchId: id,
+					Events: []*mvccpb.Event{
+						event,
+					},
+				})
+			}
+		case *apisix.Consumer:
+			for id := range ws.consumer {
+				resps = append(resps, &etcdserverpb.WatchResponse{
+					Header: &etcdserverpb.ResponseHeader{
+						Revision: e.revisioner.Revision(),
+					},
+					WatchId: id,
+					Events: []*mvccpb.Event{
+						event,
+					},
+				})
+			}
+		case *apisix.Ssl:
+			for id := range ws.ssl {
+				resps = append(resps, &etcdserverpb.WatchResponse{
+					Header: &etcdserverpb.ResponseHeader{
+						Revision: e.revisioner.Revision(),
+					},
+					WatchId: id,
+					Events: []*mvccpb.Event{
+						event,
+					},
+				})
+			}
 		}
 		ws.mu.RUnlock()
 		go func(ws *watchStream) {
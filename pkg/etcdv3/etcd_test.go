@@ -109,11 +109,14 @@ func TestPushEvents(t *testing.T) {
 	etcd, err := NewEtcdV3Server(cfg, cache.NewInMemoryCache(), f)
 	assert.Nil(t, err)
 	ws := &watchStream{
-		ctx:      context.Background(),
-		eventCh:  make(chan *etcdserverpb.WatchResponse),
-		etcd:     etcd.(*etcdV3),
-		route:    make(map[int64]struct{}),
-		upstream: make(map[int64]struct{}),
+		ctx:          context.Background(),
+		eventCh:      make(chan *etcdserverpb.WatchResponse),
+		etcd:         etcd.(*etcdV3),
+		route:        make(map[int64]struct{}),
+		upstream:     make(map[int64]struct{}),
+		pluginConfig: make(map[int64]struct{}),
+		consumer:     make(map[int64]struct{}),
+		ssl:          make(map[int64]struct{}),
 	}
 	etcd.(*etcdV3).watchers[1] = ws
 	ws.route[1] = struct{}{}
@@ -130,6 +133,18 @@ func TestPushEvents(t *testing.T) {
 	}
 }
 
+func TestResourceKey(t *testing.T) {
+	e := &etcdV3{keyPrefix: "/apisix"}
+	assert.Equal(t, e.resourceKey(&apisix.Route{Id: "1"}), "/apisix/routes/1")
+	assert.Equal(t, e.resourceKey(&apisix.Upstream{Id: "2"}), "/apisix/upstreams/2")
+	assert.Equal(t, e.resourceKey(&apisix.PluginConfig{Id: "3"}), "/apisix/plugin_configs/3")
+	assert.Equal(t, e.resourceKey("unknown"), "")
+
+	e.keyPrefix = "/gateway-a"
+	assert.Equal(t, e.resourceKey(&apisix.Route{Id: "1"}), "/gateway-a/routes/1")
+	assert.Equal(t, e.resourceKey(&apisix.Upstream{Id: "2"}), "/gateway-a/upstreams/2")
+}
+
 func TestVersion(t *testing.T) {
 	rw := httptest.NewRecorder()
 	req := httptest.NewRequest("GET", "/version", nil)
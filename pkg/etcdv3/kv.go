@@ -166,6 +166,64 @@ func (e *etcdV3) findExactKey(key []byte) (*etcdserverpb.RangeResponse, error) {
 			)
 			return nil, _errInternalError
 		}
+	case "plugin_configs":
+		e.logger.Debugw("request for plugin config",
+			zap.String("plugin_config_id", parts[2]),
+		)
+		pc, err := e.cache.PluginConfig().Get(parts[2])
+		if err != nil {
+			if err == cache.ErrObjectNotFound {
+				return nil, rpctypes.ErrKeyNotFound
+			}
+			return nil, _errInternalError
+		}
+		value, err = json.Marshal(pc)
+		if err != nil {
+			e.logger.Errorw("failed to marshal plugin config",
+				zap.Any("plugin_config", pc),
+				zap.Error(err),
+			)
+			return nil, _errInternalError
+		}
+	case "consumers":
+		e.logger.Debugw("request for consumer",
+			zap.String("username", parts[2]),
+		)
+		c, err := e.cache.Consumer().Get(parts[2])
+		if err != nil {
+			if err == cache.ErrObjectNotFound {
+				return nil, rpctypes.ErrKeyNotFound
+			}
+			return nil, _errInternalError
+		}
+		value, err = json.Marshal(c)
+		if err != nil {
+			e.logger.Errorw("failed to marshal consumer",
+				zap.Any("consumer", c),
+				zap.Error(err),
+			)
+			return nil, _errInternalError
+		}
+	case "ssl":
+		e.logger.Debugw("request for ssl",
+			zap.String("ssl_id", parts[2]),
+		)
+		ssl, err := e.cache.Ssl().Get(parts[2])
+		if err != nil {
+			if err == cache.ErrObjectNotFound {
+				return nil, rpctypes.ErrKeyNotFound
+			}
+			return nil, _errInternalError
+		}
+		value, err = json.Marshal(ssl)
+		if err != nil {
+			// The private key is never logged.
+			e.logger.Errorw("failed to marshal ssl",
+				zap.String("ssl_id", ssl.Id),
+				zap.Error(err),
+			)
+			return nil, _errInternalError
+		}
 	default:
 		e.logger.Warnw("request for unknown resources",
 			zap.String("key", string(key)),
@@ -233,6 +291,66 @@ func (e *etcdV3) findAllKeys(key []byte) (*etcdserverpb.RangeResponse, error) {
 			}
 			kvs = append(kvs, e.composeKeyValue([]byte(itemKey), value))
 		}
+	case "plugin_configs":
+		pcs, err := e.cache.PluginConfig().List()
+		if err != nil {
+			e.logger.Errorw("failed to list plugin configs",
+				zap.Error(err),
+			)
+			return nil, _errInternalError
+		}
+		for _, pc := range pcs {
+			itemKey := e.keyPrefix + "/plugin_configs/" + pc.Id
+			value, err := json.Marshal(pc)
+			if err != nil {
+				e.logger.Errorw("failed to marshal plugin config",
+					zap.Error(err),
+					zap.Any("plugin_config", pc),
+				)
+				return nil, _errInternalError
+			}
+			kvs = append(kvs, e.composeKeyValue([]byte(itemKey), value))
+		}
+	case "consumers":
+		consumers, err := e.cache.Consumer().List()
+		if err != nil {
+			e.logger.Errorw("failed to list consumers",
+				zap.Error(err),
+			)
+			return nil, _errInternalError
+		}
+		for _, c := range consumers {
+			itemKey := e.keyPrefix + "/consumers/" + c.Username
+			value, err := json.Marshal(c)
+			if err != nil {
+				e.logger.Errorw("failed to marshal consumer",
+					zap.Error(err),
+					zap.Any("consumer", c),
+				)
+				return nil, _errInternalError
+			}
+			kvs = append(kvs, e.composeKeyValue([]byte(itemKey), value))
+		}
+	case "ssl":
+		ssls, err := e.cache.Ssl().List()
+		if err != nil {
+			e.logger.Errorw("failed to list ssls",
+				zap.Error(err),
+			)
+			return nil, _errInternalError
+		}
+		for _, ssl := range ssls {
+			itemKey := e.keyPrefix + "/ssl/" + ssl.Id
+			value, err := json.Marshal(ssl)
+			if err != nil {
+				e.logger.Errorw("failed to marshal ssl",
+					zap.Error(err),
+					zap.String("ssl_id", ssl.Id),
+				)
+				return nil, _errInternalError
+			}
+			kvs = append(kvs, e.composeKeyValue([]byte(itemKey), value))
+		}
 	default:
 		return nil, rpctypes.ErrKeyNotFound
 	}
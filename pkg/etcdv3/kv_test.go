@@ -191,6 +191,39 @@ func TestFindAllKeys(t *testing.T) {
 	assert.Len(t, resp.Kvs, 1)
 	assert.Equal(t, resp.Kvs[0].CreateRevision, int64(90))
 	assert.Equal(t, resp.Kvs[0].ModRevision, int64(90))
+
+	pc := &apisix.PluginConfig{
+		Id: "1",
+	}
+	fr.rev++
+	assert.Nil(t, e.cache.PluginConfig().Insert(pc))
+	resp, err = e.findAllKeys([]byte("/apisix/plugin_configs"))
+	assert.Nil(t, err)
+	assert.Len(t, resp.Kvs, 1)
+	assert.Equal(t, resp.Kvs[0].Key, []byte("/apisix/plugin_configs/1"))
+	assert.Equal(t, resp.Kvs[0].ModRevision, int64(91))
+
+	c := &apisix.Consumer{
+		Username: "alice",
+	}
+	fr.rev++
+	assert.Nil(t, e.cache.Consumer().Insert(c))
+	resp, err = e.findAllKeys([]byte("/apisix/consumers"))
+	assert.Nil(t, err)
+	assert.Len(t, resp.Kvs, 1)
+	assert.Equal(t, resp.Kvs[0].Key, []byte("/apisix/consumers/alice"))
+	assert.Equal(t, resp.Kvs[0].ModRevision, int64(92))
+
+	ssl := &apisix.Ssl{
+		Id: "1",
+	}
+	fr.rev++
+	assert.Nil(t, e.cache.Ssl().Insert(ssl))
+	resp, err = e.findAllKeys([]byte("/apisix/ssl"))
+	assert.Nil(t, err)
+	assert.Len(t, resp.Kvs, 1)
+	assert.Equal(t, resp.Kvs[0].Key, []byte("/apisix/ssl/1"))
+	assert.Equal(t, resp.Kvs[0].ModRevision, int64(93))
 }
 
 func TestRangeRequest(t *testing.T) {
@@ -0,0 +1,126 @@
+package etcdv3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/api7/apisix-mesh-agent/pkg/cache"
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner"
+)
+
+// _maxReadinessReasons is the max number of dangling references shown in
+// the reason of the readiness check.
+const _maxReadinessReasons = 5
+
+// Readiness is the result of the readiness check.
+type Readiness struct {
+	Ready  bool   `json:"ready"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// CheckReadiness checks whether the configuration is ready to be served, it's
+// not ready while the provisioner (reported by the r, it's optional) is not
+// ready, or routes refer to upstreams or plugin configs that are not received
+// yet (dangling references), since requests hitting these routes fail. It's
+// the only source of the readiness, so all the readiness endpoints agree.
+func CheckReadiness(c cache.Cache, r provisioner.ReadinessReporter) Readiness {
+	if r != nil && !r.Ready() {
+		return Readiness{Reason: "provisioner is not ready"}
+	}
+	dangling, err := findDanglingReferences(c)
+	if err != nil {
+		return Readiness{Reason: err.Error()}
+	}
+	if len(dangling) > 0 {
+		return Readiness{Reason: fmt.Sprintf("%d dangling references: %s", len(dangling), summarizeReasons(dangling))}
+	}
+	return Readiness{Ready: true}
+}
+
+// WriteReadiness writes the readiness as the response, the status code is
+// 503 if it's not ready.
+func WriteReadiness(w http.ResponseWriter, r Readiness, logger *log.Logger) {
+	status := http.StatusOK
+	if !r.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	data, _ := json.Marshal(r)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(data); err != nil {
+		logger.Warnw("failed to send readiness",
+			zap.Error(err),
+		)
+	}
+}
+
+// readyz reports the readiness, the provisioner readiness is reported by
+// the revisioner if it implements provisioner.ReadinessReporter.
+func (e *etcdV3) readyz(w http.ResponseWriter, req *http.Request) {
+	r, _ := e.revisioner.(provisioner.ReadinessReporter)
+	WriteReadiness(w, CheckReadiness(e.cache, r), e.logger)
+}
+
+// findDanglingReferences checks the referential integrity of the cached
+// resources, the references which cannot be resolved are returned (sorted).
+func findDanglingReferences(c cache.Cache) ([]string, error) {
+	routes, err := c.Route().List()
+	if err != nil {
+		return nil, err
+	}
+	upstreams, err := c.Upstream().List()
+	if err != nil {
+		return nil, err
+	}
+	pluginConfigs, err := c.PluginConfig().List()
+	if err != nil {
+		return nil, err
+	}
+	upstreamIds := make(map[string]struct{}, len(upstreams))
+	for _, ups := range upstreams {
+		upstreamIds[ups.Id] = struct{}{}
+	}
+	pluginConfigIds := make(map[string]struct{}, len(pluginConfigs))
+	for _, pc := range pluginConfigs {
+		pluginConfigIds[pc.Id] = struct{}{}
+	}
+
+	var dangling []string
+	checkUpstream := func(route, id string) {
+		if id == "" {
+			return
+		}
+		if _, ok := upstreamIds[id]; !ok {
+			dangling = append(dangling, fmt.Sprintf("route %s refers to missing upstream %s", route, id))
+		}
+	}
+	for _, r := range routes {
+		checkUpstream(r.Id, r.UpstreamId)
+		for _, rule := range r.GetPlugins().GetTrafficSplit().GetRules() {
+			for _, wu := range rule.GetWeightedUpstreams() {
+				checkUpstream(r.Id, wu.GetUpstreamId())
+			}
+		}
+		if r.PluginConfigId == "" {
+			continue
+		}
+		if _, ok := pluginConfigIds[r.PluginConfigId]; !ok {
+			dangling = append(dangling, fmt.Sprintf("route %s refers to missing plugin config %s", r.Id, r.PluginConfigId))
+		}
+	}
+	sort.Strings(dangling)
+	return dangling, nil
+}
+
+func summarizeReasons(reasons []string) string {
+	if len(reasons) <= _maxReadinessReasons {
+		return strings.Join(reasons, "; ")
+	}
+	return fmt.Sprintf("%s; and %d more", strings.Join(reasons[:_maxReadinessReasons], "; "), len(reasons)-_maxReadinessReasons)
+}
@@ -0,0 +1,91 @@
+package etcdv3
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/cache"
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestReadyz(t *testing.T) {
+	c := cache.NewInMemoryCache()
+	e := &etcdV3{
+		logger: log.DefaultLogger,
+		cache:  c,
+	}
+	readyz := func() (int, string) {
+		rw := httptest.NewRecorder()
+		e.readyz(rw, httptest.NewRequest("GET", "/readyz", nil))
+		return rw.Code, rw.Body.String()
+	}
+
+	code, body := readyz()
+	assert.Equal(t, code, 200)
+	assert.Equal(t, body, `{"ready":true}`)
+
+	assert.Nil(t, c.Route().Insert(&apisix.Route{
+		Id:             "1",
+		UpstreamId:     "2",
+		PluginConfigId: "3",
+		Plugins: &apisix.Plugins{
+			TrafficSplit: &apisix.TrafficSplit{
+				Rules: []*apisix.TrafficSplit_Rule{
+					{
+						WeightedUpstreams: []*apisix.TrafficSplit_WeightedUpstream{
+							{UpstreamId: "4", Weight: 1},
+							{Weight: 1},
+						},
+					},
+				},
+			},
+		},
+	}))
+	code, body = readyz()
+	assert.Equal(t, code, 503)
+	assert.Equal(t, body, `{"ready":false,"reason":"3 dangling references: route 1 refers to missing plugin config 3; route 1 refers to missing upstream 2; route 1 refers to missing upstream 4"}`)
+
+	assert.Nil(t, c.Upstream().Insert(&apisix.Upstream{Id: "2"}))
+	assert.Nil(t, c.Upstream().Insert(&apisix.Upstream{Id: "4"}))
+	assert.Nil(t, c.PluginConfig().Insert(&apisix.PluginConfig{Id: "3"}))
+	code, body = readyz()
+	assert.Equal(t, code, 200)
+	assert.Equal(t, body, `{"ready":true}`)
+}
+
+type readyRevisioner struct {
+	fakeRevisioner
+	ready bool
+}
+
+func (r *readyRevisioner) Ready() bool {
+	return r.ready
+}
+
+func TestReadyzProvisionerNotReady(t *testing.T) {
+	r := &readyRevisioner{}
+	e := &etcdV3{
+		logger:     log.DefaultLogger,
+		cache:      cache.NewInMemoryCache(),
+		revisioner: r,
+	}
+	rw := httptest.NewRecorder()
+	e.readyz(rw, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, rw.Code, 503)
+	assert.Equal(t, rw.Body.String(), `{"ready":false,"reason":"provisioner is not ready"}`)
+
+	r.ready = true
+	rw = httptest.NewRecorder()
+	e.readyz(rw, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, rw.Code, 200)
+	assert.Equal(t, rw.Body.String(), `{"ready":true}`)
+}
+
+func TestSummarizeReasons(t *testing.T) {
+	reasons := []string{"a", "b", "c", "d", "e", "f", "g"}
+	assert.Equal(t, summarizeReasons(reasons[:2]), "a; b")
+	assert.Equal(t, summarizeReasons(reasons), "a; b; c; d; e; and 2 more")
+}
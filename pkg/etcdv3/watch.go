@@ -34,14 +34,17 @@ func randInt64() int64 {
 }
 
 type watchStream struct {
-	id       int64
-	ctx      context.Context
-	etcd     *etcdV3
-	stream   etcdserverpb.Watch_WatchServer
-	mu       sync.RWMutex
-	route    map[int64]struct{}
-	upstream map[int64]struct{}
-	eventCh  chan *etcdserverpb.WatchResponse
+	id           int64
+	ctx          context.Context
+	etcd         *etcdV3
+	stream       etcdserverpb.Watch_WatchServer
+	mu           sync.RWMutex
+	route        map[int64]struct{}
+	upstream     map[int64]struct{}
+	pluginConfig map[int64]struct{}
+	consumer     map[int64]struct{}
+	ssl          map[int64]struct{}
+	eventCh      chan *etcdserverpb.WatchResponse
 }
 
 func (ws *watchStream) cancelWatch(id int64) bool {
@@ -55,6 +58,18 @@ func (ws *watchStream) cancelWatch(id int64) bool {
 		delete(ws.upstream, id)
 		return true
 	}
+	if _, ok := ws.pluginConfig[id]; ok {
+		delete(ws.pluginConfig, id)
+		return true
+	}
+	if _, ok := ws.consumer[id]; ok {
+		delete(ws.consumer, id)
+		return true
+	}
+	if _, ok := ws.ssl[id]; ok {
+		delete(ws.ssl, id)
+		return true
+	}
 	return false
 }
 
@@ -71,6 +86,21 @@ func (ws *watchStream) createWatch(id int64, resource string) error {
 			return _errDuplicatedWatchId
 		}
 		ws.upstream[id] = struct{}{}
+	} else if resource == "plugin_config" {
+		if _, ok := ws.pluginConfig[id]; ok {
+			return _errDuplicatedWatchId
+		}
+		ws.pluginConfig[id] = struct{}{}
+	} else if resource == "consumer" {
+		if _, ok := ws.consumer[id]; ok {
+			return _errDuplicatedWatchId
+		}
+		ws.consumer[id] = struct{}{}
+	} else if resource == "ssl" {
+		if _, ok := ws.ssl[id]; ok {
+			return _errDuplicatedWatchId
+		}
+		ws.ssl[id] = struct{}{}
 	}
 	return nil
 }
@@ -84,6 +114,12 @@ func (ws *watchStream) firstWatch(id int64, resource string, minRev int64) error
 		kvs, err = ws.findAllRoutes(minRev)
 	} else if resource == "upstream" {
 		kvs, err = ws.findAllUpstreams(minRev)
+	} else if resource == "plugin_config" {
+		kvs, err = ws.findAllPluginConfigs(minRev)
+	} else if resource == "consumer" {
+		kvs, err = ws.findAllConsumers(minRev)
+	} else if resource == "ssl" {
+		kvs, err = ws.findAllSsls(minRev)
 	}
 	if err != nil {
 		return err
@@ -189,6 +225,126 @@ func (ws *watchStream) findAllUpstreams(minRev int64) ([]*mvccpb.KeyValue, error
 	return kvs, nil
 }
 
+func (ws *watchStream) findAllPluginConfigs(minRev int64) ([]*mvccpb.KeyValue, error) {
+	pcs, err := ws.etcd.cache.PluginConfig().List()
+	if err != nil {
+		ws.etcd.logger.Errorw("failed to list plugin configs",
+			zap.Error(err),
+		)
+		return nil, _errInternalError
+	}
+	var kvs []*mvccpb.KeyValue
+	for _, pc := range pcs {
+		key := ws.etcd.keyPrefix + "/plugin_configs/" + pc.Id
+		ws.etcd.metaMu.RLock()
+		m, ok := ws.etcd.metaCache[key]
+		ws.etcd.metaMu.RUnlock()
+		if !ok {
+			ws.etcd.logger.Warnw("found plugin config without metadata",
+				zap.String("plugin_config_name", key),
+			)
+			continue
+		}
+		if m.modRevision >= minRev {
+			value, err := json.Marshal(pc)
+			if err != nil {
+				ws.etcd.logger.Errorw("protojson marshal failure",
+					zap.Error(err),
+					zap.Any("plugin_config", pc),
+				)
+				return nil, err
+			}
+			kvs = append(kvs, &mvccpb.KeyValue{
+				Key:            []byte(key),
+				CreateRevision: m.createRevision,
+				ModRevision:    m.modRevision,
+				Value:          value,
+			})
+		}
+	}
+	return kvs, nil
+}
+
+func (ws *watchStream) findAllConsumers(minRev int64) ([]*mvccpb.KeyValue, error) {
+	consumers, err := ws.etcd.cache.Consumer().List()
+	if err != nil {
+		ws.etcd.logger.Errorw("failed to list consumers",
+			zap.Error(err),
+		)
+		return nil, _errInternalError
+	}
+	var kvs []*mvccpb.KeyValue
+	for _, c := range consumers {
+		key := ws.etcd.keyPrefix + "/consumers/" + c.Username
+		ws.etcd.metaMu.RLock()
+		m, ok := ws.etcd.metaCache[key]
+		ws.etcd.metaMu.RUnlock()
+		if !ok {
+			ws.etcd.logger.Warnw("found consumer without metadata",
+				zap.String("consumer_name", key),
+			)
+			continue
+		}
+		if m.modRevision >= minRev {
+			value, err := json.Marshal(c)
+			if err != nil {
+				ws.etcd.logger.Errorw("protojson marshal failure",
+					zap.Error(err),
+					zap.Any("consumer", c),
+				)
+				return nil, err
+			}
+			kvs = append(kvs, &mvccpb.KeyValue{
+				Key:            []byte(key),
+				CreateRevision: m.createRevision,
+				ModRevision:    m.modRevision,
+				Value:          value,
+			})
+		}
+	}
+	return kvs, nil
+}
+
+func (ws *watchStream) findAllSsls(minRev int64) ([]*mvccpb.KeyValue, error) {
+	ssls, err := ws.etcd.cache.Ssl().List()
+	if err != nil {
+		ws.etcd.logger.Errorw("failed to list ssls",
+			zap.Error(err),
+		)
+		return nil, _errInternalError
+	}
+	var kvs []*mvccpb.KeyValue
+	for _, ssl := range ssls {
+		key := ws.etcd.keyPrefix + "/ssl/" + ssl.Id
+		ws.etcd.metaMu.RLock()
+		m, ok := ws.etcd.metaCache[key]
+		ws.etcd.metaMu.RUnlock()
+		if !ok {
+			ws.etcd.logger.Warnw("found ssl without metadata",
+				zap.String("ssl_name", key),
+			)
+			continue
+		}
+		if m.modRevision >= minRev {
+			value, err := json.Marshal(ssl)
+			if err != nil {
+				ws.etcd.logger.Errorw("protojson marshal failure",
+					zap.Error(err),
+					zap.String("ssl_id", ssl.Id),
+				)
+				return nil, err
+			}
+			kvs = append(kvs, &mvccpb.KeyValue{
+				Key:            []byte(key),
+				CreateRevision: m.createRevision,
+				ModRevision:    m.modRevision,
+				Value:          value,
+			})
+		}
+	}
+	return kvs, nil
+}
+
 func (e *etcdV3) addWatchStream(ws *watchStream) {
 	e.watcherMu.Lock()
 	id := e.nextWatchId
@@ -201,12 +357,15 @@ func (e *etcdV3) addWatchStream(ws *watchStream) {
 func (e *etcdV3) Watch(stream etcdserverpb.Watch_WatchServer) error {
 	ctx, cancel := context.WithCancel(stream.Context())
 	ws := &watchStream{
-		stream:   stream,
-		route:    make(map[int64]struct{}),
-		upstream: make(map[int64]struct{}),
-		etcd:     e,
-		eventCh:  make(chan *etcdserverpb.WatchResponse),
-		ctx:      ctx,
+		stream:       stream,
+		route:        make(map[int64]struct{}),
+		upstream:     make(map[int64]struct{}),
+		pluginConfig: make(map[int64]struct{}),
+		consumer:     make(map[int64]struct{}),
+		ssl:          make(map[int64]struct{}),
+		etcd:         e,
+		eventCh:      make(chan *etcdserverpb.WatchResponse),
+		ctx:          ctx,
 	}
 	e.addWatchStream(ws)
 	e.logger.Debugw("add new watcher",
@@ -283,6 +442,12 @@ func (ws *watchStream) onWire() error {
 				resource = "route"
 			} else if string(uv.CreateRequest.Key) == ws.etcd.keyPrefix+"/upstreams" {
 				resource = "upstream"
+			} else if string(uv.CreateRequest.Key) == ws.etcd.keyPrefix+"/plugin_configs" {
+				resource = "plugin_config"
+			} else if string(uv.CreateRequest.Key) == ws.etcd.keyPrefix+"/consumers" {
+				resource = "consumer"
+			} else if string(uv.CreateRequest.Key) == ws.etcd.keyPrefix+"/ssl" {
+				resource = "ssl"
 			} // others are not concerned
 			if uv.CreateRequest.WatchId == 0 {
 				id = randInt64()
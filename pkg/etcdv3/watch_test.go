@@ -21,18 +21,33 @@ import (
 
 func TestCreateAndCancelWatch(t *testing.T) {
 	ws := &watchStream{
-		route:    make(map[int64]struct{}),
-		upstream: make(map[int64]struct{}),
+		route:        make(map[int64]struct{}),
+		upstream:     make(map[int64]struct{}),
+		pluginConfig: make(map[int64]struct{}),
+		consumer:     make(map[int64]struct{}),
+		ssl:          make(map[int64]struct{}),
 	}
 	assert.Nil(t, ws.createWatch(1, "route"))
 	assert.Nil(t, ws.createWatch(2, "upstream"))
 	assert.Equal(t, ws.createWatch(1, "route"), _errDuplicatedWatchId)
 	assert.Equal(t, ws.createWatch(2, "upstream"), _errDuplicatedWatchId)
+	assert.Nil(t, ws.createWatch(3, "plugin_config"))
+	assert.Equal(t, ws.createWatch(3, "plugin_config"), _errDuplicatedWatchId)
+	assert.Nil(t, ws.createWatch(4, "consumer"))
+	assert.Equal(t, ws.createWatch(4, "consumer"), _errDuplicatedWatchId)
+	assert.Nil(t, ws.createWatch(5, "ssl"))
+	assert.Equal(t, ws.createWatch(5, "ssl"), _errDuplicatedWatchId)
 
 	assert.Equal(t, ws.cancelWatch(1), true)
 	assert.Equal(t, ws.cancelWatch(1), false)
 	assert.Equal(t, ws.cancelWatch(2), true)
 	assert.Equal(t, ws.cancelWatch(2), false)
+	assert.Equal(t, ws.cancelWatch(3), true)
+	assert.Equal(t, ws.cancelWatch(3), false)
+	assert.Equal(t, ws.cancelWatch(4), true)
+	assert.Equal(t, ws.cancelWatch(4), false)
+	assert.Equal(t, ws.cancelWatch(5), true)
+	assert.Equal(t, ws.cancelWatch(5), false)
 }
 
 func TestFindAllRoutes(t *testing.T) {
@@ -63,9 +78,12 @@ func TestFindAllRoutes(t *testing.T) {
 	assert.Nil(t, err)
 
 	ws := &watchStream{
-		etcd:     etcd.(*etcdV3),
-		route:    make(map[int64]struct{}),
-		upstream: make(map[int64]struct{}),
+		etcd:         etcd.(*etcdV3),
+		route:        make(map[int64]struct{}),
+		upstream:     make(map[int64]struct{}),
+		pluginConfig: make(map[int64]struct{}),
+		consumer:     make(map[int64]struct{}),
+		ssl:          make(map[int64]struct{}),
 	}
 	ws.etcd.metaCache = map[string]meta{
 		"/apisix/routes/01": {
@@ -122,9 +140,12 @@ func TestFindAllUpstreams(t *testing.T) {
 	assert.Nil(t, err)
 
 	ws := &watchStream{
-		etcd:     etcd.(*etcdV3),
-		route:    make(map[int64]struct{}),
-		upstream: make(map[int64]struct{}),
+		etcd:         etcd.(*etcdV3),
+		route:        make(map[int64]struct{}),
+		upstream:     make(map[int64]struct{}),
+		pluginConfig: make(map[int64]struct{}),
+		consumer:     make(map[int64]struct{}),
+		ssl:          make(map[int64]struct{}),
 	}
 	ws.etcd.metaCache = map[string]meta{
 		"/apisix/upstreams/01": {
@@ -0,0 +1,172 @@
+// Package metrics collects the metrics of apisix-mesh-agent and exposes
+// them in the Prometheus text exposition format.
+package metrics
+
+import (
+	"context"
+	"expvar"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+)
+
+var (
+	// XDSParseErrors counts the xDS contents (files) which cannot be parsed.
+	XDSParseErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "mesh_agent_xds_parse_errors_total",
+		Help: "The number of xDS contents which cannot be parsed.",
+	})
+	// Events counts the generated events, keyed by the event type.
+	Events = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mesh_agent_events_total",
+		Help: "The number of generated events.",
+	}, []string{"type"})
+	// XDSRejectedContents counts the xDS contents (files) rejected since the
+	// tracking limits are reached.
+	XDSRejectedContents = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "mesh_agent_xds_rejected_contents_total",
+		Help: "The number of xDS contents rejected by the tracking limits.",
+	})
+	// WatchedFiles is the number of xDS files which resources are translated.
+	WatchedFiles = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "mesh_agent_watched_files",
+		Help: "The number of xDS files which resources are translated.",
+	})
+	// LastHeartbeatTimestamp is the unix timestamp of the last heartbeat from
+	// the provisioner.
+	LastHeartbeatTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "mesh_agent_provisioner_last_heartbeat_timestamp_seconds",
+		Help: "The unix timestamp of the last heartbeat from the provisioner.",
+	})
+	// WriteThrottleQueueDepth is the number of events waiting to be pushed to
+	// the etcd server, they're queued or delayed by the write throttling.
+	WriteThrottleQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "mesh_agent_write_throttle_queue_depth",
+		Help: "The number of events delayed by the write throttling.",
+	})
+	// WriteThrottleDelaySeconds is the delay (in seconds) of the last delivery
+	// due to the write throttling.
+	WriteThrottleDelaySeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "mesh_agent_write_throttle_delay_seconds",
+		Help: "The delay of the last delivery due to the write throttling.",
+	})
+	// WriteThrottleDelaySecondsTotal is the accumulated delay (in seconds) due
+	// to the write throttling.
+	WriteThrottleDelaySecondsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "mesh_agent_write_throttle_delay_seconds_total",
+		Help: "The accumulated delay due to the write throttling.",
+	})
+
+	// Registry is the registry of all metrics above.
+	Registry = prometheus.NewRegistry()
+
+	// _eventTypes are the event types exposed by the Events counter, all
+	// of them are exposed even if they're never counted.
+	_eventTypes = []types.EventType{
+		types.EventAdd,
+		types.EventUpdate,
+		types.EventDelete,
+	}
+)
+
+func init() {
+	Registry.MustRegister(
+		XDSParseErrors,
+		Events,
+		XDSRejectedContents,
+		WatchedFiles,
+		LastHeartbeatTimestamp,
+		WriteThrottleQueueDepth,
+		WriteThrottleDelaySeconds,
+		WriteThrottleDelaySecondsTotal,
+	)
+	for _, typ := range _eventTypes {
+		Events.WithLabelValues(string(typ))
+	}
+}
+
+// CountEvents increases the Events counter by the events.
+func CountEvents(events []types.Event) {
+	for _, ev := range events {
+		Events.WithLabelValues(string(ev.Type)).Inc()
+	}
+}
+
+// Handler returns the HTTP handler which serves the metrics.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Server serves the metrics on the /metrics endpoint, and the debugging
+// endpoints if pprof is enabled.
+type Server struct {
+	listener net.Listener
+	srv      *http.Server
+}
+
+// NewServer creates the metrics Server which listens on the addr, the pprof
+// and expvar endpoints are served as well if enablePprof is true.
+func NewServer(addr string, enablePprof bool) (*Server, error) {
+	li, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	if enablePprof {
+		registerDebugHandlers(mux)
+	}
+	return &Server{
+		listener: li,
+		srv: &http.Server{
+			Handler:      mux,
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// Serve serves the metrics until the Server is shut down.
+func (s *Server) Serve() error {
+	if err := s.srv.Serve(s.listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Shutdown shuts down the Server gracefully.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
+
+// registerDebugHandlers registers the handlers for debugging (pprof and
+// expvar). Since they're not authenticated and the listen address might
+// not be a loopback one, only requests from the loopback addresses are
+// served.
+func registerDebugHandlers(mux *http.ServeMux) {
+	mux.Handle("/debug/vars", loopbackOnly(expvar.Handler()))
+	mux.Handle("/debug/pprof/", loopbackOnly(http.HandlerFunc(pprof.Index)))
+	mux.Handle("/debug/pprof/cmdline", loopbackOnly(http.HandlerFunc(pprof.Cmdline)))
+	mux.Handle("/debug/pprof/profile", loopbackOnly(http.HandlerFunc(pprof.Profile)))
+	mux.Handle("/debug/pprof/symbol", loopbackOnly(http.HandlerFunc(pprof.Symbol)))
+	mux.Handle("/debug/pprof/trace", loopbackOnly(http.HandlerFunc(pprof.Trace)))
+}
+
+// loopbackOnly rejects the requests which are not from the loopback
+// addresses with 403.
+func loopbackOnly(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if ip := net.ParseIP(host); err != nil || ip == nil || !ip.IsLoopback() {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
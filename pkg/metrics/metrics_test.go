@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+)
+
+func TestCountEvents(t *testing.T) {
+	added := testutil.ToFloat64(Events.WithLabelValues(string(types.EventAdd)))
+	deleted := testutil.ToFloat64(Events.WithLabelValues(string(types.EventDelete)))
+
+	CountEvents([]types.Event{
+		{Type: types.EventAdd},
+		{Type: types.EventAdd},
+		{Type: types.EventDelete},
+	})
+	assert.Equal(t, testutil.ToFloat64(Events.WithLabelValues(string(types.EventAdd))), added+2)
+	assert.Equal(t, testutil.ToFloat64(Events.WithLabelValues(string(types.EventDelete))), deleted+1)
+}
+
+func TestServer(t *testing.T) {
+	WatchedFiles.Set(3)
+
+	srv, err := NewServer("127.0.0.1:0", false)
+	assert.Nil(t, err)
+	go func() {
+		assert.Nil(t, srv.Serve())
+	}()
+	defer srv.Shutdown(context.Background())
+
+	resp, err := http.Get("http://" + srv.listener.Addr().String() + "/metrics")
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	out := string(body)
+	assert.Contains(t, out, "# TYPE mesh_agent_watched_files gauge\nmesh_agent_watched_files 3\n")
+	// All event types are exposed even if they're never counted.
+	assert.Contains(t, out, "mesh_agent_events_total{type=\"update\"}")
+	assert.Contains(t, out, "# TYPE mesh_agent_xds_parse_errors_total counter\n")
+}
+
+func TestServerDebugHandlers(t *testing.T) {
+	newRequest := func(path, remoteAddr string) *http.Request {
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = remoteAddr
+		return req
+	}
+
+	// Nothing is served if pprof is disabled.
+	srv, err := NewServer("127.0.0.1:0", false)
+	assert.Nil(t, err)
+	defer srv.listener.Close()
+	for _, path := range []string{"/debug/vars", "/debug/pprof/", "/debug/pprof/cmdline"} {
+		rw := httptest.NewRecorder()
+		srv.srv.Handler.ServeHTTP(rw, newRequest(path, "127.0.0.1:12345"))
+		assert.Equal(t, rw.Code, 404, path)
+	}
+
+	srv, err = NewServer("127.0.0.1:0", true)
+	assert.Nil(t, err)
+	defer srv.listener.Close()
+	for _, path := range []string{"/debug/vars", "/debug/pprof/"} {
+		rw := httptest.NewRecorder()
+		srv.srv.Handler.ServeHTTP(rw, newRequest(path, "127.0.0.1:12345"))
+		assert.Equal(t, rw.Code, 200, path)
+		rw = httptest.NewRecorder()
+		srv.srv.Handler.ServeHTTP(rw, newRequest(path, "[::1]:12345"))
+		assert.Equal(t, rw.Code, 200, path)
+
+		// Requests from other addresses are rejected.
+		rw = httptest.NewRecorder()
+		srv.srv.Handler.ServeHTTP(rw, newRequest(path, "10.0.0.1:12345"))
+		assert.Equal(t, rw.Code, 403, path)
+	}
+	// The metrics are still served to everyone.
+	rw := httptest.NewRecorder()
+	srv.srv.Handler.ServeHTTP(rw, newRequest("/metrics", "10.0.0.1:12345"))
+	assert.Equal(t, rw.Code, 200)
+}
@@ -0,0 +1,219 @@
+package provisioner
+
+import (
+	"fmt"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// _maxQueuedBatches is the max number of event batches queued for a per
+// resource type channel of the TypedProvisioner, queued batches will be
+// coalesced once it's reached.
+const _maxQueuedBatches = 64
+
+// TypedProvisioner wraps a Provisioner and dispatches its events to the
+// per resource type channels, so that consumers can subscribe to the types
+// they care about, e.g. process the upstream (endpoint) changes on a
+// dedicated fast path. Channel() still delivers all events as the wrapped
+// provisioner does, none of them are dropped or merged. Events of a channel
+// keep their order, and a slow consumer of one channel won't block others
+// since events are queued. The per resource type channels which are not
+// consumed don't grow without limit, the queued events are coalesced (only
+// the net change of each resource is kept) once too many batches are
+// queued.
+type TypedProvisioner struct {
+	Provisioner
+
+	all       chan []types.Event
+	routes    chan []types.Event
+	upstreams chan []types.Event
+	others    chan []types.Event
+}
+
+// NewTypedProvisioner creates a TypedProvisioner which wraps p. The
+// Channel() of p shouldn't be used by others anymore.
+func NewTypedProvisioner(p Provisioner) *TypedProvisioner {
+	return &TypedProvisioner{
+		Provisioner: p,
+		all:         make(chan []types.Event),
+		routes:      make(chan []types.Event),
+		upstreams:   make(chan []types.Event),
+		others:      make(chan []types.Event),
+	}
+}
+
+// Channel returns a readonly channel where caller can get all events.
+func (p *TypedProvisioner) Channel() <-chan []types.Event {
+	return p.all
+}
+
+// RouteChannel returns a readonly channel where caller can get route events.
+func (p *TypedProvisioner) RouteChannel() <-chan []types.Event {
+	return p.routes
+}
+
+// UpstreamChannel returns a readonly channel where caller can get upstream
+// events.
+func (p *TypedProvisioner) UpstreamChannel() <-chan []types.Event {
+	return p.upstreams
+}
+
+// OtherChannel returns a readonly channel where caller can get events of
+// types other than routes and upstreams.
+func (p *TypedProvisioner) OtherChannel() <-chan []types.Event {
+	return p.others
+}
+
+// Run launches the wrapped provisioner and dispatches its events, all
+// channels will be closed once the channel of the wrapped provisioner
+// is closed.
+func (p *TypedProvisioner) Run(stop chan struct{}) error {
+	var (
+		all       = make(chan []types.Event)
+		routes    = make(chan []types.Event)
+		upstreams = make(chan []types.Event)
+		others    = make(chan []types.Event)
+	)
+	go queueEvents(all, p.all, 0)
+	go queueEvents(routes, p.routes, _maxQueuedBatches)
+	go queueEvents(upstreams, p.upstreams, _maxQueuedBatches)
+	go queueEvents(others, p.others, _maxQueuedBatches)
+	go func() {
+		defer func() {
+			close(all)
+			close(routes)
+			close(upstreams)
+			close(others)
+		}()
+		for events := range p.Provisioner.Channel() {
+			all <- events
+			var r, u, o []types.Event
+			for _, ev := range events {
+				switch eventObject(ev).(type) {
+				case *apisix.Route:
+					r = append(r, ev)
+				case *apisix.Upstream:
+					u = append(u, ev)
+				default:
+					o = append(o, ev)
+				}
+			}
+			if len(r) > 0 {
+				routes <- r
+			}
+			if len(u) > 0 {
+				upstreams <- u
+			}
+			if len(o) > 0 {
+				others <- o
+			}
+		}
+	}()
+	return p.Provisioner.Run(stop)
+}
+
+// queueEvents forwards events from in to out, events are queued so that
+// sending to in never blocks for long. Once max batches are queued, they're
+// coalesced to a single batch, see coalesceEvents, the queue is unbounded
+// if max is 0. The out channel will be closed once in is closed and all
+// queued events are sent.
+func queueEvents(in <-chan []types.Event, out chan<- []types.Event, max int) {
+	var pending [][]types.Event
+	for in != nil || len(pending) > 0 {
+		var (
+			send chan<- []types.Event
+			next []types.Event
+		)
+		if len(pending) > 0 {
+			send = out
+			next = pending[0]
+		}
+		select {
+		case events, ok := <-in:
+			if !ok {
+				in = nil
+				continue
+			}
+			pending = append(pending, events)
+			if max > 0 && len(pending) > max {
+				pending = [][]types.Event{coalesceEvents(pending)}
+			}
+		case send <- next:
+			pending = pending[1:]
+		}
+	}
+	close(out)
+}
+
+// coalesceEvents merges the batches to a single one, only the net change
+// of each resource is kept (in the position of the latest event of it), so
+// the size of it is bounded by the number of resources. A resource added
+// and then deleted is dropped, a resource added and then updated is added
+// with the latest object, and a resource which existed before the batches
+// is deleted or updated, depending on whether it exists in the end. Events
+// of unknown resources are all kept.
+func coalesceEvents(batches [][]types.Event) []types.Event {
+	var (
+		events []types.Event
+		first  = make(map[string]types.EventType)
+		latest = make(map[string]int)
+	)
+	for _, batch := range batches {
+		events = append(events, batch...)
+	}
+	for i, ev := range events {
+		if key, ok := eventKey(ev); ok {
+			if _, seen := first[key]; !seen {
+				first[key] = ev.Type
+			}
+			latest[key] = i
+		}
+	}
+	coalesced := make([]types.Event, 0, len(latest))
+	for i, ev := range events {
+		key, ok := eventKey(ev)
+		if !ok {
+			coalesced = append(coalesced, ev)
+			continue
+		}
+		if latest[key] != i {
+			continue
+		}
+		if ev.Type != types.EventHeartbeat {
+			switch {
+			case first[key] == types.EventAdd && ev.Type == types.EventDelete:
+				continue
+			case first[key] == types.EventAdd:
+				ev.Type = types.EventAdd
+			case ev.Type != types.EventDelete:
+				ev.Type = types.EventUpdate
+			}
+		}
+		coalesced = append(coalesced, ev)
+	}
+	return coalesced
+}
+
+// eventKey returns the key of the resource of the event, heartbeat events
+// share the same key.
+func eventKey(ev types.Event) (string, bool) {
+	obj := eventObject(ev)
+	if obj == nil {
+		return string(ev.Type), true
+	}
+	if o, ok := obj.(interface{ GetId() string }); ok && o.GetId() != "" {
+		return fmt.Sprintf("%T/%s", obj, o.GetId()), true
+	}
+	if o, ok := obj.(interface{ GetUsername() string }); ok && o.GetUsername() != "" {
+		return fmt.Sprintf("%T/%s", obj, o.GetUsername()), true
+	}
+	return "", false
+}
+
+func eventObject(ev types.Event) interface{} {
+	if ev.Type == types.EventDelete {
+		return ev.Tombstone
+	}
+	return ev.Object
+}
@@ -0,0 +1,187 @@
+package provisioner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+type fakeProvisioner struct {
+	evChan chan []types.Event
+}
+
+func (p *fakeProvisioner) Channel() <-chan []types.Event {
+	return p.evChan
+}
+
+func (p *fakeProvisioner) Run(stop chan struct{}) error {
+	<-stop
+	close(p.evChan)
+	return nil
+}
+
+func TestTypedProvisioner(t *testing.T) {
+	fp := &fakeProvisioner{evChan: make(chan []types.Event)}
+	p := NewTypedProvisioner(fp)
+	stop := make(chan struct{})
+	go func() {
+		assert.Nil(t, p.Run(stop))
+	}()
+
+	fp.evChan <- []types.Event{
+		{Type: types.EventAdd, Object: &apisix.Route{Id: "1"}},
+		{Type: types.EventAdd, Object: &apisix.Upstream{Id: "2"}},
+	}
+	fp.evChan <- []types.Event{
+		{Type: types.EventDelete, Tombstone: &apisix.Route{Id: "3"}},
+		{Type: types.EventAdd, Object: &apisix.PluginConfig{Id: "4"}},
+	}
+	fp.evChan <- []types.Event{
+		{Type: types.EventUpdate, Object: &apisix.Upstream{Id: "5"}},
+	}
+
+	// Upstream events can be consumed before the route ones.
+	events := <-p.UpstreamChannel()
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Object.(*apisix.Upstream).Id, "2")
+	events = <-p.UpstreamChannel()
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Object.(*apisix.Upstream).Id, "5")
+
+	events = <-p.RouteChannel()
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Object.(*apisix.Route).Id, "1")
+	events = <-p.RouteChannel()
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Tombstone.(*apisix.Route).Id, "3")
+
+	events = <-p.OtherChannel()
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Object.(*apisix.PluginConfig).Id, "4")
+
+	// Channel() delivers all events as they're emitted.
+	events = <-p.Channel()
+	assert.Len(t, events, 2)
+	events = <-p.Channel()
+	assert.Len(t, events, 2)
+	events = <-p.Channel()
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Object.(*apisix.Upstream).Id, "5")
+
+	close(stop)
+	_, ok := <-p.RouteChannel()
+	assert.False(t, ok)
+	_, ok = <-p.UpstreamChannel()
+	assert.False(t, ok)
+	_, ok = <-p.OtherChannel()
+	assert.False(t, ok)
+	_, ok = <-p.Channel()
+	assert.False(t, ok)
+}
+
+func TestQueueEventsCoalesce(t *testing.T) {
+	in := make(chan []types.Event)
+	out := make(chan []types.Event)
+	go queueEvents(in, out, 2)
+
+	// Nobody consumes the out channel.
+	in <- []types.Event{
+		{Type: types.EventAdd, Object: &apisix.Upstream{Id: "1"}},
+		{Type: types.EventAdd, Object: &apisix.Upstream{Id: "2"}},
+	}
+	in <- []types.Event{
+		{Type: types.EventUpdate, Object: &apisix.Upstream{Id: "1", Name: "v2"}},
+	}
+	in <- []types.Event{
+		{Type: types.EventDelete, Tombstone: &apisix.Upstream{Id: "2"}},
+		{Type: types.EventAdd, Object: &apisix.Consumer{Username: "jack"}},
+	}
+	in <- []types.Event{
+		{Type: types.EventUpdate, Object: &apisix.Upstream{Id: "1", Name: "v3"}},
+	}
+	close(in)
+
+	// The first three batches were coalesced once the limit was exceeded,
+	// the upstream 2 was added and deleted so it's dropped.
+	events := <-out
+	assert.Len(t, events, 2)
+	assert.Equal(t, events[0].Type, types.EventAdd)
+	assert.Equal(t, events[0].Object.(*apisix.Upstream).Name, "v2")
+	assert.Equal(t, events[1].Type, types.EventAdd)
+	assert.Equal(t, events[1].Object.(*apisix.Consumer).Username, "jack")
+
+	events = <-out
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Object.(*apisix.Upstream).Name, "v3")
+
+	_, ok := <-out
+	assert.False(t, ok)
+}
+
+func TestCoalesceEvents(t *testing.T) {
+	events := coalesceEvents([][]types.Event{
+		{
+			{Type: types.EventAdd, Object: &apisix.Route{Id: "1"}},
+			{Type: types.EventDelete, Tombstone: &apisix.Route{Id: "2"}},
+			{Type: types.EventUpdate, Object: &apisix.Route{Id: "3"}},
+			{Type: types.EventHeartbeat},
+		},
+		{
+			{Type: types.EventDelete, Tombstone: &apisix.Route{Id: "1"}},
+			{Type: types.EventAdd, Object: &apisix.Route{Id: "2", Name: "v2"}},
+			{Type: types.EventDelete, Tombstone: &apisix.Route{Id: "3"}},
+			{Type: types.EventHeartbeat},
+		},
+	})
+
+	// Route 1 was added and deleted, route 2 was re-created and route 3
+	// was deleted in the end.
+	assert.Len(t, events, 3)
+	assert.Equal(t, events[0].Type, types.EventUpdate)
+	assert.Equal(t, events[0].Object.(*apisix.Route).Name, "v2")
+	assert.Equal(t, events[1].Type, types.EventDelete)
+	assert.Equal(t, events[1].Tombstone.(*apisix.Route).Id, "3")
+	assert.Equal(t, events[2].Type, types.EventHeartbeat)
+}
+
+func TestTypedProvisionerChannelLossless(t *testing.T) {
+	fp := &fakeProvisioner{evChan: make(chan []types.Event)}
+	p := NewTypedProvisioner(fp)
+	stop := make(chan struct{})
+	go func() {
+		assert.Nil(t, p.Run(stop))
+	}()
+
+	// Neither channel is consumed until all batches are emitted.
+	batches := _maxQueuedBatches + 2
+	for i := 0; i < batches; i++ {
+		fp.evChan <- []types.Event{
+			{Type: types.EventAdd, Object: &apisix.Upstream{Id: "1"}},
+		}
+		fp.evChan <- []types.Event{
+			{Type: types.EventDelete, Tombstone: &apisix.Upstream{Id: "1"}},
+		}
+	}
+	close(stop)
+
+	for i := 0; i < batches; i++ {
+		events := <-p.Channel()
+		assert.Len(t, events, 1)
+		assert.Equal(t, events[0].Type, types.EventAdd)
+		events = <-p.Channel()
+		assert.Len(t, events, 1)
+		assert.Equal(t, events[0].Type, types.EventDelete)
+	}
+	_, ok := <-p.Channel()
+	assert.False(t, ok)
+
+	// The upstream channel was coalesced.
+	received := 0
+	for range p.UpstreamChannel() {
+		received++
+	}
+	assert.Less(t, received, 2*batches)
+}
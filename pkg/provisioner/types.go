@@ -1,9 +1,16 @@
 package provisioner
 
 import (
+	"errors"
+	"time"
+
 	"github.com/api7/apisix-mesh-agent/pkg/types"
 )
 
+// ErrNoClientCerts means the provisioner doesn't connect to the config
+// source with the client certificates.
+var ErrNoClientCerts = errors.New("no client certificates configured")
+
 // Provisioner provisions config event.
 // The source type can be xDS or UDPA or whatever anything else.
 type Provisioner interface {
@@ -12,3 +19,33 @@ type Provisioner interface {
 	// Run launches the provisioner.
 	Run(chan struct{}) error
 }
+
+// Restorer is implemented by the provisioners which persist their state
+// across restarts, they only generate events for the changes since the
+// last state, so the resources restored from it should be applied by the
+// caller in advance.
+type Restorer interface {
+	// Restored returns the resources restored from the persisted state,
+	// as the add events.
+	Restored() []types.Event
+}
+
+// ReadinessReporter is implemented by the provisioners which know whether
+// the initial configuration is provisioned, the provisioners which don't
+// implement it are always considered ready.
+type ReadinessReporter interface {
+	// Ready returns whether the provisioner is ready, it's safe to be
+	// called concurrently.
+	Ready() bool
+}
+
+// CertReloader is implemented by the provisioners which might connect to
+// the config source with the client certificates.
+type CertReloader interface {
+	// ReloadCerts re-reads the client certificate, private key and the CA
+	// certificate, the connections made after it use them, the expiry of
+	// the new client certificate is returned. The last ones are kept if it
+	// fails, ErrNoClientCerts is returned if there are none. It's safe to
+	// be called concurrently.
+	ReloadCerts() (time.Time, error)
+}
@@ -0,0 +1,36 @@
+package util
+
+import (
+	"sort"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// UpstreamIDCollisions finds the upstreams which have different names but
+// share the same id (e.g. the ids hashed from the cluster names collide),
+// such upstreams overwrite each other in the store, and routes to them are
+// mixed. Names of the collided upstreams are returned (sorted), keyed by the
+// id.
+func UpstreamIDCollisions(upstreams []*apisix.Upstream) map[string][]string {
+	names := make(map[string][]string)
+	for _, ups := range upstreams {
+		dup := false
+		for _, name := range names[ups.Id] {
+			if name == ups.Name {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			names[ups.Id] = append(names[ups.Id], ups.Name)
+		}
+	}
+	collisions := make(map[string][]string)
+	for id, list := range names {
+		if len(list) > 1 {
+			sort.Strings(list)
+			collisions[id] = list
+		}
+	}
+	return collisions
+}
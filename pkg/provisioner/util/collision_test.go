@@ -0,0 +1,28 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestUpstreamIDCollisions(t *testing.T) {
+	// The CRC32 checksums of these two names are same.
+	name1 := "outbound|921||httpbin.default.svc.cluster.local"
+	name2 := "outbound|30552600||httpbin.default.svc.cluster.local"
+	assert.Equal(t, id.GenID(name1), id.GenID(name2))
+
+	upstreams := []*apisix.Upstream{
+		{Name: name2, Id: id.GenID(name2)},
+		{Name: "httpbin", Id: id.GenID("httpbin")},
+		{Name: name1, Id: id.GenID(name1)},
+		{Name: name1, Id: id.GenID(name1)},
+	}
+	assert.Equal(t, UpstreamIDCollisions(upstreams), map[string][]string{
+		id.GenID(name1): {name2, name1},
+	})
+	assert.Len(t, UpstreamIDCollisions(upstreams[1:]), 0)
+}
@@ -0,0 +1,23 @@
+package util
+
+import (
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// MergeConsumers appends the consumers in src to dst, consumers whose
+// usernames already exist are skipped, since the same credentials can be
+// configured by auth filters in many listeners.
+func MergeConsumers(dst, src []*apisix.Consumer) []*apisix.Consumer {
+	usernames := make(map[string]struct{}, len(dst))
+	for _, c := range dst {
+		usernames[c.Username] = struct{}{}
+	}
+	for _, c := range src {
+		if _, ok := usernames[c.Username]; ok {
+			continue
+		}
+		usernames[c.Username] = struct{}{}
+		dst = append(dst, c)
+	}
+	return dst
+}
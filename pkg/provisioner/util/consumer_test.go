@@ -0,0 +1,26 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestMergeConsumers(t *testing.T) {
+	dst := []*apisix.Consumer{{Username: "alice"}}
+	src := []*apisix.Consumer{
+		{Username: "alice", Desc: "dup"},
+		{Username: "bob"},
+		{Username: "bob", Desc: "dup"},
+	}
+	merged := MergeConsumers(dst, src)
+	assert.Len(t, merged, 2)
+	assert.Equal(t, merged[0].Username, "alice")
+	assert.Equal(t, merged[0].Desc, "")
+	assert.Equal(t, merged[1].Username, "bob")
+	assert.Equal(t, merged[1].Desc, "")
+
+	assert.Len(t, MergeConsumers(nil, nil), 0)
+}
@@ -0,0 +1,42 @@
+package util
+
+import (
+	"time"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+)
+
+// HeartbeatTicker returns the channel which ticks at the heartbeat interval
+// and the function to stop it. The channel is nil (never ticks) if the
+// interval is not positive, so that it can be selected unconditionally.
+func HeartbeatTicker(interval time.Duration) (<-chan time.Time, func()) {
+	if interval <= 0 {
+		return nil, func() {}
+	}
+	ticker := time.NewTicker(interval)
+	return ticker.C, ticker.Stop
+}
+
+// HeartbeatEvents returns the batch of a heartbeat.
+func HeartbeatEvents() []types.Event {
+	return []types.Event{
+		{
+			Type: types.EventHeartbeat,
+		},
+	}
+}
+
+// DropHeartbeatEvents drops the heartbeat events, it reports whether there
+// was any heartbeat.
+func DropHeartbeatEvents(events []types.Event) ([]types.Event, bool) {
+	var heartbeat bool
+	filtered := make([]types.Event, 0, len(events))
+	for _, ev := range events {
+		if ev.Type == types.EventHeartbeat {
+			heartbeat = true
+			continue
+		}
+		filtered = append(filtered, ev)
+	}
+	return filtered, heartbeat
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestHeartbeatTicker(t *testing.T) {
+	ch, stop := HeartbeatTicker(0)
+	assert.Nil(t, ch)
+	stop()
+
+	ch, stop = HeartbeatTicker(time.Millisecond)
+	defer stop()
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		assert.Fail(t, "no heartbeat")
+	}
+}
+
+func TestDropHeartbeatEvents(t *testing.T) {
+	events, ok := DropHeartbeatEvents(HeartbeatEvents())
+	assert.True(t, ok)
+	assert.Len(t, events, 0)
+
+	add := types.Event{Type: types.EventAdd, Object: &apisix.Route{Id: "1"}}
+	del := types.Event{Type: types.EventDelete, Tombstone: &apisix.Upstream{Id: "2"}}
+	events, ok = DropHeartbeatEvents([]types.Event{add, del})
+	assert.False(t, ok)
+	assert.Equal(t, events, []types.Event{add, del})
+
+	events, ok = DropHeartbeatEvents([]types.Event{add, {Type: types.EventHeartbeat}, del})
+	assert.True(t, ok)
+	assert.Equal(t, events, []types.Event{add, del})
+}
@@ -0,0 +1,29 @@
+package util
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// LimitConnectionsForUpstreams enables the limit-conn plugin on routes which
+// refer to the upstreams with connection limits (translated from the circuit
+// breakers), the limit function returns the limit of an upstream id, or nil.
+// Only the upstream_id of routes is checked, since the limit of a route
+// cannot be shared by the traffic-split legs. Routes which have the plugin
+// already (e.g. from the adaptive_concurrency filter) are kept.
+func LimitConnectionsForUpstreams(routes []*apisix.Route, limit func(id string) *apisix.LimitConn) {
+	for _, r := range routes {
+		if r.GetPlugins().GetLimitConn() != nil || r.GetUpstreamId() == "" {
+			continue
+		}
+		lc := limit(r.GetUpstreamId())
+		if lc == nil {
+			continue
+		}
+		if r.Plugins == nil {
+			r.Plugins = &apisix.Plugins{}
+		}
+		r.Plugins.LimitConn = proto.Clone(lc).(*apisix.LimitConn)
+	}
+}
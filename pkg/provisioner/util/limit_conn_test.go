@@ -0,0 +1,47 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestLimitConnectionsForUpstreams(t *testing.T) {
+	routes := []*apisix.Route{
+		{
+			Name:       "route1",
+			UpstreamId: "1",
+		},
+		{
+			Name:       "route2",
+			UpstreamId: "2",
+		},
+		{
+			Name:       "route3",
+			UpstreamId: "1",
+			Plugins: &apisix.Plugins{
+				LimitConn: &apisix.LimitConn{Conn: 5},
+			},
+		},
+		{
+			Name: "route4",
+		},
+	}
+	limit := &apisix.LimitConn{Conn: 100, Burst: 10}
+	LimitConnectionsForUpstreams(routes, func(id string) *apisix.LimitConn {
+		if id == "1" {
+			return limit
+		}
+		return nil
+	})
+	assert.Equal(t, routes[0].Plugins.LimitConn.Conn, int32(100))
+	assert.Equal(t, routes[0].Plugins.LimitConn.Burst, int32(10))
+	// The limit is cloned.
+	assert.False(t, routes[0].Plugins.LimitConn == limit)
+	assert.Nil(t, routes[1].Plugins)
+	// The existing plugin config is kept.
+	assert.Equal(t, routes[2].Plugins.LimitConn.Conn, int32(5))
+	assert.Nil(t, routes[3].Plugins)
+}
@@ -1,46 +1,103 @@
 package util
 
 import (
+	"fmt"
+	"strings"
+
 	apisixutil "github.com/api7/apisix-mesh-agent/pkg/apisix"
 	"github.com/api7/apisix-mesh-agent/pkg/types"
 	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
 )
 
-// Manifest collects a couples Routes, Upstreams.
+// Manifest collects a couples Routes, Upstreams, PluginConfigs, Consumers
+// and Ssls.
 type Manifest struct {
-	Routes    []*apisix.Route
-	Upstreams []*apisix.Upstream
+	Routes        []*apisix.Route
+	Upstreams     []*apisix.Upstream
+	PluginConfigs []*apisix.PluginConfig
+	Consumers     []*apisix.Consumer
+	Ssls          []*apisix.Ssl
 }
 
 // DiffFrom checks the difference between m and m2 from m's point of view.
-func (m *Manifest) DiffFrom(m2 *Manifest) (*Manifest, *Manifest, *Manifest) {
+// The ignoredFields are field paths prefixed by the resource kind ("route",
+// "upstream", "plugin_config", "consumer" or "ssl"), e.g. "route.desc",
+// "upstream.labels.timestamp", changes only on these fields will not be
+// treated as updates.
+func (m *Manifest) DiffFrom(m2 *Manifest, ignoredFields ...string) (*Manifest, *Manifest, *Manifest) {
 	var (
 		added   Manifest
 		updated Manifest
 		deleted Manifest
+
+		// the ignored fields keyed by the resource kind.
+		ignored = make(map[string][]string)
 	)
+	for _, field := range ignoredFields {
+		if i := strings.Index(field, "."); i > 0 {
+			ignored[field[:i]] = append(ignored[field[:i]], field[i+1:])
+		}
+	}
 
-	a, d, u := apisixutil.CompareRoutes(m.Routes, m2.Routes)
+	a, d, u := apisixutil.CompareRoutes(m.Routes, m2.Routes, ignored["route"]...)
 	added.Routes = append(added.Routes, a...)
 	updated.Routes = append(updated.Routes, u...)
 	deleted.Routes = append(deleted.Routes, d...)
 
-	au, du, uu := apisixutil.CompareUpstreams(m.Upstreams, m2.Upstreams)
+	au, du, uu := apisixutil.CompareUpstreams(m.Upstreams, m2.Upstreams, ignored["upstream"]...)
 	added.Upstreams = append(added.Upstreams, au...)
 	updated.Upstreams = append(updated.Upstreams, uu...)
 	deleted.Upstreams = append(deleted.Upstreams, du...)
 
+	ap, dp, up := apisixutil.ComparePluginConfigs(m.PluginConfigs, m2.PluginConfigs, ignored["plugin_config"]...)
+	added.PluginConfigs = append(added.PluginConfigs, ap...)
+	updated.PluginConfigs = append(updated.PluginConfigs, up...)
+	deleted.PluginConfigs = append(deleted.PluginConfigs, dp...)
+
+	ac, dc, uc := apisixutil.CompareConsumers(m.Consumers, m2.Consumers, ignored["consumer"]...)
+	added.Consumers = append(added.Consumers, ac...)
+	updated.Consumers = append(updated.Consumers, uc...)
+	deleted.Consumers = append(deleted.Consumers, dc...)
+
+	as, ds, us := apisixutil.CompareSsls(m.Ssls, m2.Ssls, ignored["ssl"]...)
+	added.Ssls = append(added.Ssls, as...)
+	updated.Ssls = append(updated.Ssls, us...)
+	deleted.Ssls = append(deleted.Ssls, ds...)
+
 	return &added, &deleted, &updated
 }
 
 // Size calculates the number of resources in the manifest.
 func (m *Manifest) Size() int {
-	return len(m.Upstreams) + len(m.Routes)
+	return len(m.Upstreams) + len(m.Routes) + len(m.PluginConfigs) + len(m.Consumers) + len(m.Ssls)
 }
 
-// Events generates events according to its collection.
+// Events generates events according to its collection. Consumers and
+// PluginConfigs are placed before Routes (or after them for deletion), since
+// Routes refer to them. Ssls are placed first (or last for deletion), since
+// they are the certificates for all of the others.
 func (m *Manifest) Events(evType types.EventType) []types.Event {
 	var events []types.Event
+	if evType != types.EventDelete {
+		for _, s := range m.Ssls {
+			events = append(events, types.Event{
+				Type:   evType,
+				Object: s,
+			})
+		}
+		for _, c := range m.Consumers {
+			events = append(events, types.Event{
+				Type:   evType,
+				Object: c,
+			})
+		}
+		for _, pc := range m.PluginConfigs {
+			events = append(events, types.Event{
+				Type:   evType,
+				Object: pc,
+			})
+		}
+	}
 	for _, r := range m.Routes {
 		if evType == types.EventDelete {
 			events = append(events, types.Event{
@@ -67,5 +124,96 @@ func (m *Manifest) Events(evType types.EventType) []types.Event {
 			})
 		}
 	}
+	if evType == types.EventDelete {
+		for _, pc := range m.PluginConfigs {
+			events = append(events, types.Event{
+				Type:      types.EventDelete,
+				Tombstone: pc,
+			})
+		}
+		for _, c := range m.Consumers {
+			events = append(events, types.Event{
+				Type:      types.EventDelete,
+				Tombstone: c,
+			})
+		}
+		for _, s := range m.Ssls {
+			events = append(events, types.Event{
+				Type:      types.EventDelete,
+				Tombstone: s,
+			})
+		}
+	}
 	return events
 }
+
+// Summarize generates a one-line brief of the changes, only the number of
+// resources will be shown, e.g.
+// "1 added, 2 updated, 0 deleted routes; 1 added, 0 updated, 0 deleted upstreams; ...",
+// all kinds (routes, upstreams, plugin configs, consumers and ssls) are
+// always shown.
+func Summarize(added, deleted, updated *Manifest) string {
+	var routes, upstreams, pluginConfigs, consumers, ssls [3]int
+	for i, m := range []*Manifest{added, updated, deleted} {
+		if m != nil {
+			routes[i] = len(m.Routes)
+			upstreams[i] = len(m.Upstreams)
+			pluginConfigs[i] = len(m.PluginConfigs)
+			consumers[i] = len(m.Consumers)
+			ssls[i] = len(m.Ssls)
+		}
+	}
+	kinds := []struct {
+		name   string
+		counts [3]int
+	}{
+		{"routes", routes},
+		{"upstreams", upstreams},
+		{"plugin configs", pluginConfigs},
+		{"consumers", consumers},
+		{"ssls", ssls},
+	}
+	parts := make([]string, 0, len(kinds))
+	for _, k := range kinds {
+		parts = append(parts, fmt.Sprintf("%d added, %d updated, %d deleted %s",
+			k.counts[0], k.counts[1], k.counts[2], k.name,
+		))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// SummarizeEvents is like Summarize, but the changes are the events, so
+// that events not from the diff of manifests are also counted. Events of
+// unknown types (e.g. heartbeats) are ignored.
+func SummarizeEvents(events []types.Event) string {
+	var added, deleted, updated Manifest
+	for _, ev := range events {
+		var (
+			m   *Manifest
+			obj interface{}
+		)
+		switch ev.Type {
+		case types.EventAdd:
+			m, obj = &added, ev.Object
+		case types.EventUpdate:
+			m, obj = &updated, ev.Object
+		case types.EventDelete:
+			m, obj = &deleted, ev.Tombstone
+		default:
+			continue
+		}
+		switch o := obj.(type) {
+		case *apisix.Route:
+			m.Routes = append(m.Routes, o)
+		case *apisix.Upstream:
+			m.Upstreams = append(m.Upstreams, o)
+		case *apisix.PluginConfig:
+			m.PluginConfigs = append(m.PluginConfigs, o)
+		case *apisix.Consumer:
+			m.Consumers = append(m.Consumers, o)
+		case *apisix.Ssl:
+			m.Ssls = append(m.Ssls, o)
+		}
+	}
+	return Summarize(&added, &deleted, &updated)
+}
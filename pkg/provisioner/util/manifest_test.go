@@ -96,3 +96,250 @@ func TestManifestDiffFrom(t *testing.T) {
 	assert.Equal(t, u.Routes[0].Id, "2")
 	assert.Equal(t, u.Routes[0].Uris, []string{"/foo"})
 }
+
+func TestManifestWithPluginConfigs(t *testing.T) {
+	m := &Manifest{
+		Routes: []*apisix.Route{
+			{
+				Id:             "1",
+				PluginConfigId: "1",
+			},
+		},
+		PluginConfigs: []*apisix.PluginConfig{
+			{
+				Id: "1",
+			},
+		},
+	}
+	assert.Equal(t, m.Size(), 2)
+
+	// Plugin configs should be created before and deleted after the routes.
+	evs := m.Events(types.EventAdd)
+	assert.Len(t, evs, 2)
+	assert.Equal(t, evs[0].Object, m.PluginConfigs[0])
+	assert.Equal(t, evs[1].Object, m.Routes[0])
+	evs = m.Events(types.EventDelete)
+	assert.Len(t, evs, 2)
+	assert.Equal(t, evs[0].Tombstone, m.Routes[0])
+	assert.Equal(t, evs[1].Tombstone, m.PluginConfigs[0])
+
+	m2 := &Manifest{
+		Routes: []*apisix.Route{
+			{
+				Id:             "1",
+				PluginConfigId: "2",
+			},
+		},
+		PluginConfigs: []*apisix.PluginConfig{
+			{
+				Id: "2",
+			},
+		},
+	}
+	a, d, u := m.DiffFrom(m2)
+	assert.Equal(t, a.PluginConfigs, m2.PluginConfigs)
+	assert.Equal(t, d.PluginConfigs, m.PluginConfigs)
+	assert.Equal(t, u.Routes, m2.Routes)
+}
+
+func TestManifestWithConsumers(t *testing.T) {
+	m := &Manifest{
+		Routes: []*apisix.Route{
+			{
+				Id: "1",
+			},
+		},
+		Consumers: []*apisix.Consumer{
+			{
+				Username: "alice",
+			},
+		},
+	}
+	assert.Equal(t, m.Size(), 2)
+
+	// Consumers should be created before and deleted after the routes.
+	evs := m.Events(types.EventAdd)
+	assert.Len(t, evs, 2)
+	assert.Equal(t, evs[0].Object, m.Consumers[0])
+	assert.Equal(t, evs[1].Object, m.Routes[0])
+	evs = m.Events(types.EventDelete)
+	assert.Len(t, evs, 2)
+	assert.Equal(t, evs[0].Tombstone, m.Routes[0])
+	assert.Equal(t, evs[1].Tombstone, m.Consumers[0])
+
+	m2 := &Manifest{
+		Routes: []*apisix.Route{
+			{
+				Id: "1",
+			},
+		},
+		Consumers: []*apisix.Consumer{
+			{
+				Username: "alice",
+				Desc:     "alice",
+			},
+			{
+				Username: "bob",
+			},
+		},
+	}
+	a, d, u := m.DiffFrom(m2)
+	assert.Equal(t, a.Consumers, m2.Consumers[1:])
+	assert.Nil(t, d.Consumers)
+	assert.Equal(t, u.Consumers, m2.Consumers[:1])
+	assert.Nil(t, u.Routes)
+}
+
+func TestManifestWithSsls(t *testing.T) {
+	m := &Manifest{
+		Upstreams: []*apisix.Upstream{
+			{
+				Id: "1",
+			},
+		},
+		Ssls: []*apisix.Ssl{
+			{
+				Id:   "1",
+				Snis: []string{"httpbin.org"},
+			},
+		},
+	}
+	assert.Equal(t, m.Size(), 2)
+
+	// Ssls should be created first and deleted last.
+	evs := m.Events(types.EventAdd)
+	assert.Len(t, evs, 2)
+	assert.Equal(t, evs[0].Object, m.Ssls[0])
+	assert.Equal(t, evs[1].Object, m.Upstreams[0])
+	evs = m.Events(types.EventDelete)
+	assert.Len(t, evs, 2)
+	assert.Equal(t, evs[0].Tombstone, m.Upstreams[0])
+	assert.Equal(t, evs[1].Tombstone, m.Ssls[0])
+
+	m2 := &Manifest{
+		Ssls: []*apisix.Ssl{
+			{
+				Id:   "1",
+				Snis: []string{"httpbin.org", "*.httpbin.org"},
+			},
+			{
+				Id: "2",
+			},
+		},
+	}
+	a, d, u := m.DiffFrom(m2)
+	assert.Equal(t, a.Ssls, m2.Ssls[1:])
+	assert.Nil(t, d.Ssls)
+	assert.Equal(t, u.Ssls, m2.Ssls[:1])
+	assert.Equal(t, d.Upstreams, m.Upstreams)
+}
+
+func TestManifestDiffFromWithIgnoredFields(t *testing.T) {
+	m := &Manifest{
+		Routes: []*apisix.Route{
+			{
+				Id:   "1",
+				Desc: "pushed at 1",
+			},
+		},
+		Upstreams: []*apisix.Upstream{
+			{
+				Id:   "1",
+				Desc: "pushed at 1",
+			},
+		},
+	}
+	m2 := &Manifest{
+		Routes: []*apisix.Route{
+			{
+				Id:   "1",
+				Desc: "pushed at 2",
+			},
+		},
+		Upstreams: []*apisix.Upstream{
+			{
+				Id:   "1",
+				Desc: "pushed at 2",
+			},
+		},
+	}
+	_, _, u := m.DiffFrom(m2, "route.desc")
+	assert.Equal(t, u.Size(), 1)
+	assert.Len(t, u.Upstreams, 1)
+
+	_, _, u = m.DiffFrom(m2, "route.desc", "upstream.desc")
+	assert.Equal(t, u.Size(), 0)
+
+	// Other kinds honor the ignored fields as well.
+	m = &Manifest{
+		PluginConfigs: []*apisix.PluginConfig{{Id: "1", Desc: "pushed at 1"}},
+		Consumers:     []*apisix.Consumer{{Username: "jack", Desc: "pushed at 1"}},
+		Ssls:          []*apisix.Ssl{{Id: "1", Labels: map[string]string{"timestamp": "1"}}},
+	}
+	m2 = &Manifest{
+		PluginConfigs: []*apisix.PluginConfig{{Id: "1", Desc: "pushed at 2"}},
+		Consumers:     []*apisix.Consumer{{Username: "jack", Desc: "pushed at 2"}},
+		Ssls:          []*apisix.Ssl{{Id: "1", Labels: map[string]string{"timestamp": "2"}}},
+	}
+	_, _, u = m.DiffFrom(m2, "route.desc", "upstream.desc")
+	assert.Equal(t, u.Size(), 3)
+
+	_, _, u = m.DiffFrom(m2, "plugin_config.desc", "consumer.desc")
+	assert.Equal(t, u.Size(), 1)
+	assert.Len(t, u.Ssls, 1)
+
+	_, _, u = m.DiffFrom(m2, "plugin_config.desc", "consumer.desc", "ssl.labels.timestamp")
+	assert.Equal(t, u.Size(), 0)
+}
+
+func TestSummarize(t *testing.T) {
+	added := &Manifest{
+		Routes: []*apisix.Route{
+			{}, {}, {},
+		},
+		Upstreams: []*apisix.Upstream{
+			{}, {},
+		},
+	}
+	updated := &Manifest{
+		Routes: []*apisix.Route{
+			{},
+		},
+		Consumers: []*apisix.Consumer{
+			{},
+		},
+	}
+	deleted := &Manifest{
+		PluginConfigs: []*apisix.PluginConfig{
+			{}, {},
+		},
+		Ssls: []*apisix.Ssl{
+			{},
+		},
+	}
+	assert.Equal(t, Summarize(added, deleted, updated),
+		"3 added, 1 updated, 0 deleted routes; 2 added, 0 updated, 0 deleted upstreams; "+
+			"0 added, 0 updated, 2 deleted plugin configs; 0 added, 1 updated, 0 deleted consumers; "+
+			"0 added, 0 updated, 1 deleted ssls")
+	assert.Equal(t, Summarize(nil, nil, nil),
+		"0 added, 0 updated, 0 deleted routes; 0 added, 0 updated, 0 deleted upstreams; "+
+			"0 added, 0 updated, 0 deleted plugin configs; 0 added, 0 updated, 0 deleted consumers; "+
+			"0 added, 0 updated, 0 deleted ssls")
+}
+
+func TestSummarizeEvents(t *testing.T) {
+	events := []types.Event{
+		{Type: types.EventAdd, Object: &apisix.Route{}},
+		{Type: types.EventAdd, Object: &apisix.Upstream{}},
+		{Type: types.EventUpdate, Object: &apisix.Upstream{}},
+		// Upstreams updated by EDS are counted too.
+		{Type: types.EventUpdate, Object: &apisix.Upstream{}},
+		{Type: types.EventDelete, Tombstone: &apisix.Ssl{}},
+		{Type: types.EventDelete, Tombstone: &apisix.Consumer{}},
+	}
+	events = append(events, HeartbeatEvents()...)
+	assert.Equal(t, SummarizeEvents(events),
+		"1 added, 0 updated, 0 deleted routes; 1 added, 2 updated, 0 deleted upstreams; "+
+			"0 added, 0 updated, 0 deleted plugin configs; 0 added, 0 updated, 1 deleted consumers; "+
+			"0 added, 0 updated, 1 deleted ssls")
+}
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+)
+
+// OrderedEvents generates events of the added, deleted and updated manifests
+// (any of them can be nil) in the given order, see config.DefaultEventOrder
+// and config.DependencyEventOrder.
+func OrderedEvents(added, deleted, updated *Manifest, order string) []types.Event {
+	var events []types.Event
+	if order != config.DependencyEventOrder {
+		if added != nil {
+			events = append(events, added.Events(types.EventAdd)...)
+		}
+		if deleted != nil {
+			events = append(events, deleted.Events(types.EventDelete)...)
+		}
+		if updated != nil {
+			events = append(events, updated.Events(types.EventUpdate)...)
+		}
+		return events
+	}
+
+	if added == nil {
+		added = &Manifest{}
+	}
+	if updated == nil {
+		updated = &Manifest{}
+	}
+	// Resources are created (or updated) before the ones referring to
+	// them, and deleted after them.
+	for _, s := range added.Ssls {
+		events = append(events, types.Event{Type: types.EventAdd, Object: s})
+	}
+	for _, s := range updated.Ssls {
+		events = append(events, types.Event{Type: types.EventUpdate, Object: s})
+	}
+	for _, u := range added.Upstreams {
+		events = append(events, types.Event{Type: types.EventAdd, Object: u})
+	}
+	for _, u := range updated.Upstreams {
+		events = append(events, types.Event{Type: types.EventUpdate, Object: u})
+	}
+	for _, c := range added.Consumers {
+		events = append(events, types.Event{Type: types.EventAdd, Object: c})
+	}
+	for _, c := range updated.Consumers {
+		events = append(events, types.Event{Type: types.EventUpdate, Object: c})
+	}
+	for _, pc := range added.PluginConfigs {
+		events = append(events, types.Event{Type: types.EventAdd, Object: pc})
+	}
+	for _, pc := range updated.PluginConfigs {
+		events = append(events, types.Event{Type: types.EventUpdate, Object: pc})
+	}
+	for _, r := range added.Routes {
+		events = append(events, types.Event{Type: types.EventAdd, Object: r})
+	}
+	for _, r := range updated.Routes {
+		events = append(events, types.Event{Type: types.EventUpdate, Object: r})
+	}
+	if deleted != nil {
+		events = append(events, deleted.Events(types.EventDelete)...)
+	}
+	return events
+}
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestOrderedEvents(t *testing.T) {
+	added := &Manifest{
+		Routes:        []*apisix.Route{{Id: "r1"}},
+		Upstreams:     []*apisix.Upstream{{Id: "u1"}},
+		PluginConfigs: []*apisix.PluginConfig{{Id: "pc1"}},
+		Consumers:     []*apisix.Consumer{{Username: "c1"}},
+		Ssls:          []*apisix.Ssl{{Id: "s1"}},
+	}
+	deleted := &Manifest{
+		Routes:    []*apisix.Route{{Id: "r2"}},
+		Upstreams: []*apisix.Upstream{{Id: "u2"}},
+		Consumers: []*apisix.Consumer{{Username: "c2"}},
+		Ssls:      []*apisix.Ssl{{Id: "s2"}},
+	}
+	updated := &Manifest{
+		Routes:    []*apisix.Route{{Id: "r3"}},
+		Upstreams: []*apisix.Upstream{{Id: "u3"}},
+	}
+	brief := func(events []types.Event) []string {
+		var ids []string
+		for _, ev := range events {
+			obj := ev.Object
+			if ev.Type == types.EventDelete {
+				obj = ev.Tombstone
+			}
+			switch o := obj.(type) {
+			case *apisix.Route:
+				ids = append(ids, string(ev.Type)+":"+o.Id)
+			case *apisix.Upstream:
+				ids = append(ids, string(ev.Type)+":"+o.Id)
+			case *apisix.PluginConfig:
+				ids = append(ids, string(ev.Type)+":"+o.Id)
+			case *apisix.Consumer:
+				ids = append(ids, string(ev.Type)+":"+o.Username)
+			case *apisix.Ssl:
+				ids = append(ids, string(ev.Type)+":"+o.Id)
+			}
+		}
+		return ids
+	}
+
+	events := OrderedEvents(added, deleted, updated, config.DefaultEventOrder)
+	assert.Equal(t, brief(events), []string{
+		"add:s1", "add:c1", "add:pc1", "add:r1", "add:u1",
+		"delete:r2", "delete:u2", "delete:c2", "delete:s2",
+		"update:r3", "update:u3",
+	})
+	// The empty order is same to the default one.
+	assert.Equal(t, OrderedEvents(added, deleted, updated, ""), events)
+
+	events = OrderedEvents(added, deleted, updated, config.DependencyEventOrder)
+	assert.Equal(t, brief(events), []string{
+		"add:s1",
+		"add:u1", "update:u3",
+		"add:c1",
+		"add:pc1",
+		"add:r1", "update:r3",
+		"delete:r2", "delete:u2", "delete:c2", "delete:s2",
+	})
+
+	events = OrderedEvents(nil, deleted, nil, config.DependencyEventOrder)
+	assert.Equal(t, brief(events), []string{"delete:r2", "delete:u2", "delete:c2", "delete:s2"})
+}
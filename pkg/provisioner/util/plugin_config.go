@@ -0,0 +1,54 @@
+package util
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// ExtractPluginConfigs finds the identical plugin sets shared by at least two
+// routes, and moves them to apisix.PluginConfig objects, these routes will
+// refer to them by the plugin_config_id field. The id of a plugin config is
+// derived from the hash of the namespace and the plugin set, so it's stable
+// across translations. The namespace should be unique for each set of routes
+// (e.g. the filename for the file provisioner), so that plugin configs from
+// different sets never collide.
+func ExtractPluginConfigs(namespace string, routes []*apisix.Route) []*apisix.PluginConfig {
+	var (
+		order  []string
+		groups = make(map[string][]*apisix.Route)
+	)
+	for _, r := range routes {
+		if r.Plugins == nil {
+			continue
+		}
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(r.Plugins)
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		key := string(data)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], r)
+	}
+
+	var pcs []*apisix.PluginConfig
+	for _, key := range order {
+		group := groups[key]
+		if len(group) < 2 {
+			continue
+		}
+		pc := &apisix.PluginConfig{
+			Id:      id.GenID(namespace + "/" + key),
+			Plugins: group[0].Plugins,
+		}
+		for _, r := range group {
+			r.Plugins = nil
+			r.PluginConfigId = pc.Id
+		}
+		pcs = append(pcs, pc)
+	}
+	return pcs
+}
@@ -0,0 +1,57 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestExtractPluginConfigs(t *testing.T) {
+	newPlugins := func(status int32) *apisix.Plugins {
+		return &apisix.Plugins{
+			FaultInjection: &apisix.FaultInjection{
+				Abort: &apisix.FaultInjection_Abort{
+					HttpStatus: status,
+				},
+			},
+		}
+	}
+	routes := []*apisix.Route{
+		{Id: "1", Plugins: newPlugins(503)},
+		{Id: "2", Plugins: newPlugins(403)},
+		{Id: "3", Plugins: newPlugins(503)},
+		{Id: "4"},
+	}
+	pcs := ExtractPluginConfigs("a.json", routes)
+	assert.Len(t, pcs, 1)
+	assert.NotEmpty(t, pcs[0].Id)
+	assert.Equal(t, pcs[0].Plugins.FaultInjection.Abort.HttpStatus, int32(503))
+
+	assert.Nil(t, routes[0].Plugins)
+	assert.Equal(t, routes[0].PluginConfigId, pcs[0].Id)
+	assert.Nil(t, routes[2].Plugins)
+	assert.Equal(t, routes[2].PluginConfigId, pcs[0].Id)
+	// Plugins used by only one route are still embedded.
+	assert.NotNil(t, routes[1].Plugins)
+	assert.Empty(t, routes[1].PluginConfigId)
+	assert.Empty(t, routes[3].PluginConfigId)
+
+	// Ids are stable for the same namespace and plugin set.
+	routes2 := []*apisix.Route{
+		{Id: "5", Plugins: newPlugins(503)},
+		{Id: "6", Plugins: newPlugins(503)},
+	}
+	pcs2 := ExtractPluginConfigs("a.json", routes2)
+	assert.Len(t, pcs2, 1)
+	assert.Equal(t, pcs2[0].Id, pcs[0].Id)
+
+	routes3 := []*apisix.Route{
+		{Id: "5", Plugins: newPlugins(503)},
+		{Id: "6", Plugins: newPlugins(503)},
+	}
+	pcs3 := ExtractPluginConfigs("b.json", routes3)
+	assert.Len(t, pcs3, 1)
+	assert.NotEqual(t, pcs3[0].Id, pcs[0].Id)
+}
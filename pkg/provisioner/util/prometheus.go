@@ -0,0 +1,35 @@
+package util
+
+import (
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// EnablePrometheusForTrackedUpstreams enables the prometheus plugin on
+// routes which refer to the stats tracked upstreams, either directly or
+// by the traffic-split legs, the tracked function tells whether an
+// upstream id is tracked. Routes which have the plugin already are kept.
+func EnablePrometheusForTrackedUpstreams(routes []*apisix.Route, tracked func(id string) bool) {
+	for _, r := range routes {
+		if r.GetPlugins().GetPrometheus() != nil || !refersToTrackedUpstream(r, tracked) {
+			continue
+		}
+		if r.Plugins == nil {
+			r.Plugins = &apisix.Plugins{}
+		}
+		r.Plugins.Prometheus = &apisix.Prometheus{}
+	}
+}
+
+func refersToTrackedUpstream(r *apisix.Route, tracked func(id string) bool) bool {
+	if r.GetUpstreamId() != "" && tracked(r.GetUpstreamId()) {
+		return true
+	}
+	for _, rule := range r.GetPlugins().GetTrafficSplit().GetRules() {
+		for _, wu := range rule.GetWeightedUpstreams() {
+			if wu.GetUpstreamId() != "" && tracked(wu.GetUpstreamId()) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestEnablePrometheusForTrackedUpstreams(t *testing.T) {
+	routes := []*apisix.Route{
+		{
+			Name:       "route1",
+			UpstreamId: "1",
+		},
+		{
+			Name:       "route2",
+			UpstreamId: "2",
+			Plugins: &apisix.Plugins{
+				TrafficSplit: &apisix.TrafficSplit{
+					Rules: []*apisix.TrafficSplit_Rule{
+						{
+							WeightedUpstreams: []*apisix.TrafficSplit_WeightedUpstream{
+								{Weight: 3},
+								{UpstreamId: "1", Weight: 7},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:       "route3",
+			UpstreamId: "2",
+		},
+		{
+			Name:       "route4",
+			UpstreamId: "1",
+			Plugins: &apisix.Plugins{
+				Prometheus: &apisix.Prometheus{PreferName: true},
+			},
+		},
+	}
+	EnablePrometheusForTrackedUpstreams(routes, func(id string) bool {
+		return id == "1"
+	})
+	assert.NotNil(t, routes[0].Plugins.Prometheus)
+	assert.NotNil(t, routes[1].Plugins.Prometheus)
+	assert.NotNil(t, routes[1].Plugins.TrafficSplit)
+	assert.Nil(t, routes[2].Plugins)
+	// The existing plugin config is kept.
+	assert.True(t, routes[3].Plugins.Prometheus.PreferName)
+}
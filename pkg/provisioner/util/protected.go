@@ -0,0 +1,56 @@
+package util
+
+import (
+	"github.com/api7/apisix-mesh-agent/pkg/set"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+)
+
+// NewProtectedSet creates the set of protected resources, each element
+// is the name or the id of a resource.
+func NewProtectedSet(resources []string) set.StringSet {
+	protected := make(set.StringSet, len(resources))
+	for _, r := range resources {
+		protected.Add(r)
+	}
+	return protected
+}
+
+// DropProtectedEvents drops events whose object (or tombstone) is protected,
+// i.e. its name or id (username for consumers) is in the protected set, so resources managed manually
+// won't be overwritten or deleted by the translated ones.
+func DropProtectedEvents(events []types.Event, protected set.StringSet) []types.Event {
+	if len(protected) == 0 {
+		return events
+	}
+	filtered := make([]types.Event, 0, len(events))
+	for _, ev := range events {
+		obj := ev.Object
+		if ev.Type == types.EventDelete {
+			obj = ev.Tombstone
+		}
+		if isProtected(obj, protected) {
+			continue
+		}
+		filtered = append(filtered, ev)
+	}
+	return filtered
+}
+
+func isProtected(obj interface{}, protected set.StringSet) bool {
+	if o, ok := obj.(interface{ GetId() string }); ok {
+		if _, ok := protected[o.GetId()]; ok {
+			return true
+		}
+	}
+	if o, ok := obj.(interface{ GetName() string }); ok {
+		if _, ok := protected[o.GetName()]; ok {
+			return true
+		}
+	}
+	if o, ok := obj.(interface{ GetUsername() string }); ok {
+		if _, ok := protected[o.GetUsername()]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestDropProtectedEvents(t *testing.T) {
+	events := []types.Event{
+		{
+			Type:   types.EventAdd,
+			Object: &apisix.Route{Id: "1", Name: "route1"},
+		},
+		{
+			Type:   types.EventUpdate,
+			Object: &apisix.Upstream{Id: "2", Name: "httpbin"},
+		},
+		{
+			Type:      types.EventDelete,
+			Tombstone: &apisix.Route{Id: "3", Name: "route3"},
+		},
+		{
+			Type:   types.EventAdd,
+			Object: &apisix.PluginConfig{Id: "4"},
+		},
+		{
+			Type:   types.EventAdd,
+			Object: &apisix.Consumer{Username: "alice"},
+		},
+	}
+	assert.Equal(t, DropProtectedEvents(events, nil), events)
+
+	evs := DropProtectedEvents(events, NewProtectedSet([]string{"route1", "httpbin", "3", "alice"}))
+	assert.Len(t, evs, 1)
+	assert.Equal(t, evs[0].Object.(*apisix.PluginConfig).Id, "4")
+	// The original events are not modified.
+	assert.Len(t, events, 5)
+	assert.Equal(t, events[0].Object.(*apisix.Route).Name, "route1")
+}
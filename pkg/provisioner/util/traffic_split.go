@@ -0,0 +1,44 @@
+package util
+
+import (
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// DropMissingTrafficSplitUpstreams drops the weighted upstreams (legs) in the
+// traffic-split plugin of routes which refer to unknown upstreams, the known
+// function tells whether an upstream id is known. Only the missing legs are
+// dropped so the rest of the split still works, the plugin is removed if no
+// legs left. The ids of dropped upstreams are returned, keyed by the route
+// name.
+func DropMissingTrafficSplitUpstreams(routes []*apisix.Route, known func(id string) bool) map[string][]string {
+	missing := make(map[string][]string)
+	for _, r := range routes {
+		ts := r.GetPlugins().GetTrafficSplit()
+		if ts == nil {
+			continue
+		}
+		var rules []*apisix.TrafficSplit_Rule
+		for _, rule := range ts.GetRules() {
+			var legs []*apisix.TrafficSplit_WeightedUpstream
+			for _, wu := range rule.GetWeightedUpstreams() {
+				// Legs without upstream id go to the upstream of route.
+				if wu.GetUpstreamId() != "" && !known(wu.GetUpstreamId()) {
+					missing[r.Name] = append(missing[r.Name], wu.GetUpstreamId())
+					continue
+				}
+				legs = append(legs, wu)
+			}
+			if len(legs) == 0 {
+				continue
+			}
+			rule.WeightedUpstreams = legs
+			rules = append(rules, rule)
+		}
+		if len(rules) == 0 {
+			r.Plugins.TrafficSplit = nil
+		} else {
+			ts.Rules = rules
+		}
+	}
+	return missing
+}
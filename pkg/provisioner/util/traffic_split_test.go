@@ -0,0 +1,64 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestDropMissingTrafficSplitUpstreams(t *testing.T) {
+	routes := []*apisix.Route{
+		{
+			Name:       "route1",
+			UpstreamId: "1",
+			Plugins: &apisix.Plugins{
+				TrafficSplit: &apisix.TrafficSplit{
+					Rules: []*apisix.TrafficSplit_Rule{
+						{
+							WeightedUpstreams: []*apisix.TrafficSplit_WeightedUpstream{
+								{UpstreamId: "1", Weight: 3},
+								{UpstreamId: "2", Weight: 7},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:       "route2",
+			UpstreamId: "3",
+			Plugins: &apisix.Plugins{
+				TrafficSplit: &apisix.TrafficSplit{
+					Rules: []*apisix.TrafficSplit_Rule{
+						{
+							WeightedUpstreams: []*apisix.TrafficSplit_WeightedUpstream{
+								{UpstreamId: "3", Weight: 1},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:       "route3",
+			UpstreamId: "1",
+		},
+	}
+	known := func(id string) bool {
+		return id == "1"
+	}
+	missing := DropMissingTrafficSplitUpstreams(routes, known)
+	assert.Equal(t, missing, map[string][]string{
+		"route1": {"2"},
+		"route2": {"3"},
+	})
+	// The valid leg is kept.
+	assert.Len(t, routes[0].Plugins.TrafficSplit.Rules, 1)
+	assert.Equal(t, routes[0].Plugins.TrafficSplit.Rules[0].WeightedUpstreams, []*apisix.TrafficSplit_WeightedUpstream{
+		{UpstreamId: "1", Weight: 3},
+	})
+	assert.Nil(t, routes[1].Plugins.TrafficSplit)
+	assert.Nil(t, routes[2].Plugins)
+}
@@ -4,6 +4,52 @@ import (
 	"strings"
 )
 
+const (
+	// LabelManagedBy is the label key which marks the manager of resources.
+	LabelManagedBy = "managed-by"
+	// LabelSourceFile is the label key which marks the source (e.g. the filename)
+	// that resources are generated from.
+	LabelSourceFile = "source-file"
+	// LabelXDSType is the label key which marks the xDS resource type that
+	// resources are translated from.
+	LabelXDSType = "xds-type"
+	// ManagedByMeshAgent is the value of the LabelManagedBy label.
+	ManagedByMeshAgent = "apisix-mesh-agent"
+
+	// Apache APISIX limits the length of label values.
+	_maxLabelValueLength = 64
+)
+
+// ProvenanceLabels generates labels to mark the provenance of resources,
+// the LabelSourceFile label will be omitted if source is empty. Sources
+// longer than 64 characters will be truncated from the head, since the
+// tail part is more specific.
+func ProvenanceLabels(source, xdsType string) map[string]string {
+	labels := map[string]string{
+		LabelManagedBy: ManagedByMeshAgent,
+		LabelXDSType:   xdsType,
+	}
+	if source != "" {
+		if len(source) > _maxLabelValueLength {
+			source = source[len(source)-_maxLabelValueLength:]
+		}
+		labels[LabelSourceFile] = source
+	}
+	return labels
+}
+
+// MergeLabels copies the labels in src to dst (created if it's nil), labels
+// in src take precedence, and dst is returned.
+func MergeLabels(dst, src map[string]string) map[string]string {
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
 // GenNodeId generates an id used for xDS protocol. The format is like:
 // sidecar~172.10.0.2~12345asad034~default.svc.cluster.local
 func GenNodeId(runId, ipAddr, dnsDomain string) string {
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,3 +11,28 @@ func TestGenNodeId(t *testing.T) {
 	id := GenNodeId("12345", "10.0.5.3", "default.svc.cluster.local")
 	assert.Equal(t, id, "sidecar~10.0.5.3~12345~default.svc.cluster.local")
 }
+
+func TestMergeLabels(t *testing.T) {
+	labels := MergeLabels(nil, map[string]string{"a": "1"})
+	assert.Equal(t, labels, map[string]string{"a": "1"})
+
+	labels = MergeLabels(map[string]string{"a": "0", "b": "2"}, map[string]string{"a": "1"})
+	assert.Equal(t, labels, map[string]string{"a": "1", "b": "2"})
+}
+
+func TestProvenanceLabels(t *testing.T) {
+	labels := ProvenanceLabels("", "Cluster")
+	assert.Equal(t, labels, map[string]string{
+		LabelManagedBy: ManagedByMeshAgent,
+		LabelXDSType:   "Cluster",
+	})
+
+	labels = ProvenanceLabels("/etc/xds/rds.json", "RouteConfiguration")
+	assert.Equal(t, labels[LabelSourceFile], "/etc/xds/rds.json")
+	assert.Equal(t, labels[LabelXDSType], "RouteConfiguration")
+
+	source := "/" + strings.Repeat("a", 64) + "/rds.json"
+	labels = ProvenanceLabels(source, "RouteConfiguration")
+	assert.Len(t, labels[LabelSourceFile], 64)
+	assert.True(t, strings.HasSuffix(labels[LabelSourceFile], "/rds.json"))
+}
@@ -0,0 +1,209 @@
+// Package configmap implements the xds-v3-configmap provisioner, which
+// watches a ConfigMap through the Kubernetes API instead of the files.
+package configmap
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner/xds/v3/file"
+)
+
+type xdsConfigMapProvisioner struct {
+	*file.XDSContentProvisioner
+
+	namespace string
+	name      string
+	client    kubernetes.Interface
+}
+
+// NewXDSConfigMapProvisioner creates a ConfigMap backed Provisioner, it watches
+// the given ConfigMap through the Kubernetes API, each data key in the ConfigMap
+// will be treated as a file, so the value should be in the format DiscoveryResponse,
+// just like the files watched by the xds-v3-file provisioner.
+// The in-cluster credentials will be used unless the kubeconfig is specified.
+func NewXDSConfigMapProvisioner(cfg *config.Config) (provisioner.Provisioner, error) {
+	if cfg.XDSConfigMapName == "" {
+		return nil, errors.New("xds-v3-configmap provisioner: no configmap name")
+	}
+	restConfig, err := clientcmd.BuildConfigFromFlags("", cfg.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	namespace := cfg.XDSConfigMapNamespace
+	if namespace == "" {
+		namespace = cfg.RunningContext.PodNamespace
+	}
+	p, err := newXDSConfigMapProvisioner(cfg, client, namespace, cfg.XDSConfigMapName)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func newXDSConfigMapProvisioner(cfg *config.Config, client kubernetes.Interface, namespace, name string) (*xdsConfigMapProvisioner, error) {
+	p, err := file.NewXDSContentProvisioner(cfg, "xds-configmap-provisioner")
+	if err != nil {
+		return nil, err
+	}
+	return &xdsConfigMapProvisioner{
+		XDSContentProvisioner: p,
+		namespace:             namespace,
+		name:                  name,
+		client:                client,
+	}, nil
+}
+
+func (p *xdsConfigMapProvisioner) Run(stop chan struct{}) error {
+	logger := p.Logger()
+	logger.Infow("xds v3 configmap provisioner started",
+		zap.String("namespace", p.namespace),
+		zap.String("name", p.name),
+	)
+	defer logger.Infow("xds v3 configmap provisioner exited")
+	defer p.Close()
+
+	factory := informers.NewSharedInformerFactoryWithOptions(p.client, 0,
+		informers.WithNamespace(p.namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", p.name).String()
+		}),
+	)
+	// The informer handlers run on the goroutine of the informer, they
+	// only queue the changes, which are handled by Serve, so no events
+	// are sent after the informer is stopped.
+	queue := make(chan func())
+	enqueue := func(fn func()) {
+		select {
+		case queue <- fn:
+		case <-stop:
+		}
+	}
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			enqueue(func() { p.onAdd(obj) })
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			enqueue(func() { p.onUpdate(oldObj, newObj) })
+		},
+		DeleteFunc: func(obj interface{}) {
+			enqueue(func() { p.onDelete(obj) })
+		},
+	})
+	informerDone := make(chan struct{})
+	go func() {
+		defer close(informerDone)
+		informer.Run(stop)
+	}()
+
+	if !cache.WaitForCacheSync(stop, informer.HasSynced) {
+		<-informerDone
+		return errors.New("failed to sync configmap cache")
+	}
+	p.Serve(queue, stop)
+	// Wait for the informer (and its handlers) to stop, the channel is
+	// closed then.
+	<-informerDone
+	return nil
+}
+
+// sourceName returns the name of the data key in the ConfigMap,
+// it's used as the filename.
+func (p *xdsConfigMapProvisioner) sourceName(key string) string {
+	return fmt.Sprintf("configmap/%s/%s/%s", p.namespace, p.name, key)
+}
+
+// sortedKeys returns the data keys of the ConfigMap in order, so that the
+// keys are handled in a stable order, like the watched files.
+func sortedKeys(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (p *xdsConfigMapProvisioner) onAdd(obj interface{}) {
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	p.Logger().Infow("configmap added",
+		zap.String("namespace", cm.Namespace),
+		zap.String("name", cm.Name),
+		zap.String("resource_version", cm.ResourceVersion),
+	)
+	for _, key := range sortedKeys(cm.Data) {
+		p.HandleContent(p.sourceName(key), []byte(cm.Data[key]))
+	}
+}
+
+func (p *xdsConfigMapProvisioner) onUpdate(oldObj, newObj interface{}) {
+	oldCM, ok := oldObj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	newCM, ok := newObj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	if oldCM.ResourceVersion == newCM.ResourceVersion {
+		return
+	}
+	p.Logger().Infow("configmap updated",
+		zap.String("namespace", newCM.Namespace),
+		zap.String("name", newCM.Name),
+		zap.String("resource_version", newCM.ResourceVersion),
+	)
+	for _, key := range sortedKeys(oldCM.Data) {
+		if _, ok := newCM.Data[key]; !ok {
+			p.HandleContentRemoval(p.sourceName(key))
+		}
+	}
+	for _, key := range sortedKeys(newCM.Data) {
+		value := newCM.Data[key]
+		if oldValue, ok := oldCM.Data[key]; ok && oldValue == value {
+			continue
+		}
+		p.HandleContent(p.sourceName(key), []byte(value))
+	}
+}
+
+func (p *xdsConfigMapProvisioner) onDelete(obj interface{}) {
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		cm, ok = tombstone.Obj.(*corev1.ConfigMap)
+		if !ok {
+			return
+		}
+	}
+	p.Logger().Infow("configmap deleted",
+		zap.String("namespace", cm.Namespace),
+		zap.String("name", cm.Name),
+	)
+	for _, key := range sortedKeys(cm.Data) {
+		p.HandleContentRemoval(p.sourceName(key))
+	}
+}
@@ -0,0 +1,196 @@
+package configmap
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/testutil"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestXDSConfigMapProvisioner(t *testing.T) {
+	cluster, err := ioutil.ReadFile("testdata/cluster.json")
+	assert.Nil(t, err)
+	route, err := ioutil.ReadFile("testdata/route.json")
+	assert.Nil(t, err)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "xds",
+			ResourceVersion: "1",
+		},
+		Data: map[string]string{
+			"cluster.json": string(cluster),
+		},
+	}
+	client := fake.NewSimpleClientset(cm)
+
+	p, err := newXDSConfigMapProvisioner(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}, client, "default", "xds")
+	assert.Nil(t, err)
+	stopCh := make(chan struct{})
+	evCh := p.Channel()
+	go func() {
+		assert.Nil(t, p.Run(stopCh))
+	}()
+
+	var events []types.Event
+	select {
+	case events = <-evCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event arrived in time")
+	}
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventAdd)
+	assert.Equal(t, events[0].Object.(*apisix.Upstream).Name, "httpbin.default.svc.cluster.local")
+	assert.Equal(t, events[0].Source, "configmap/default/xds/cluster.json")
+
+	cm = cm.DeepCopy()
+	cm.ResourceVersion = "2"
+	cm.Data = map[string]string{
+		"route.json": string(route),
+	}
+	_, err = client.CoreV1().ConfigMaps("default").Update(context.Background(), cm, metav1.UpdateOptions{})
+	assert.Nil(t, err)
+
+	var (
+		routeEv    *types.Event
+		upstreamEv *types.Event
+	)
+	for routeEv == nil || upstreamEv == nil {
+		select {
+		case events = <-evCh:
+		case <-time.After(2 * time.Second):
+			t.Fatal("no event arrived in time")
+		}
+		for i := range events {
+			switch events[i].Type {
+			case types.EventAdd:
+				routeEv = &events[i]
+			case types.EventDelete:
+				upstreamEv = &events[i]
+			}
+		}
+	}
+	assert.Equal(t, routeEv.Object.(*apisix.Route).Name, "route1#vhost1#rc1")
+	assert.Equal(t, upstreamEv.Tombstone.(*apisix.Upstream).Name, "httpbin.default.svc.cluster.local")
+
+	close(stopCh)
+}
+
+func TestXDSConfigMapProvisionerStop(t *testing.T) {
+	cluster, err := ioutil.ReadFile("testdata/cluster.json")
+	assert.Nil(t, err)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "xds",
+			ResourceVersion: "1",
+		},
+		Data: map[string]string{
+			"cluster.json": string(cluster),
+		},
+	}
+	client := fake.NewSimpleClientset(cm)
+
+	p, err := newXDSConfigMapProvisioner(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}, client, "default", "xds")
+	assert.Nil(t, err)
+	stopCh := make(chan struct{})
+	evCh := p.Channel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- p.Run(stopCh)
+	}()
+	select {
+	case <-evCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event arrived in time")
+	}
+
+	// Changes arriving while stopping must not be sent on the closed
+	// channel.
+	for i := 2; i < 10; i++ {
+		cm = cm.DeepCopy()
+		cm.ResourceVersion = fmt.Sprint(i)
+		if i%2 == 0 {
+			cm.Data = nil
+		} else {
+			cm.Data = map[string]string{"cluster.json": string(cluster)}
+		}
+		_, err = client.CoreV1().ConfigMaps("default").Update(context.Background(), cm, metav1.UpdateOptions{})
+		assert.Nil(t, err)
+	}
+	close(stopCh)
+	for range evCh {
+	}
+	assert.Nil(t, <-errCh)
+}
+
+func TestXDSConfigMapProvisionerKeyOrder(t *testing.T) {
+	p, err := newXDSConfigMapProvisioner(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}, nil, "default", "xds")
+	assert.Nil(t, err)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "xds",
+			ResourceVersion: "1",
+		},
+		Data: make(map[string]string),
+	}
+	keys := []string{"a.json", "b.json", "c.json", "d.json", "e.json"}
+	for _, key := range keys {
+		data, err := protojson.Marshal(&discoveryv3.DiscoveryResponse{
+			TypeUrl: types.ClusterUrl,
+			Resources: []*anypb.Any{
+				testutil.NewAny(t, &clusterv3.Cluster{
+					Name: key,
+					ClusterDiscoveryType: &clusterv3.Cluster_Type{
+						Type: clusterv3.Cluster_EDS,
+					},
+				}),
+			},
+		})
+		assert.Nil(t, err)
+		cm.Data[key] = string(data)
+	}
+
+	// The keys are handled in order, so are the events.
+	p.onAdd(cm)
+	for _, key := range keys {
+		events := <-p.Channel()
+		assert.Len(t, events, 1)
+		assert.Equal(t, events[0].Source, "configmap/default/xds/"+key)
+	}
+	p.onDelete(cm)
+	for _, key := range keys {
+		events := <-p.Channel()
+		assert.Len(t, events, 1)
+		assert.Equal(t, events[0].Type, types.EventDelete)
+		assert.Equal(t, events[0].Source, "configmap/default/xds/"+key)
+	}
+}
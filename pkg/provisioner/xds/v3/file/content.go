@@ -0,0 +1,72 @@
+package file
+
+import (
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner/util"
+)
+
+// XDSContentProvisioner is the xds-v3-file provisioner without the files,
+// the contents (in the format of DiscoveryResponse, like the files) are
+// delivered by the embedding provisioner (e.g. the ConfigMap one) from
+// other sources, the source names are used as the filenames. The embedding
+// provisioner implements Run with Serve and Close.
+type XDSContentProvisioner struct {
+	*xdsFileProvisioner
+}
+
+// NewXDSContentProvisioner creates the XDSContentProvisioner object, the
+// name is used by the logger.
+func NewXDSContentProvisioner(cfg *config.Config, name string) (*XDSContentProvisioner, error) {
+	p, err := newXDSFileProvisioner(cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	return &XDSContentProvisioner{
+		xdsFileProvisioner: p,
+	}, nil
+}
+
+// Logger returns the logger of the provisioner.
+func (p *XDSContentProvisioner) Logger() *log.Logger {
+	return p.logger
+}
+
+// HandleContent generates events according to the content and the last
+// state of the source, it should only be called by the functions queued
+// to Serve.
+func (p *XDSContentProvisioner) HandleContent(source string, data []byte) {
+	p.handleContent(source, data)
+}
+
+// HandleContentRemoval generates events since the source was removed, it
+// should only be called by the functions queued to Serve.
+func (p *XDSContentProvisioner) HandleContentRemoval(source string) {
+	p.handleContentRemoval(source)
+}
+
+// Serve marks the provisioner ready, then runs the queued functions and
+// sends the heartbeats until stop is closed. Contents are only handled on
+// this goroutine, so the state is never touched concurrently.
+func (p *XDSContentProvisioner) Serve(queue <-chan func(), stop chan struct{}) {
+	p.setReady(true)
+	heartbeat, stopHeartbeat := util.HeartbeatTicker(p.heartbeatInterval)
+	defer stopHeartbeat()
+	for {
+		select {
+		case fn := <-queue:
+			fn()
+		case <-heartbeat:
+			p.sendEvents(util.HeartbeatEvents())
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Close waits for all events to be delivered, and then closes the channel,
+// nothing should be queued after it.
+func (p *XDSContentProvisioner) Close() {
+	p.sending.Wait()
+	close(p.evChan)
+}
@@ -0,0 +1,62 @@
+package file
+
+import (
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// pendingFileEvent is the latest watch event of a file in the debounce
+// window.
+type pendingFileEvent struct {
+	ev    fsnotify.Event
+	timer *time.Timer
+}
+
+// debounceFileEvent delays the watch event, events of the same file in the
+// window are coalesced into the last one (the window is restarted), so
+// that the file is parsed only once after a burst of writes.
+func (p *xdsFileProvisioner) debounceFileEvent(ev fsnotify.Event, stop chan struct{}) {
+	if pe, ok := p.pendingEvents[ev.Name]; ok {
+		pe.ev = ev
+		// The timer might be fired already, in which case the pending
+		// event is flushed earlier and the next firing is a no-op.
+		pe.timer.Reset(p.watchDebounce)
+		return
+	}
+	name := ev.Name
+	p.pendingEvents[name] = &pendingFileEvent{
+		ev: ev,
+		timer: time.AfterFunc(p.watchDebounce, func() {
+			select {
+			case p.debounced <- name:
+			case <-stop:
+			}
+		}),
+	}
+}
+
+// flushFileEvent handles the pending event of the file (if any).
+func (p *xdsFileProvisioner) flushFileEvent(name string) {
+	pe, ok := p.pendingEvents[name]
+	if !ok {
+		return
+	}
+	delete(p.pendingEvents, name)
+	p.dispatchFileEvent(pe.ev)
+}
+
+// flushFileEvents handles all the pending events immediately, in the order
+// of filenames.
+func (p *xdsFileProvisioner) flushFileEvents() {
+	names := make([]string, 0, len(p.pendingEvents))
+	for name, pe := range p.pendingEvents {
+		pe.timer.Stop()
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p.flushFileEvent(name)
+	}
+}
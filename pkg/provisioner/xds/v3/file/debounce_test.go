@@ -0,0 +1,71 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestFileProvisionerDebounceFileEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xds-debounce")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	cluster, err := ioutil.ReadFile("testdata/cluster.json")
+	assert.Nil(t, err)
+	filename := filepath.Join(dir, "cluster.json")
+	assert.Nil(t, ioutil.WriteFile(filename, cluster, 0644))
+
+	cfg := &config.Config{
+		LogLevel:         "debug",
+		LogOutput:        "stderr",
+		XDSWatchFiles:    []string{dir},
+		XDSWatchDebounce: 50 * time.Millisecond,
+	}
+	pr, err := NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	p := pr.(*xdsFileProvisioner)
+	stop := make(chan struct{})
+
+	// A burst of events is coalesced into the last one.
+	for _, op := range []fsnotify.Op{fsnotify.Create, fsnotify.Write, fsnotify.Write} {
+		p.debounceFileEvent(fsnotify.Event{Name: filename, Op: op}, stop)
+	}
+	assert.Len(t, p.pendingEvents, 1)
+	var name string
+	select {
+	case name = <-p.debounced:
+	case <-time.After(time.Second):
+		t.Fatal("debounce window is not over in time")
+	}
+	assert.Equal(t, name, filename)
+	p.flushFileEvent(name)
+	assert.Len(t, p.pendingEvents, 0)
+
+	events := <-p.Channel()
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventAdd)
+	assert.Equal(t, events[0].Object.(*apisix.Upstream).Name, "httpbin.default.svc.cluster.local")
+	// Flushing again is a no-op.
+	p.flushFileEvent(name)
+
+	// Pending events are flushed without waiting for the window.
+	p.watchDebounce = time.Hour
+	p.debounceFileEvent(fsnotify.Event{Name: filename, Op: fsnotify.Write}, stop)
+	assert.Nil(t, os.Remove(filename))
+	p.debounceFileEvent(fsnotify.Event{Name: filename, Op: fsnotify.Remove}, stop)
+	p.flushFileEvents()
+	assert.Len(t, p.pendingEvents, 0)
+	events = <-p.Channel()
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventDelete)
+	close(stop)
+}
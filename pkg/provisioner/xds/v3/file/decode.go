@@ -0,0 +1,111 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// decodeDiscoveryResponse decodes the DiscoveryResponse (in JSON format)
+// from r incrementally, resources are decoded one by one, so that only
+// the raw JSON of a single resource (instead of the whole content) is
+// buffered at the same time, the decoded resources are still kept in dr.
+// The content can also be multiple DiscoveryResponses (e.g. JSONL), their
+// resources are merged into the first one, whitespaces (including empty
+// lines) between them are skipped.
+func decodeDiscoveryResponse(r io.Reader, dr *discoveryv3.DiscoveryResponse) error {
+	dec := json.NewDecoder(r)
+	if err := decodeNextDiscoveryResponse(dec, dr); err != nil {
+		return err
+	}
+	for dec.More() {
+		var next discoveryv3.DiscoveryResponse
+		if err := decodeNextDiscoveryResponse(dec, &next); err != nil {
+			return err
+		}
+		dr.Resources = append(dr.Resources, next.Resources...)
+	}
+	return nil
+}
+
+func decodeNextDiscoveryResponse(dec *json.Decoder, dr *discoveryv3.DiscoveryResponse) error {
+	var (
+		resources []*anypb.Any
+		others    = make(map[string]json.RawMessage)
+	)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("unexpected token %v", tok)
+		}
+		if key != "resources" {
+			var value json.RawMessage
+			if err := dec.Decode(&value); err != nil {
+				return err
+			}
+			others[key] = value
+			continue
+		}
+		tok, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		// "resources": null is the same as an empty list.
+		if tok == nil {
+			continue
+		}
+		if d, ok := tok.(json.Delim); !ok || d != '[' {
+			return fmt.Errorf("unexpected token %v, expected [", tok)
+		}
+		for dec.More() {
+			var value json.RawMessage
+			if err := dec.Decode(&value); err != nil {
+				return err
+			}
+			var res anypb.Any
+			if err := protojson.Unmarshal(value, &res); err != nil {
+				return err
+			}
+			resources = append(resources, &res)
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+
+	// Fields other than resources are small, just delegate them to protojson.
+	data, err := json.Marshal(others)
+	if err != nil {
+		return err
+	}
+	if err := protojson.Unmarshal(data, dr); err != nil {
+		return err
+	}
+	dr.Resources = resources
+	return nil
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("unexpected token %v, expected %v", tok, delim)
+	}
+	return nil
+}
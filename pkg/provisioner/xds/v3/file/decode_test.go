@@ -0,0 +1,195 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestDecodeDiscoveryResponse(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/route.json")
+	assert.Nil(t, err)
+
+	var expected discoveryv3.DiscoveryResponse
+	assert.Nil(t, protojson.Unmarshal(data, &expected))
+
+	var dr discoveryv3.DiscoveryResponse
+	assert.Nil(t, decodeDiscoveryResponse(bytes.NewReader(data), &dr))
+	assert.Equal(t, dr.VersionInfo, "0")
+	assert.Len(t, dr.Resources, 1)
+	assert.True(t, proto.Equal(&dr, &expected))
+
+	for _, bad := range []string{
+		"",
+		"[]",
+		`{"resources": {}}`,
+		`{"resources": 1}`,
+		`{"resources": [{"@type": "type.googleapis.com/unknown"}]}`,
+		`{"versionInfo": 1}`,
+		`{"resources": []`,
+	} {
+		assert.NotNil(t, decodeDiscoveryResponse(strings.NewReader(bad), &dr), bad)
+	}
+
+	// null resources are the same as no resources.
+	var empty discoveryv3.DiscoveryResponse
+	assert.Nil(t, decodeDiscoveryResponse(strings.NewReader(`{"versionInfo": "1", "resources": null}`), &empty))
+	assert.Equal(t, empty.VersionInfo, "1")
+	assert.Len(t, empty.Resources, 0)
+}
+
+func TestDecodeDiscoveryResponseMatcher(t *testing.T) {
+	data := `{"resources": [{
+		"@type": "type.googleapis.com/envoy.config.route.v3.RouteConfiguration",
+		"name": "rc1",
+		"virtual_hosts": [{
+			"name": "vhost1",
+			"domains": ["*"],
+			"matcher": {"matcher_tree": {
+				"input": {"name": "path", "typed_config": {
+					"@type": "type.googleapis.com/envoy.type.matcher.v3.HttpRequestHeaderMatchInput",
+					"header_name": ":path"
+				}},
+				"exact_match_map": {"map": {"/get": {"action": {"name": "route", "typed_config": {
+					"@type": "type.googleapis.com/envoy.config.route.v3.Route",
+					"route": {"cluster": "httpbin"}
+				}}}}}
+			}}
+		}]
+	}]}`
+	var dr discoveryv3.DiscoveryResponse
+	assert.Nil(t, decodeDiscoveryResponse(strings.NewReader(data), &dr))
+	assert.Len(t, dr.Resources, 1)
+	var rc routev3.RouteConfiguration
+	assert.Nil(t, anypb.UnmarshalTo(dr.Resources[0], &rc, proto.UnmarshalOptions{}))
+	assert.NotNil(t, rc.VirtualHosts[0].GetMatcher().GetMatcherTree())
+}
+
+func TestDecodeDiscoveryResponseAdditionalAddresses(t *testing.T) {
+	data := `{"resources": [{
+		"@type": "type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment",
+		"cluster_name": "test",
+		"endpoints": [{"lb_endpoints": [{"endpoint": {
+			"address": {"socket_address": {"address": "10.0.3.11", "port_value": 8000}},
+			"additional_addresses": [{"address": {"socket_address": {"address": "fd00::11", "port_value": 8000}}}]
+		}}]}]
+	}]}`
+	var dr discoveryv3.DiscoveryResponse
+	assert.Nil(t, decodeDiscoveryResponse(strings.NewReader(data), &dr))
+	var la endpointv3.ClusterLoadAssignment
+	assert.Nil(t, anypb.UnmarshalTo(dr.Resources[0], &la, proto.UnmarshalOptions{}))
+	addrs := la.Endpoints[0].LbEndpoints[0].GetEndpoint().GetAdditionalAddresses()
+	assert.Len(t, addrs, 1)
+	assert.Equal(t, addrs[0].GetAddress().GetSocketAddress().GetAddress(), "fd00::11")
+}
+
+func TestDecodeDiscoveryResponseJSONL(t *testing.T) {
+	var lines []string
+	for i := 0; i < 3; i++ {
+		res, err := anypb.New(&clusterv3.Cluster{
+			Name: fmt.Sprintf("cluster-%d", i),
+		})
+		assert.Nil(t, err)
+		data, err := protojson.Marshal(&discoveryv3.DiscoveryResponse{
+			VersionInfo: fmt.Sprint(i),
+			Resources:   []*anypb.Any{res},
+		})
+		assert.Nil(t, err)
+		lines = append(lines, string(data))
+	}
+	// Empty lines and trailing whitespaces are allowed.
+	data := lines[0] + "\n\n" + lines[1] + "\n" + lines[2] + "\n  \n"
+
+	var dr discoveryv3.DiscoveryResponse
+	assert.Nil(t, decodeDiscoveryResponse(strings.NewReader(data), &dr))
+	assert.Equal(t, dr.VersionInfo, "0")
+	assert.Len(t, dr.Resources, 3)
+	for i, res := range dr.Resources {
+		var c clusterv3.Cluster
+		assert.Nil(t, res.UnmarshalTo(&c))
+		assert.Equal(t, c.Name, fmt.Sprintf("cluster-%d", i))
+	}
+
+	assert.NotNil(t, decodeDiscoveryResponse(strings.NewReader(lines[0]+"\n{"), &dr))
+	assert.NotNil(t, decodeDiscoveryResponse(strings.NewReader(lines[0]+"\n[]"), &dr))
+}
+
+func generateLargeSnapshot(b *testing.B, n int) string {
+	dr := &discoveryv3.DiscoveryResponse{
+		VersionInfo: "0",
+	}
+	for i := 0; i < n; i++ {
+		res, err := anypb.New(&clusterv3.Cluster{
+			Name: fmt.Sprintf("outbound|80||svc-%d.default.svc.cluster.local", i),
+			ClusterDiscoveryType: &clusterv3.Cluster_Type{
+				Type: clusterv3.Cluster_EDS,
+			},
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		dr.Resources = append(dr.Resources, res)
+	}
+	data, err := protojson.Marshal(dr)
+	if err != nil {
+		b.Fatal(err)
+	}
+	f, err := ioutil.TempFile("", "snapshot-*.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		b.Fatal(err)
+	}
+	return f.Name()
+}
+
+func BenchmarkReadAndUnmarshalDiscoveryResponse(b *testing.B) {
+	filename := generateLargeSnapshot(b, 10000)
+	defer os.Remove(filename)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var dr discoveryv3.DiscoveryResponse
+		if err := protojson.Unmarshal(data, &dr); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeDiscoveryResponse(b *testing.B) {
+	filename := generateLargeSnapshot(b, 10000)
+	defer os.Remove(filename)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f, err := os.Open(filename)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var dr discoveryv3.DiscoveryResponse
+		if err := decodeDiscoveryResponse(f, &dr); err != nil {
+			b.Fatal(err)
+		}
+		f.Close()
+	}
+}
@@ -1,19 +1,29 @@
 package file
 
 import (
+	"fmt"
+	"sort"
+
 	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	"github.com/golang/protobuf/ptypes/any"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
 	xdsv3 "github.com/api7/apisix-mesh-agent/pkg/adaptor/xds/v3"
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/set"
 	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
 )
 
-func (p *xdsFileProvisioner) processRouteConfigurationV3(res *any.Any) []*apisix.Route {
+// processRouteConfigurationV3 translates the route configuration, the hash
+// and retry policies of routes are collected to the policies (if not nil).
+func (p *xdsFileProvisioner) processRouteConfigurationV3(res *any.Any, tenant string, policies *clusterPolicies) []*apisix.Route {
 	var route routev3.RouteConfiguration
 	err := anypb.UnmarshalTo(res, &route, proto.UnmarshalOptions{
 		DiscardUnknown: true,
@@ -26,17 +36,147 @@ func (p *xdsFileProvisioner) processRouteConfigurationV3(res *any.Any) []*apisix
 		return nil
 	}
 
-	routes, err := p.v3Adaptor.TranslateRouteConfiguration(&route, nil)
+	// The listener which refers to the route configuration by RDS might
+	// be seen already.
+	opts := &xdsv3.TranslateOptions{
+		Tenant: tenant,
+	}
+	name := xdsv3.TenantScopedName(tenant, route.GetName())
+	if addr, ok := p.routeOriginalDestination[name]; ok {
+		opts.RouteOriginalDestination = map[string]string{route.GetName(): addr}
+	}
+	if filters, ok := p.routeHTTPFilters[name]; ok {
+		opts.RouteHTTPFilters = map[string][]*hcmv3.HttpFilter{route.GetName(): filters}
+	}
+	routes, err := p.v3Adaptor.TranslateRouteConfiguration(&route, opts)
 	if err != nil {
 		p.logger.Errorw("failed to translate RouteConfiguration to APISIX routes",
 			zap.Error(err),
 			zap.Any("route", &route),
 		)
+		return nil
 	}
+	policies.collect(p.v3Adaptor, &route, tenant)
 	return routes
 }
 
-func (p *xdsFileProvisioner) processClusterV3(res *any.Any) []*apisix.Upstream {
+// processListenerV3 translates the route configurations inline in the HTTP
+// connection managers of the listener, for the ones referred by RDS, the
+// listener address and HTTP filters are recorded, so that they can be
+// applied once the route configurations are seen, the (tenant scoped) names
+// of them are returned. Credentials in the auth filters are translated to
+// consumers. Like processRouteConfigurationV3, policies of the inline routes
+// are collected.
+func (p *xdsFileProvisioner) processListenerV3(res *any.Any, tenant string, policies *clusterPolicies) ([]*apisix.Route, []*apisix.Consumer, []string) {
+	var listener listenerv3.Listener
+	err := anypb.UnmarshalTo(res, &listener, proto.UnmarshalOptions{
+		DiscardUnknown: true,
+	})
+	if err != nil {
+		p.logger.Errorw("found invalid Listener resource",
+			zap.Error(err),
+			zap.Any("resource", res),
+		)
+		return nil, nil, nil
+	}
+	// Listeners with api_listener (for gRPC xDS clients) don't
+	// have addresses, so the routes are not bound to any address.
+	var addr string
+	if listener.GetApiListener() == nil {
+		sockAddr := listener.GetAddress().GetSocketAddress()
+		if sockAddr == nil || sockAddr.GetPortValue() == 0 {
+			// Only use listener which listens on socket.
+			return nil, nil, nil
+		}
+		addr = fmt.Sprintf("%s:%d", sockAddr.GetAddress(), sockAddr.GetPortValue())
+	}
+	names, cfgs, err := p.v3Adaptor.CollectRouteNamesAndConfigs(&listener)
+	if err != nil {
+		p.logger.Errorw("failed to collect route configurations from Listener",
+			zap.Error(err),
+			zap.String("listener", listener.GetName()),
+		)
+		return nil, nil, nil
+	}
+	filters, err := p.v3Adaptor.CollectRouteHTTPFilters(&listener)
+	if err != nil {
+		p.logger.Errorw("failed to collect HTTP filters from Listener",
+			zap.Error(err),
+			zap.String("listener", listener.GetName()),
+		)
+		return nil, nil, nil
+	}
+	scopedNames := make([]string, 0, len(names))
+	for _, name := range names {
+		scoped := xdsv3.TenantScopedName(tenant, name)
+		p.bindRouteConfiguration(scoped, addr, filters[name])
+		scopedNames = append(scopedNames, scoped)
+	}
+	consumers, err := p.v3Adaptor.CollectConsumers(&listener)
+	if err != nil {
+		p.logger.Errorw("failed to collect consumers from Listener",
+			zap.Error(err),
+			zap.String("listener", listener.GetName()),
+		)
+	}
+
+	var routes []*apisix.Route
+	for _, cfg := range cfgs {
+		opts := &xdsv3.TranslateOptions{
+			RouteOriginalDestination: map[string]string{cfg.GetName(): addr},
+			RouteHTTPFilters:         filters,
+			Tenant:                   tenant,
+		}
+		partial, err := p.v3Adaptor.TranslateRouteConfiguration(cfg, opts)
+		if err != nil {
+			p.logger.Errorw("failed to translate RouteConfiguration in Listener to APISIX routes",
+				zap.Error(err),
+				zap.String("listener", listener.GetName()),
+				zap.String("route_configuration", cfg.GetName()),
+			)
+			continue
+		}
+		policies.collect(p.v3Adaptor, cfg, tenant)
+		routes = append(routes, partial...)
+	}
+	return routes, consumers, scopedNames
+}
+
+// bindRouteConfiguration records the listener address and HTTP filters of
+// the route configuration referred by RDS, it's marked stale if they're
+// changed, so that it can be translated again.
+func (p *xdsFileProvisioner) bindRouteConfiguration(name, addr string, filters []*hcmv3.HttpFilter) {
+	lastAddr, bound := p.routeOriginalDestination[name]
+	if bound && lastAddr == addr && equalHTTPFilters(p.routeHTTPFilters[name], filters) {
+		return
+	}
+	p.routeOriginalDestination[name] = addr
+	p.routeHTTPFilters[name] = filters
+	p.staleRouteConfigurations.Add(name)
+}
+
+// unbindRouteConfiguration forgets the listener address and HTTP filters
+// of the route configuration, it's marked stale so that it can be
+// translated again.
+func (p *xdsFileProvisioner) unbindRouteConfiguration(name string) {
+	delete(p.routeOriginalDestination, name)
+	delete(p.routeHTTPFilters, name)
+	p.staleRouteConfigurations.Add(name)
+}
+
+func equalHTTPFilters(a, b []*hcmv3.HttpFilter) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *xdsFileProvisioner) processClusterV3(res *any.Any, tenant string) []*apisix.Upstream {
 	var cluster clusterv3.Cluster
 	err := anypb.UnmarshalTo(res, &cluster, proto.UnmarshalOptions{
 		DiscardUnknown: true,
@@ -52,20 +192,48 @@ func (p *xdsFileProvisioner) processClusterV3(res *any.Any) []*apisix.Upstream {
 	if err != nil && err != xdsv3.ErrRequireFurtherEDS {
 		p.logger.Errorw("failed to translate Cluster to APISIX routes",
 			zap.Error(err),
-			zap.Any("cluster", &cluster),
+			zap.Any("cluster", xdsv3.RedactCluster(&cluster)),
 		)
 		return nil
 	}
+	if tenant != "" {
+		ups.Name = xdsv3.TenantScopedName(tenant, ups.Name)
+		ups.Id = id.GenID(ups.Name)
+	}
+	p.addTenantCluster(tenant, cluster.Name)
+	if xdsv3.IsClusterStatsTracked(&cluster) {
+		p.statsTrackedUpstreams.Add(ups.Id)
+	} else {
+		delete(p.statsTrackedUpstreams, ups.Id)
+	}
+	if lc := xdsv3.GetClusterConnectionLimit(&cluster); lc != nil {
+		p.connectionLimits[ups.Id] = lc
+	} else {
+		delete(p.connectionLimits, ups.Id)
+	}
 	if err == xdsv3.ErrRequireFurtherEDS {
 		p.logger.Warnw("cluster depends on another EDS config, an upstream without nodes setting was generated",
 			zap.Any("upstream", ups),
 		)
+		if name := xdsv3.TenantScopedName(tenant, xdsv3.GetEDSServiceName(&cluster)); name != ups.Name {
+			p.edsServiceNames[name] = ups.Name
+		}
+	}
+	if name := xdsv3.GetClusterSecretName(&cluster); name != "" {
+		// The secret might be seen already, or the upstream will be
+		// patched once it arrives.
+		p.clusterSecrets[ups.Name] = xdsv3.TenantScopedName(tenant, name)
+		if secret, ok := p.secrets[p.clusterSecrets[ups.Name]]; ok {
+			p.patchUpstreamWithSecret(ups, secret)
+		}
+	} else {
+		delete(p.clusterSecrets, ups.Name)
 	}
 	p.upstreamCache[ups.Name] = ups
 	return []*apisix.Upstream{ups}
 }
 
-func (p *xdsFileProvisioner) processClusterLoadAssignmentV3(res *any.Any) []*apisix.Upstream {
+func (p *xdsFileProvisioner) processClusterLoadAssignmentV3(res *any.Any, tenant string) []*apisix.Upstream {
 	var cla endpointv3.ClusterLoadAssignment
 	err := anypb.UnmarshalTo(res, &cla, proto.UnmarshalOptions{
 		DiscardUnknown: true,
@@ -78,7 +246,13 @@ func (p *xdsFileProvisioner) processClusterLoadAssignmentV3(res *any.Any) []*api
 		return nil
 	}
 
-	ups, ok := p.upstreamCache[cla.ClusterName]
+	// The cluster_name in ClusterLoadAssignment is the EDS service name
+	// of the cluster, which might be different from the cluster name.
+	clusterName := xdsv3.TenantScopedName(tenant, cla.ClusterName)
+	if name, ok := p.edsServiceNames[clusterName]; ok {
+		clusterName = name
+	}
+	ups, ok := p.upstreamCache[clusterName]
 	if !ok {
 		p.logger.Warnw("found invalid ClusterLoadAssignment resource",
 			zap.String("reason", "cluster unknown"),
@@ -106,6 +280,125 @@ func (p *xdsFileProvisioner) processClusterLoadAssignmentV3(res *any.Any) []*api
 	// Do not set on the original ups to avoid race conditions.
 	newUps := proto.Clone(ups).(*apisix.Upstream)
 	newUps.Nodes = nodes
-	p.upstreamCache[cla.ClusterName] = newUps
+	if err := xdsv3.CheckUpstreamSNI(newUps, &cla); err != nil {
+		p.logger.Warnw("per endpoint SNI cannot be honored",
+			zap.Error(err),
+			zap.String("cluster_name", clusterName),
+		)
+	}
+	p.upstreamCache[clusterName] = newUps
 	return []*apisix.Upstream{newUps}
 }
+
+// processSecretV3 records the secret and translates it to the APISIX Ssl
+// if it carries the TLS certificate, the upstreams using it as the client
+// certificate are patched and returned too. Secrets are logged by the names
+// only, since they contain the private keys.
+func (p *xdsFileProvisioner) processSecretV3(res *any.Any, tenant string) ([]*apisix.Ssl, []*apisix.Upstream) {
+	var secret tlsv3.Secret
+	err := anypb.UnmarshalTo(res, &secret, proto.UnmarshalOptions{
+		DiscardUnknown: true,
+	})
+	if err != nil {
+		p.logger.Errorw("found invalid Secret resource",
+			zap.Error(err),
+		)
+		return nil, nil
+	}
+	name := xdsv3.TenantScopedName(tenant, secret.Name)
+	p.secrets[name] = &secret
+
+	var upstreams []*apisix.Upstream
+	for upsName, secretName := range p.clusterSecrets {
+		ups, ok := p.upstreamCache[upsName]
+		if secretName != name || !ok {
+			continue
+		}
+		// Do not set on the original ups to avoid race conditions.
+		newUps := proto.Clone(ups).(*apisix.Upstream)
+		if p.patchUpstreamWithSecret(newUps, &secret) {
+			p.upstreamCache[upsName] = newUps
+			upstreams = append(upstreams, newUps)
+		}
+	}
+
+	ssl, err := p.v3Adaptor.TranslateSecret(&secret)
+	if err != nil {
+		p.logger.Errorw("failed to translate Secret to APISIX ssl",
+			zap.Error(err),
+			zap.String("secret", name),
+		)
+		return nil, upstreams
+	}
+	if ssl == nil {
+		return nil, upstreams
+	}
+	if tenant != "" {
+		ssl.Id = id.GenID(name)
+	}
+	return []*apisix.Ssl{ssl}, upstreams
+}
+
+// forgetRemovedSecrets forgets the secrets which were delivered by the file
+// but are not in the names (all of them if the names are nil), unless other
+// files still deliver them. The upstreams using them as the client
+// certificates are returned with the certificates cleared.
+func (p *xdsFileProvisioner) forgetRemovedSecrets(filename string, names set.StringSet) []*apisix.Upstream {
+	removed := make(set.StringSet)
+	for name := range p.fileSecrets[filename] {
+		if _, ok := names[name]; !ok {
+			removed.Add(name)
+		}
+	}
+	if len(names) > 0 {
+		p.fileSecrets[filename] = names
+	} else {
+		delete(p.fileSecrets, filename)
+	}
+	for _, others := range p.fileSecrets {
+		for name := range others {
+			delete(removed, name)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	for name := range removed {
+		delete(p.secrets, name)
+	}
+
+	var upsNames []string
+	for upsName, secretName := range p.clusterSecrets {
+		if _, ok := removed[secretName]; ok {
+			upsNames = append(upsNames, upsName)
+		}
+	}
+	sort.Strings(upsNames)
+	var upstreams []*apisix.Upstream
+	for _, upsName := range upsNames {
+		ups, ok := p.upstreamCache[upsName]
+		if !ok || ups.Tls == nil {
+			continue
+		}
+		// Do not set on the original ups to avoid race conditions.
+		newUps := proto.Clone(ups).(*apisix.Upstream)
+		newUps.Tls = nil
+		p.upstreamCache[upsName] = newUps
+		upstreams = append(upstreams, newUps)
+	}
+	return upstreams
+}
+
+// patchUpstreamWithSecret sets the client certificate of the upstream by
+// the secret, it reports whether the upstream is changed.
+func (p *xdsFileProvisioner) patchUpstreamWithSecret(ups *apisix.Upstream, secret *tlsv3.Secret) bool {
+	if err := xdsv3.PatchUpstreamWithSecret(ups, secret); err != nil {
+		p.logger.Errorw("failed to patch upstream with the client certificate",
+			zap.Error(err),
+			zap.String("upstream", ups.Name),
+			zap.String("secret", secret.GetName()),
+		)
+		return false
+	}
+	return true
+}
@@ -7,6 +7,8 @@ import (
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/golang/protobuf/ptypes/any"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/stretchr/testify/assert"
@@ -18,6 +20,8 @@ import (
 	"github.com/api7/apisix-mesh-agent/pkg/id"
 	"github.com/api7/apisix-mesh-agent/pkg/log"
 	"github.com/api7/apisix-mesh-agent/pkg/provisioner/util"
+	"github.com/api7/apisix-mesh-agent/pkg/testutil"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
 	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
 )
 
@@ -67,7 +71,7 @@ func TestProcessRouteConfigurationV3(t *testing.T) {
 	var opaque any.Any
 	opaque.TypeUrl = "type.googleapis.com/" + string(rc.ProtoReflect().Descriptor().FullName())
 	assert.Nil(t, anypb.MarshalFrom(&opaque, rc, proto2.MarshalOptions{}))
-	routes := p.processRouteConfigurationV3(&opaque)
+	routes := p.processRouteConfigurationV3(&opaque, "", nil)
 	assert.Len(t, routes, 1)
 }
 
@@ -140,7 +144,7 @@ func TestProcessClusterV3(t *testing.T) {
 		state:         make(map[string]*util.Manifest),
 		upstreamCache: make(map[string]*apisix.Upstream),
 	}
-	upstreams := p.processClusterV3(&opaque)
+	upstreams := p.processClusterV3(&opaque, "")
 	assert.Len(t, upstreams, 1)
 	assert.Equal(t, upstreams[0].Name, "httpbin.default.svc.cluster.local")
 	assert.Equal(t, upstreams[0].Id, id.GenID(upstreams[0].Name))
@@ -219,7 +223,7 @@ func TestProcessClusterLoadAssignment(t *testing.T) {
 		upstreamCache: make(map[string]*apisix.Upstream),
 	}
 	// Reject since the cluster is unknown.
-	assert.Nil(t, p.processClusterLoadAssignmentV3(&opaque))
+	assert.Nil(t, p.processClusterLoadAssignmentV3(&opaque, ""))
 
 	ups := &apisix.Upstream{
 		Name: "httpbin.default.svc.cluster.local",
@@ -233,12 +237,12 @@ func TestProcessClusterLoadAssignment(t *testing.T) {
 	}
 	p.upstreamCache[ups.Name] = ups
 	// Reject since the cluster already has endpoints.
-	assert.Nil(t, p.processClusterLoadAssignmentV3(&opaque))
+	assert.Nil(t, p.processClusterLoadAssignmentV3(&opaque, ""))
 
 	ups.Nodes = nil
 	p.upstreamCache[ups.Name] = ups
 
-	uset := p.processClusterLoadAssignmentV3(&opaque)
+	uset := p.processClusterLoadAssignmentV3(&opaque, "")
 	assert.Len(t, uset, 1)
 	assert.Len(t, uset[0].Nodes, 2)
 	assert.Equal(t, uset[0].Nodes[0].Host, "10.0.3.11")
@@ -249,3 +253,177 @@ func TestProcessClusterLoadAssignment(t *testing.T) {
 	assert.Equal(t, uset[0].Nodes[1].Port, int32(8000))
 	assert.Equal(t, uset[0].Nodes[1].Weight, int32(80))
 }
+
+func TestProcessSecretV3(t *testing.T) {
+	cert, key := testutil.NewCertificate(t, "httpbin.org")
+	secret := &tlsv3.Secret{
+		Name: "default",
+		Type: &tlsv3.Secret_TlsCertificate{
+			TlsCertificate: &tlsv3.TlsCertificate{
+				CertificateChain: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineString{
+						InlineString: cert,
+					},
+				},
+				PrivateKey: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineString{
+						InlineString: key,
+					},
+				},
+			},
+		},
+	}
+	var opaque any.Any
+	assert.Nil(t, anypb.MarshalFrom(&opaque, secret, proto2.MarshalOptions{}))
+
+	cfg := &config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}
+	adaptor, err := xdsv3.NewAdaptor(cfg)
+	assert.Nil(t, err)
+	p := &xdsFileProvisioner{
+		logger:    log.DefaultLogger,
+		v3Adaptor: adaptor,
+		secrets:   make(map[string]*tlsv3.Secret),
+	}
+	ssls, ups := p.processSecretV3(&opaque, "")
+	assert.Nil(t, ups)
+	assert.Len(t, ssls, 1)
+	assert.Equal(t, ssls[0].Id, id.GenID("default"))
+	assert.Equal(t, ssls[0].Snis, []string{"httpbin.org"})
+	assert.NotNil(t, p.secrets["default"])
+
+	ssls, _ = p.processSecretV3(&opaque, "tenant1")
+	assert.Len(t, ssls, 1)
+	assert.Equal(t, ssls[0].Id, id.GenID(xdsv3.TenantScopedName("tenant1", "default")))
+
+	// The validation context is recorded but not translated.
+	secret = &tlsv3.Secret{
+		Name: "ROOTCA",
+		Type: &tlsv3.Secret_ValidationContext{
+			ValidationContext: &tlsv3.CertificateValidationContext{},
+		},
+	}
+	assert.Nil(t, anypb.MarshalFrom(&opaque, secret, proto2.MarshalOptions{}))
+	ssls, _ = p.processSecretV3(&opaque, "")
+	assert.Nil(t, ssls)
+	assert.NotNil(t, p.secrets["ROOTCA"])
+}
+
+func TestProcessSecretV3WithClientCertificate(t *testing.T) {
+	cert, key := testutil.NewCertificate(t, "httpbin.org")
+	secret := &tlsv3.Secret{
+		Name: "default",
+		Type: &tlsv3.Secret_TlsCertificate{
+			TlsCertificate: &tlsv3.TlsCertificate{
+				CertificateChain: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineString{
+						InlineString: cert,
+					},
+				},
+				PrivateKey: &corev3.DataSource{
+					Specifier: &corev3.DataSource_InlineString{
+						InlineString: key,
+					},
+				},
+			},
+		},
+	}
+	tlsCtx := &tlsv3.UpstreamTlsContext{
+		CommonTlsContext: &tlsv3.CommonTlsContext{
+			TlsCertificateSdsSecretConfigs: []*tlsv3.SdsSecretConfig{
+				{Name: "default"},
+			},
+		},
+	}
+	tlsCtxAny, err := anypb.New(tlsCtx)
+	assert.Nil(t, err)
+	cluster := &clusterv3.Cluster{
+		Name:     "httpbin.default.svc.cluster.local",
+		LbPolicy: clusterv3.Cluster_ROUND_ROBIN,
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_EDS,
+		},
+		TransportSocket: &corev3.TransportSocket{
+			Name: "envoy.transport_sockets.tls",
+			ConfigType: &corev3.TransportSocket_TypedConfig{
+				TypedConfig: tlsCtxAny,
+			},
+		},
+	}
+	secretAny, err := anypb.New(secret)
+	assert.Nil(t, err)
+	clusterAny, err := anypb.New(cluster)
+	assert.Nil(t, err)
+
+	p, err := newXDSFileProvisioner(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}, "test")
+	assert.Nil(t, err)
+
+	// The secret is behind the cluster.
+	evs := p.generateEventsFromDiscoveryResponseV3("clusters.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl:   types.ClusterUrl,
+		Resources: []*anypb.Any{clusterAny},
+	})
+	assert.Len(t, evs, 1)
+	assert.Equal(t, evs[0].Object.(*apisix.Upstream).Scheme, "https")
+	assert.Nil(t, evs[0].Object.(*apisix.Upstream).Tls)
+
+	evs = p.generateEventsFromDiscoveryResponseV3("secrets.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl:   types.SecretUrl,
+		Resources: []*anypb.Any{secretAny},
+	})
+	assert.Len(t, evs, 2)
+	assert.Equal(t, evs[0].Type, types.EventAdd)
+	assert.IsType(t, evs[0].Object, &apisix.Ssl{})
+	assert.Equal(t, evs[1].Type, types.EventUpdate)
+	assert.Equal(t, evs[1].Object.(*apisix.Upstream).Tls, &apisix.Upstream_TLS{
+		ClientCert: cert,
+		ClientKey:  key,
+	})
+
+	// The secret is seen already.
+	p, err = newXDSFileProvisioner(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}, "test")
+	assert.Nil(t, err)
+	evs = p.generateEventsFromDiscoveryResponseV3("secrets.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl:   types.SecretUrl,
+		Resources: []*anypb.Any{secretAny},
+	})
+	assert.Len(t, evs, 1)
+	evs = p.generateEventsFromDiscoveryResponseV3("clusters.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl:   types.ClusterUrl,
+		Resources: []*anypb.Any{clusterAny},
+	})
+	assert.Len(t, evs, 1)
+	assert.Equal(t, evs[0].Object.(*apisix.Upstream).Tls.ClientKey, key)
+
+	// The secret is no longer delivered.
+	evs = p.generateEventsFromDiscoveryResponseV3("secrets.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl: types.SecretUrl,
+	})
+	assert.Len(t, evs, 2)
+	assert.Equal(t, evs[0].Type, types.EventDelete)
+	assert.Equal(t, evs[1].Type, types.EventUpdate)
+	assert.Nil(t, evs[1].Object.(*apisix.Upstream).Tls)
+	assert.Nil(t, p.secrets["default"])
+
+	// The file of the secret is removed.
+	evs = p.generateEventsFromDiscoveryResponseV3("secrets.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl:   types.SecretUrl,
+		Resources: []*anypb.Any{secretAny},
+	})
+	assert.Len(t, evs, 2)
+	assert.Equal(t, evs[1].Object.(*apisix.Upstream).Tls.ClientKey, key)
+	p.handleContentRemoval("secrets.json")
+	evs = <-p.Channel()
+	assert.Equal(t, evs[len(evs)-1].Type, types.EventUpdate)
+	assert.Nil(t, evs[len(evs)-1].Object.(*apisix.Upstream).Tls)
+	assert.Nil(t, p.upstreamCache[cluster.Name].Tls)
+	assert.Nil(t, p.secrets["default"])
+}
@@ -0,0 +1,35 @@
+package file
+
+import (
+	"errors"
+)
+
+var (
+	// errTooManyTrackedFiles means the content comes from a new file while
+	// the max number of tracked files is reached.
+	errTooManyTrackedFiles = errors.New("too many tracked files")
+	// errTooManyTrackedResources means the resources of all tracked files
+	// would exceed the max number if the content is accepted.
+	errTooManyTrackedResources = errors.New("too many tracked resources")
+)
+
+// checkTrackingLimits checks whether the file with the number of xDS resources
+// can be tracked. The limits reject new contents instead of evicting the old
+// ones, since evicted files would delete resources which are still in use.
+// Files are only counted when any limit is set.
+func (p *xdsFileProvisioner) checkTrackingLimits(filename string, resources int) error {
+	last, tracked := p.trackedResources[filename]
+	if p.maxTrackedFiles > 0 && !tracked && len(p.trackedResources) >= p.maxTrackedFiles {
+		return errTooManyTrackedFiles
+	}
+	if p.maxTrackedResources > 0 {
+		total := resources - last
+		for _, n := range p.trackedResources {
+			total += n
+		}
+		if total > p.maxTrackedResources {
+			return errTooManyTrackedResources
+		}
+	}
+	return nil
+}
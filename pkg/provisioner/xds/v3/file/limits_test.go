@@ -0,0 +1,73 @@
+package file
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/metrics"
+	"github.com/api7/apisix-mesh-agent/pkg/testutil"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+)
+
+func newClustersResponse(t *testing.T, names ...string) *discoveryv3.DiscoveryResponse {
+	dr := &discoveryv3.DiscoveryResponse{
+		TypeUrl: types.ClusterUrl,
+	}
+	for _, name := range names {
+		dr.Resources = append(dr.Resources, testutil.NewAny(t, newEDSCluster(name, clusterv3.Cluster_ROUND_ROBIN)))
+	}
+	return dr
+}
+
+func TestFileProvisionerMaxTrackedFiles(t *testing.T) {
+	p, err := newXDSFileProvisioner(&config.Config{
+		LogLevel:           "debug",
+		LogOutput:          "stderr",
+		XDSMaxTrackedFiles: 2,
+	}, "test")
+	assert.Nil(t, err)
+
+	assert.Len(t, p.generateEventsFromDiscoveryResponseV3("a.json", newClustersResponse(t, "a")), 1)
+	assert.Len(t, p.generateEventsFromDiscoveryResponseV3("b.json", newClustersResponse(t, "b")), 1)
+
+	rejected := promtestutil.ToFloat64(metrics.XDSRejectedContents)
+	assert.Nil(t, p.generateEventsFromDiscoveryResponseV3("c.json", newClustersResponse(t, "c")))
+	assert.Equal(t, promtestutil.ToFloat64(metrics.XDSRejectedContents), rejected+1)
+	_, ok := p.state["c.json"]
+	assert.False(t, ok)
+
+	// Tracked files can still be updated.
+	assert.Len(t, p.generateEventsFromDiscoveryResponseV3("b.json", newClustersResponse(t, "b", "bb")), 1)
+
+	// The slot is released once the file is removed.
+	p.handleContentRemoval("a.json")
+	assert.Len(t, p.generateEventsFromDiscoveryResponseV3("c.json", newClustersResponse(t, "c")), 1)
+}
+
+func TestFileProvisionerMaxTrackedResources(t *testing.T) {
+	p, err := newXDSFileProvisioner(&config.Config{
+		LogLevel:               "debug",
+		LogOutput:              "stderr",
+		XDSMaxTrackedResources: 3,
+	}, "test")
+	assert.Nil(t, err)
+
+	assert.Len(t, p.generateEventsFromDiscoveryResponseV3("a.json", newClustersResponse(t, "a1", "a2")), 2)
+
+	rejected := promtestutil.ToFloat64(metrics.XDSRejectedContents)
+	assert.Nil(t, p.generateEventsFromDiscoveryResponseV3("b.json", newClustersResponse(t, "b1", "b2")))
+	assert.Equal(t, promtestutil.ToFloat64(metrics.XDSRejectedContents), rejected+1)
+
+	// The last content of the file is kept.
+	assert.Nil(t, p.generateEventsFromDiscoveryResponseV3("a.json", newClustersResponse(t, "a1", "a2", "a3", "a4")))
+	assert.Len(t, p.state["a.json"].Upstreams, 2)
+
+	// Shrinking the file makes room for others.
+	assert.Len(t, p.generateEventsFromDiscoveryResponseV3("a.json", newClustersResponse(t, "a1")), 1)
+	assert.Len(t, p.generateEventsFromDiscoveryResponseV3("b.json", newClustersResponse(t, "b1", "b2")), 2)
+}
@@ -0,0 +1,92 @@
+package file
+
+import (
+	"errors"
+	"sync"
+
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"go.uber.org/zap"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+)
+
+// ErrProvisionerStopped means the provisioner was already stopped.
+var ErrProvisionerStopped = errors.New("provisioner stopped")
+
+// XDSMemoryProvisioner is a Provisioner whose DiscoveryResponses are pushed
+// programmatically, it's the in-memory counterpart of the xds-v3-file
+// provisioner, so that the translation can be embedded in other programs
+// (and tests) without files or gRPC.
+type XDSMemoryProvisioner struct {
+	*xdsFileProvisioner
+
+	mu      sync.Mutex
+	done    chan struct{}
+	stopped bool
+}
+
+// NewXDSMemoryProvisioner creates the XDSMemoryProvisioner object.
+func NewXDSMemoryProvisioner(cfg *config.Config) (*XDSMemoryProvisioner, error) {
+	p, err := newXDSFileProvisioner(cfg, "xds-memory-provisioner")
+	if err != nil {
+		return nil, err
+	}
+	return &XDSMemoryProvisioner{
+		xdsFileProvisioner: p,
+		done:               make(chan struct{}),
+	}, nil
+}
+
+func (p *XDSMemoryProvisioner) Run(stop chan struct{}) error {
+	p.logger.Infow("xds v3 memory provisioner started")
+	defer p.logger.Infow("xds v3 memory provisioner exited")
+	// Responses are pushed by the caller, there is nothing to wait.
+	p.setReady(true)
+
+	<-stop
+	// Unblock the pending PushResponse (if any) before taking the lock.
+	close(p.done)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopped = true
+	close(p.evChan)
+	return nil
+}
+
+// PushResponse runs the DiscoveryResponse through the translation pipeline,
+// events will be emitted on the Channel(). Like the SotW protocol, responses
+// are grouped by their type URLs, a response replaces all the resources in
+// the last response with the same type URL, so pushing an empty response
+// removes them.
+func (p *XDSMemoryProvisioner) PushResponse(dr *discoveryv3.DiscoveryResponse) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		return ErrProvisionerStopped
+	}
+	p.logger.Debugw("discovery response pushed",
+		zap.String("type_url", dr.GetTypeUrl()),
+		zap.String("version_info", dr.GetVersionInfo()),
+	)
+	source := p.sourceName(dr.GetTypeUrl())
+	events := p.generateEventsFromDiscoveryResponseV3(source, dr)
+	if len(events) > 0 {
+		p.logChanges(source, events)
+		events = withSource(source, events)
+		// Send synchronously (with the lock held) so events are
+		// emitted in the order of pushes.
+		select {
+		case p.evChan <- events:
+		case <-p.done:
+			return ErrProvisionerStopped
+		}
+	}
+	return nil
+}
+
+// sourceName returns the name of the source of responses with the
+// given type URL, it's used as the filename.
+func (p *XDSMemoryProvisioner) sourceName(typeUrl string) string {
+	return "memory/" + typeUrl
+}
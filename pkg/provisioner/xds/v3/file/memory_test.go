@@ -0,0 +1,79 @@
+package file
+
+import (
+	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestXDSMemoryProvisioner(t *testing.T) {
+	cfg := &config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}
+	p, err := NewXDSMemoryProvisioner(cfg)
+	assert.Nil(t, err)
+
+	stopCh := make(chan struct{})
+	runCh := make(chan error)
+	go func() {
+		runCh <- p.Run(stopCh)
+	}()
+
+	cluster := &clusterv3.Cluster{
+		Name: "httpbin.default.svc.cluster.local",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_EDS,
+		},
+		LbPolicy: clusterv3.Cluster_ROUND_ROBIN,
+	}
+	any, err := anypb.New(cluster)
+	assert.Nil(t, err)
+
+	pushed := make(chan error)
+	go func() {
+		pushed <- p.PushResponse(&discoveryv3.DiscoveryResponse{
+			TypeUrl:   "type.googleapis.com/envoy.config.cluster.v3.Cluster",
+			Resources: []*anypb.Any{any},
+		})
+	}()
+
+	var events []types.Event
+	select {
+	case events = <-p.Channel():
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event arrived in time")
+	}
+	assert.Nil(t, <-pushed)
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventAdd)
+	assert.Equal(t, events[0].Object.(*apisix.Upstream).Name, "httpbin.default.svc.cluster.local")
+
+	// An empty response removes resources in the last one.
+	go func() {
+		pushed <- p.PushResponse(&discoveryv3.DiscoveryResponse{
+			TypeUrl: "type.googleapis.com/envoy.config.cluster.v3.Cluster",
+		})
+	}()
+	select {
+	case events = <-p.Channel():
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event arrived in time")
+	}
+	assert.Nil(t, <-pushed)
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventDelete)
+	assert.Equal(t, events[0].Tombstone.(*apisix.Upstream).Name, "httpbin.default.svc.cluster.local")
+
+	close(stopCh)
+	assert.Nil(t, <-runCh)
+	assert.Equal(t, p.PushResponse(&discoveryv3.DiscoveryResponse{}), ErrProvisionerStopped)
+}
@@ -0,0 +1,42 @@
+package file
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+)
+
+func TestFileProvisionerOneshot(t *testing.T) {
+	cfg := &config.Config{
+		LogLevel:      "debug",
+		LogOutput:     "stderr",
+		XDSWatchFiles: []string{"testdata/cluster.json", "testdata/route.json"},
+		XDSOneshot:    true,
+	}
+	pr, err := NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	p := pr.(*xdsFileProvisioner)
+	assert.Nil(t, p.watcher)
+
+	errCh := make(chan error, 1)
+	go func() {
+		// The stop channel is never closed, Run exits by itself.
+		errCh <- p.Run(make(chan struct{}))
+	}()
+
+	var events []types.Event
+	for evs := range p.Channel() {
+		events = append(events, evs...)
+	}
+	assert.NotEmpty(t, events)
+	select {
+	case err := <-errCh:
+		assert.Nil(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("provisioner didn't exit in time")
+	}
+}
@@ -0,0 +1,111 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	xdsv3 "github.com/api7/apisix-mesh-agent/pkg/adaptor/xds/v3"
+)
+
+const (
+	// PartialUpdateIdentifier is the sentinel in the control_plane.identifier
+	// of DiscoveryResponses, which marks the response as a partial update,
+	// its resources are merged into the last ones of the same file.
+	PartialUpdateIdentifier = "apisix-mesh-agent/partial-update"
+
+	_discoveryResourceTypeUrl = "type.googleapis.com/envoy.service.discovery.v3.Resource"
+)
+
+// errNoPartialUpdateBase means the partial update arrives before any full
+// DiscoveryResponse of the file.
+var errNoPartialUpdateBase = errors.New("no full discovery response to merge into")
+
+// isPartialUpdate reports whether the DiscoveryResponse is a partial update.
+func isPartialUpdate(dr *discoveryv3.DiscoveryResponse) bool {
+	return dr.GetControlPlane().GetIdentifier() == PartialUpdateIdentifier
+}
+
+// mergeDiscoveryResponse records the DiscoveryResponse of the file and
+// returns the one to translate. Full responses are recorded as is. For
+// partial updates, resources are merged into the recorded ones by their
+// type urls and names: resources wrapped in the discovery Resource with
+// the "resource" field are upserted, those without it are deletions of
+// the resources named (with the type url of the response). Plain
+// resources are upserted.
+func (p *xdsFileProvisioner) mergeDiscoveryResponse(filename string, dr *discoveryv3.DiscoveryResponse) (*discoveryv3.DiscoveryResponse, error) {
+	if !isPartialUpdate(dr) {
+		p.responses[filename] = dr
+		return dr, nil
+	}
+	base, ok := p.responses[filename]
+	if !ok {
+		return nil, errNoPartialUpdateBase
+	}
+	type key struct {
+		typeUrl string
+		name    string
+	}
+	var (
+		resources = make([]*anypb.Any, len(base.GetResources()))
+		index     = make(map[key]int, len(base.GetResources()))
+		deleted   = make(map[int]struct{})
+	)
+	for i, res := range base.GetResources() {
+		resources[i] = res
+		// Resources which cannot be named are kept as is.
+		if name, err := xdsv3.GetResourceName(res); err == nil {
+			index[key{res.GetTypeUrl(), name}] = i
+		}
+	}
+	for _, res := range dr.GetResources() {
+		if res.GetTypeUrl() == _discoveryResourceTypeUrl {
+			var wrapper discoveryv3.Resource
+			if err := res.UnmarshalTo(&wrapper); err != nil {
+				return nil, err
+			}
+			if wrapper.GetResource() == nil {
+				if dr.GetTypeUrl() == "" {
+					return nil, fmt.Errorf("type_url is required to delete %s", wrapper.GetName())
+				}
+				if i, ok := index[key{dr.GetTypeUrl(), wrapper.GetName()}]; ok {
+					deleted[i] = struct{}{}
+					delete(index, key{dr.GetTypeUrl(), wrapper.GetName()})
+				}
+				continue
+			}
+			res = wrapper.GetResource()
+		}
+		name, err := xdsv3.GetResourceName(res)
+		if err != nil {
+			return nil, err
+		}
+		k := key{res.GetTypeUrl(), name}
+		if i, ok := index[k]; ok {
+			resources[i] = res
+			continue
+		}
+		index[k] = len(resources)
+		resources = append(resources, res)
+	}
+
+	merged := &discoveryv3.DiscoveryResponse{
+		VersionInfo: dr.GetVersionInfo(),
+		TypeUrl:     base.GetTypeUrl(),
+	}
+	for i, res := range resources {
+		if _, ok := deleted[i]; !ok {
+			merged.Resources = append(merged.Resources, res)
+		}
+	}
+	p.logger.Debugw("merged partial update",
+		zap.String("filename", filename),
+		zap.Int("resources", len(dr.GetResources())),
+		zap.Int("deleted", len(deleted)),
+	)
+	p.responses[filename] = merged
+	return merged, nil
+}
@@ -0,0 +1,134 @@
+package file
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	xdsv3 "github.com/api7/apisix-mesh-agent/pkg/adaptor/xds/v3"
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/testutil"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func newEDSCluster(name string, lbPolicy clusterv3.Cluster_LbPolicy) *clusterv3.Cluster {
+	return &clusterv3.Cluster{
+		Name: name,
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_EDS,
+		},
+		LbPolicy: lbPolicy,
+	}
+}
+
+func TestFileProvisionerPartialUpdates(t *testing.T) {
+	p, err := newXDSFileProvisioner(&config.Config{
+		LogLevel:          "debug",
+		LogOutput:         "stderr",
+		XDSPartialUpdates: true,
+	}, "test")
+	assert.Nil(t, err)
+
+	// A partial update without the full one is rejected.
+	partial := &discoveryv3.DiscoveryResponse{
+		TypeUrl: types.ClusterUrl,
+		ControlPlane: &corev3.ControlPlane{
+			Identifier: PartialUpdateIdentifier,
+		},
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, newEDSCluster("a", clusterv3.Cluster_LEAST_REQUEST)),
+		},
+	}
+	assert.Nil(t, p.generateEventsFromDiscoveryResponseV3("clusters.json", partial))
+
+	events := p.generateEventsFromDiscoveryResponseV3("clusters.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl: types.ClusterUrl,
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, newEDSCluster("a", clusterv3.Cluster_ROUND_ROBIN)),
+			testutil.NewAny(t, newEDSCluster("b", clusterv3.Cluster_ROUND_ROBIN)),
+		},
+	})
+	assert.Len(t, events, 2)
+
+	// "a" is updated, "c" is added and "b" is deleted.
+	partial.Resources = append(partial.Resources,
+		testutil.NewAny(t, &discoveryv3.Resource{
+			Resource: testutil.NewAny(t, newEDSCluster("c", clusterv3.Cluster_ROUND_ROBIN)),
+		}),
+		testutil.NewAny(t, &discoveryv3.Resource{
+			Name: "b",
+		}),
+	)
+	events = p.generateEventsFromDiscoveryResponseV3("clusters.json", partial)
+	assert.Len(t, events, 3)
+	for _, ev := range events {
+		switch ev.Type {
+		case types.EventAdd:
+			assert.Equal(t, ev.Object.(*apisix.Upstream).Name, "c")
+		case types.EventUpdate:
+			assert.Equal(t, ev.Object.(*apisix.Upstream).Name, "a")
+			assert.Equal(t, ev.Object.(*apisix.Upstream).Type, "least_conn")
+		case types.EventDelete:
+			assert.Equal(t, ev.Tombstone.(*apisix.Upstream).Name, "b")
+		}
+	}
+	merged := p.responses["clusters.json"]
+	assert.Len(t, merged.Resources, 2)
+	name, err := xdsv3.GetResourceName(merged.Resources[0])
+	assert.Nil(t, err)
+	assert.Equal(t, name, "a")
+	name, err = xdsv3.GetResourceName(merged.Resources[1])
+	assert.Nil(t, err)
+	assert.Equal(t, name, "c")
+
+	// Deletions require the type url of the response.
+	partial = &discoveryv3.DiscoveryResponse{
+		ControlPlane: &corev3.ControlPlane{
+			Identifier: PartialUpdateIdentifier,
+		},
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, &discoveryv3.Resource{Name: "a"}),
+		},
+	}
+	_, err = p.mergeDiscoveryResponse("clusters.json", partial)
+	assert.NotNil(t, err)
+
+	// The recorded response is dropped with the file.
+	p.handleContentRemoval("clusters.json")
+	assert.Nil(t, p.responses["clusters.json"])
+	events = <-p.Channel()
+	assert.Len(t, events, 2)
+}
+
+func TestFileProvisionerDuplicatedResources(t *testing.T) {
+	p, err := newXDSFileProvisioner(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}, "test")
+	assert.Nil(t, err)
+
+	// The last cluster with the same name wins.
+	events := p.generateEventsFromDiscoveryResponseV3("clusters.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl: types.ClusterUrl,
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, newEDSCluster("a", clusterv3.Cluster_ROUND_ROBIN)),
+			testutil.NewAny(t, newEDSCluster("b", clusterv3.Cluster_ROUND_ROBIN)),
+			testutil.NewAny(t, newEDSCluster("a", clusterv3.Cluster_LEAST_REQUEST)),
+		},
+	})
+	assert.Len(t, events, 2)
+	lbTypes := make(map[string]string)
+	for _, ev := range events {
+		ups := ev.Object.(*apisix.Upstream)
+		lbTypes[ups.Name] = ups.Type
+	}
+	assert.Equal(t, lbTypes, map[string]string{
+		"a": "least_conn",
+		"b": "roundrobin",
+	})
+}
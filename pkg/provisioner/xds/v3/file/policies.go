@@ -0,0 +1,165 @@
+package file
+
+import (
+	"sort"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"google.golang.org/protobuf/proto"
+
+	xdsv3 "github.com/api7/apisix-mesh-agent/pkg/adaptor/xds/v3"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner/util"
+	"github.com/api7/apisix-mesh-agent/pkg/set"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// clusterPolicies are the hash and retry policies collected from the routes
+// of a file, keyed by the (tenant scoped) cluster name.
+type clusterPolicies struct {
+	hash  map[string]*xdsv3.HashPolicy
+	retry map[string]*xdsv3.RetryPolicy
+}
+
+func newClusterPolicies() *clusterPolicies {
+	return &clusterPolicies{
+		hash:  make(map[string]*xdsv3.HashPolicy),
+		retry: make(map[string]*xdsv3.RetryPolicy),
+	}
+}
+
+// collect collects the policies of routes in the route configuration, it's
+// a no-op if cp is nil.
+func (cp *clusterPolicies) collect(adaptor xdsv3.Adaptor, route *routev3.RouteConfiguration, tenant string) {
+	if cp == nil {
+		return
+	}
+	for cluster, hp := range adaptor.CollectClusterHashPolicies(route) {
+		cp.hash[xdsv3.TenantScopedName(tenant, cluster)] = hp
+	}
+	for cluster, rp := range adaptor.CollectClusterRetryPolicies(route) {
+		cp.retry[xdsv3.TenantScopedName(tenant, cluster)] = rp
+	}
+}
+
+func (cp *clusterPolicies) empty() bool {
+	return len(cp.hash) == 0 && len(cp.retry) == 0
+}
+
+// updateClusterPolicies replaces the policies collected from the file, and
+// rebuilds the policies of all files (the ones of latter files win if
+// there are conflicts). Clusters whose policies are changed (including the
+// removed ones) are returned.
+func (p *xdsFileProvisioner) updateClusterPolicies(filename string, cp *clusterPolicies) set.StringSet {
+	if cp == nil || cp.empty() {
+		delete(p.filePolicies, filename)
+	} else {
+		p.filePolicies[filename] = cp
+	}
+	filenames := make([]string, 0, len(p.filePolicies))
+	for name := range p.filePolicies {
+		filenames = append(filenames, name)
+	}
+	sort.Strings(filenames)
+
+	merged := newClusterPolicies()
+	for _, name := range filenames {
+		for cluster, hp := range p.filePolicies[name].hash {
+			merged.hash[cluster] = hp
+		}
+		for cluster, rp := range p.filePolicies[name].retry {
+			merged.retry[cluster] = rp
+		}
+	}
+
+	changed := make(set.StringSet)
+	for cluster, hp := range merged.hash {
+		if old, ok := p.hashPolicies[cluster]; !ok || *old != *hp {
+			changed.Add(cluster)
+		}
+	}
+	for cluster := range p.hashPolicies {
+		if _, ok := merged.hash[cluster]; !ok {
+			changed.Add(cluster)
+		}
+	}
+	for cluster, rp := range merged.retry {
+		if old, ok := p.retryPolicies[cluster]; !ok || *old != *rp {
+			changed.Add(cluster)
+		}
+	}
+	for cluster := range p.retryPolicies {
+		if _, ok := merged.retry[cluster]; !ok {
+			changed.Add(cluster)
+		}
+	}
+	p.hashPolicies = merged.hash
+	p.retryPolicies = merged.retry
+	return changed
+}
+
+// repatchUpstreams patches the upstreams of the clusters again since their
+// policies changed, so that the removed policies don't linger. Upstreams in
+// the translated manifest (they're patched already) and the ones no longer
+// in any file are skipped. Like the EDS updated upstreams, update events
+// of them are returned.
+func (p *xdsFileProvisioner) repatchUpstreams(clusters set.StringSet, translated *util.Manifest, updated []*apisix.Upstream) []types.Event {
+	if len(clusters) == 0 {
+		return nil
+	}
+	skipped := make(set.StringSet)
+	if translated != nil {
+		for _, ups := range translated.Upstreams {
+			skipped.Add(ups.Name)
+		}
+	}
+	for _, ups := range updated {
+		skipped.Add(ups.Name)
+	}
+	existing := make(set.StringSet)
+	for _, rm := range p.state {
+		if rm == nil {
+			continue
+		}
+		for _, ups := range rm.Upstreams {
+			existing.Add(ups.Name)
+		}
+	}
+
+	names := clusters.Strings()
+	sort.Strings(names)
+	var evs []types.Event
+	for _, name := range names {
+		if _, ok := skipped[name]; ok {
+			continue
+		}
+		if _, ok := existing[name]; !ok {
+			continue
+		}
+		ups, ok := p.upstreamCache[name]
+		if !ok {
+			continue
+		}
+		// Do not modify the original ups to avoid race conditions.
+		newUps := proto.Clone(ups).(*apisix.Upstream)
+		resetUpstreamPolicies(newUps)
+		p.patchUpstreamsWithHashPolicies([]*apisix.Upstream{newUps})
+		p.patchUpstreamsWithRetryPolicies([]*apisix.Upstream{newUps})
+		p.upstreamCache[name] = newUps
+		evs = append(evs, types.Event{
+			Type:   types.EventUpdate,
+			Object: newUps,
+		})
+	}
+	return evs
+}
+
+// resetUpstreamPolicies resets the fields patched by the hash and retry
+// policies to the ones set by the cluster translation, i.e. consistent
+// hashing upstreams fall back to the default hash policy.
+func resetUpstreamPolicies(ups *apisix.Upstream) {
+	ups.HashOn = ""
+	ups.Key = ""
+	xdsv3.DefaultHashPolicy.PatchUpstream(ups)
+	ups.Retries = 0
+	ups.RetryTimeout = 0
+}
@@ -0,0 +1,125 @@
+package file
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/testutil"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func newHashPolicyRoutesResponse(t *testing.T, cluster, header string) *discoveryv3.DiscoveryResponse {
+	action := &routev3.RouteAction{
+		ClusterSpecifier: &routev3.RouteAction_Cluster{
+			Cluster: cluster,
+		},
+	}
+	if header != "" {
+		action.HashPolicy = []*routev3.RouteAction_HashPolicy{
+			{
+				PolicySpecifier: &routev3.RouteAction_HashPolicy_Header_{
+					Header: &routev3.RouteAction_HashPolicy_Header{
+						HeaderName: header,
+					},
+				},
+			},
+		}
+	}
+	rc := &routev3.RouteConfiguration{
+		Name: "rc1",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name:    "vhost1",
+				Domains: []string{"*"},
+				Routes: []*routev3.Route{
+					{
+						Name: "route1",
+						Match: &routev3.RouteMatch{
+							PathSpecifier: &routev3.RouteMatch_Prefix{
+								Prefix: "/",
+							},
+						},
+						Action: &routev3.Route_Route{
+							Route: action,
+						},
+					},
+				},
+			},
+		},
+	}
+	return &discoveryv3.DiscoveryResponse{
+		TypeUrl:   types.RouteConfigurationUrl,
+		Resources: []*anypb.Any{testutil.NewAny(t, rc)},
+	}
+}
+
+func upstreamEvents(events []types.Event) []*apisix.Upstream {
+	var upstreams []*apisix.Upstream
+	for _, ev := range events {
+		if ups, ok := ev.Object.(*apisix.Upstream); ok {
+			upstreams = append(upstreams, ups)
+		}
+	}
+	return upstreams
+}
+
+func TestFileProvisionerHashPolicyRemoval(t *testing.T) {
+	p, err := newXDSFileProvisioner(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}, "test")
+	assert.Nil(t, err)
+
+	cds := &discoveryv3.DiscoveryResponse{
+		TypeUrl: types.ClusterUrl,
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, newEDSCluster("a", clusterv3.Cluster_RING_HASH)),
+		},
+	}
+	upstreams := upstreamEvents(p.generateEventsFromDiscoveryResponseV3("cds.json", cds))
+	assert.Len(t, upstreams, 1)
+	assert.Equal(t, upstreams[0].HashOn, "vars")
+
+	// The upstream in another file is patched by the hash policy.
+	events := p.generateEventsFromDiscoveryResponseV3("rds.json", newHashPolicyRoutesResponse(t, "a", "x-user"))
+	upstreams = upstreamEvents(events)
+	assert.Len(t, upstreams, 1)
+	assert.Equal(t, upstreams[0].HashOn, "header")
+	assert.Equal(t, upstreams[0].Key, "x-user")
+
+	// The hash policy is removed from the route.
+	events = p.generateEventsFromDiscoveryResponseV3("rds.json", newHashPolicyRoutesResponse(t, "a", ""))
+	upstreams = upstreamEvents(events)
+	assert.Len(t, upstreams, 1)
+	assert.Equal(t, upstreams[0].Type, "chash")
+	assert.Equal(t, upstreams[0].HashOn, "vars")
+	assert.Equal(t, upstreams[0].Key, "remote_addr")
+	assert.Len(t, p.hashPolicies, 0)
+
+	// Nothing changes if the policies stay the same.
+	events = p.generateEventsFromDiscoveryResponseV3("rds.json", newHashPolicyRoutesResponse(t, "a", ""))
+	assert.Len(t, upstreamEvents(events), 0)
+
+	// The hash policy is removed with the file.
+	upstreams = upstreamEvents(p.generateEventsFromDiscoveryResponseV3("rds.json", newHashPolicyRoutesResponse(t, "a", "x-user")))
+	assert.Len(t, upstreams, 1)
+	assert.Equal(t, upstreams[0].Key, "x-user")
+	p.handleContentRemoval("rds.json")
+	upstreams = upstreamEvents(<-p.Channel())
+	assert.Len(t, upstreams, 1)
+	assert.Equal(t, upstreams[0].Key, "remote_addr")
+	assert.Len(t, p.filePolicies, 0)
+
+	// Upstreams no longer in any file are not re-emitted.
+	p.generateEventsFromDiscoveryResponseV3("rds.json", newHashPolicyRoutesResponse(t, "a", "x-user"))
+	p.generateEventsFromDiscoveryResponseV3("cds.json", &discoveryv3.DiscoveryResponse{TypeUrl: types.ClusterUrl})
+	events = p.generateEventsFromDiscoveryResponseV3("rds.json", newHashPolicyRoutesResponse(t, "a", ""))
+	assert.Len(t, upstreamEvents(events), 0)
+}
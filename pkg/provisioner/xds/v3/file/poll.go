@@ -0,0 +1,76 @@
+package file
+
+import (
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner/util"
+)
+
+// runPolling polls the watched files periodically, it's the fallback when the
+// file system notifications cannot be trusted (e.g. on some network mounts).
+// Changes are detected by comparing the modification time and the size of files
+// with the last seen ones, and they are handled in the same way as the events
+// from the watcher.
+func (p *xdsFileProvisioner) runPolling(stop chan struct{}) error {
+	p.logger.Infow("polling watched files",
+		zap.Duration("interval", p.pollInterval),
+	)
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+	heartbeat, stopHeartbeat := util.HeartbeatTicker(p.heartbeatInterval)
+	defer stopHeartbeat()
+
+	for {
+		select {
+		case <-heartbeat:
+			p.sendEvents(util.HeartbeatEvents())
+		case <-stop:
+			// Wait for all events to be delivered before closing the
+			// channel.
+			p.sending.Wait()
+			return nil
+		case <-ticker.C:
+			p.pollFiles()
+		}
+	}
+}
+
+func (p *xdsFileProvisioner) pollFiles() {
+	files, err := p.walkFiles(true)
+	if err != nil {
+		p.logger.Errorw("failed to poll watched files",
+			zap.Error(err),
+		)
+		return
+	}
+	for _, file := range sortedFilenames(p.fileStats) {
+		if _, ok := files[file]; ok {
+			continue
+		}
+		p.logger.Infow("file removal detected by polling",
+			zap.String("filename", file),
+		)
+		delete(p.fileStats, file)
+		p.handleFileEvent(fsnotify.Event{
+			Name: file,
+			Op:   fsnotify.Remove,
+		})
+	}
+	for _, file := range sortedFilenames(files) {
+		info := files[file]
+		if last, ok := p.fileStats[file]; ok && last.ModTime().Equal(info.ModTime()) && last.Size() == info.Size() {
+			continue
+		}
+		p.logger.Infow("file change detected by polling",
+			zap.String("filename", file),
+		)
+		p.fileStats[file] = info
+		p.handleFileEvent(fsnotify.Event{
+			Name: file,
+			Op:   fsnotify.Write,
+		})
+	}
+}
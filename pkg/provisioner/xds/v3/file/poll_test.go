@@ -0,0 +1,105 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestFileProvisionerPolling(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xds-poll")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := &config.Config{
+		LogLevel:        "debug",
+		LogOutput:       "stderr",
+		XDSWatchFiles:   []string{dir},
+		XDSPollInterval: 1,
+	}
+	pr, err := NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	p := pr.(*xdsFileProvisioner)
+	assert.Nil(t, p.watcher)
+	p.pollInterval = 50 * time.Millisecond
+
+	stopCh := make(chan struct{})
+	go func() {
+		assert.Nil(t, p.Run(stopCh))
+	}()
+
+	cluster, err := ioutil.ReadFile("testdata/cluster.json")
+	assert.Nil(t, err)
+	filename := filepath.Join(dir, "cluster.json")
+	assert.Nil(t, ioutil.WriteFile(filename, cluster, 0644))
+
+	var events []types.Event
+	select {
+	case events = <-p.Channel():
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event arrived in time")
+	}
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventAdd)
+	assert.Equal(t, events[0].Object.(*apisix.Upstream).Name, "httpbin.default.svc.cluster.local")
+
+	assert.Nil(t, os.Remove(filename))
+	select {
+	case events = <-p.Channel():
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event arrived in time")
+	}
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventDelete)
+	assert.Equal(t, events[0].Tombstone.(*apisix.Upstream).Name, "httpbin.default.svc.cluster.local")
+
+	close(stopCh)
+	_, ok := <-p.Channel()
+	assert.Equal(t, ok, false)
+}
+
+func TestFileProvisionerPollingWithHeartbeats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xds-poll")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := &config.Config{
+		LogLevel:          "debug",
+		LogOutput:         "stderr",
+		XDSWatchFiles:     []string{dir},
+		XDSPollInterval:   3600,
+		HeartbeatInterval: 50 * time.Millisecond,
+	}
+	pr, err := NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	p := pr.(*xdsFileProvisioner)
+
+	stopCh := make(chan struct{})
+	go func() {
+		assert.Nil(t, p.Run(stopCh))
+	}()
+
+	for i := 0; i < 2; i++ {
+		var events []types.Event
+		select {
+		case events = <-p.Channel():
+		case <-time.After(2 * time.Second):
+			t.Fatal("no heartbeat arrived in time")
+		}
+		assert.Len(t, events, 1)
+		assert.Equal(t, events[0].Type, types.EventHeartbeat)
+		assert.Nil(t, events[0].Object)
+	}
+
+	close(stopCh)
+	for range p.Channel() {
+	}
+}
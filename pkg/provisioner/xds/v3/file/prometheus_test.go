@@ -0,0 +1,138 @@
+package file
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/testutil"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func newPrefixRoute(name, prefix, cluster string) *routev3.Route {
+	return &routev3.Route{
+		Name: name,
+		Match: &routev3.RouteMatch{
+			PathSpecifier: &routev3.RouteMatch_Prefix{
+				Prefix: prefix,
+			},
+		},
+		Action: &routev3.Route_Route{
+			Route: &routev3.RouteAction{
+				ClusterSpecifier: &routev3.RouteAction_Cluster{
+					Cluster: cluster,
+				},
+			},
+		},
+	}
+}
+
+func TestFileProvisionerPrometheusForTrackedClusters(t *testing.T) {
+	p, err := newXDSFileProvisioner(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}, "test")
+	assert.Nil(t, err)
+
+	tracked := newEDSCluster("a", clusterv3.Cluster_ROUND_ROBIN)
+	tracked.TrackClusterStats = &clusterv3.TrackClusterStats{
+		TimeoutBudgets: true,
+	}
+	events := p.generateEventsFromDiscoveryResponseV3("bootstrap.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl: types.ClusterUrl,
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, tracked),
+			testutil.NewAny(t, newEDSCluster("b", clusterv3.Cluster_ROUND_ROBIN)),
+			testutil.NewAny(t, &routev3.RouteConfiguration{
+				Name: "rc1",
+				VirtualHosts: []*routev3.VirtualHost{
+					{
+						Name:    "vhost1",
+						Domains: []string{"*"},
+						Routes: []*routev3.Route{
+							newPrefixRoute("route1", "/a", "a"),
+							newPrefixRoute("route2", "/b", "b"),
+						},
+					},
+				},
+			}),
+		},
+	})
+	var routes int
+	for _, ev := range events {
+		r, ok := ev.Object.(*apisix.Route)
+		if !ok {
+			continue
+		}
+		routes++
+		// Only the route to the tracked cluster has the plugin.
+		assert.Equal(t, r.UpstreamId == id.GenID("a"), r.GetPlugins().GetPrometheus() != nil)
+	}
+	assert.Equal(t, routes, 2)
+}
+
+func TestFileProvisionerPrometheusForClustersInOtherFiles(t *testing.T) {
+	p, err := newXDSFileProvisioner(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}, "test")
+	assert.Nil(t, err)
+
+	events := p.generateEventsFromDiscoveryResponseV3("routes.json", &discoveryv3.DiscoveryResponse{
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, &routev3.RouteConfiguration{
+				Name: "rc1",
+				VirtualHosts: []*routev3.VirtualHost{
+					{
+						Name:    "vhost1",
+						Domains: []string{"*"},
+						Routes: []*routev3.Route{
+							newPrefixRoute("route1", "/a", "a"),
+						},
+					},
+				},
+			}),
+		},
+	})
+	assert.Len(t, events, 1)
+	assert.Nil(t, events[0].Object.(*apisix.Route).GetPlugins().GetPrometheus())
+
+	// The route is updated once the cluster is seen, or its stats
+	// tracking is changed.
+	tracked := newEDSCluster("a", clusterv3.Cluster_ROUND_ROBIN)
+	tracked.TrackClusterStats = &clusterv3.TrackClusterStats{
+		RequestResponseSizes: true,
+	}
+	untracked := newEDSCluster("a", clusterv3.Cluster_ROUND_ROBIN)
+	for _, c := range []struct {
+		cluster *clusterv3.Cluster
+		tracked bool
+	}{
+		{cluster: tracked, tracked: true},
+		{cluster: untracked, tracked: false},
+	} {
+		events = p.generateEventsFromDiscoveryResponseV3("clusters.json", &discoveryv3.DiscoveryResponse{
+			Resources: []*anypb.Any{
+				testutil.NewAny(t, c.cluster),
+			},
+		})
+		var routes int
+		for _, ev := range events {
+			r, ok := ev.Object.(*apisix.Route)
+			if !ok {
+				continue
+			}
+			routes++
+			assert.Equal(t, ev.Type, types.EventUpdate)
+			assert.Equal(t, r.GetPlugins().GetPrometheus() != nil, c.tracked)
+		}
+		assert.Equal(t, routes, 1)
+	}
+}
@@ -0,0 +1,182 @@
+package file
+
+import (
+	"sort"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner/util"
+	"github.com/api7/apisix-mesh-agent/pkg/set"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// upstreamsSnapshot is the snapshot of the upstreams which the checks of
+// routes depend on, see changedUpstreams.
+type upstreamsSnapshot struct {
+	known   set.StringSet
+	tracked set.StringSet
+	limits  map[string]*apisix.LimitConn
+}
+
+func (p *xdsFileProvisioner) snapshotUpstreams() *upstreamsSnapshot {
+	tracked := make(set.StringSet, len(p.statsTrackedUpstreams))
+	for id := range p.statsTrackedUpstreams {
+		tracked.Add(id)
+	}
+	limits := make(map[string]*apisix.LimitConn, len(p.connectionLimits))
+	for id, lc := range p.connectionLimits {
+		limits[id] = lc
+	}
+	return &upstreamsSnapshot{
+		known:   p.knownUpstreams(),
+		tracked: tracked,
+		limits:  limits,
+	}
+}
+
+// knownUpstreams returns the ids of the upstreams of all clusters seen.
+func (p *xdsFileProvisioner) knownUpstreams() set.StringSet {
+	known := make(set.StringSet, len(p.upstreamCache))
+	for _, ups := range p.upstreamCache {
+		known.Add(ups.Id)
+	}
+	return known
+}
+
+// changedUpstreams returns the ids of the upstreams which are added or
+// removed since the snapshot, or whose stats tracking or connection limit
+// is changed.
+func (p *xdsFileProvisioner) changedUpstreams(s *upstreamsSnapshot) set.StringSet {
+	changed := make(set.StringSet)
+	addDifference(changed, s.known, p.knownUpstreams())
+	addDifference(changed, s.tracked, p.statsTrackedUpstreams)
+	for id, lc := range p.connectionLimits {
+		if !proto.Equal(s.limits[id], lc) {
+			changed.Add(id)
+		}
+	}
+	for id := range s.limits {
+		if _, ok := p.connectionLimits[id]; !ok {
+			changed.Add(id)
+		}
+	}
+	return changed
+}
+
+// addDifference adds the elements in only one of a and b to diff.
+func addDifference(diff, a, b set.StringSet) {
+	for e := range a {
+		if _, ok := b[e]; !ok {
+			diff.Add(e)
+		}
+	}
+	for e := range b {
+		if _, ok := a[e]; !ok {
+			diff.Add(e)
+		}
+	}
+}
+
+// forgetRemovedUpstreams forgets the upstreams which are in the last state
+// of the file but not in the new one (nil if the file is removed), unless
+// other files still have them, so the removed clusters are not counted as
+// known (or colliding) upstreams any more.
+func (p *xdsFileProvisioner) forgetRemovedUpstreams(filename string, rm *util.Manifest) {
+	rmo := p.state[filename]
+	if rmo == nil {
+		return
+	}
+	kept := make(set.StringSet)
+	if rm != nil {
+		for _, ups := range rm.Upstreams {
+			kept.Add(ups.Name)
+		}
+	}
+	for name, state := range p.state {
+		if name == filename || state == nil {
+			continue
+		}
+		for _, ups := range state.Upstreams {
+			kept.Add(ups.Name)
+		}
+	}
+	for _, ups := range rmo.Upstreams {
+		if _, ok := kept[ups.Name]; ok {
+			continue
+		}
+		delete(p.upstreamCache, ups.Name)
+		delete(p.statsTrackedUpstreams, ups.Id)
+		delete(p.connectionLimits, ups.Id)
+		delete(p.clusterSecrets, ups.Name)
+	}
+}
+
+// checkRoutes runs the checks which depend on the upstreams referred by the
+// routes, the routes are modified in place.
+func (p *xdsFileProvisioner) checkRoutes(routes []*apisix.Route) {
+	p.checkTrafficSplitReferences(routes)
+	p.enablePrometheusForTrackedUpstreams(routes)
+	p.limitConnectionsForUpstreams(routes)
+}
+
+// recheckRoutes checks the routes of other files again if they refer to the
+// changed upstreams, since the clusters might be seen after (or removed
+// before) the routes referring to them, so the result doesn't depend on the
+// order of files. The events of these files are returned.
+func (p *xdsFileProvisioner) recheckRoutes(filename string, changed set.StringSet) []types.Event {
+	if len(changed) == 0 {
+		return nil
+	}
+	filenames := make([]string, 0, len(p.uncheckedRoutes))
+	for name := range p.uncheckedRoutes {
+		filenames = append(filenames, name)
+	}
+	sort.Strings(filenames)
+
+	var evs []types.Event
+	for _, name := range filenames {
+		rmo := p.state[name]
+		if name == filename || rmo == nil || !refersToUpstreams(p.uncheckedRoutes[name], changed) {
+			continue
+		}
+		p.logger.Infow("check routes again since the upstreams referred by them changed",
+			zap.String("filename", name),
+		)
+		rm := *rmo
+		rm.Routes = cloneRoutes(p.uncheckedRoutes[name])
+		p.checkRoutes(rm.Routes)
+		if p.sharedPluginConfigs {
+			rm.PluginConfigs = util.ExtractPluginConfigs(name, rm.Routes)
+		}
+		evs = append(evs, p.generateEvents(name, rmo, &rm)...)
+	}
+	return evs
+}
+
+// refersToUpstreams tells whether any of the routes refers to the upstreams,
+// either directly or by the traffic-split legs.
+func refersToUpstreams(routes []*apisix.Route, ids set.StringSet) bool {
+	for _, r := range routes {
+		if _, ok := ids[r.GetUpstreamId()]; ok {
+			return true
+		}
+		for _, rule := range r.GetPlugins().GetTrafficSplit().GetRules() {
+			for _, wu := range rule.GetWeightedUpstreams() {
+				if _, ok := ids[wu.GetUpstreamId()]; ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func cloneRoutes(routes []*apisix.Route) []*apisix.Route {
+	cloned := make([]*apisix.Route, 0, len(routes))
+	for _, r := range routes {
+		cloned = append(cloned, proto.Clone(r).(*apisix.Route))
+	}
+	return cloned
+}
@@ -0,0 +1,189 @@
+package file
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/testutil"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func newWeightedRouteConfiguration(clusters ...string) *routev3.RouteConfiguration {
+	wc := &routev3.WeightedCluster{}
+	for _, cluster := range clusters {
+		wc.Clusters = append(wc.Clusters, &routev3.WeightedCluster_ClusterWeight{
+			Name:   cluster,
+			Weight: &wrappers.UInt32Value{Value: uint32(100 / len(clusters))},
+		})
+	}
+	route := newPrefixRoute("route1", "/", clusters[0])
+	route.GetRoute().ClusterSpecifier = &routev3.RouteAction_WeightedClusters{
+		WeightedClusters: wc,
+	}
+	return &routev3.RouteConfiguration{
+		Name: "rc1",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name:    "vhost1",
+				Domains: []string{"*"},
+				Routes:  []*routev3.Route{route},
+			},
+		},
+	}
+}
+
+// getTrafficSplitUpstreams returns the upstream ids of the traffic-split
+// legs of the first route in the events.
+func getTrafficSplitUpstreams(t *testing.T, events []types.Event) []string {
+	for _, ev := range events {
+		r, ok := ev.Object.(*apisix.Route)
+		if !ok {
+			continue
+		}
+		var ids []string
+		for _, rule := range r.GetPlugins().GetTrafficSplit().GetRules() {
+			for _, wu := range rule.GetWeightedUpstreams() {
+				ids = append(ids, wu.GetUpstreamId())
+			}
+		}
+		return ids
+	}
+	t.Fatal("no route in the events")
+	return nil
+}
+
+func TestFileProvisionerRecheckTrafficSplitReferences(t *testing.T) {
+	p, err := newXDSFileProvisioner(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}, "test")
+	assert.Nil(t, err)
+
+	// The cluster "b" is not seen yet, so the leg is dropped.
+	events := p.generateEventsFromDiscoveryResponseV3("routes.json", &discoveryv3.DiscoveryResponse{
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, newEDSCluster("a", clusterv3.Cluster_ROUND_ROBIN)),
+			testutil.NewAny(t, newWeightedRouteConfiguration("a", "b")),
+		},
+	})
+	assert.Equal(t, getTrafficSplitUpstreams(t, events), []string{id.GenID("a")})
+
+	// The leg is restored once the cluster is seen in another file.
+	events = p.generateEventsFromDiscoveryResponseV3("clusters.json", &discoveryv3.DiscoveryResponse{
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, newEDSCluster("b", clusterv3.Cluster_ROUND_ROBIN)),
+		},
+	})
+	assert.Len(t, events, 2)
+	assert.Equal(t, events[0].Type, types.EventAdd)
+	assert.Equal(t, events[1].Type, types.EventUpdate)
+	assert.Equal(t, getTrafficSplitUpstreams(t, events), []string{id.GenID("a"), id.GenID("b")})
+	assert.Equal(t, p.state["routes.json"].Routes[0].GetPlugins().GetTrafficSplit().GetRules()[0].GetWeightedUpstreams()[1].GetUpstreamId(), id.GenID("b"))
+
+	// Nothing changes if the upstreams are same.
+	events = p.generateEventsFromDiscoveryResponseV3("clusters.json", &discoveryv3.DiscoveryResponse{
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, newEDSCluster("b", clusterv3.Cluster_ROUND_ROBIN)),
+		},
+	})
+	assert.Len(t, events, 0)
+}
+
+func TestFileProvisionerRecheckConnectionLimits(t *testing.T) {
+	p, err := newXDSFileProvisioner(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}, "test")
+	assert.Nil(t, err)
+
+	cluster := newEDSCluster("a", clusterv3.Cluster_ROUND_ROBIN)
+	p.generateEventsFromDiscoveryResponseV3("clusters.json", &discoveryv3.DiscoveryResponse{
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, cluster),
+		},
+	})
+	events := p.generateEventsFromDiscoveryResponseV3("routes.json", &discoveryv3.DiscoveryResponse{
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, newWeightedRouteConfiguration("a")),
+		},
+	})
+	assert.Len(t, events, 1)
+	assert.Nil(t, events[0].Object.(*apisix.Route).GetPlugins().GetLimitConn())
+
+	// The route is updated once the circuit breakers of the cluster
+	// change.
+	for _, conn := range []uint32{10, 20, 0} {
+		cluster.CircuitBreakers = &clusterv3.CircuitBreakers{
+			Thresholds: []*clusterv3.CircuitBreakers_Thresholds{
+				{MaxConnections: &wrappers.UInt32Value{Value: conn}},
+			},
+		}
+		events = p.generateEventsFromDiscoveryResponseV3("clusters.json", &discoveryv3.DiscoveryResponse{
+			Resources: []*anypb.Any{
+				testutil.NewAny(t, cluster),
+			},
+		})
+		var routes int
+		for _, ev := range events {
+			r, ok := ev.Object.(*apisix.Route)
+			if !ok {
+				continue
+			}
+			routes++
+			assert.Equal(t, ev.Type, types.EventUpdate)
+			assert.Equal(t, r.GetPlugins().GetLimitConn().GetConn(), int32(conn))
+		}
+		assert.Equal(t, routes, 1)
+	}
+}
+
+func TestFileProvisionerForgetRemovedUpstreams(t *testing.T) {
+	p, err := newXDSFileProvisioner(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}, "test")
+	assert.Nil(t, err)
+
+	p.generateEventsFromDiscoveryResponseV3("clusters.json", &discoveryv3.DiscoveryResponse{
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, newEDSCluster("a", clusterv3.Cluster_ROUND_ROBIN)),
+			testutil.NewAny(t, newEDSCluster("b", clusterv3.Cluster_ROUND_ROBIN)),
+		},
+	})
+	events := p.generateEventsFromDiscoveryResponseV3("routes.json", &discoveryv3.DiscoveryResponse{
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, newWeightedRouteConfiguration("a", "b")),
+		},
+	})
+	assert.Equal(t, getTrafficSplitUpstreams(t, events), []string{id.GenID("a"), id.GenID("b")})
+
+	// The cluster "b" is removed from the file, the leg is dropped.
+	events = p.generateEventsFromDiscoveryResponseV3("clusters.json", &discoveryv3.DiscoveryResponse{
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, newEDSCluster("a", clusterv3.Cluster_ROUND_ROBIN)),
+		},
+	})
+	assert.Len(t, events, 2)
+	assert.Equal(t, events[0].Type, types.EventDelete)
+	assert.Equal(t, events[1].Type, types.EventUpdate)
+	assert.Equal(t, getTrafficSplitUpstreams(t, events), []string{id.GenID("a")})
+	assert.NotContains(t, p.upstreamCache, "b")
+
+	// The file is removed.
+	p.handleContentRemoval("clusters.json")
+	events = <-p.Channel()
+	assert.Len(t, events, 2)
+	assert.Equal(t, events[0].Type, types.EventDelete)
+	assert.Equal(t, events[1].Type, types.EventUpdate)
+	assert.Nil(t, events[1].Object.(*apisix.Route).GetPlugins().GetTrafficSplit())
+	assert.Len(t, p.upstreamCache, 0)
+}
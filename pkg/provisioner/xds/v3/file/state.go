@@ -0,0 +1,181 @@
+package file
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner/util"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+)
+
+// _stateFileSuffix is the suffix of the files which persist the state.
+const _stateFileSuffix = ".state.json"
+
+// persistedState is the on-disk format of the state of a watched file.
+type persistedState struct {
+	Filename string         `json:"filename"`
+	Manifest *util.Manifest `json:"manifest"`
+	// Response is the last (merged) DiscoveryResponse of the file, it's
+	// only persisted if the partial updates are enabled, so that they can
+	// be merged into it after restarts.
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// stateFile returns the path to persist the state of the watched file, the
+// filename is hashed since it might be nested.
+func stateFile(dir, filename string) string {
+	return filepath.Join(dir, fmt.Sprintf("%x%s", sha256.Sum256([]byte(filename)), _stateFileSuffix))
+}
+
+// loadState loads the persisted states into the state, so that events are
+// only generated for the changes since them. Corrupted states are dropped
+// with a warning, resources of their files will be added again.
+func (p *xdsFileProvisioner) loadState() error {
+	// The states contain the private keys of ssls.
+	if err := os.MkdirAll(p.stateDir, 0700); err != nil {
+		return err
+	}
+	entries, err := ioutil.ReadDir(p.stateDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), _stateFileSuffix) {
+			continue
+		}
+		path := filepath.Join(p.stateDir, entry.Name())
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var st persistedState
+		if err := json.Unmarshal(data, &st); err != nil || st.Filename == "" || st.Manifest == nil {
+			p.logger.Warnw("ignore corrupted state",
+				zap.Error(err),
+				zap.String("path", path),
+			)
+			continue
+		}
+		p.state[st.Filename] = st.Manifest
+		if p.partialUpdates && len(st.Response) > 0 {
+			var dr discoveryv3.DiscoveryResponse
+			if err := protojson.Unmarshal(st.Response, &dr); err != nil {
+				p.logger.Warnw("ignore corrupted partial update base in state",
+					zap.Error(err),
+					zap.String("path", path),
+				)
+				continue
+			}
+			p.responses[st.Filename] = &dr
+		}
+	}
+	p.updateWatchedFiles()
+	p.logger.Infow("state restored",
+		zap.String("dir", p.stateDir),
+		zap.Int("files", len(p.state)),
+	)
+	return nil
+}
+
+// saveState persists the state of the file, it's removed if the manifest
+// is nil. The file is replaced atomically, so a crash never leaves a partial
+// one. Failures are logged only, the state in memory is still right.
+func (p *xdsFileProvisioner) saveState(filename string, m *util.Manifest) {
+	if p.stateDir == "" {
+		return
+	}
+	path := stateFile(p.stateDir, filename)
+	if m == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			p.logger.Errorw("failed to remove state",
+				zap.Error(err),
+				zap.String("filename", filename),
+				zap.String("path", path),
+			)
+		}
+		return
+	}
+	st := &persistedState{
+		Filename: filename,
+		Manifest: m,
+	}
+	var err error
+	if dr, ok := p.responses[filename]; ok && p.partialUpdates {
+		st.Response, err = protojson.Marshal(dr)
+	}
+	var data []byte
+	if err == nil {
+		data, err = json.Marshal(st)
+	}
+	if err == nil {
+		err = writeFileAtomically(path, data)
+	}
+	if err != nil {
+		p.logger.Errorw("failed to persist state",
+			zap.Error(err),
+			zap.String("filename", filename),
+			zap.String("path", path),
+		)
+	}
+}
+
+func writeFileAtomically(path string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Restored implements provisioner.Restorer.
+func (p *xdsFileProvisioner) Restored() []types.Event {
+	filenames := make([]string, 0, len(p.state))
+	for filename, m := range p.state {
+		if m != nil {
+			filenames = append(filenames, filename)
+		}
+	}
+	sort.Strings(filenames)
+	var events []types.Event
+	for _, filename := range filenames {
+		events = append(events, p.state[filename].Events(types.EventAdd)...)
+	}
+	return util.DropProtectedEvents(events, p.protected)
+}
+
+// removeMissingFiles generates the delete events for the files which are
+// in the restored state but no longer exist.
+func (p *xdsFileProvisioner) removeMissingFiles(files map[string]os.FileInfo) {
+	var missing []string
+	for filename, m := range p.state {
+		if _, ok := files[filename]; !ok && m != nil {
+			missing = append(missing, filename)
+		}
+	}
+	sort.Strings(missing)
+	for _, filename := range missing {
+		p.logger.Infow("file in the restored state no longer exists",
+			zap.String("filename", filename),
+		)
+		p.handleContentRemoval(filename)
+	}
+}
@@ -0,0 +1,121 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/testutil"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestFileProvisionerPersistState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xds-state")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	cfg := &config.Config{
+		LogLevel:      "debug",
+		LogOutput:     "stderr",
+		XDSWatchFiles: []string{"/etc/xds"},
+		XDSOneshot:    true,
+		XDSStateDir:   dir,
+	}
+
+	prov, err := NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	p := prov.(*xdsFileProvisioner)
+	assert.Empty(t, p.Restored())
+	assert.Len(t, p.generateEventsFromDiscoveryResponseV3("/etc/xds/a.json", newClustersResponse(t, "a1", "a2")), 2)
+	assert.Len(t, p.generateEventsFromDiscoveryResponseV3("/etc/xds/b.json", newClustersResponse(t, "b")), 1)
+	// Corrupted states are ignored.
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "bad"+_stateFileSuffix), []byte("{"), 0600))
+
+	// Restarted.
+	prov, err = NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	p = prov.(*xdsFileProvisioner)
+	restored := p.Restored()
+	assert.Len(t, restored, 3)
+	for _, ev := range restored {
+		assert.Equal(t, ev.Type, types.EventAdd)
+	}
+	assert.Len(t, p.generateEventsFromDiscoveryResponseV3("/etc/xds/a.json", newClustersResponse(t, "a1", "a2")), 0)
+	evs := p.generateEventsFromDiscoveryResponseV3("/etc/xds/a.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl: types.ClusterUrl,
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, newEDSCluster("a1", clusterv3.Cluster_LEAST_REQUEST)),
+		},
+	})
+	assert.Len(t, evs, 2)
+	assert.Equal(t, evs[0].Type, types.EventDelete)
+	assert.Equal(t, evs[0].Tombstone.(*apisix.Upstream).Name, "a2")
+	assert.Equal(t, evs[1].Type, types.EventUpdate)
+	assert.Equal(t, evs[1].Object.(*apisix.Upstream).Type, "least_conn")
+
+	// The file b.json no longer exists.
+	p.removeMissingFiles(map[string]os.FileInfo{"/etc/xds/a.json": nil})
+	evs = <-p.Channel()
+	assert.Len(t, evs, 1)
+	assert.Equal(t, evs[0].Type, types.EventDelete)
+	assert.Equal(t, evs[0].Tombstone.(*apisix.Upstream).Name, "b")
+	_, err = os.Stat(stateFile(dir, "/etc/xds/b.json"))
+	assert.True(t, os.IsNotExist(err))
+
+	prov, err = NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	assert.Len(t, prov.(*xdsFileProvisioner).Restored(), 1)
+}
+
+func TestFileProvisionerPersistPartialUpdateBase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xds-state")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	cfg := &config.Config{
+		LogLevel:          "debug",
+		LogOutput:         "stderr",
+		XDSWatchFiles:     []string{"/etc/xds"},
+		XDSOneshot:        true,
+		XDSStateDir:       dir,
+		XDSPartialUpdates: true,
+	}
+
+	prov, err := NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	p := prov.(*xdsFileProvisioner)
+	assert.Len(t, p.generateEventsFromDiscoveryResponseV3("/etc/xds/a.json", newClustersResponse(t, "a1", "a2")), 2)
+
+	// Restarted, the partial update is merged into the persisted response.
+	prov, err = NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	p = prov.(*xdsFileProvisioner)
+	assert.Len(t, p.Restored(), 2)
+	evs := p.generateEventsFromDiscoveryResponseV3("/etc/xds/a.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl: types.ClusterUrl,
+		ControlPlane: &corev3.ControlPlane{
+			Identifier: PartialUpdateIdentifier,
+		},
+		Resources: []*anypb.Any{
+			testutil.NewAny(t, newEDSCluster("a3", clusterv3.Cluster_ROUND_ROBIN)),
+		},
+	})
+	assert.Len(t, evs, 1)
+	assert.Equal(t, evs[0].Type, types.EventAdd)
+	assert.Equal(t, evs[0].Object.(*apisix.Upstream).Name, "a3")
+	assert.Len(t, p.responses["/etc/xds/a.json"].Resources, 3)
+
+	// The merged response is persisted as well.
+	prov, err = NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	p = prov.(*xdsFileProvisioner)
+	assert.Len(t, p.Restored(), 3)
+	assert.Len(t, p.responses["/etc/xds/a.json"].Resources, 3)
+}
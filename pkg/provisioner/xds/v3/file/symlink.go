@@ -0,0 +1,97 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// _atomicWriterDataDir is the symlink (to the timestamped directory) in the
+// ConfigMap volumes, Kubernetes re-points it atomically on updates, files
+// in the volume are symlinks through it.
+const _atomicWriterDataDir = "..data"
+
+// isAtomicWriterPath reports whether the path is maintained by the atomic
+// writer of Kubernetes (e.g. "..data" and "..2021_06_01_12_00_00.123"),
+// rather than the user files.
+func isAtomicWriterPath(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), "..")
+}
+
+// handleAtomicWriterEvent handles the events of the paths maintained by the
+// atomic writer. Once the "..data" symlink is re-pointed, files in the same
+// directory are changed (through it) without any event, so they're parsed
+// again, unchanged resources won't generate events.
+func (p *xdsFileProvisioner) handleAtomicWriterEvent(ev fsnotify.Event) {
+	if filepath.Base(ev.Name) != _atomicWriterDataDir || ev.Op != fsnotify.Create {
+		return
+	}
+	dir := filepath.Dir(ev.Name)
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		p.logger.Errorw("failed to read directory",
+			zap.Error(err),
+			zap.String("directory", dir),
+		)
+		return
+	}
+	var files []string
+	for _, entry := range entries {
+		if isAtomicWriterPath(entry.Name()) || entry.IsDir() {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(files)
+	p.logger.Infow("configmap volume was updated atomically",
+		zap.String("directory", dir),
+		zap.Strings("files", files),
+	)
+	for _, file := range files {
+		p.handleFileEvent(fsnotify.Event{
+			Name: file,
+			Op:   fsnotify.Write,
+		})
+	}
+}
+
+// handleSymlinkSwap handles the removal of a watched symlink, the watch
+// follows the symlink, so the removal might be the old target instead
+// of the symlink itself. If the symlink still resolves, the watch is
+// re-added (on the new target) and it's parsed again. It reports whether
+// the removal is a symlink swap.
+func (p *xdsFileProvisioner) handleSymlinkSwap(path string) bool {
+	if info, err := os.Lstat(path); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return false
+	}
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	if info, err := os.Stat(target); err != nil || info.IsDir() {
+		return false
+	}
+	// The watch of the removed target was dropped by the watcher.
+	_ = p.watcher.Remove(path)
+	if err := p.watcher.Add(path); err != nil {
+		p.logger.Errorw("failed to re-watch symlink",
+			zap.Error(err),
+			zap.String("filename", path),
+			zap.String("target", target),
+		)
+	}
+	p.logger.Infow("watched symlink was swapped",
+		zap.String("filename", path),
+		zap.String("target", target),
+	)
+	p.handleFileEvent(fsnotify.Event{
+		Name: path,
+		Op:   fsnotify.Write,
+	})
+	return true
+}
@@ -0,0 +1,103 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// writeAtomically mimics the atomic writer of Kubernetes, which writes the
+// files to a new timestamped directory and re-points the "..data" symlink.
+func writeAtomically(t *testing.T, dir, version string, files map[string]string) {
+	tsDir := "..2021_06_01_00_00_0" + version
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, tsDir), 0755))
+	for name, content := range files {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, tsDir, name), []byte(content), 0644))
+	}
+	old, _ := os.Readlink(filepath.Join(dir, _atomicWriterDataDir))
+	assert.Nil(t, os.Symlink(tsDir, filepath.Join(dir, "..data_tmp")))
+	assert.Nil(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, _atomicWriterDataDir)))
+	for name := range files {
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); os.IsNotExist(err) {
+			assert.Nil(t, os.Symlink(filepath.Join(_atomicWriterDataDir, name), link))
+		}
+	}
+	if old != "" {
+		assert.Nil(t, os.RemoveAll(filepath.Join(dir, old)))
+	}
+}
+
+func TestFileProvisionerAtomicSymlinkSwap(t *testing.T) {
+	cluster, err := ioutil.ReadFile("testdata/cluster.json")
+	assert.Nil(t, err)
+	updatedCluster := strings.Replace(string(cluster), `"type": "EDS"`, `"type": "EDS", "lbPolicy": "LEAST_REQUEST"`, 1)
+
+	for _, watchFile := range []bool{false, true} {
+		dir, err := ioutil.TempDir("", "xds-configmap")
+		assert.Nil(t, err)
+		defer os.RemoveAll(dir)
+		writeAtomically(t, dir, "1", map[string]string{"cluster.json": string(cluster)})
+
+		watched := dir
+		if watchFile {
+			watched = filepath.Join(dir, "cluster.json")
+		}
+		cfg := &config.Config{
+			LogLevel:      "debug",
+			LogOutput:     "stderr",
+			XDSWatchFiles: []string{watched},
+		}
+		pr, err := NewXDSProvisioner(cfg)
+		assert.Nil(t, err)
+		p := pr.(*xdsFileProvisioner)
+
+		stopCh := make(chan struct{})
+		go func() {
+			assert.Nil(t, p.Run(stopCh))
+		}()
+
+		var events []types.Event
+		select {
+		case events = <-p.Channel():
+		case <-time.After(2 * time.Second):
+			t.Fatal("no event arrived in time")
+		}
+		// Files in the timestamped directory are not read again.
+		assert.Len(t, events, 1)
+		assert.Equal(t, events[0].Type, types.EventAdd)
+		assert.Equal(t, events[0].Object.(*apisix.Upstream).Type, "roundrobin")
+		// Wait for the watches to be added.
+		time.Sleep(100 * time.Millisecond)
+
+		writeAtomically(t, dir, "2", map[string]string{"cluster.json": updatedCluster})
+		select {
+		case events = <-p.Channel():
+		case <-time.After(2 * time.Second):
+			t.Fatal("no event arrived in time")
+		}
+		assert.Len(t, events, 1)
+		assert.Equal(t, events[0].Type, types.EventUpdate)
+		assert.Equal(t, events[0].Object.(*apisix.Upstream).Type, "least_conn")
+
+		// Nothing is deleted.
+		select {
+		case events = <-p.Channel():
+			t.Fatalf("unexpected events: %v", events)
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		close(stopCh)
+		_, ok := <-p.Channel()
+		assert.Equal(t, ok, false)
+	}
+}
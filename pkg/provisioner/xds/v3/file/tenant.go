@@ -0,0 +1,83 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	xdsv3 "github.com/api7/apisix-mesh-agent/pkg/adaptor/xds/v3"
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/set"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+// tenantOf finds the tenant of the file, which is decided by the
+// longest watched path that contains it. An empty string is returned
+// if the file doesn't belong to any tenant.
+func (p *xdsFileProvisioner) tenantOf(filename string) string {
+	var (
+		tenant  string
+		longest int
+	)
+	filename = filepath.Clean(filename)
+	for path, t := range p.tenants {
+		if filename != path && !strings.HasPrefix(filename, path+string(os.PathSeparator)) {
+			continue
+		}
+		if len(path) > longest {
+			longest = len(path)
+			tenant = t
+		}
+	}
+	return tenant
+}
+
+func (p *xdsFileProvisioner) addTenantCluster(tenant, cluster string) {
+	if len(p.tenants) == 0 {
+		return
+	}
+	clusters, ok := p.tenantClusters[tenant]
+	if !ok {
+		clusters = set.StringSet{}
+		p.tenantClusters[tenant] = clusters
+	}
+	clusters.Add(cluster)
+}
+
+// checkTenantReferences warns routes that refer to upstreams outside their
+// tenant, these routes cannot work since names of upstreams are scoped.
+// The problematic routes are returned.
+func (p *xdsFileProvisioner) checkTenantReferences(tenant string, routes []*apisix.Route) []*apisix.Route {
+	if len(p.tenants) == 0 || len(routes) == 0 {
+		return nil
+	}
+	own := p.tenantClusters[tenant]
+	// The upstream ids that routes in this tenant will refer to, if the
+	// clusters of other tenants are referenced.
+	foreign := make(map[string]string)
+	for t, clusters := range p.tenantClusters {
+		if t == tenant {
+			continue
+		}
+		for cluster := range clusters {
+			if _, ok := own[cluster]; ok {
+				continue
+			}
+			foreign[id.GenID(xdsv3.TenantScopedName(tenant, cluster))] = t
+		}
+	}
+	var bad []*apisix.Route
+	for _, r := range routes {
+		if t, ok := foreign[r.UpstreamId]; ok {
+			p.logger.Warnw("route refers to an upstream outside its tenant",
+				zap.String("route", r.Name),
+				zap.String("tenant", tenant),
+				zap.String("upstream_tenant", t),
+			)
+			bad = append(bad, r)
+		}
+	}
+	return bad
+}
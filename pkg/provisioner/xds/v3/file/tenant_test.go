@@ -0,0 +1,136 @@
+package file
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	xdsv3 "github.com/api7/apisix-mesh-agent/pkg/adaptor/xds/v3"
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner/util"
+	"github.com/api7/apisix-mesh-agent/pkg/set"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestTenantOf(t *testing.T) {
+	p := &xdsFileProvisioner{
+		tenants: map[string]string{
+			"/etc/xds":        "default",
+			"/etc/xds/team-a": "team-a",
+		},
+	}
+	assert.Equal(t, p.tenantOf("/etc/xds/route.json"), "default")
+	assert.Equal(t, p.tenantOf("/etc/xds/team-a/route.json"), "team-a")
+	assert.Equal(t, p.tenantOf("/etc/xds/team-a"), "team-a")
+	assert.Equal(t, p.tenantOf("/etc/xds/team-ab/route.json"), "default")
+	assert.Equal(t, p.tenantOf("/etc/other/route.json"), "")
+}
+
+func TestGenerateEventsWithTenants(t *testing.T) {
+	cfg := &config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}
+	adaptor, err := xdsv3.NewAdaptor(cfg)
+	assert.Nil(t, err)
+	p := &xdsFileProvisioner{
+		logger:                  log.DefaultLogger,
+		v3Adaptor:               adaptor,
+		state:                   make(map[string]*util.Manifest),
+		upstreamCache:           make(map[string]*apisix.Upstream),
+		uncheckedRoutes:         make(map[string][]*apisix.Route),
+		updatedUpstreamsFromEDS: make(map[string][]*apisix.Upstream),
+		filePolicies:            make(map[string]*clusterPolicies),
+		hashPolicies:            make(map[string]*xdsv3.HashPolicy),
+		edsServiceNames:         make(map[string]string),
+		tenants: map[string]string{
+			"/etc/xds/a": "tenant-a",
+			"/etc/xds/b": "tenant-b",
+		},
+		tenantClusters: make(map[string]set.StringSet),
+
+		listenerRouteNames:       make(map[string]set.StringSet),
+		staleRouteConfigurations: make(set.StringSet),
+		routeConfigurationFiles:  make(map[string]string),
+		rdsResponses:             make(map[string]*discoveryv3.DiscoveryResponse),
+	}
+
+	newRouteConfiguration := func(cluster string) *any.Any {
+		res, err := anypb.New(&routev3.RouteConfiguration{
+			Name: "rc1",
+			VirtualHosts: []*routev3.VirtualHost{
+				{
+					Name:    "vhost1",
+					Domains: []string{"*"},
+					Routes: []*routev3.Route{
+						{
+							Name: "route1",
+							Match: &routev3.RouteMatch{
+								PathSpecifier: &routev3.RouteMatch_Prefix{
+									Prefix: "/",
+								},
+							},
+							Action: &routev3.Route_Route{
+								Route: &routev3.RouteAction{
+									ClusterSpecifier: &routev3.RouteAction_Cluster{
+										Cluster: cluster,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+		assert.Nil(t, err)
+		return res
+	}
+	cluster, err := anypb.New(&clusterv3.Cluster{
+		Name: "httpbin",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_STATIC,
+		},
+	})
+	assert.Nil(t, err)
+
+	// The same resources in both tenants.
+	for _, filename := range []string{"/etc/xds/a/xds.json", "/etc/xds/b/xds.json"} {
+		evs := p.generateEventsFromDiscoveryResponseV3(filename, &discoveryv3.DiscoveryResponse{
+			Resources: []*any.Any{cluster, newRouteConfiguration("httpbin")},
+		})
+		assert.Len(t, evs, 2)
+	}
+	ma := p.state["/etc/xds/a/xds.json"]
+	mb := p.state["/etc/xds/b/xds.json"]
+	assert.Equal(t, ma.Upstreams[0].Name, "tenant-a/httpbin")
+	assert.Equal(t, ma.Upstreams[0].Id, id.GenID("tenant-a/httpbin"))
+	assert.Equal(t, ma.Routes[0].UpstreamId, ma.Upstreams[0].Id)
+	assert.Equal(t, mb.Upstreams[0].Name, "tenant-b/httpbin")
+	assert.Equal(t, mb.Routes[0].UpstreamId, mb.Upstreams[0].Id)
+	assert.NotEqual(t, ma.Routes[0].Id, mb.Routes[0].Id)
+	assert.NotEqual(t, ma.Upstreams[0].Id, mb.Upstreams[0].Id)
+
+	// Route in tenant-b refers to the cluster only in tenant-a.
+	onlyA, err := anypb.New(&clusterv3.Cluster{
+		Name: "only-a",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_STATIC,
+		},
+	})
+	assert.Nil(t, err)
+	p.generateEventsFromDiscoveryResponseV3("/etc/xds/a/only-a.json", &discoveryv3.DiscoveryResponse{
+		Resources: []*any.Any{onlyA},
+	})
+	routes := p.processRouteConfigurationV3(newRouteConfiguration("only-a"), "tenant-b", nil)
+	bad := p.checkTenantReferences("tenant-b", routes)
+	assert.Len(t, bad, 1)
+	assert.Equal(t, bad[0].Name, "tenant-b/route1#vhost1#rc1")
+	assert.Nil(t, p.checkTenantReferences("tenant-a", p.processRouteConfigurationV3(newRouteConfiguration("only-a"), "tenant-a", nil)))
+}
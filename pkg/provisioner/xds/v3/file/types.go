@@ -1,22 +1,29 @@
 package file
 
 import (
+	"bytes"
 	"errors"
-	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/fsnotify/fsnotify"
 	"go.uber.org/zap"
-	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 
 	xdsv3 "github.com/api7/apisix-mesh-agent/pkg/adaptor/xds/v3"
 	"github.com/api7/apisix-mesh-agent/pkg/config"
 	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/metrics"
 	"github.com/api7/apisix-mesh-agent/pkg/provisioner"
 	"github.com/api7/apisix-mesh-agent/pkg/provisioner/util"
+	"github.com/api7/apisix-mesh-agent/pkg/set"
 	"github.com/api7/apisix-mesh-agent/pkg/types"
 	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
 )
@@ -30,6 +37,101 @@ type xdsFileProvisioner struct {
 	state                   map[string]*util.Manifest
 	upstreamCache           map[string]*apisix.Upstream
 	updatedUpstreamsFromEDS map[string][]*apisix.Upstream
+	// the routes of each file before the checks on the upstreams they
+	// refer to, keyed by the filename, so that they can be checked again
+	// once the upstreams change, see recheckRoutes.
+	uncheckedRoutes map[string][]*apisix.Route
+	// pollInterval is the interval to poll the watched files,
+	// the watcher is used if it's 0.
+	pollInterval time.Duration
+	// whether to exit once the events of the initial files are
+	// delivered, instead of watching them.
+	oneshot bool
+	// the directory to persist the state, see saveState.
+	stateDir string
+	// the interval to emit the heartbeat events, no heartbeat is
+	// emitted if it's 0.
+	heartbeatInterval time.Duration
+	// the batches of events waiting to be sent, they're sent in order by
+	// a single goroutine, which runs while the queue is not empty, and
+	// sending tracks it, see sendEvents.
+	sendMu      sync.Mutex
+	sendQueue   [][]types.Event
+	sendRunning bool
+	sending     sync.WaitGroup
+	// whether the provisioner is ready (1) or not (0), it's accessed
+	// atomically, see Ready.
+	ready int32
+	// the last seen stats of files, keyed by the filename.
+	fileStats map[string]os.FileInfo
+	// hash and retry policies collected from the routes of each file,
+	// keyed by the filename, and the merged ones of all files, keyed by
+	// the cluster name, see updateClusterPolicies.
+	filePolicies  map[string]*clusterPolicies
+	hashPolicies  map[string]*xdsv3.HashPolicy
+	retryPolicies map[string]*xdsv3.RetryPolicy
+	// the cluster names keyed by the EDS service name.
+	edsServiceNames map[string]string
+	// the ids of upstreams whose clusters opt into the stats tracking.
+	statsTrackedUpstreams set.StringSet
+	// the connection limits translated from the circuit breakers of
+	// clusters, keyed by the upstream id.
+	connectionLimits map[string]*apisix.LimitConn
+	// whether to mark resources with provenance labels.
+	provenanceLabels bool
+	// fields that should be ignored when diffing.
+	diffIgnoredFields []string
+	// the order of events in a batch.
+	eventOrder string
+	// whether to extract shared plugins to plugin configs.
+	sharedPluginConfigs bool
+	// names or ids of resources which won't generate events.
+	protected set.StringSet
+	// the directories added to the watcher.
+	watchedDirs set.StringSet
+	// watchDebounce is the window to coalesce the watch events of the
+	// same file, the pending ones are keyed by the filename, and their
+	// names are sent to debounced once the window is over.
+	watchDebounce time.Duration
+	pendingEvents map[string]*pendingFileEvent
+	debounced     chan string
+	// the tenants keyed by the (cleaned) watched path.
+	tenants map[string]string
+	// the (unscoped) cluster names of each tenant.
+	tenantClusters map[string]set.StringSet
+	// the listener addresses and HTTP filters of the route configurations
+	// referred by listeners through RDS, keyed by the (tenant scoped)
+	// route configuration name.
+	routeOriginalDestination map[string]string
+	routeHTTPFilters         map[string][]*hcmv3.HttpFilter
+	// the (tenant scoped) names of the route configurations referred by
+	// the listeners of each file, and the ones whose listener address or
+	// HTTP filters are changed, see translateStaleRouteConfigurations.
+	listenerRouteNames       map[string]set.StringSet
+	staleRouteConfigurations set.StringSet
+	// the files of the route configurations keyed by the (tenant scoped)
+	// name, and the last responses of these files, so that they can be
+	// translated again once the listeners referring to them change.
+	routeConfigurationFiles map[string]string
+	rdsResponses            map[string]*discoveryv3.DiscoveryResponse
+	// the SDS secrets keyed by the (tenant scoped) name, so that the TLS
+	// contexts referring to them can be resolved.
+	secrets map[string]*tlsv3.Secret
+	// the (tenant scoped) names of the SDS secrets carrying the client
+	// certificates, keyed by the upstream name.
+	clusterSecrets map[string]string
+	// the (tenant scoped) names of the SDS secrets delivered by each file,
+	// so that they are forgotten once the file stops delivering them.
+	fileSecrets map[string]set.StringSet
+	// whether to merge the partial updates, the last (merged) responses
+	// are recorded by filename for them.
+	partialUpdates bool
+	responses      map[string]*discoveryv3.DiscoveryResponse
+	// the number of xDS resources of the tracked files, keyed by the
+	// filename, and the limits of them, see checkTrackingLimits.
+	trackedResources    map[string]int
+	maxTrackedFiles     int
+	maxTrackedResources int
 }
 
 // NewXDSProvisioner creates a files backed Provisioner, it watches
@@ -43,12 +145,48 @@ func NewXDSProvisioner(cfg *config.Config) (provisioner.Provisioner, error) {
 	if len(cfg.XDSWatchFiles) == 0 {
 		return nil, errors.New("xds-v3-file provisioner: no watch files")
 	}
-	watcher, err := fsnotify.NewWatcher()
+	p, err := newXDSFileProvisioner(cfg, "xds-file-provisioner")
 	if err != nil {
 		return nil, err
 	}
+	if cfg.XDSOneshot {
+		// Files are read only once, so neither the watcher nor the
+		// polling is needed.
+		p.oneshot = true
+	} else if cfg.XDSPollInterval > 0 {
+		// File system notifications are not used, so the watcher
+		// is not created in case it's unavailable.
+		p.pollInterval = time.Duration(cfg.XDSPollInterval) * time.Second
+	} else {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return nil, err
+		}
+		p.watcher = watcher
+		p.watchedDirs = set.StringSet{}
+		p.watchDebounce = cfg.XDSWatchDebounce
+		p.pendingEvents = make(map[string]*pendingFileEvent)
+		p.debounced = make(chan string)
+	}
+	p.files = cfg.XDSWatchFiles
+	for path, tenant := range cfg.XDSWatchFileTenants {
+		p.tenants[filepath.Clean(path)] = tenant
+	}
+	if cfg.XDSStateDir != "" {
+		p.stateDir = cfg.XDSStateDir
+		if err := p.loadState(); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// newXDSFileProvisioner creates the xdsFileProvisioner object without
+// the file watcher, so it can be reused by other sources whose contents
+// are also DiscoveryResponse.
+func newXDSFileProvisioner(cfg *config.Config, logContext string) (*xdsFileProvisioner, error) {
 	logger, err := log.NewLogger(
-		log.WithContext("xds-file-provisioner"),
+		log.WithContext(logContext),
 		log.WithLogLevel(cfg.LogLevel),
 		log.WithOutputFile(cfg.LogOutput),
 	)
@@ -60,14 +198,46 @@ func NewXDSProvisioner(cfg *config.Config) (provisioner.Provisioner, error) {
 		return nil, err
 	}
 	p := &xdsFileProvisioner{
-		watcher:                 watcher,
 		logger:                  logger,
 		v3Adaptor:               adaptor,
 		evChan:                  make(chan []types.Event),
-		files:                   cfg.XDSWatchFiles,
 		state:                   make(map[string]*util.Manifest),
+		fileStats:               make(map[string]os.FileInfo),
 		upstreamCache:           make(map[string]*apisix.Upstream),
 		updatedUpstreamsFromEDS: make(map[string][]*apisix.Upstream),
+		uncheckedRoutes:         make(map[string][]*apisix.Route),
+		filePolicies:            make(map[string]*clusterPolicies),
+		hashPolicies:            make(map[string]*xdsv3.HashPolicy),
+		retryPolicies:           make(map[string]*xdsv3.RetryPolicy),
+		edsServiceNames:         make(map[string]string),
+		statsTrackedUpstreams:   make(set.StringSet),
+		connectionLimits:        make(map[string]*apisix.LimitConn),
+		provenanceLabels:        cfg.ProvenanceLabels,
+		diffIgnoredFields:       cfg.DiffIgnoredFields,
+		eventOrder:              cfg.EventOrder,
+		sharedPluginConfigs:     cfg.SharedPluginConfigs,
+		protected:               util.NewProtectedSet(cfg.ProtectedResources),
+		tenants:                 make(map[string]string),
+		tenantClusters:          make(map[string]set.StringSet),
+
+		routeOriginalDestination: make(map[string]string),
+		routeHTTPFilters:         make(map[string][]*hcmv3.HttpFilter),
+		listenerRouteNames:       make(map[string]set.StringSet),
+		staleRouteConfigurations: make(set.StringSet),
+		routeConfigurationFiles:  make(map[string]string),
+		rdsResponses:             make(map[string]*discoveryv3.DiscoveryResponse),
+		secrets:                  make(map[string]*tlsv3.Secret),
+		clusterSecrets:           make(map[string]string),
+		fileSecrets:              make(map[string]set.StringSet),
+
+		partialUpdates: cfg.XDSPartialUpdates,
+		responses:      make(map[string]*discoveryv3.DiscoveryResponse),
+
+		trackedResources:    make(map[string]int),
+		maxTrackedFiles:     cfg.XDSMaxTrackedFiles,
+		maxTrackedResources: cfg.XDSMaxTrackedResources,
+
+		heartbeatInterval: cfg.HeartbeatInterval,
 	}
 	return p, nil
 }
@@ -80,26 +250,67 @@ func (p *xdsFileProvisioner) Run(stop chan struct{}) error {
 	if err := p.handleInitialFileEvents(); err != nil {
 		return err
 	}
+	if p.oneshot {
+		// Wait for all events to be delivered before closing the
+		// channel.
+		p.sending.Wait()
+		return nil
+	}
+	if p.pollInterval > 0 {
+		p.setReady(true)
+		return p.runPolling(stop)
+	}
 
-	for _, file := range p.files {
-		if err := p.watcher.Add(file); err != nil {
-			return err
-		}
+	if err := p.watchPaths(); err != nil {
+		return err
 	}
+	p.setReady(true)
 
+	heartbeat, stopHeartbeat := util.HeartbeatTicker(p.heartbeatInterval)
+	defer stopHeartbeat()
+	// resyncRetry fires when the failed resync should be retried, it's
+	// nil otherwise.
+	var resyncRetry <-chan time.Time
+	resync := func() {
+		resyncRetry = nil
+		if err := p.resyncFiles(); err != nil {
+			p.logger.Errorw("failed to resync watched files, will retry later",
+				zap.Error(err),
+				zap.Duration("retry_interval", _resyncRetryInterval),
+			)
+			resyncRetry = time.After(_resyncRetryInterval)
+			return
+		}
+		p.logger.Infow("resynced watched files after watch errors")
+		p.setReady(true)
+	}
 	for {
 		select {
+		case <-heartbeat:
+			p.sendEvents(util.HeartbeatEvents())
+		case <-resyncRetry:
+			resync()
 		case <-stop:
 			if err := p.watcher.Close(); err != nil {
 				p.logger.Errorw("failed to close watcher",
 					zap.Error(err),
 				)
 			}
+			p.flushFileEvents()
+			// Wait for all events to be delivered before closing the
+			// channel.
+			p.sending.Wait()
 			return nil
+		case name := <-p.debounced:
+			p.flushFileEvent(name)
 		case err := <-p.watcher.Errors:
 			p.logger.Errorw("detected watch errors",
 				zap.Error(err),
 			)
+			// Changes might be missed, the provisioner isn't ready until
+			// the files are resynced.
+			p.setReady(false)
+			resync()
 		case ev := <-p.watcher.Events:
 			switch ev.Op {
 			case fsnotify.Create, fsnotify.Write, fsnotify.Remove:
@@ -114,99 +325,277 @@ func (p *xdsFileProvisioner) Run(stop chan struct{}) error {
 				)
 				continue
 			}
-			p.handleFileEvent(ev)
+			if p.watchDebounce > 0 {
+				p.debounceFileEvent(ev, stop)
+				continue
+			}
+			p.dispatchFileEvent(ev)
 		}
 	}
 }
 
+// dispatchFileEvent handles the watch event according to the kind of
+// the path.
+func (p *xdsFileProvisioner) dispatchFileEvent(ev fsnotify.Event) {
+	if isAtomicWriterPath(ev.Name) {
+		p.handleAtomicWriterEvent(ev)
+		return
+	}
+	if ev.Op == fsnotify.Create {
+		if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+			p.handleDirCreation(ev.Name)
+			return
+		}
+	}
+	if ev.Op == fsnotify.Remove && (p.handleDirRemoval(ev.Name) || p.handleSymlinkSwap(ev.Name)) {
+		return
+	}
+	p.handleFileEvent(ev)
+}
+
 func (p *xdsFileProvisioner) handleInitialFileEvents() error {
-	var files []string
+	files, err := p.walkFiles(false)
+	if err != nil {
+		return err
+	}
+	for _, file := range sortedFilenames(files) {
+		p.fileStats[file] = files[file]
+		p.handleFileEvent(fsnotify.Event{
+			Name: file,
+			Op:   fsnotify.Write,
+		})
+	}
+	if p.stateDir != "" {
+		p.removeMissingFiles(files)
+	}
+	return nil
+}
 
+// walkFiles returns the stats of files in the watched paths (directories
+// are walked recursively), keyed by the filename. Non-existent paths will
+// be skipped if ignoreMissing is true.
+func (p *xdsFileProvisioner) walkFiles(ignoreMissing bool) (map[string]os.FileInfo, error) {
+	files := make(map[string]os.FileInfo)
 	for _, file := range p.files {
 		info, err := os.Stat(file)
 		if err != nil {
-			return err
+			if ignoreMissing && os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
 		}
 		if !info.IsDir() {
-			files = append(files, file)
-		} else {
-			err = filepath.Walk(file, func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					return err
-				}
+			files[file] = info
+			continue
+		}
+		err = filepath.Walk(file, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if path != file && isAtomicWriterPath(path) {
+				// Files of the ConfigMap volumes are reached by the
+				// symlinks outside.
 				if info.IsDir() {
-					return nil
+					return filepath.SkipDir
 				}
-				files = append(files, path)
 				return nil
-			})
-			if err != nil {
-				return err
 			}
+			if info.Mode()&os.ModeSymlink != 0 {
+				// Use the stat of the target, so the changes are
+				// detected by polling.
+				if info, err = os.Stat(path); err != nil {
+					return err
+				}
+			}
+			if info.IsDir() {
+				return nil
+			}
+			files[path] = info
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
-	for _, file := range files {
-		p.handleFileEvent(fsnotify.Event{
-			Name: file,
-			Op:   fsnotify.Write,
-		})
+	return files, nil
+}
+
+func sortedFilenames(files map[string]os.FileInfo) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
 	}
-	return nil
+	sort.Strings(names)
+	return names
 }
 
 func (p *xdsFileProvisioner) Channel() <-chan []types.Event {
 	return p.evChan
 }
 
+// Ready returns whether the initial files are handled and the watching
+// works, it turns to false once the watcher fails, since the changes might
+// be missed, and back to true once the files are resynced.
+func (p *xdsFileProvisioner) Ready() bool {
+	return atomic.LoadInt32(&p.ready) == 1
+}
+
+func (p *xdsFileProvisioner) setReady(ready bool) {
+	var v int32
+	if ready {
+		v = 1
+	}
+	atomic.StoreInt32(&p.ready, v)
+}
+
 func (p *xdsFileProvisioner) handleFileEvent(ev fsnotify.Event) {
-	var (
-		events []types.Event
-	)
-	if ev.Op != fsnotify.Remove {
-		data, err := ioutil.ReadFile(ev.Name)
-		if err != nil {
-			p.logger.Errorw("failed to read file",
-				zap.Error(err),
-				zap.String("filename", ev.Name),
-				zap.String("type", ev.Op.String()),
-			)
-			return
-		}
+	if ev.Op == fsnotify.Remove {
+		p.handleContentRemoval(ev.Name)
+		return
+	}
+	f, err := os.Open(ev.Name)
+	if err != nil {
+		p.logger.Errorw("failed to open file",
+			zap.Error(err),
+			zap.String("filename", ev.Name),
+			zap.String("type", ev.Op.String()),
+		)
+		return
+	}
+	defer f.Close()
 
-		var dr discoveryv3.DiscoveryResponse
-		if err := protojson.Unmarshal(data, &dr); err != nil {
-			p.logger.Errorw("failed to unmarshal file",
-				zap.Error(err),
-				zap.String("filename", ev.Name),
-				zap.String("type", ev.Op.String()),
-			)
-			return
-		}
-		events = p.generateEventsFromDiscoveryResponseV3(ev.Name, &dr)
-	} else {
-		rmo, ok := p.state[ev.Name]
-		if ok {
-			events = p.generateEvents(ev.Name, rmo, nil)
-			// Upstreams which nodes are supported by EDS should reset
-			// its nodes to nil, the event should be update, not delete.
-			for _, ups := range p.updatedUpstreamsFromEDS[ev.Name] {
-				// Do not modify the original ups to avoid race conditions.
-				newUps := proto.Clone(ups).(*apisix.Upstream)
-				newUps.Nodes = nil
-				events = append(events, types.Event{
-					Type:   types.EventUpdate,
-					Object: newUps,
-				})
-			}
-			delete(p.updatedUpstreamsFromEDS, ev.Name)
+	// Decode the file incrementally instead of reading it fully into
+	// memory, snapshots might be very large in big meshes.
+	var dr discoveryv3.DiscoveryResponse
+	if err := decodeDiscoveryResponse(f, &dr); err != nil {
+		metrics.XDSParseErrors.Inc()
+		p.logger.Errorw("failed to unmarshal file",
+			zap.Error(err),
+			zap.String("filename", ev.Name),
+		)
+		return
+	}
+	p.sendChanges(ev.Name, p.generateEventsFromDiscoveryResponseV3(ev.Name, &dr))
+}
+
+// handleContent parses the data (in the format of DiscoveryResponse) from
+// the source (it's the filename for the file provisioner) and generates
+// events according to the last state of the source.
+func (p *xdsFileProvisioner) handleContent(source string, data []byte) {
+	var dr discoveryv3.DiscoveryResponse
+	if err := decodeDiscoveryResponse(bytes.NewReader(data), &dr); err != nil {
+		metrics.XDSParseErrors.Inc()
+		p.logger.Errorw("failed to unmarshal file",
+			zap.Error(err),
+			zap.String("filename", source),
+		)
+		return
+	}
+	p.sendChanges(source, p.generateEventsFromDiscoveryResponseV3(source, &dr))
+}
+
+// handleContentRemoval generates events since the source was removed.
+func (p *xdsFileProvisioner) handleContentRemoval(source string) {
+	var events []types.Event
+	upstreams := p.snapshotUpstreams()
+	rmo, ok := p.state[source]
+	if ok {
+		p.forgetRemovedUpstreams(source, nil)
+		events = p.generateEvents(source, rmo, nil)
+		// Upstreams which nodes are supported by EDS should reset
+		// its nodes to nil, the event should be update, not delete.
+		for _, ups := range p.updatedUpstreamsFromEDS[source] {
+			// Do not modify the original ups to avoid race conditions.
+			newUps := proto.Clone(ups).(*apisix.Upstream)
+			newUps.Nodes = nil
+			events = append(events, types.Event{
+				Type:   types.EventUpdate,
+				Object: newUps,
+			})
 		}
+		delete(p.updatedUpstreamsFromEDS, source)
 	}
+	// Upstreams in other files which use the secrets of the file are
+	// updated with the client certificates cleared.
+	for _, ups := range p.forgetRemovedSecrets(source, nil) {
+		events = append(events, types.Event{
+			Type:   types.EventUpdate,
+			Object: ups,
+		})
+	}
+	delete(p.uncheckedRoutes, source)
+	delete(p.responses, source)
+	delete(p.trackedResources, source)
+	events = append(events, p.repatchUpstreams(p.updateClusterPolicies(source, nil), nil, nil)...)
+	p.updateRouteConfigurationFiles(source, nil, nil)
+	p.updateListenerRouteNames(source, nil)
+	events = append(events, p.recheckRoutes(source, p.changedUpstreams(upstreams))...)
+	events = append(events, p.translateStaleRouteConfigurations()...)
+	p.sendChanges(source, util.DropProtectedEvents(events, p.protected))
+}
+
+// sendChanges logs the summary of the events caused by the change of the
+// source, and then sends them with the source.
+func (p *xdsFileProvisioner) sendChanges(source string, events []types.Event) {
+	p.logChanges(source, events)
+	p.sendEvents(withSource(source, events))
+}
 
-	// Send events in another goroutine to avoid blocking the watch.
-	if len(events) > 0 {
-		go func() {
-			p.evChan <- events
-		}()
+// withSource sets the source of the events, events caused by the change
+// (e.g. upstreams in other files patched again) are attributed to it too.
+func withSource(source string, events []types.Event) []types.Event {
+	for i := range events {
+		events[i].Source = source
+	}
+	return events
+}
+
+// logChanges logs the summary of the events caused by the change of the
+// source. The summary is made from the events, since not all of them come
+// from the diff of the source (e.g. upstreams updated by EDS or patched
+// again).
+func (p *xdsFileProvisioner) logChanges(source string, events []types.Event) {
+	if len(events) == 0 {
+		return
+	}
+	p.logger.Infow("applied changes from xds file",
+		zap.String("filename", source),
+		zap.String("summary", util.SummarizeEvents(events)),
+	)
+}
+
+// sendEvents queues the events to be sent by another goroutine, so the watch
+// is not blocked, batches are sent in the order they are queued.
+func (p *xdsFileProvisioner) sendEvents(events []types.Event) {
+	if len(events) == 0 {
+		return
+	}
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	p.sendQueue = append(p.sendQueue, events)
+	if !p.sendRunning {
+		p.sendRunning = true
+		p.sending.Add(1)
+		go p.sendQueuedEvents()
+	}
+}
+
+// sendQueuedEvents sends the queued batches one by one until the queue is
+// empty.
+func (p *xdsFileProvisioner) sendQueuedEvents() {
+	defer p.sending.Done()
+	for {
+		p.sendMu.Lock()
+		if len(p.sendQueue) == 0 {
+			p.sendRunning = false
+			p.sendMu.Unlock()
+			return
+		}
+		events := p.sendQueue[0]
+		p.sendQueue[0] = nil
+		p.sendQueue = p.sendQueue[1:]
+		p.sendMu.Unlock()
+		p.evChan <- events
 	}
 }
 
@@ -214,41 +603,119 @@ func (p *xdsFileProvisioner) generateEventsFromDiscoveryResponseV3(filename stri
 	p.logger.Debugw("parsing discovery response v3",
 		zap.Any("content", dr),
 	)
+	lastResponse, responded := p.responses[filename]
+	if p.partialUpdates {
+		merged, err := p.mergeDiscoveryResponse(filename, dr)
+		if err != nil {
+			p.logger.Errorw("failed to merge partial update",
+				zap.Error(err),
+				zap.String("filename", filename),
+			)
+			return nil
+		}
+		dr = merged
+	} else if isPartialUpdate(dr) {
+		p.logger.Warnw("partial updates are not enabled, the response replaces all resources of the file",
+			zap.String("filename", filename),
+		)
+	}
+	resources, dups := xdsv3.DedupResources(dr.GetResources())
+	for typeUrl, names := range dups {
+		p.logger.Warnw("found resources with duplicated names in the discovery response, only the last ones are kept",
+			zap.String("filename", filename),
+			zap.String("type", typeUrl),
+			zap.Strings("names", names),
+		)
+	}
+	if err := p.checkTrackingLimits(filename, len(resources)); err != nil {
+		p.logger.Errorw("reject the content of file since the tracking limit is reached, the last one is kept",
+			zap.Error(err),
+			zap.String("filename", filename),
+			zap.Int("resources", len(resources)),
+			zap.Int("max_tracked_files", p.maxTrackedFiles),
+			zap.Int("max_tracked_resources", p.maxTrackedResources),
+		)
+		metrics.XDSRejectedContents.Inc()
+		// The merged partial update is discarded too.
+		if p.partialUpdates {
+			if responded {
+				p.responses[filename] = lastResponse
+			} else {
+				delete(p.responses, filename)
+			}
+		}
+		return nil
+	}
+	if p.maxTrackedFiles > 0 || p.maxTrackedResources > 0 {
+		p.trackedResources[filename] = len(resources)
+	}
 	var (
-		rm               util.Manifest
-		updatedUpstreams []*apisix.Upstream
+		rm                  util.Manifest
+		updatedUpstreams    []*apisix.Upstream
+		tenant              = p.tenantOf(filename)
+		routeConfigurations = make(set.StringSet)
+		boundRouteNames     = make(set.StringSet)
+		secretNames         = make(set.StringSet)
+		policies            = newClusterPolicies()
+		upstreams           = p.snapshotUpstreams()
 	)
-	for _, res := range dr.GetResources() {
+	for _, res := range resources {
 		switch res.GetTypeUrl() {
 		case types.RouteConfigurationUrl:
-			rm.Routes = append(rm.Routes, p.processRouteConfigurationV3(res)...)
-		case types.ClusterUrl:
-			rm.Upstreams = append(rm.Upstreams, p.processClusterV3(res)...)
-		case types.ClusterLoadAssignmentUrl:
-			var slot int
-			ups := p.processClusterLoadAssignmentV3(res)
-			for i := 0; i < len(ups); i++ {
-				var found bool
-				for j := 0; j < len(rm.Upstreams); j++ {
-					// EDS should be merged to the CDS if the CDS are in the
-					// same DiscoveryResponse.
-					if rm.Upstreams[i].Name == ups[i].Name {
-						found = true
-						rm.Upstreams[i] = ups[i]
-						break
-					}
-					// else the upstreams generated by EDS should be appended.
+			if name, err := xdsv3.GetResourceName(res); err == nil {
+				routeConfigurations.Add(xdsv3.TenantScopedName(tenant, name))
+			}
+			routes := p.processRouteConfigurationV3(res, tenant, policies)
+			if p.provenanceLabels {
+				for _, r := range routes {
+					r.Labels = util.MergeLabels(r.Labels, util.ProvenanceLabels(filename, "RouteConfiguration"))
+				}
+			}
+			rm.Routes = append(rm.Routes, routes...)
+		case types.ListenerUrl:
+			// Routes inline in listeners are treated like the ones
+			// in RouteConfiguration.
+			routes, consumers, names := p.processListenerV3(res, tenant, policies)
+			for _, name := range names {
+				boundRouteNames.Add(name)
+			}
+			if p.provenanceLabels {
+				for _, r := range routes {
+					r.Labels = util.MergeLabels(r.Labels, util.ProvenanceLabels(filename, "Listener"))
 				}
-				if !found {
-					ups[slot] = ups[i]
-					slot++
+				for _, c := range consumers {
+					c.Labels = util.MergeLabels(c.Labels, util.ProvenanceLabels(filename, "Listener"))
 				}
 			}
-			for i := slot; i < len(ups); i++ {
-				ups[i] = nil
+			rm.Routes = append(rm.Routes, routes...)
+			rm.Consumers = util.MergeConsumers(rm.Consumers, consumers)
+		case types.ClusterUrl:
+			// Labels are patched before the EDS merging, so upstreams
+			// generated by EDS can inherit them.
+			ups := p.processClusterV3(res, tenant)
+			if p.provenanceLabels {
+				for _, u := range ups {
+					u.Labels = util.MergeLabels(u.Labels, util.ProvenanceLabels(filename, "Cluster"))
+				}
+			}
+			rm.Upstreams = append(rm.Upstreams, ups...)
+		case types.SecretUrl:
+			if name, err := xdsv3.GetResourceName(res); err == nil {
+				secretNames.Add(xdsv3.TenantScopedName(tenant, name))
+			}
+			ssls, ups := p.processSecretV3(res, tenant)
+			if p.provenanceLabels {
+				for _, s := range ssls {
+					s.Labels = util.MergeLabels(s.Labels, util.ProvenanceLabels(filename, "Secret"))
+				}
 			}
-			ups = ups[:slot]
-			updatedUpstreams = append(updatedUpstreams, ups...)
+			rm.Ssls = append(rm.Ssls, ssls...)
+			// Upstreams using the client certificate are updated like
+			// the ones by EDS.
+			updatedUpstreams = append(updatedUpstreams, mergeUpdatedUpstreams(&rm, ups)...)
+		case types.ClusterLoadAssignmentUrl:
+			ups := p.processClusterLoadAssignmentV3(res, tenant)
+			updatedUpstreams = append(updatedUpstreams, mergeUpdatedUpstreams(&rm, ups)...)
 		default:
 			p.logger.Warnw("ignore unnecessary resource",
 				zap.String("type", res.GetTypeUrl()),
@@ -256,6 +723,35 @@ func (p *xdsFileProvisioner) generateEventsFromDiscoveryResponseV3(filename stri
 			)
 		}
 	}
+	// Upstreams are patched after all resources are processed since
+	// the RouteConfiguration might be behind the Cluster.
+	changedPolicies := p.updateClusterPolicies(filename, policies)
+	p.patchUpstreamsWithHashPolicies(rm.Upstreams)
+	p.patchUpstreamsWithHashPolicies(updatedUpstreams)
+	p.patchUpstreamsWithRetryPolicies(rm.Upstreams)
+	p.patchUpstreamsWithRetryPolicies(updatedUpstreams)
+	p.checkTenantReferences(tenant, rm.Routes)
+	if len(rm.Routes) > 0 {
+		p.uncheckedRoutes[filename] = cloneRoutes(rm.Routes)
+	} else {
+		delete(p.uncheckedRoutes, filename)
+	}
+	p.checkRoutes(rm.Routes)
+	if len(rm.Upstreams) > 0 {
+		p.checkUpstreamIDCollisions()
+	}
+	if p.sharedPluginConfigs {
+		rm.PluginConfigs = util.ExtractPluginConfigs(filename, rm.Routes)
+	}
+
+	p.updateRouteConfigurationFiles(filename, dr, routeConfigurations)
+	p.updateListenerRouteNames(filename, boundRouteNames)
+	p.forgetRemovedUpstreams(filename, &rm)
+	// Upstreams using the client certificates which are no longer
+	// delivered are updated like the ones by EDS.
+	cleared := p.forgetRemovedSecrets(filename, secretNames)
+	updatedUpstreams = append(updatedUpstreams, mergeUpdatedUpstreams(&rm, cleared)...)
+
 	evs := p.generateEvents(filename, p.state[filename], &rm)
 
 	if len(updatedUpstreams) > 0 {
@@ -278,10 +774,170 @@ func (p *xdsFileProvisioner) generateEventsFromDiscoveryResponseV3(filename stri
 			zap.Any("upstreams", updatedUpstreams),
 		)
 	}
+	// Upstreams in other files are patched again if the policies of
+	// them are changed by the routes of this file.
+	evs = append(evs, p.repatchUpstreams(changedPolicies, &rm, updatedUpstreams)...)
+	// Routes in other files are checked again if the upstreams referred
+	// by them are changed by this file.
+	evs = append(evs, p.recheckRoutes(filename, p.changedUpstreams(upstreams))...)
 
+	// Route configurations in other files (or this one) are translated
+	// after the state of this file is settled.
+	evs = append(evs, p.translateStaleRouteConfigurations()...)
+
+	return util.DropProtectedEvents(evs, p.protected)
+}
+
+// updateRouteConfigurationFiles records the route configurations in the
+// file, and the response if there are any.
+func (p *xdsFileProvisioner) updateRouteConfigurationFiles(filename string, dr *discoveryv3.DiscoveryResponse, names set.StringSet) {
+	for name, file := range p.routeConfigurationFiles {
+		if _, ok := names[name]; file == filename && !ok {
+			delete(p.routeConfigurationFiles, name)
+		}
+	}
+	for name := range names {
+		p.routeConfigurationFiles[name] = filename
+	}
+	if len(names) > 0 {
+		p.rdsResponses[filename] = dr
+	} else {
+		delete(p.rdsResponses, filename)
+	}
+}
+
+// updateListenerRouteNames records the route configurations referred by the
+// listeners in the file, the ones no longer referred are unbound.
+func (p *xdsFileProvisioner) updateListenerRouteNames(filename string, names set.StringSet) {
+	for name := range p.listenerRouteNames[filename] {
+		if _, ok := names[name]; !ok {
+			p.unbindRouteConfiguration(name)
+		}
+	}
+	if len(names) > 0 {
+		p.listenerRouteNames[filename] = names
+	} else {
+		delete(p.listenerRouteNames, filename)
+	}
+}
+
+// translateStaleRouteConfigurations translates the files of the route
+// configurations whose listener address or HTTP filters are changed again,
+// so that the listener might be seen after the route configurations. The
+// events of these files are returned.
+func (p *xdsFileProvisioner) translateStaleRouteConfigurations() []types.Event {
+	files := make(set.StringSet)
+	for name := range p.staleRouteConfigurations {
+		if file, ok := p.routeConfigurationFiles[name]; ok {
+			files.Add(file)
+		}
+	}
+	p.staleRouteConfigurations = make(set.StringSet)
+
+	filenames := files.Strings()
+	sort.Strings(filenames)
+	var evs []types.Event
+	for _, filename := range filenames {
+		p.logger.Infow("translate route configurations again since the listeners referring to them changed",
+			zap.String("filename", filename),
+		)
+		evs = append(evs, p.generateEventsFromDiscoveryResponseV3(filename, p.rdsResponses[filename])...)
+	}
 	return evs
 }
 
+// mergeUpdatedUpstreams merges the upstreams updated by other resources
+// (like EDS) to the CDS ones if they're in the same DiscoveryResponse, the
+// rest of them are returned.
+func mergeUpdatedUpstreams(rm *util.Manifest, ups []*apisix.Upstream) []*apisix.Upstream {
+	var slot int
+	for i := 0; i < len(ups); i++ {
+		var found bool
+		for j := 0; j < len(rm.Upstreams); j++ {
+			if rm.Upstreams[j].Name == ups[i].Name {
+				found = true
+				rm.Upstreams[j] = ups[i]
+				break
+			}
+			// else the updated upstreams should be appended.
+		}
+		if !found {
+			ups[slot] = ups[i]
+			slot++
+		}
+	}
+	for i := slot; i < len(ups); i++ {
+		ups[i] = nil
+	}
+	return ups[:slot]
+}
+
+// checkTrafficSplitReferences drops the traffic-split legs which refer to
+// unknown upstreams, the rest of the split keeps working.
+func (p *xdsFileProvisioner) checkTrafficSplitReferences(routes []*apisix.Route) {
+	known := p.knownUpstreams()
+	missing := util.DropMissingTrafficSplitUpstreams(routes, func(id string) bool {
+		_, ok := known[id]
+		return ok
+	})
+	for route, ids := range missing {
+		p.logger.Warnw("drop traffic-split legs referring to unknown upstreams",
+			zap.String("route", route),
+			zap.Strings("upstream_ids", ids),
+		)
+	}
+}
+
+// enablePrometheusForTrackedUpstreams enables the prometheus plugin on
+// routes to the stats tracked upstreams, routes seen before the clusters
+// are checked again by recheckRoutes.
+func (p *xdsFileProvisioner) enablePrometheusForTrackedUpstreams(routes []*apisix.Route) {
+	util.EnablePrometheusForTrackedUpstreams(routes, func(id string) bool {
+		_, ok := p.statsTrackedUpstreams[id]
+		return ok
+	})
+}
+
+// limitConnectionsForUpstreams enables the limit-conn plugin on routes to
+// the upstreams whose clusters have circuit breakers, routes seen before
+// the clusters are checked again by recheckRoutes.
+func (p *xdsFileProvisioner) limitConnectionsForUpstreams(routes []*apisix.Route) {
+	util.LimitConnectionsForUpstreams(routes, func(id string) *apisix.LimitConn {
+		return p.connectionLimits[id]
+	})
+}
+
+// checkUpstreamIDCollisions reports the clusters (across all files) which
+// are translated to upstreams with the same id.
+func (p *xdsFileProvisioner) checkUpstreamIDCollisions() {
+	upstreams := make([]*apisix.Upstream, 0, len(p.upstreamCache))
+	for _, ups := range p.upstreamCache {
+		upstreams = append(upstreams, ups)
+	}
+	for id, clusters := range util.UpstreamIDCollisions(upstreams) {
+		p.logger.Errorw("clusters are translated to the same upstream id, they overwrite each other",
+			zap.String("upstream_id", id),
+			zap.Strings("clusters", clusters),
+		)
+	}
+}
+
+func (p *xdsFileProvisioner) patchUpstreamsWithHashPolicies(upstreams []*apisix.Upstream) {
+	for _, ups := range upstreams {
+		if hp, ok := p.hashPolicies[ups.Name]; ok {
+			hp.PatchUpstream(ups)
+		}
+	}
+}
+
+func (p *xdsFileProvisioner) patchUpstreamsWithRetryPolicies(upstreams []*apisix.Upstream) {
+	for _, ups := range upstreams {
+		if rp, ok := p.retryPolicies[ups.Name]; ok {
+			rp.PatchUpstream(ups)
+		}
+	}
+}
+
 func (p *xdsFileProvisioner) generateEvents(filename string, rmo, rm *util.Manifest) []types.Event {
 	var (
 		added   *util.Manifest
@@ -293,7 +949,7 @@ func (p *xdsFileProvisioner) generateEvents(filename string, rmo, rm *util.Manif
 	} else if rm == nil {
 		deleted = rmo
 	} else {
-		added, deleted, updated = rmo.DiffFrom(rm)
+		added, deleted, updated = rmo.DiffFrom(rm, p.diffIgnoredFields...)
 	}
 	p.logger.Debugw("found changes (after converting to APISIX resources) in xds file",
 		zap.String("filename", filename),
@@ -302,6 +958,7 @@ func (p *xdsFileProvisioner) generateEvents(filename string, rmo, rm *util.Manif
 		zap.Any("updated", updated),
 	)
 	p.state[filename] = rm
+	p.updateWatchedFiles()
 
 	var count int
 	if added != nil {
@@ -313,18 +970,25 @@ func (p *xdsFileProvisioner) generateEvents(filename string, rmo, rm *util.Manif
 	if updated != nil {
 		count += updated.Size()
 	}
+	// Differences only on the ignored fields are not persisted, unless
+	// the partial updates are enabled, since the base of them changes.
+	if count > 0 || rm == nil || p.partialUpdates {
+		p.saveState(filename, rm)
+	}
 	if count == 0 {
 		return nil
 	}
-	events := make([]types.Event, 0, count)
-	if added != nil {
-		events = append(events, added.Events(types.EventAdd)...)
-	}
-	if deleted != nil {
-		events = append(events, deleted.Events(types.EventDelete)...)
-	}
-	if updated != nil {
-		events = append(events, updated.Events(types.EventUpdate)...)
+	return util.OrderedEvents(added, deleted, updated, p.eventOrder)
+}
+
+// updateWatchedFiles updates the watched files gauge by the files which
+// resources are translated.
+func (p *xdsFileProvisioner) updateWatchedFiles() {
+	var n float64
+	for _, rm := range p.state {
+		if rm != nil {
+			n++
+		}
 	}
-	return events
+	metrics.WatchedFiles.Set(n)
 }
@@ -1,6 +1,7 @@
 package file
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -10,11 +11,15 @@ import (
 	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/golang/protobuf/ptypes/any"
 	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
 	"google.golang.org/protobuf/encoding/protojson"
 	proto2 "google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
@@ -23,7 +28,9 @@ import (
 	"github.com/api7/apisix-mesh-agent/pkg/config"
 	"github.com/api7/apisix-mesh-agent/pkg/id"
 	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/metrics"
 	"github.com/api7/apisix-mesh-agent/pkg/provisioner/util"
+	"github.com/api7/apisix-mesh-agent/pkg/set"
 	"github.com/api7/apisix-mesh-agent/pkg/types"
 	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
 )
@@ -182,10 +189,16 @@ func TestFileProvisionerGenerateEventsFromDiscoveryResponse(t *testing.T) {
 	adaptor, err := xdsv3.NewAdaptor(cfg)
 	assert.Nil(t, err)
 	p := &xdsFileProvisioner{
-		logger:        log.DefaultLogger,
-		v3Adaptor:     adaptor,
-		state:         make(map[string]*util.Manifest),
-		upstreamCache: make(map[string]*apisix.Upstream),
+		logger:          log.DefaultLogger,
+		v3Adaptor:       adaptor,
+		state:           make(map[string]*util.Manifest),
+		upstreamCache:   make(map[string]*apisix.Upstream),
+		uncheckedRoutes: make(map[string][]*apisix.Route),
+
+		listenerRouteNames:       make(map[string]set.StringSet),
+		staleRouteConfigurations: make(set.StringSet),
+		routeConfigurationFiles:  make(map[string]string),
+		rdsResponses:             make(map[string]*discoveryv3.DiscoveryResponse),
 	}
 	events := p.generateEventsFromDiscoveryResponseV3("null", dr)
 	assert.Len(t, events, 2)
@@ -321,3 +334,642 @@ func TestFileProvisionerHandleFileEvent(t *testing.T) {
 	_, ok := <-evCh
 	assert.Equal(t, ok, false)
 }
+
+func TestFileProvisionerGenerateEventsWithHashPolicy(t *testing.T) {
+	c := &clusterv3.Cluster{
+		Name: "httpbin.default.svc.cluster.local",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_STATIC,
+		},
+		LbPolicy: clusterv3.Cluster_RING_HASH,
+	}
+	rc := &routev3.RouteConfiguration{
+		Name: "rc1",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name:    "vhost1",
+				Domains: []string{"*"},
+				Routes: []*routev3.Route{
+					{
+						Name: "route1",
+						Match: &routev3.RouteMatch{
+							PathSpecifier: &routev3.RouteMatch_Prefix{
+								Prefix: "/",
+							},
+						},
+						Action: &routev3.Route_Route{
+							Route: &routev3.RouteAction{
+								ClusterSpecifier: &routev3.RouteAction_Cluster{
+									Cluster: "httpbin.default.svc.cluster.local",
+								},
+								HashPolicy: []*routev3.RouteAction_HashPolicy{
+									{
+										PolicySpecifier: &routev3.RouteAction_HashPolicy_Header_{
+											Header: &routev3.RouteAction_HashPolicy_Header{
+												HeaderName: "x-user",
+											},
+										},
+										Terminal: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	var (
+		opaque  any.Any
+		opaque2 any.Any
+	)
+	assert.Nil(t, anypb.MarshalFrom(&opaque, c, proto2.MarshalOptions{}))
+	assert.Nil(t, anypb.MarshalFrom(&opaque2, rc, proto2.MarshalOptions{}))
+	dr := &discoveryv3.DiscoveryResponse{
+		VersionInfo: "0",
+		// Cluster is ahead of the RouteConfiguration.
+		Resources: []*any.Any{&opaque, &opaque2},
+	}
+
+	cfg := &config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}
+	adaptor, err := xdsv3.NewAdaptor(cfg)
+	assert.Nil(t, err)
+	p := &xdsFileProvisioner{
+		logger:          log.DefaultLogger,
+		v3Adaptor:       adaptor,
+		state:           make(map[string]*util.Manifest),
+		upstreamCache:   make(map[string]*apisix.Upstream),
+		uncheckedRoutes: make(map[string][]*apisix.Route),
+		filePolicies:    make(map[string]*clusterPolicies),
+		hashPolicies:    make(map[string]*xdsv3.HashPolicy),
+
+		listenerRouteNames:       make(map[string]set.StringSet),
+		staleRouteConfigurations: make(set.StringSet),
+		routeConfigurationFiles:  make(map[string]string),
+		rdsResponses:             make(map[string]*discoveryv3.DiscoveryResponse),
+	}
+	events := p.generateEventsFromDiscoveryResponseV3("null", dr)
+	assert.Len(t, events, 2)
+	ups := events[1].Object.(*apisix.Upstream)
+	assert.Equal(t, ups.Type, "chash")
+	assert.Equal(t, ups.HashOn, "header")
+	assert.Equal(t, ups.Key, "x-user")
+}
+
+func TestFileProvisionerGenerateEventsWithEDSServiceName(t *testing.T) {
+	c := &clusterv3.Cluster{
+		Name: "outbound|80||httpbin.default.svc.cluster.local",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_EDS,
+		},
+		EdsClusterConfig: &clusterv3.Cluster_EdsClusterConfig{
+			ServiceName: "httpbin.default.svc.cluster.local",
+		},
+		LbPolicy: clusterv3.Cluster_ROUND_ROBIN,
+	}
+	ep := &endpointv3.ClusterLoadAssignment{
+		ClusterName: "httpbin.default.svc.cluster.local",
+		Endpoints: []*endpointv3.LocalityLbEndpoints{
+			{
+				LbEndpoints: []*endpointv3.LbEndpoint{
+					{
+						HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+							Endpoint: &endpointv3.Endpoint{
+								Address: &corev3.Address{
+									Address: &corev3.Address_SocketAddress{
+										SocketAddress: &corev3.SocketAddress{
+											Protocol: corev3.SocketAddress_TCP,
+											Address:  "10.0.3.11",
+											PortSpecifier: &corev3.SocketAddress_PortValue{
+												PortValue: 8000,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	var (
+		opaque  any.Any
+		opaque2 any.Any
+	)
+	assert.Nil(t, anypb.MarshalFrom(&opaque, c, proto2.MarshalOptions{}))
+	assert.Nil(t, anypb.MarshalFrom(&opaque2, ep, proto2.MarshalOptions{}))
+	dr := &discoveryv3.DiscoveryResponse{
+		VersionInfo: "0",
+		Resources:   []*any.Any{&opaque, &opaque2},
+	}
+
+	cfg := &config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}
+	adaptor, err := xdsv3.NewAdaptor(cfg)
+	assert.Nil(t, err)
+	p := &xdsFileProvisioner{
+		logger:          log.DefaultLogger,
+		v3Adaptor:       adaptor,
+		state:           make(map[string]*util.Manifest),
+		upstreamCache:   make(map[string]*apisix.Upstream),
+		uncheckedRoutes: make(map[string][]*apisix.Route),
+		edsServiceNames: make(map[string]string),
+
+		listenerRouteNames:       make(map[string]set.StringSet),
+		staleRouteConfigurations: make(set.StringSet),
+		routeConfigurationFiles:  make(map[string]string),
+		rdsResponses:             make(map[string]*discoveryv3.DiscoveryResponse),
+	}
+	events := p.generateEventsFromDiscoveryResponseV3("null", dr)
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventAdd)
+	ups := events[0].Object.(*apisix.Upstream)
+	assert.Equal(t, ups.Name, "outbound|80||httpbin.default.svc.cluster.local")
+	assert.Len(t, ups.Nodes, 1)
+	assert.Equal(t, ups.Nodes[0].Host, "10.0.3.11")
+}
+
+func TestFileProvisionerGenerateEventsWithProvenanceLabels(t *testing.T) {
+	rc := &routev3.RouteConfiguration{
+		Name: "rc1",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name:    "vhost1",
+				Domains: []string{"*"},
+				Routes: []*routev3.Route{
+					{
+						Name: "route1",
+						Match: &routev3.RouteMatch{
+							PathSpecifier: &routev3.RouteMatch_Path{
+								Path: "/foo",
+							},
+						},
+						Action: &routev3.Route_Route{
+							Route: &routev3.RouteAction{
+								ClusterSpecifier: &routev3.RouteAction_Cluster{
+									Cluster: "httpbin.default.svc.cluster.local",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	c := &clusterv3.Cluster{
+		Name: "httpbin.default.svc.cluster.local",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_EDS,
+		},
+		LbPolicy: clusterv3.Cluster_ROUND_ROBIN,
+	}
+	var (
+		opaque  any.Any
+		opaque2 any.Any
+	)
+	assert.Nil(t, anypb.MarshalFrom(&opaque, rc, proto2.MarshalOptions{}))
+	assert.Nil(t, anypb.MarshalFrom(&opaque2, c, proto2.MarshalOptions{}))
+	dr := &discoveryv3.DiscoveryResponse{
+		VersionInfo: "0",
+		Resources:   []*any.Any{&opaque, &opaque2},
+	}
+
+	cfg := &config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}
+	adaptor, err := xdsv3.NewAdaptor(cfg)
+	assert.Nil(t, err)
+	p := &xdsFileProvisioner{
+		logger:           log.DefaultLogger,
+		v3Adaptor:        adaptor,
+		state:            make(map[string]*util.Manifest),
+		upstreamCache:    make(map[string]*apisix.Upstream),
+		uncheckedRoutes:  make(map[string][]*apisix.Route),
+		edsServiceNames:  make(map[string]string),
+		provenanceLabels: true,
+
+		listenerRouteNames:       make(map[string]set.StringSet),
+		staleRouteConfigurations: make(set.StringSet),
+		routeConfigurationFiles:  make(map[string]string),
+		rdsResponses:             make(map[string]*discoveryv3.DiscoveryResponse),
+	}
+	events := p.generateEventsFromDiscoveryResponseV3("/etc/xds/assets.json", dr)
+	assert.Len(t, events, 2)
+	for _, ev := range events {
+		switch obj := ev.Object.(type) {
+		case *apisix.Route:
+			assert.Equal(t, obj.Id, id.GenID("route1#vhost1#rc1"))
+			assert.Equal(t, obj.Labels, map[string]string{
+				util.LabelManagedBy:  util.ManagedByMeshAgent,
+				util.LabelSourceFile: "/etc/xds/assets.json",
+				util.LabelXDSType:    "RouteConfiguration",
+			})
+		case *apisix.Upstream:
+			assert.Equal(t, obj.Id, id.GenID("httpbin.default.svc.cluster.local"))
+			assert.Equal(t, obj.Labels, map[string]string{
+				util.LabelManagedBy:  util.ManagedByMeshAgent,
+				util.LabelSourceFile: "/etc/xds/assets.json",
+				util.LabelXDSType:    "Cluster",
+			})
+		}
+	}
+
+	// Labels are stable, so nothing changed.
+	events = p.generateEventsFromDiscoveryResponseV3("/etc/xds/assets.json", dr)
+	assert.Len(t, events, 0)
+}
+
+func TestFileProvisionerGenerateEventsWithIgnoredFields(t *testing.T) {
+	p := &xdsFileProvisioner{
+		logger:            log.DefaultLogger,
+		state:             make(map[string]*util.Manifest),
+		diffIgnoredFields: []string{"route.desc"},
+	}
+	rmo := &util.Manifest{
+		Routes: []*apisix.Route{
+			{
+				Id:   "1",
+				Desc: "pushed at 1",
+			},
+		},
+	}
+	rm := &util.Manifest{
+		Routes: []*apisix.Route{
+			{
+				Id:   "1",
+				Desc: "pushed at 2",
+			},
+		},
+	}
+	events := p.generateEvents("null", rmo, rm)
+	assert.Len(t, events, 0)
+
+	rm.Routes[0].Name = "new name"
+	events = p.generateEvents("null", rmo, rm)
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventUpdate)
+}
+
+func TestFileProvisionerGenerateEventsWithUpstreamIDCollision(t *testing.T) {
+	// The CRC32 checksums of these two names are same.
+	names := []string{
+		"outbound|921||httpbin.default.svc.cluster.local",
+		"outbound|30552600||httpbin.default.svc.cluster.local",
+	}
+	var resources []*any.Any
+	for _, name := range names {
+		c := &clusterv3.Cluster{
+			Name: name,
+			ClusterDiscoveryType: &clusterv3.Cluster_Type{
+				Type: clusterv3.Cluster_STATIC,
+			},
+			LbPolicy: clusterv3.Cluster_ROUND_ROBIN,
+		}
+		var opaque any.Any
+		assert.Nil(t, anypb.MarshalFrom(&opaque, c, proto2.MarshalOptions{}))
+		resources = append(resources, &opaque)
+	}
+	dr := &discoveryv3.DiscoveryResponse{
+		VersionInfo: "0",
+		Resources:   resources,
+	}
+
+	var buf bytes.Buffer
+	logger, err := log.NewLogger(
+		log.WithLogLevel("error"),
+		log.WithWriteSyncer(zapcore.AddSync(&buf)),
+	)
+	assert.Nil(t, err)
+	adaptor, err := xdsv3.NewAdaptor(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	})
+	assert.Nil(t, err)
+	p := &xdsFileProvisioner{
+		logger:          logger,
+		v3Adaptor:       adaptor,
+		state:           make(map[string]*util.Manifest),
+		upstreamCache:   make(map[string]*apisix.Upstream),
+		uncheckedRoutes: make(map[string][]*apisix.Route),
+		edsServiceNames: make(map[string]string),
+
+		listenerRouteNames:       make(map[string]set.StringSet),
+		staleRouteConfigurations: make(set.StringSet),
+		routeConfigurationFiles:  make(map[string]string),
+		rdsResponses:             make(map[string]*discoveryv3.DiscoveryResponse),
+	}
+	p.generateEventsFromDiscoveryResponseV3("null", dr)
+	assert.Contains(t, buf.String(), "clusters are translated to the same upstream id")
+	assert.Contains(t, buf.String(), id.GenID(names[0]))
+	assert.Contains(t, buf.String(), names[0])
+	assert.Contains(t, buf.String(), names[1])
+}
+
+func TestFileProvisionerGenerateEventsFromListener(t *testing.T) {
+	newHCM := func(hcm *hcmv3.HttpConnectionManager) *listenerv3.Filter {
+		var opaque any.Any
+		assert.Nil(t, anypb.MarshalFrom(&opaque, hcm, proto2.MarshalOptions{}))
+		return &listenerv3.Filter{
+			Name: "envoy.filters.network.http_connection_manager",
+			ConfigType: &listenerv3.Filter_TypedConfig{
+				TypedConfig: &opaque,
+			},
+		}
+	}
+	listener := &listenerv3.Listener{
+		Name: "listener1",
+		Address: &corev3.Address{
+			Address: &corev3.Address_SocketAddress{
+				SocketAddress: &corev3.SocketAddress{
+					Address: "0.0.0.0",
+					PortSpecifier: &corev3.SocketAddress_PortValue{
+						PortValue: 9080,
+					},
+				},
+			},
+		},
+		FilterChains: []*listenerv3.FilterChain{
+			{
+				Filters: []*listenerv3.Filter{
+					newHCM(&hcmv3.HttpConnectionManager{
+						RouteSpecifier: &hcmv3.HttpConnectionManager_RouteConfig{
+							RouteConfig: &routev3.RouteConfiguration{
+								Name: "inline",
+								VirtualHosts: []*routev3.VirtualHost{
+									{
+										Name:    "vhost",
+										Domains: []string{"*"},
+										Routes: []*routev3.Route{
+											{
+												Name: "route1",
+												Match: &routev3.RouteMatch{
+													PathSpecifier: &routev3.RouteMatch_Prefix{
+														Prefix: "/foo",
+													},
+												},
+												Action: &routev3.Route_Route{
+													Route: &routev3.RouteAction{
+														ClusterSpecifier: &routev3.RouteAction_Cluster{
+															Cluster: "httpbin",
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					}),
+				},
+			},
+			{
+				Filters: []*listenerv3.Filter{
+					newHCM(&hcmv3.HttpConnectionManager{
+						RouteSpecifier: &hcmv3.HttpConnectionManager_Rds{
+							Rds: &hcmv3.Rds{
+								RouteConfigName: "rds",
+							},
+						},
+					}),
+				},
+			},
+		},
+	}
+	var opaque any.Any
+	assert.Nil(t, anypb.MarshalFrom(&opaque, listener, proto2.MarshalOptions{}))
+	dr := &discoveryv3.DiscoveryResponse{
+		VersionInfo: "0",
+		Resources:   []*any.Any{&opaque},
+	}
+
+	adaptor, err := xdsv3.NewAdaptor(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	})
+	assert.Nil(t, err)
+	p := &xdsFileProvisioner{
+		logger:          log.DefaultLogger,
+		v3Adaptor:       adaptor,
+		state:           make(map[string]*util.Manifest),
+		upstreamCache:   make(map[string]*apisix.Upstream),
+		uncheckedRoutes: make(map[string][]*apisix.Route),
+		filePolicies:    make(map[string]*clusterPolicies),
+		hashPolicies:    make(map[string]*xdsv3.HashPolicy),
+
+		routeOriginalDestination: make(map[string]string),
+		routeHTTPFilters:         make(map[string][]*hcmv3.HttpFilter),
+		listenerRouteNames:       make(map[string]set.StringSet),
+		staleRouteConfigurations: make(set.StringSet),
+		routeConfigurationFiles:  make(map[string]string),
+		rdsResponses:             make(map[string]*discoveryv3.DiscoveryResponse),
+	}
+	events := p.generateEventsFromDiscoveryResponseV3("null", dr)
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventAdd)
+	route := events[0].Object.(*apisix.Route)
+	assert.Equal(t, route.Uris, []string{"/foo*"})
+	assert.Equal(t, route.UpstreamId, id.GenID("httpbin"))
+	assert.Equal(t, p.routeOriginalDestination["rds"], "0.0.0.0:9080")
+
+	// Nothing changed.
+	events = p.generateEventsFromDiscoveryResponseV3("null", dr)
+	assert.Len(t, events, 0)
+}
+
+func TestFileProvisionerResolveRDSFromListener(t *testing.T) {
+	newListener := func(port uint32) *any.Any {
+		hcm, err := anypb.New(&hcmv3.HttpConnectionManager{
+			RouteSpecifier: &hcmv3.HttpConnectionManager_Rds{
+				Rds: &hcmv3.Rds{
+					RouteConfigName: "rds",
+				},
+			},
+		})
+		assert.Nil(t, err)
+		res, err := anypb.New(&listenerv3.Listener{
+			Name: "listener1",
+			Address: &corev3.Address{
+				Address: &corev3.Address_SocketAddress{
+					SocketAddress: &corev3.SocketAddress{
+						Address: "10.0.0.1",
+						PortSpecifier: &corev3.SocketAddress_PortValue{
+							PortValue: port,
+						},
+					},
+				},
+			},
+			FilterChains: []*listenerv3.FilterChain{
+				{
+					Filters: []*listenerv3.Filter{
+						{
+							Name: "envoy.filters.network.http_connection_manager",
+							ConfigType: &listenerv3.Filter_TypedConfig{
+								TypedConfig: hcm,
+							},
+						},
+					},
+				},
+			},
+		})
+		assert.Nil(t, err)
+		return res
+	}
+	rc, err := anypb.New(&routev3.RouteConfiguration{
+		Name: "rds",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name:    "vhost",
+				Domains: []string{"*"},
+				Routes: []*routev3.Route{
+					{
+						Name: "route1",
+						Match: &routev3.RouteMatch{
+							PathSpecifier: &routev3.RouteMatch_Prefix{
+								Prefix: "/foo",
+							},
+						},
+						Action: &routev3.Route_Route{
+							Route: &routev3.RouteAction{
+								ClusterSpecifier: &routev3.RouteAction_Cluster{
+									Cluster: "httpbin",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	assert.Nil(t, err)
+
+	p, err := newXDSFileProvisioner(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}, "test")
+	assert.Nil(t, err)
+
+	// The route configuration is seen before the listener.
+	events := p.generateEventsFromDiscoveryResponseV3("rds.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl:   types.RouteConfigurationUrl,
+		Resources: []*any.Any{rc},
+	})
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventAdd)
+	assert.Nil(t, events[0].Object.(*apisix.Route).Vars)
+
+	events = p.generateEventsFromDiscoveryResponseV3("lds.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl:   types.ListenerUrl,
+		Resources: []*any.Any{newListener(9080)},
+	})
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventUpdate)
+	assert.Len(t, events[0].Object.(*apisix.Route).Vars, 1)
+	assert.Equal(t, events[0].Object.(*apisix.Route).Vars[0].Vars, []string{"connection_original_dst", "==", "10.0.0.1:9080"})
+
+	// Nothing changed.
+	events = p.generateEventsFromDiscoveryResponseV3("lds.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl:   types.ListenerUrl,
+		Resources: []*any.Any{newListener(9080)},
+	})
+	assert.Len(t, events, 0)
+
+	// The listener changed.
+	events = p.generateEventsFromDiscoveryResponseV3("lds.json", &discoveryv3.DiscoveryResponse{
+		TypeUrl:   types.ListenerUrl,
+		Resources: []*any.Any{newListener(9081)},
+	})
+	assert.Len(t, events, 1)
+	assert.Len(t, events[0].Object.(*apisix.Route).Vars, 1)
+	assert.Equal(t, events[0].Object.(*apisix.Route).Vars[0].Vars, []string{"connection_original_dst", "==", "10.0.0.1:9081"})
+
+	// The listener is removed.
+	p.handleContentRemoval("lds.json")
+	events = <-p.Channel()
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventUpdate)
+	assert.Nil(t, events[0].Object.(*apisix.Route).Vars)
+	assert.Empty(t, p.routeOriginalDestination)
+}
+
+func TestFileProvisionerMetrics(t *testing.T) {
+	p, err := newXDSFileProvisioner(&config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}, "test")
+	assert.Nil(t, err)
+
+	parseErrors := testutil.ToFloat64(metrics.XDSParseErrors)
+	p.handleContent("bad.json", []byte("{"))
+	assert.Equal(t, testutil.ToFloat64(metrics.XDSParseErrors), parseErrors+1)
+
+	assert.Len(t, p.generateEventsFromDiscoveryResponseV3("a.json", newClustersResponse(t, "a", "aa")), 2)
+	assert.Len(t, p.generateEventsFromDiscoveryResponseV3("b.json", newClustersResponse(t, "b")), 1)
+	assert.Equal(t, testutil.ToFloat64(metrics.WatchedFiles), float64(2))
+
+	p.handleContentRemoval("a.json")
+	assert.Len(t, <-p.Channel(), 2)
+	assert.Equal(t, testutil.ToFloat64(metrics.WatchedFiles), float64(1))
+}
+
+func TestFileProvisionerSendEventsInOrder(t *testing.T) {
+	p := &xdsFileProvisioner{
+		evChan: make(chan []types.Event),
+	}
+	var expected []string
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprint(i)
+		expected = append(expected, key)
+		p.sendEvents([]types.Event{{Type: types.EventAdd, Object: &apisix.Route{Id: key}}})
+	}
+	// Empty batches are not sent.
+	p.sendEvents(nil)
+
+	done := make(chan struct{})
+	go func() {
+		p.sending.Wait()
+		close(p.evChan)
+		close(done)
+	}()
+	var got []string
+	for events := range p.evChan {
+		got = append(got, events[0].Object.(*apisix.Route).Id)
+	}
+	<-done
+	assert.Equal(t, got, expected)
+}
+
+func TestMergeUpdatedUpstreams(t *testing.T) {
+	rm := &util.Manifest{
+		Upstreams: []*apisix.Upstream{
+			{Name: "a"},
+			{Name: "b"},
+			{Name: "c"},
+		},
+	}
+	// The updated upstreams are not in the same order as the manifest ones,
+	// "c" must replace the third manifest upstream rather than the first.
+	ups := []*apisix.Upstream{
+		{Name: "c", Nodes: []*apisix.Node{{Host: "10.0.0.3", Port: 80}}},
+		{Name: "d"},
+		{Name: "a", Nodes: []*apisix.Node{{Host: "10.0.0.1", Port: 80}}},
+	}
+	rest := mergeUpdatedUpstreams(rm, ups)
+	assert.Len(t, rest, 1)
+	assert.Equal(t, rest[0].Name, "d")
+
+	assert.Len(t, rm.Upstreams, 3)
+	assert.Equal(t, rm.Upstreams[0].Name, "a")
+	assert.Equal(t, rm.Upstreams[0].Nodes[0].Host, "10.0.0.1")
+	assert.Equal(t, rm.Upstreams[1].Name, "b")
+	assert.Nil(t, rm.Upstreams[1].Nodes)
+	assert.Equal(t, rm.Upstreams[2].Name, "c")
+	assert.Equal(t, rm.Upstreams[2].Nodes[0].Host, "10.0.0.3")
+}
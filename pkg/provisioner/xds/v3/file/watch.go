@@ -0,0 +1,175 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+
+	"github.com/api7/apisix-mesh-agent/pkg/set"
+)
+
+// _resyncRetryInterval is the interval to retry the failed resync of the
+// watched files, see resyncFiles.
+const _resyncRetryInterval = 5 * time.Second
+
+// watchPaths adds the watched paths to the watcher, directories are
+// watched recursively since the watcher only notifies the changes of
+// direct children.
+func (p *xdsFileProvisioner) watchPaths() error {
+	for _, file := range p.files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			if err := p.watcher.Add(file); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := p.watchDir(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchDir adds the directory and all its subdirectories to the watcher.
+func (p *xdsFileProvisioner) watchDir(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != dir && isAtomicWriterPath(path) {
+			return filepath.SkipDir
+		}
+		if _, ok := p.watchedDirs[path]; ok {
+			return nil
+		}
+		if err := p.watcher.Add(path); err != nil {
+			return err
+		}
+		p.watchedDirs.Add(path)
+		return nil
+	})
+}
+
+// handleDirCreation watches the created directory (recursively), files
+// already in it are handled as created, since their events might be
+// missed before the watch is added.
+func (p *xdsFileProvisioner) handleDirCreation(dir string) {
+	if err := p.watchDir(dir); err != nil {
+		p.logger.Errorw("failed to watch directory",
+			zap.Error(err),
+			zap.String("directory", dir),
+		)
+	}
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && isAtomicWriterPath(path) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		p.logger.Errorw("failed to walk directory",
+			zap.Error(err),
+			zap.String("directory", dir),
+		)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		p.handleFileEvent(fsnotify.Event{
+			Name: file,
+			Op:   fsnotify.Create,
+		})
+	}
+}
+
+// handleDirRemoval cleans up the watches of the removed directory (and its
+// subdirectories), files in it are handled as removed. It reports whether
+// the path was a watched directory.
+func (p *xdsFileProvisioner) handleDirRemoval(dir string) bool {
+	if _, ok := p.watchedDirs[dir]; !ok {
+		return false
+	}
+	prefix := dir + string(filepath.Separator)
+	for path := range p.watchedDirs {
+		if path != dir && !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		// The watch might be removed by the watcher already when
+		// the directory was deleted.
+		_ = p.watcher.Remove(path)
+		delete(p.watchedDirs, path)
+	}
+	var files []string
+	for file := range p.state {
+		if strings.HasPrefix(file, prefix) {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		p.handleFileEvent(fsnotify.Event{
+			Name: file,
+			Op:   fsnotify.Remove,
+		})
+	}
+	return true
+}
+
+// resyncFiles watches the paths again and handles all the files after the
+// watcher failed, since the changes in between might be missed. Files are
+// handled as written, only the changes generate events, and the files which
+// are gone are handled as removed.
+func (p *xdsFileProvisioner) resyncFiles() error {
+	// Watches might be lost, the watched directories are collected again.
+	p.watchedDirs = set.StringSet{}
+	if err := p.watchPaths(); err != nil {
+		return err
+	}
+	files, err := p.walkFiles(true)
+	if err != nil {
+		return err
+	}
+	var removed []string
+	for file := range p.state {
+		if _, ok := files[file]; !ok {
+			removed = append(removed, file)
+		}
+	}
+	sort.Strings(removed)
+	for _, file := range removed {
+		delete(p.fileStats, file)
+		p.handleFileEvent(fsnotify.Event{
+			Name: file,
+			Op:   fsnotify.Remove,
+		})
+	}
+	for _, file := range sortedFilenames(files) {
+		p.fileStats[file] = files[file]
+		p.handleFileEvent(fsnotify.Event{
+			Name: file,
+			Op:   fsnotify.Write,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package file
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestFileProvisionerWatchNestedDirectories(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xds-watch")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := &config.Config{
+		LogLevel:      "debug",
+		LogOutput:     "stderr",
+		XDSWatchFiles: []string{dir},
+	}
+	pr, err := NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	p := pr.(*xdsFileProvisioner)
+
+	stopCh := make(chan struct{})
+	go func() {
+		assert.Nil(t, p.Run(stopCh))
+	}()
+	// Wait for the watches to be added.
+	time.Sleep(100 * time.Millisecond)
+
+	// Prepare the nested directories outside and move them in, so the
+	// file already exists when the directory is created.
+	staging, err := ioutil.TempDir("", "xds-watch-staging")
+	assert.Nil(t, err)
+	defer os.RemoveAll(staging)
+	cluster, err := ioutil.ReadFile("testdata/cluster.json")
+	assert.Nil(t, err)
+	assert.Nil(t, os.MkdirAll(filepath.Join(staging, "a", "b"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(staging, "a", "b", "cluster.json"), cluster, 0644))
+	assert.Nil(t, os.Rename(filepath.Join(staging, "a"), filepath.Join(dir, "a")))
+
+	var events []types.Event
+	select {
+	case events = <-p.Channel():
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event arrived in time")
+	}
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventAdd)
+	assert.Equal(t, events[0].Object.(*apisix.Upstream).Name, "httpbin.default.svc.cluster.local")
+
+	// Changes in the nested directory are watched.
+	route, err := ioutil.ReadFile("testdata/route.json")
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "a", "b", "route.json"), route, 0644))
+	select {
+	case events = <-p.Channel():
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event arrived in time")
+	}
+	assert.Equal(t, events[0].Type, types.EventAdd)
+	_, ok := events[0].Object.(*apisix.Route)
+	assert.Equal(t, ok, true)
+
+	assert.Nil(t, os.RemoveAll(filepath.Join(dir, "a")))
+	deleted := 0
+	for deleted < 2 {
+		select {
+		case events = <-p.Channel():
+		case <-time.After(2 * time.Second):
+			t.Fatal("no event arrived in time")
+		}
+		for _, ev := range events {
+			assert.Equal(t, ev.Type, types.EventDelete)
+			deleted++
+		}
+	}
+
+	close(stopCh)
+	_, ok = <-p.Channel()
+	assert.Equal(t, ok, false)
+}
+
+func TestFileProvisionerReadiness(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xds-watch")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	pr, err := NewXDSProvisioner(&config.Config{
+		LogLevel:      "debug",
+		LogOutput:     "stderr",
+		XDSWatchFiles: []string{dir},
+	})
+	assert.Nil(t, err)
+	p := pr.(*xdsFileProvisioner)
+	assert.False(t, p.Ready())
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go func() {
+		assert.Nil(t, p.Run(stopCh))
+	}()
+	// Wait for the watches to be added.
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, p.Ready())
+
+	// Changes are missed once the watch is lost.
+	assert.Nil(t, p.watcher.Remove(dir))
+	cluster, err := ioutil.ReadFile("testdata/cluster.json")
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "cluster.json"), cluster, 0644))
+	time.Sleep(100 * time.Millisecond)
+
+	// The files are resynced after the watch errors, and the readiness
+	// is restored.
+	p.watcher.Errors <- errors.New("queue overflow")
+	var events []types.Event
+	select {
+	case events = <-p.Channel():
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event arrived in time")
+	}
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventAdd)
+	assert.Equal(t, events[0].Object.(*apisix.Upstream).Name, "httpbin.default.svc.cluster.local")
+	assert.Eventually(t, p.Ready, time.Second, 10*time.Millisecond)
+
+	// The watch is added again.
+	assert.Nil(t, os.Remove(filepath.Join(dir, "cluster.json")))
+	select {
+	case events = <-p.Channel():
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event arrived in time")
+	}
+	assert.Len(t, events, 1)
+	assert.Equal(t, events[0].Type, types.EventDelete)
+}
@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+var _errNoCACertificates = errors.New("no certificates found in the CA file")
+
+// reloadableCredentials are the TLS credentials to the config source, the
+// client certificate, private key and CA certificate are re-read by reload,
+// the connections made after it (e.g. the reconnections) use the new ones,
+// the established connections are kept.
+type reloadableCredentials struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu         sync.RWMutex
+	creds      credentials.TransportCredentials
+	serverName string
+}
+
+func newReloadableCredentials(certFile, keyFile, caFile string) (*reloadableCredentials, error) {
+	c := &reloadableCredentials{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+	}
+	if _, err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// reload re-reads the files, the expiry of the new client certificate is
+// returned, the last credentials are kept if it fails.
+func (c *reloadableCredentials) reload() (time.Time, error) {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return time.Time{}, err
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if c.caFile != "" {
+		data, err := ioutil.ReadFile(c.caFile)
+		if err != nil {
+			return time.Time{}, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return time.Time{}, _errNoCACertificates
+		}
+		cfg.RootCAs = pool
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cfg.ServerName = c.serverName
+	c.creds = credentials.NewTLS(cfg)
+	return leaf.NotAfter, nil
+}
+
+func (c *reloadableCredentials) current() credentials.TransportCredentials {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.creds
+}
+
+func (c *reloadableCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return c.current().ClientHandshake(ctx, authority, conn)
+}
+
+func (c *reloadableCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return c.current().ServerHandshake(conn)
+}
+
+func (c *reloadableCredentials) Info() credentials.ProtocolInfo {
+	return c.current().Info()
+}
+
+// Clone returns the credentials itself, so that the reloading applies to
+// the copies held by the gRPC connection.
+func (c *reloadableCredentials) Clone() credentials.TransportCredentials {
+	return c
+}
+
+func (c *reloadableCredentials) OverrideServerName(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.serverName = name
+	return c.creds.OverrideServerName(name)
+}
@@ -0,0 +1,142 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner"
+	"github.com/api7/apisix-mesh-agent/pkg/testutil"
+)
+
+// writeCertificate writes a self-signed certificate (for localhost) valid
+// for the duration and its private key to the files.
+func writeCertificate(t *testing.T, certFile, keyFile string, validity time.Duration) *x509.Certificate {
+	cert, certPEM, keyPEM := testutil.GenerateCertificate(t, validity, "localhost")
+	assert.Nil(t, ioutil.WriteFile(certFile, []byte(certPEM), 0600))
+	assert.Nil(t, ioutil.WriteFile(keyFile, []byte(keyPEM), 0600))
+	return cert
+}
+
+func TestReloadableCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xds-certs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	var (
+		certFile   = filepath.Join(dir, "cert.pem")
+		keyFile    = filepath.Join(dir, "key.pem")
+		serverCert = filepath.Join(dir, "server-cert.pem")
+		serverKey  = filepath.Join(dir, "server-key.pem")
+	)
+	cert := writeCertificate(t, certFile, keyFile, time.Hour)
+	writeCertificate(t, serverCert, serverKey, time.Hour)
+
+	// The server records the client certificates of the handshakes.
+	pair, err := tls.LoadX509KeyPair(serverCert, serverKey)
+	assert.Nil(t, err)
+	li, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{pair},
+		ClientAuth:   tls.RequireAnyClientCert,
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2"},
+	})
+	assert.Nil(t, err)
+	defer li.Close()
+	clientCerts := make(chan *x509.Certificate, 2)
+	go func() {
+		for {
+			conn, err := li.Accept()
+			if err != nil {
+				return
+			}
+			tc := conn.(*tls.Conn)
+			if err := tc.Handshake(); err == nil {
+				clientCerts <- tc.ConnectionState().PeerCertificates[0]
+			}
+			tc.Close()
+		}
+	}()
+	_, port, err := net.SplitHostPort(li.Addr().String())
+	assert.Nil(t, err)
+	handshake := func(c *reloadableCredentials) *x509.Certificate {
+		conn, err := net.Dial("tcp", li.Addr().String())
+		assert.Nil(t, err)
+		tc, _, err := c.ClientHandshake(context.Background(), "localhost:"+port, conn)
+		assert.Nil(t, err)
+		defer tc.Close()
+		select {
+		case cert := <-clientCerts:
+			return cert
+		case <-time.After(2 * time.Second):
+			t.Fatal("no handshake in time")
+		}
+		return nil
+	}
+
+	c, err := newReloadableCredentials(certFile, keyFile, serverCert)
+	assert.Nil(t, err)
+	assert.Equal(t, c.Info().SecurityProtocol, "tls")
+	assert.Equal(t, handshake(c).SerialNumber, cert.SerialNumber)
+
+	// The connections after reloading use the new certificate.
+	newCert := writeCertificate(t, certFile, keyFile, 2*time.Hour)
+	notAfter, err := c.reload()
+	assert.Nil(t, err)
+	assert.Equal(t, notAfter, newCert.NotAfter)
+	assert.Equal(t, handshake(c).SerialNumber, newCert.SerialNumber)
+
+	// The last certificate is kept if it fails.
+	assert.Nil(t, ioutil.WriteFile(certFile, []byte("bad"), 0600))
+	_, err = c.reload()
+	assert.NotNil(t, err)
+	assert.Equal(t, handshake(c).SerialNumber, newCert.SerialNumber)
+	writeCertificate(t, certFile, keyFile, time.Hour)
+	assert.Nil(t, ioutil.WriteFile(serverCert, []byte("bad"), 0600))
+	_, err = c.reload()
+	assert.Equal(t, err, _errNoCACertificates)
+
+	_, err = newReloadableCredentials(filepath.Join(dir, "missing"), keyFile, "")
+	assert.NotNil(t, err)
+}
+
+func TestGRPCProvisionerReloadCerts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xds-certs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeCertificate(t, certFile, keyFile, time.Hour)
+
+	cfg := &config.Config{
+		LogLevel:        "info",
+		LogOutput:       "stderr",
+		Provisioner:     "xds-v3-grpc",
+		XDSConfigSource: "grpc://127.0.0.1:11111",
+		RunningContext: &config.RunningContext{
+			PodNamespace: "default",
+			IPAddress:    "1.1.1.1",
+		},
+	}
+	p, err := NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	_, err = p.(provisioner.CertReloader).ReloadCerts()
+	assert.Equal(t, err, provisioner.ErrNoClientCerts)
+
+	cfg.XDSClientCert = certFile
+	cfg.XDSClientKey = keyFile
+	p, err = NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	cert := writeCertificate(t, certFile, keyFile, 3*time.Hour)
+	notAfter, err := p.(provisioner.CertReloader).ReloadCerts()
+	assert.Nil(t, err)
+	assert.Equal(t, notAfter, cert.NotAfter)
+}
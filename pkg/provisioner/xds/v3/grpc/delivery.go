@@ -10,6 +10,7 @@ import (
 	"google.golang.org/protobuf/types/known/anypb"
 
 	xdsv3 "github.com/api7/apisix-mesh-agent/pkg/adaptor/xds/v3"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner/util"
 	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
 )
 
@@ -28,6 +29,7 @@ func (p *grpcProvisioner) processRouteConfigurationV3(res *any.Any) ([]*apisix.R
 
 	opts := &xdsv3.TranslateOptions{
 		RouteOriginalDestination: p.routeOwnership,
+		RouteHTTPFilters:         p.routeHTTPFilters,
 	}
 	routes, err := p.v3Adaptor.TranslateRouteConfiguration(&route, opts)
 	if err != nil {
@@ -37,6 +39,12 @@ func (p *grpcProvisioner) processRouteConfigurationV3(res *any.Any) ([]*apisix.R
 		)
 		return nil, err
 	}
+	if p.provenanceLabels {
+		for _, r := range routes {
+			r.Labels = util.MergeLabels(r.Labels, util.ProvenanceLabels("", "RouteConfiguration"))
+		}
+	}
+	p.collectUpstreamPolicies(&route)
 	return routes, nil
 }
 
@@ -46,20 +54,39 @@ func (p *grpcProvisioner) processStaticRouteConfigurations(rcs []*routev3.RouteC
 	)
 	opts := &xdsv3.TranslateOptions{
 		RouteOriginalDestination: p.routeOwnership,
+		RouteHTTPFilters:         p.routeHTTPFilters,
 	}
 	for _, rc := range rcs {
-		route, err := p.v3Adaptor.TranslateRouteConfiguration(rc, opts)
+		partial, err := p.v3Adaptor.TranslateRouteConfiguration(rc, opts)
 		if err != nil {
 			p.logger.Errorw("failed to translate RouteConfiguration to APISIX routes",
 				zap.Error(err),
-				zap.Any("route", &route),
+				zap.Any("route", rc),
 			)
 			return nil, err
 		}
+		if p.provenanceLabels {
+			for _, r := range partial {
+				r.Labels = util.MergeLabels(r.Labels, util.ProvenanceLabels("", "Listener"))
+			}
+		}
+		p.collectUpstreamPolicies(rc)
+		routes = append(routes, partial...)
 	}
 	return routes, nil
 }
 
+// collectUpstreamPolicies collects the hash and retry policies of routes,
+// they are patched to the upstreams of target clusters.
+func (p *grpcProvisioner) collectUpstreamPolicies(rc *routev3.RouteConfiguration) {
+	for cluster, hp := range p.v3Adaptor.CollectClusterHashPolicies(rc) {
+		p.hashPolicies[cluster] = hp
+	}
+	for cluster, rp := range p.v3Adaptor.CollectClusterRetryPolicies(rc) {
+		p.retryPolicies[cluster] = rp
+	}
+}
+
 func (p *grpcProvisioner) processClusterV3(res *any.Any) (*apisix.Upstream, error) {
 	var cluster clusterv3.Cluster
 	err := anypb.UnmarshalTo(res, &cluster, proto.UnmarshalOptions{
@@ -76,15 +103,44 @@ func (p *grpcProvisioner) processClusterV3(res *any.Any) (*apisix.Upstream, erro
 	if err != nil && err != xdsv3.ErrRequireFurtherEDS {
 		return nil, err
 	}
+	if p.provenanceLabels {
+		ups.Labels = util.MergeLabels(ups.Labels, util.ProvenanceLabels("", "Cluster"))
+	}
 	if err == xdsv3.ErrRequireFurtherEDS {
 		p.logger.Warnw("cluster depends on another EDS config, an upstream without nodes setting was generated",
 			zap.Any("upstream", ups),
 		)
-		p.edsRequiredClusters.Add(ups.Name)
+		// EDS should be requested by the service name, which might be
+		// different from the cluster name.
+		name := xdsv3.GetEDSServiceName(&cluster)
+		p.edsRequiredClusters.Add(name)
+		p.edsServiceNames[name] = ups.Name
+	}
+	if hp, ok := p.hashPolicies[ups.Name]; ok {
+		hp.PatchUpstream(ups)
+	}
+	if rp, ok := p.retryPolicies[ups.Name]; ok {
+		rp.PatchUpstream(ups)
+	}
+	if xdsv3.IsClusterStatsTracked(&cluster) {
+		p.statsTrackedUpstreams.Add(ups.Id)
+	}
+	if lc := xdsv3.GetClusterConnectionLimit(&cluster); lc != nil {
+		p.connectionLimits[ups.Id] = lc
 	}
 	return ups, nil
 }
 
+// redactClusterResource unmarshals the Cluster resource for logging, with the
+// private keys redacted, only the type url is kept if it's not a valid one.
+func redactClusterResource(res *any.Any) interface{} {
+	var cluster clusterv3.Cluster
+	if err := anypb.UnmarshalTo(res, &cluster, proto.UnmarshalOptions{DiscardUnknown: true}); err != nil {
+		return res.GetTypeUrl()
+	}
+	return xdsv3.RedactCluster(&cluster)
+}
+
 func (p *grpcProvisioner) processClusterLoadAssignmentV3(res *any.Any) (*apisix.Upstream, error) {
 	var cla endpointv3.ClusterLoadAssignment
 	err := anypb.UnmarshalTo(res, &cla, proto.UnmarshalOptions{
@@ -98,7 +154,11 @@ func (p *grpcProvisioner) processClusterLoadAssignmentV3(res *any.Any) (*apisix.
 		return nil, err
 	}
 
-	ups, ok := p.upstreams[cla.ClusterName]
+	clusterName := cla.ClusterName
+	if name, ok := p.edsServiceNames[clusterName]; ok {
+		clusterName = name
+	}
+	ups, ok := p.upstreams[clusterName]
 	if !ok {
 		p.logger.Warnw("found invalid ClusterLoadAssignment resource",
 			zap.String("reason", "cluster unknown"),
@@ -119,6 +179,12 @@ func (p *grpcProvisioner) processClusterLoadAssignmentV3(res *any.Any) (*apisix.
 	// Do not set on the original ups to avoid race conditions.
 	newUps := proto.Clone(ups).(*apisix.Upstream)
 	newUps.Nodes = nodes
-	p.upstreams[cla.ClusterName] = newUps
+	if err := xdsv3.CheckUpstreamSNI(newUps, &cla); err != nil {
+		p.logger.Warnw("per endpoint SNI cannot be honored",
+			zap.Error(err),
+			zap.String("cluster_name", clusterName),
+		)
+	}
+	p.upstreams[clusterName] = newUps
 	return newUps, nil
 }
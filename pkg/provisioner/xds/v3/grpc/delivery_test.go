@@ -12,6 +12,7 @@ import (
 	xdsv3 "github.com/api7/apisix-mesh-agent/pkg/adaptor/xds/v3"
 	"github.com/api7/apisix-mesh-agent/pkg/config"
 	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/set"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	"github.com/golang/protobuf/ptypes/any"
 	"github.com/golang/protobuf/ptypes/wrappers"
@@ -137,7 +138,9 @@ func TestProcessClusterV3(t *testing.T) {
 	p := &grpcProvisioner{
 		logger:    log.DefaultLogger,
 		v3Adaptor: adaptor,
-		upstreams: make(map[string]*apisix.Upstream),
+		translationState: translationState{
+			upstreams: make(map[string]*apisix.Upstream),
+		},
 	}
 	ups, err := p.processClusterV3(&opaque)
 	assert.Nil(t, err)
@@ -214,7 +217,9 @@ func TestProcessClusterLoadAssignment(t *testing.T) {
 	p := &grpcProvisioner{
 		logger:    log.DefaultLogger,
 		v3Adaptor: adaptor,
-		upstreams: make(map[string]*apisix.Upstream),
+		translationState: translationState{
+			upstreams: make(map[string]*apisix.Upstream),
+		},
 	}
 	// Reject since the cluster is unknown.
 	ups, err := p.processClusterLoadAssignmentV3(&opaque)
@@ -250,3 +255,74 @@ func TestProcessClusterLoadAssignment(t *testing.T) {
 	assert.Equal(t, ups.Nodes[1].Port, int32(8000))
 	assert.Equal(t, ups.Nodes[1].Weight, int32(80))
 }
+
+func TestProcessClusterLoadAssignmentWithEDSServiceName(t *testing.T) {
+	c := &clusterv3.Cluster{
+		Name: "outbound|80||httpbin.default.svc.cluster.local",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_EDS,
+		},
+		EdsClusterConfig: &clusterv3.Cluster_EdsClusterConfig{
+			ServiceName: "httpbin.default.svc.cluster.local",
+		},
+		LbPolicy: clusterv3.Cluster_ROUND_ROBIN,
+	}
+	cla := &endpointv3.ClusterLoadAssignment{
+		ClusterName: "httpbin.default.svc.cluster.local",
+		Endpoints: []*endpointv3.LocalityLbEndpoints{
+			{
+				LbEndpoints: []*endpointv3.LbEndpoint{
+					{
+						HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+							Endpoint: &endpointv3.Endpoint{
+								Address: &corev3.Address{
+									Address: &corev3.Address_SocketAddress{
+										SocketAddress: &corev3.SocketAddress{
+											Protocol: corev3.SocketAddress_TCP,
+											Address:  "10.0.3.11",
+											PortSpecifier: &corev3.SocketAddress_PortValue{
+												PortValue: 8000,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	var (
+		opaque  any.Any
+		opaque2 any.Any
+	)
+	assert.Nil(t, anypb.MarshalFrom(&opaque, c, proto2.MarshalOptions{}))
+	assert.Nil(t, anypb.MarshalFrom(&opaque2, cla, proto2.MarshalOptions{}))
+	cfg := &config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+	}
+	adaptor, err := xdsv3.NewAdaptor(cfg)
+	assert.Nil(t, err)
+	p := &grpcProvisioner{
+		logger:    log.DefaultLogger,
+		v3Adaptor: adaptor,
+		translationState: translationState{
+			upstreams:           make(map[string]*apisix.Upstream),
+			edsRequiredClusters: set.StringSet{},
+			edsServiceNames:     make(map[string]string),
+		},
+	}
+	ups, err := p.processClusterV3(&opaque)
+	assert.Nil(t, err)
+	p.upstreams[ups.Name] = ups
+	assert.Equal(t, p.edsRequiredClusters.Strings(), []string{"httpbin.default.svc.cluster.local"})
+
+	ups, err = p.processClusterLoadAssignmentV3(&opaque2)
+	assert.Nil(t, err)
+	assert.Equal(t, ups.Name, "outbound|80||httpbin.default.svc.cluster.local")
+	assert.Len(t, ups.Nodes, 1)
+	assert.Equal(t, ups.Nodes[0].Host, "10.0.3.11")
+	assert.Equal(t, p.upstreams["outbound|80||httpbin.default.svc.cluster.local"], ups)
+}
@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"time"
+
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner"
+)
+
+type replayProvisioner struct {
+	*grpcProvisioner
+	dir      string
+	realtime bool
+}
+
+// NewXDSReplayProvisioner creates a provisioner which replays the recorded
+// DiscoveryResponse objects in the dir, files are fed in the lexicographical
+// order of their names, each file should be a DiscoveryResponse in JSON format.
+// When realtime is true, the modification time differences between adjacent
+// files are honored as the inter-arrival timing.
+// The replay provisioner closes the event channel once all files were fed.
+func NewXDSReplayProvisioner(cfg *config.Config, dir string, realtime bool) (provisioner.Provisioner, error) {
+	// Only the translation pipeline is used, so the config source is not necessary.
+	c := *cfg
+	c.XDSConfigSource = "grpc://replay"
+	p, err := NewXDSProvisioner(&c)
+	if err != nil {
+		return nil, err
+	}
+	return &replayProvisioner{
+		grpcProvisioner: p.(*grpcProvisioner),
+		dir:             dir,
+		realtime:        realtime,
+	}, nil
+}
+
+func (p *replayProvisioner) Run(stop chan struct{}) error {
+	defer close(p.evChan)
+
+	files, err := ioutil.ReadDir(p.dir)
+	if err != nil {
+		return err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+
+	// The discovery requests are meaningless during replaying.
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case dr := <-p.sendCh:
+				p.logger.Debugw("discard discovery request during replaying",
+					zap.Any("request", dr),
+				)
+			}
+		}
+	}()
+
+	var last time.Time
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		if p.realtime && !last.IsZero() {
+			select {
+			case <-stop:
+				return nil
+			case <-time.After(f.ModTime().Sub(last)):
+			}
+		}
+		last = f.ModTime()
+
+		filename := filepath.Join(p.dir, f.Name())
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		var dr discoveryv3.DiscoveryResponse
+		if err := protojson.Unmarshal(data, &dr); err != nil {
+			p.logger.Errorw("failed to unmarshal recorded discovery response",
+				zap.Error(err),
+				zap.String("filename", filename),
+			)
+			continue
+		}
+		events, err := p.translateResponse(&dr)
+		if err != nil {
+			p.logger.Errorw("failed to translate recorded discovery response",
+				zap.Error(err),
+				zap.String("filename", filename),
+				zap.String("type_url", dr.TypeUrl),
+				zap.String("version_info", dr.VersionInfo),
+			)
+			continue
+		}
+		p.logger.Infow("replayed discovery response",
+			zap.String("filename", filename),
+			zap.String("type_url", dr.TypeUrl),
+			zap.String("version_info", dr.VersionInfo),
+			zap.Int("events", len(events)),
+		)
+		if len(events) == 0 {
+			continue
+		}
+		select {
+		case <-stop:
+			return nil
+		case p.evChan <- events:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package grpc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestReplayProvisioner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	c := &clusterv3.Cluster{
+		Name: "httpbin.default.svc.cluster.local",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_EDS,
+		},
+		LbPolicy: clusterv3.Cluster_ROUND_ROBIN,
+	}
+	res, err := anypb.New(c)
+	assert.Nil(t, err)
+	responses := []*discoveryv3.DiscoveryResponse{
+		{
+			VersionInfo: "1",
+			TypeUrl:     types.ClusterUrl,
+			Resources:   []*any.Any{res},
+		},
+		{
+			VersionInfo: "2",
+			TypeUrl:     types.ClusterUrl,
+		},
+	}
+	for i, name := range []string{"0001-cds.json", "0002-cds.json"} {
+		data, err := protojson.Marshal(responses[i])
+		assert.Nil(t, err)
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), data, 0644))
+	}
+	// Garbage should be skipped.
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "0003-bad.json"), []byte("{"), 0644))
+
+	cfg := &config.Config{
+		LogLevel:  "debug",
+		LogOutput: "stderr",
+		RunningContext: &config.RunningContext{
+			PodNamespace: "default",
+			IPAddress:    "1.1.1.1",
+		},
+	}
+	p, err := NewXDSReplayProvisioner(cfg, dir, false)
+	assert.Nil(t, err)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- p.Run(stop)
+	}()
+
+	var pushes [][]types.Event
+	timer := time.After(2 * time.Second)
+loop:
+	for {
+		select {
+		case events, ok := <-p.Channel():
+			if !ok {
+				break loop
+			}
+			pushes = append(pushes, events)
+		case <-timer:
+			t.Fatal("replay doesn't finish in time")
+		}
+	}
+	assert.Nil(t, <-errCh)
+	assert.Len(t, pushes, 2)
+	assert.Len(t, pushes[0], 1)
+	assert.Equal(t, pushes[0][0].Type, types.EventAdd)
+	assert.Equal(t, pushes[0][0].Object.(*apisix.Upstream).Name, "httpbin.default.svc.cluster.local")
+	assert.Len(t, pushes[1], 1)
+	assert.Equal(t, pushes[1][0].Type, types.EventDelete)
+}
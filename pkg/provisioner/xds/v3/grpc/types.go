@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"go.uber.org/zap"
 	"google.golang.org/genproto/googleapis/rpc/code"
@@ -33,32 +36,137 @@ var (
 	_errUnknownClusterName     = errors.New("unknown cluster name")
 )
 
-// Note this provisioner is based on the xDS State of The World
-// protocol, not the Delta one.
-type grpcProvisioner struct {
-	configSource string
-	node         *corev3.Node
-	logger       *log.Logger
-	evChan       chan []types.Event
-	v3Adaptor    xdsv3.Adaptor
+// Bits of the grpcProvisioner.accepted.
+const (
+	_listenersAccepted int32 = 1 << iota
+	_clustersAccepted
+)
 
+// translationState is the state which the translation of a response
+// depends on and updates, it's swapped as a whole so that a rejected
+// response leaves it unchanged.
+type translationState struct {
 	// find the listener (address) owner, an extra match
 	// condition will be patched to the APISIX route.
 	// "connection_original_dst == <ip>:<port>"
 	routeOwnership map[string]string
+	// the HTTP filters (type urls) of the listener that owns the route,
+	// it decides the plugin execution order.
+	routeHTTPFilters map[string][]*hcmv3.HttpFilter
 
 	// static route configuration from listeners.
 	staticRouteConfigurations []*routev3.RouteConfiguration
 
 	// last state of routes.
 	routes []*apisix.Route
+	// last state of plugin configs.
+	pluginConfigs []*apisix.PluginConfig
+	// last state of consumers (translated from listeners).
+	consumers []*apisix.Consumer
 	// last state of upstreams.
 	// map is necessary since EDS requires the original cluster
 	// by the name.
 	upstreams map[string]*apisix.Upstream
 
-	// this map enrolls all clusters that require further EDS requests.
+	// this map enrolls all clusters (the EDS service names) that require further
+	// EDS requests.
 	edsRequiredClusters set.StringSet
+	// the cluster names keyed by the EDS service name.
+	edsServiceNames map[string]string
+	// hash and retry policies collected from routes, keyed by cluster name.
+	hashPolicies  map[string]*xdsv3.HashPolicy
+	retryPolicies map[string]*xdsv3.RetryPolicy
+	// the ids of upstreams whose clusters opt into the stats tracking.
+	statsTrackedUpstreams set.StringSet
+	// the connection limits translated from the circuit breakers of
+	// clusters, keyed by the upstream id.
+	connectionLimits map[string]*apisix.LimitConn
+}
+
+// clone copies the state, maps are copied so the copy can be updated
+// without touching the original one. Slices and the objects are always
+// replaced instead of updated in place, so they're shared.
+func (s *translationState) clone() translationState {
+	c := *s
+	c.routeOwnership = make(map[string]string, len(s.routeOwnership))
+	for k, v := range s.routeOwnership {
+		c.routeOwnership[k] = v
+	}
+	c.routeHTTPFilters = make(map[string][]*hcmv3.HttpFilter, len(s.routeHTTPFilters))
+	for k, v := range s.routeHTTPFilters {
+		c.routeHTTPFilters[k] = v
+	}
+	c.upstreams = make(map[string]*apisix.Upstream, len(s.upstreams))
+	for k, v := range s.upstreams {
+		c.upstreams[k] = v
+	}
+	c.edsRequiredClusters = make(set.StringSet, len(s.edsRequiredClusters))
+	for k := range s.edsRequiredClusters {
+		c.edsRequiredClusters.Add(k)
+	}
+	c.edsServiceNames = make(map[string]string, len(s.edsServiceNames))
+	for k, v := range s.edsServiceNames {
+		c.edsServiceNames[k] = v
+	}
+	c.hashPolicies = make(map[string]*xdsv3.HashPolicy, len(s.hashPolicies))
+	for k, v := range s.hashPolicies {
+		c.hashPolicies[k] = v
+	}
+	c.retryPolicies = make(map[string]*xdsv3.RetryPolicy, len(s.retryPolicies))
+	for k, v := range s.retryPolicies {
+		c.retryPolicies[k] = v
+	}
+	c.statsTrackedUpstreams = make(set.StringSet, len(s.statsTrackedUpstreams))
+	for k := range s.statsTrackedUpstreams {
+		c.statsTrackedUpstreams.Add(k)
+	}
+	c.connectionLimits = make(map[string]*apisix.LimitConn, len(s.connectionLimits))
+	for k, v := range s.connectionLimits {
+		c.connectionLimits[k] = v
+	}
+	return c
+}
+
+// Note this provisioner is based on the xDS State of The World
+// protocol, not the Delta one.
+type grpcProvisioner struct {
+	configSource string
+	node         *corev3.Node
+	logger       *log.Logger
+	evChan       chan []types.Event
+	v3Adaptor    xdsv3.Adaptor
+
+	// the TLS credentials to the config source, it's nil if the
+	// connection is plaintext.
+	credentials *reloadableCredentials
+
+	// the state derived from the accepted responses, see translate.
+	translationState
+
+	// whether to mark resources with provenance labels.
+	provenanceLabels bool
+	// fields that should be ignored when diffing.
+	diffIgnoredFields []string
+	// the order of events in a batch.
+	eventOrder string
+	// whether to extract shared plugins to plugin configs.
+	sharedPluginConfigs bool
+	// names or ids of resources which won't generate events.
+	protected set.StringSet
+	// the interval to emit the heartbeat events, no heartbeat is
+	// emitted if it's 0.
+	heartbeatInterval time.Duration
+
+	// the last accepted version and the last received nonce, keyed by
+	// the type url, requests of a type always carry them so that the
+	// control plane knows the state of that type. They're only accessed
+	// by the translateLoop.
+	ackedVersions map[string]string
+	nonces        map[string]string
+	// the resource types (_listenersAccepted and _clustersAccepted)
+	// accepted since the stream last failed, it's accessed atomically,
+	// see Ready.
+	accepted int32
 
 	sendCh chan *discoveryv3.DiscoveryRequest
 	recvCh chan *discoveryv3.DiscoveryResponse
@@ -82,6 +190,13 @@ func NewXDSProvisioner(cfg *config.Config) (provisioner.Provisioner, error) {
 	if err != nil {
 		return nil, err
 	}
+	var creds *reloadableCredentials
+	if cfg.XDSClientCert != "" {
+		creds, err = newReloadableCredentials(cfg.XDSClientCert, cfg.XDSClientKey, cfg.XDSCACert)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// TODO Configurable domain suffix.
 	dnsDomain := cfg.RunningContext.PodNamespace + ".svc.cluster.local"
@@ -90,15 +205,31 @@ func NewXDSProvisioner(cfg *config.Config) (provisioner.Provisioner, error) {
 		UserAgentName: fmt.Sprintf("apisix-mesh-agent/%s", version.Short()),
 	}
 	return &grpcProvisioner{
-		node:                node,
-		configSource:        cs,
-		logger:              logger,
-		evChan:              make(chan []types.Event),
-		v3Adaptor:           adapter,
-		sendCh:              make(chan *discoveryv3.DiscoveryRequest),
-		recvCh:              make(chan *discoveryv3.DiscoveryResponse),
-		upstreams:           make(map[string]*apisix.Upstream),
-		edsRequiredClusters: make(map[string]struct{}),
+		node:         node,
+		configSource: cs,
+		credentials:  creds,
+		logger:       logger,
+		evChan:       make(chan []types.Event),
+		v3Adaptor:    adapter,
+		sendCh:       make(chan *discoveryv3.DiscoveryRequest),
+		recvCh:       make(chan *discoveryv3.DiscoveryResponse),
+		translationState: translationState{
+			upstreams:             make(map[string]*apisix.Upstream),
+			edsRequiredClusters:   make(map[string]struct{}),
+			edsServiceNames:       make(map[string]string),
+			hashPolicies:          make(map[string]*xdsv3.HashPolicy),
+			retryPolicies:         make(map[string]*xdsv3.RetryPolicy),
+			statsTrackedUpstreams: make(set.StringSet),
+			connectionLimits:      make(map[string]*apisix.LimitConn),
+		},
+		provenanceLabels:    cfg.ProvenanceLabels,
+		diffIgnoredFields:   cfg.DiffIgnoredFields,
+		eventOrder:          cfg.EventOrder,
+		sharedPluginConfigs: cfg.SharedPluginConfigs,
+		protected:           util.NewProtectedSet(cfg.ProtectedResources),
+		heartbeatInterval:   cfg.HeartbeatInterval,
+		ackedVersions:       make(map[string]string),
+		nonces:              make(map[string]string),
 	}, nil
 }
 
@@ -106,13 +237,42 @@ func (p *grpcProvisioner) Channel() <-chan []types.Event {
 	return p.evChan
 }
 
+// Ready returns whether a snapshot (both listeners and clusters) from the
+// control plane was accepted since the stream last failed.
+func (p *grpcProvisioner) Ready() bool {
+	return atomic.LoadInt32(&p.accepted) == _listenersAccepted|_clustersAccepted
+}
+
+// ReloadCerts re-reads the client certificate, private key and the CA
+// certificate, the reconnections to the config source use them.
+func (p *grpcProvisioner) ReloadCerts() (time.Time, error) {
+	if p.credentials == nil {
+		return time.Time{}, provisioner.ErrNoClientCerts
+	}
+	notAfter, err := p.credentials.reload()
+	if err != nil {
+		p.logger.Errorw("failed to reload client certificates, the last ones are kept",
+			zap.Error(err),
+		)
+		return time.Time{}, err
+	}
+	p.logger.Infow("reloaded client certificates",
+		zap.Time("not_after", notAfter),
+	)
+	return notAfter, nil
+}
+
 func (p *grpcProvisioner) Run(stop chan struct{}) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	defer close(p.evChan)
 
+	transport := grpcp.WithInsecure()
+	if p.credentials != nil {
+		transport = grpcp.WithTransportCredentials(p.credentials)
+	}
 	conn, err := grpcp.DialContext(ctx, p.configSource,
-		grpcp.WithInsecure(),
+		transport,
 		grpcp.WithBlock(),
 	)
 	if err != nil {
@@ -156,9 +316,10 @@ func (p *grpcProvisioner) firstSend() {
 	p.logger.Debugw("sent initial discovery requests for listeners and clusters")
 }
 
-// sendLoop receives pending DiscoveryRequest objects and sends them to client.
-// Send operation will be retried continuously until successful or the context is
-// cancelled.
+// sendLoop receives pending DiscoveryRequest objects and sends them to client
+// one by one, since Send is not safe to be called concurrently, and requests
+// (like the ACK of a response and the EDS/RDS requests after it) must reach
+// the control plane in order.
 func (p *grpcProvisioner) sendLoop(ctx context.Context, client discoveryv3.AggregatedDiscoveryService_StreamAggregatedResourcesClient) {
 	for {
 		select {
@@ -168,14 +329,12 @@ func (p *grpcProvisioner) sendLoop(ctx context.Context, client discoveryv3.Aggre
 			p.logger.Debugw("sending discovery request",
 				zap.Any("body", dr),
 			)
-			go func(dr *discoveryv3.DiscoveryRequest) {
-				if err := client.Send(dr); err != nil {
-					p.logger.Errorw("failed to send discovery request",
-						zap.Error(err),
-						zap.String("config_source", p.configSource),
-					)
-				}
-			}(dr)
+			if err := client.Send(dr); err != nil {
+				p.logger.Errorw("failed to send discovery request",
+					zap.Error(err),
+					zap.String("config_source", p.configSource),
+				)
+			}
 		}
 	}
 }
@@ -193,6 +352,9 @@ func (p *grpcProvisioner) recvLoop(ctx context.Context, client discoveryv3.Aggre
 				p.logger.Errorw("failed to receive discovery response",
 					zap.Error(err),
 				)
+				// The configuration might be stale until the listeners
+				// and clusters are accepted again.
+				atomic.StoreInt32(&p.accepted, 0)
 				continue
 			}
 		}
@@ -210,14 +372,27 @@ func (p *grpcProvisioner) recvLoop(ctx context.Context, client discoveryv3.Aggre
 }
 
 // translateLoop mediates the input DiscoveryResponse objects, translating
-// them APISIX resources, and generating an ACK request ultimately.
+// them APISIX resources, and generating an ACK (or NACK if the translation
+// failed) request ultimately.
 func (p *grpcProvisioner) translateLoop(ctx context.Context) {
-	var verInfo string
+	// Heartbeats are emitted by the translation loop, so they stop once
+	// it's stalled.
+	heartbeat, stopHeartbeat := util.HeartbeatTicker(p.heartbeatInterval)
+	defer stopHeartbeat()
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-heartbeat:
+			select {
+			case p.evChan <- util.HeartbeatEvents():
+			case <-ctx.Done():
+				return
+			}
 		case resp := <-p.recvCh:
+			// The nonce is recorded before the translation, so that
+			// the EDS/RDS requests sent by it carry the latest one.
+			p.nonces[resp.TypeUrl] = resp.Nonce
 			ackReq := &discoveryv3.DiscoveryRequest{
 				Node:          p.node,
 				TypeUrl:       resp.TypeUrl,
@@ -234,70 +409,203 @@ func (p *grpcProvisioner) translateLoop(ctx context.Context) {
 				}
 			}
 			if err := p.translate(resp); err != nil {
+				// NACK, the version stays at the last accepted one of
+				// the same type.
+				p.logger.Warnw("rejecting discovery response",
+					zap.Error(err),
+					zap.String("type", resp.TypeUrl),
+					zap.String("version", resp.VersionInfo),
+					zap.String("nonce", resp.Nonce),
+				)
 				ackReq.ErrorDetail = &status.Status{
 					Code:    int32(code.Code_INVALID_ARGUMENT),
 					Message: err.Error(),
 				}
 			} else {
-				verInfo = resp.VersionInfo
+				p.ackedVersions[resp.TypeUrl] = resp.VersionInfo
+				p.markAccepted(resp.TypeUrl)
 			}
-			ackReq.VersionInfo = verInfo
+			ackReq.VersionInfo = p.ackedVersions[resp.TypeUrl]
 			p.sendCh <- ackReq
 		}
 	}
 }
 
+// markAccepted records the response of the type url is accepted, the
+// provisioner is ready once both the listeners and clusters are accepted,
+// see Ready.
+func (p *grpcProvisioner) markAccepted(typeUrl string) {
+	var bit int32
+	switch typeUrl {
+	case types.ListenerUrl:
+		bit = _listenersAccepted
+	case types.ClusterUrl:
+		bit = _clustersAccepted
+	default:
+		return
+	}
+	for {
+		old := atomic.LoadInt32(&p.accepted)
+		if old&bit != 0 || atomic.CompareAndSwapInt32(&p.accepted, old, old|bit) {
+			return
+		}
+	}
+}
+
+// translate translates the response on a scratch copy of the state, which
+// is only kept if the response is accepted, so a rejected (NACKed) response
+// leaves the state as it was.
 func (p *grpcProvisioner) translate(resp *discoveryv3.DiscoveryResponse) error {
+	accepted := p.translationState
+	p.translationState = accepted.clone()
+	events, err := p.translateResponse(resp)
+	if err != nil {
+		p.translationState = accepted
+		return err
+	}
+	go func() {
+		p.evChan <- events
+	}()
+	return nil
+}
+
+// checkTrafficSplitReferences drops the traffic-split legs which refer to
+// unknown upstreams, the rest of the split keeps working. Since clusters
+// are always sent before routes by ADS, upstreams should be known already.
+func (p *grpcProvisioner) checkTrafficSplitReferences(routes []*apisix.Route) {
+	known := make(set.StringSet, len(p.upstreams))
+	for _, ups := range p.upstreams {
+		known.Add(ups.Id)
+	}
+	missing := util.DropMissingTrafficSplitUpstreams(routes, func(id string) bool {
+		_, ok := known[id]
+		return ok
+	})
+	for route, ids := range missing {
+		p.logger.Warnw("drop traffic-split legs referring to unknown upstreams",
+			zap.String("route", route),
+			zap.Strings("upstream_ids", ids),
+		)
+	}
+}
+
+// checkUpstreamIDCollisions reports the clusters which are translated to
+// upstreams with the same id.
+func (p *grpcProvisioner) checkUpstreamIDCollisions(upstreams []*apisix.Upstream) {
+	for id, clusters := range util.UpstreamIDCollisions(upstreams) {
+		p.logger.Errorw("clusters are translated to the same upstream id, they overwrite each other",
+			zap.String("upstream_id", id),
+			zap.Strings("clusters", clusters),
+		)
+	}
+}
+
+// translateResponse translates the DiscoveryResponse to APISIX resources
+// and generates events according to the last state.
+func (p *grpcProvisioner) translateResponse(resp *discoveryv3.DiscoveryResponse) ([]types.Event, error) {
 	var (
 		// Since the type url is fixed, only one field is filled in m and o.
 		m      util.Manifest
 		o      util.Manifest
 		events []types.Event
 	)
+	if resources, dups := xdsv3.DedupResources(resp.GetResources()); len(dups) > 0 {
+		for typeUrl, names := range dups {
+			p.logger.Warnw("found resources with duplicated names in the discovery response, only the last ones are kept",
+				zap.String("type", typeUrl),
+				zap.Strings("names", names),
+			)
+		}
+		resp = proto.Clone(resp).(*discoveryv3.DiscoveryResponse)
+		resp.Resources = resources
+	}
 	// As we use ADS, the TypeUrl field indicates the resource type already.
 	switch resp.GetTypeUrl() {
 	case types.RouteConfigurationUrl:
+		p.hashPolicies = make(map[string]*xdsv3.HashPolicy)
+		p.retryPolicies = make(map[string]*xdsv3.RetryPolicy)
 		for _, res := range resp.GetResources() {
 			partial, err := p.processRouteConfigurationV3(res)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			m.Routes = append(m.Routes, partial...)
 		}
 		if p.staticRouteConfigurations != nil {
 			partial, err := p.processStaticRouteConfigurations(p.staticRouteConfigurations)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			m.Routes = append(m.Routes, partial...)
 		}
+		p.checkTrafficSplitReferences(m.Routes)
+		util.EnablePrometheusForTrackedUpstreams(m.Routes, func(id string) bool {
+			_, ok := p.statsTrackedUpstreams[id]
+			return ok
+		})
+		util.LimitConnectionsForUpstreams(m.Routes, func(id string) *apisix.LimitConn {
+			return p.connectionLimits[id]
+		})
+		if p.sharedPluginConfigs {
+			m.PluginConfigs = util.ExtractPluginConfigs("", m.Routes)
+		}
 		o.Routes = p.routes
 		p.routes = m.Routes
+		o.PluginConfigs = p.pluginConfigs
+		p.pluginConfigs = m.PluginConfigs
+		// Hash and retry policies are configured on routes, once they are
+		// changed, the corresponding upstreams should be updated.
+		for name, ups := range p.upstreams {
+			// Do not modify the original ups to avoid race conditions.
+			newUps := proto.Clone(ups).(*apisix.Upstream)
+			if newUps.Type == "chash" {
+				hp, ok := p.hashPolicies[name]
+				if !ok {
+					hp = &xdsv3.DefaultHashPolicy
+				}
+				hp.PatchUpstream(newUps)
+			}
+			rp, ok := p.retryPolicies[name]
+			if !ok {
+				rp = &xdsv3.RetryPolicy{}
+			}
+			rp.PatchUpstream(newUps)
+			if proto.Equal(ups, newUps) {
+				continue
+			}
+			p.upstreams[name] = newUps
+			o.Upstreams = append(o.Upstreams, ups)
+			m.Upstreams = append(m.Upstreams, newUps)
+		}
 
 	case types.ClusterUrl:
 		newUps := make(map[string]*apisix.Upstream)
 		oldEdsRquiredClusters := p.edsRequiredClusters
 		p.edsRequiredClusters = set.StringSet{}
+		p.edsServiceNames = make(map[string]string)
+		p.statsTrackedUpstreams = set.StringSet{}
+		p.connectionLimits = make(map[string]*apisix.LimitConn)
 		for _, res := range resp.GetResources() {
 			ups, err := p.processClusterV3(res)
 			if err != nil {
 				if err == xdsv3.ErrFeatureNotSupportedYet {
 					p.logger.Warnw("failed to translate Cluster to APISIX upstreams",
 						zap.Error(err),
-						zap.Any("cluster", res),
+						zap.Any("cluster", redactClusterResource(res)),
 					)
 					continue
 				} else {
 					p.logger.Errorw("failed to translate Cluster to APISIX upstreams",
 						zap.Error(err),
-						zap.Any("cluster", res),
+						zap.Any("cluster", redactClusterResource(res)),
 					)
-					return err
+					return nil, err
 				}
 			}
 			m.Upstreams = append(m.Upstreams, ups)
 			newUps[ups.Name] = ups
 		}
+		p.checkUpstreamIDCollisions(m.Upstreams)
 		// TODO Refactor util.Manifest to just use map.
 		for _, ups := range p.upstreams {
 			o.Upstreams = append(o.Upstreams, ups)
@@ -314,7 +622,7 @@ func (p *grpcProvisioner) translate(resp *discoveryv3.DiscoveryResponse) error {
 		for _, res := range resp.GetResources() {
 			ups, err := p.processClusterLoadAssignmentV3(res)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			p.upstreams[ups.Name] = ups
 			m.Upstreams = append(m.Upstreams, ups)
@@ -325,6 +633,7 @@ func (p *grpcProvisioner) translate(resp *discoveryv3.DiscoveryResponse) error {
 			staticConfigs []*routev3.RouteConfiguration
 		)
 		routeOwnership := make(map[string]string)
+		routeHTTPFilters := make(map[string][]*hcmv3.HttpFilter)
 		for _, res := range resp.GetResources() {
 			var listener listenerv3.Listener
 			if err := anypb.UnmarshalTo(res, &listener, proto.UnmarshalOptions{}); err != nil {
@@ -332,18 +641,23 @@ func (p *grpcProvisioner) translate(resp *discoveryv3.DiscoveryResponse) error {
 					zap.Error(err),
 					zap.Any("response", res),
 				)
-				return err
+				return nil, err
 			}
-			sockAddr := listener.Address.GetSocketAddress()
-			if sockAddr == nil || sockAddr.GetPortValue() == 0 {
-				// Only use listener which listens on socket.
-				// TODO Support named port.
-				continue
+			// Listeners with api_listener (for gRPC xDS clients) don't
+			// have addresses, so the routes are not bound to any address.
+			var addr string
+			if listener.GetApiListener() == nil {
+				sockAddr := listener.Address.GetSocketAddress()
+				if sockAddr == nil || sockAddr.GetPortValue() == 0 {
+					// Only use listener which listens on socket.
+					// TODO Support named port.
+					continue
+				}
+				addr = fmt.Sprintf("%s:%d", sockAddr.GetAddress(), sockAddr.GetPortValue())
 			}
-			addr := fmt.Sprintf("%s:%d", sockAddr.GetAddress(), sockAddr.GetPortValue())
 			names, cfgs, err := p.v3Adaptor.CollectRouteNamesAndConfigs(&listener)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			rdsNames = append(rdsNames, names...)
 			staticConfigs = append(staticConfigs, cfgs...)
@@ -353,12 +667,27 @@ func (p *grpcProvisioner) translate(resp *discoveryv3.DiscoveryResponse) error {
 			for _, cfg := range cfgs {
 				routeOwnership[cfg.GetName()] = addr
 			}
+			filters, err := p.v3Adaptor.CollectRouteHTTPFilters(&listener)
+			if err != nil {
+				return nil, err
+			}
+			for name, f := range filters {
+				routeHTTPFilters[name] = f
+			}
+			consumers, err := p.v3Adaptor.CollectConsumers(&listener)
+			if err != nil {
+				return nil, err
+			}
+			m.Consumers = util.MergeConsumers(m.Consumers, consumers)
 		}
+		o.Consumers = p.consumers
+		p.consumers = m.Consumers
 		p.staticRouteConfigurations = staticConfigs
 		p.routeOwnership = routeOwnership
+		p.routeHTTPFilters = routeHTTPFilters
 		p.trySendRds(rdsNames)
 	default:
-		return _errUnknownResourceTypeUrl
+		return nil, _errUnknownResourceTypeUrl
 	}
 
 	// Always generate update event for EDS.
@@ -372,10 +701,7 @@ func (p *grpcProvisioner) translate(resp *discoveryv3.DiscoveryResponse) error {
 	} else {
 		events = p.generateEvents(&m, &o)
 	}
-	go func() {
-		p.evChan <- events
-	}()
-	return nil
+	return util.DropProtectedEvents(events, p.protected), nil
 }
 
 func (p *grpcProvisioner) generateEvents(m, o *util.Manifest) []types.Event {
@@ -394,7 +720,7 @@ func (p *grpcProvisioner) generateEvents(m, o *util.Manifest) []types.Event {
 	} else if m == nil {
 		deleted = o
 	} else {
-		added, deleted, updated = o.DiffFrom(m)
+		added, deleted, updated = o.DiffFrom(m, p.diffIgnoredFields...)
 	}
 	p.logger.Debugw("found changes (after converting to APISIX resources)",
 		zap.Any("added", added),
@@ -414,17 +740,10 @@ func (p *grpcProvisioner) generateEvents(m, o *util.Manifest) []types.Event {
 	if count == 0 {
 		return nil
 	}
-	events := make([]types.Event, 0, count)
-	if added != nil {
-		events = append(events, added.Events(types.EventAdd)...)
-	}
-	if deleted != nil {
-		events = append(events, deleted.Events(types.EventDelete)...)
-	}
-	if updated != nil {
-		events = append(events, updated.Events(types.EventUpdate)...)
-	}
-	return events
+	p.logger.Infow("applied changes from xds config source",
+		zap.String("summary", util.Summarize(added, deleted, updated)),
+	)
+	return util.OrderedEvents(added, deleted, updated, p.eventOrder)
 }
 
 func (p *grpcProvisioner) sendEds() {
@@ -434,8 +753,10 @@ func (p *grpcProvisioner) sendEds() {
 		return
 	}
 	dr := &discoveryv3.DiscoveryRequest{
-		Node:    p.node,
-		TypeUrl: types.ClusterLoadAssignmentUrl,
+		Node:          p.node,
+		TypeUrl:       types.ClusterLoadAssignmentUrl,
+		VersionInfo:   p.ackedVersions[types.ClusterLoadAssignmentUrl],
+		ResponseNonce: p.nonces[types.ClusterLoadAssignmentUrl],
 	}
 	for name := range p.edsRequiredClusters {
 		dr.ResourceNames = append(dr.ResourceNames, name)
@@ -454,6 +775,8 @@ func (p *grpcProvisioner) trySendRds(rdsNames []string) {
 		Node:          p.node,
 		ResourceNames: rdsNames,
 		TypeUrl:       types.RouteConfigurationUrl,
+		VersionInfo:   p.ackedVersions[types.RouteConfigurationUrl],
+		ResponseNonce: p.nonces[types.RouteConfigurationUrl],
 	}
 	p.logger.Debugw("sending RDS discovery request",
 		zap.Any("body", dr),
@@ -95,6 +95,7 @@ type fakeClient struct {
 	ctx    context.Context
 	sendCh chan *discoveryv3.DiscoveryRequest
 	recvCh chan *discoveryv3.DiscoveryResponse
+	errCh  chan error
 }
 
 func (f *fakeClient) Send(r *discoveryv3.DiscoveryRequest) error {
@@ -107,8 +108,12 @@ func (f *fakeClient) Send(r *discoveryv3.DiscoveryRequest) error {
 }
 
 func (f *fakeClient) Recv() (*discoveryv3.DiscoveryResponse, error) {
-	resp := <-f.recvCh
-	return resp, nil
+	select {
+	case resp := <-f.recvCh:
+		return resp, nil
+	case err := <-f.errCh:
+		return nil, err
+	}
 }
 
 func (f *fakeClient) Header() (metadata.MD, error) {
@@ -262,6 +267,88 @@ func TestTranslateLoop(t *testing.T) {
 	assert.Equal(t, ack.VersionInfo, "111")
 	assert.Equal(t, ack.TypeUrl, types.ClusterUrl)
 	assert.NotNil(t, ack.Node)
+	// Listeners are not accepted yet.
+	assert.False(t, gp.Ready())
+
+	// Versions are tracked per type url, the NACK carries the last
+	// accepted version of its own type.
+	gp.recvCh <- &discoveryv3.DiscoveryResponse{
+		VersionInfo: "222",
+		Nonce:       "nonce-1",
+		TypeUrl:     "type.googleapis.com/envoy.config.unknown.v3.Unknown",
+	}
+	nack := <-gp.sendCh
+	assert.NotNil(t, nack.ErrorDetail)
+	assert.Equal(t, nack.ErrorDetail.Message, "unknown resource type url")
+	assert.Equal(t, nack.VersionInfo, "")
+	assert.Equal(t, nack.ResponseNonce, "nonce-1")
+
+	resp.VersionInfo = "112"
+	resp.Nonce = "nonce-2"
+	gp.recvCh <- resp
+	ack = <-gp.sendCh
+	assert.Nil(t, ack.ErrorDetail)
+	assert.Equal(t, ack.VersionInfo, "112")
+	assert.Equal(t, ack.ResponseNonce, "nonce-2")
+	assert.False(t, gp.Ready())
+
+	gp.recvCh <- &discoveryv3.DiscoveryResponse{
+		VersionInfo: "1",
+		TypeUrl:     types.ListenerUrl,
+	}
+	ack = <-gp.sendCh
+	assert.Nil(t, ack.ErrorDetail)
+	assert.True(t, gp.Ready())
+}
+
+func TestReadinessResetOnStreamFailure(t *testing.T) {
+	cfg := &config.Config{
+		RunId:           "12345",
+		LogLevel:        "info",
+		LogOutput:       "stderr",
+		Provisioner:     "xds-v3-grpc",
+		XDSConfigSource: "grpc://127.0.0.1:11111",
+		RunningContext: &config.RunningContext{
+			PodNamespace: "default",
+			IPAddress:    "1.1.1.1",
+		},
+	}
+	p, err := NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	gp := p.(*grpcProvisioner)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &fakeClient{
+		sendCh: make(chan *discoveryv3.DiscoveryRequest),
+		recvCh: make(chan *discoveryv3.DiscoveryResponse),
+		errCh:  make(chan error),
+	}
+	go gp.recvLoop(ctx, client)
+	go gp.translateLoop(ctx)
+
+	accept := func(typeUrl string) {
+		gp.recvCh <- &discoveryv3.DiscoveryResponse{
+			VersionInfo: "1",
+			TypeUrl:     typeUrl,
+		}
+		ack := <-gp.sendCh
+		assert.Nil(t, ack.ErrorDetail)
+	}
+	accept(types.ListenerUrl)
+	accept(types.ClusterUrl)
+	assert.True(t, gp.Ready())
+
+	client.errCh <- errors.New("stream broken")
+	assert.Eventually(t, func() bool {
+		return !gp.Ready()
+	}, time.Second, 10*time.Millisecond)
+
+	// Both listeners and clusters have to be accepted again.
+	accept(types.ListenerUrl)
+	assert.False(t, gp.Ready())
+	accept(types.ClusterUrl)
+	assert.True(t, gp.Ready())
 }
 
 func TestTranslate(t *testing.T) {
@@ -417,6 +504,68 @@ func TestTranslate(t *testing.T) {
 	assert.Equal(t, evs[0].Object.(*apisix.Upstream).Nodes[0].Port, int32(8000))
 }
 
+func TestTranslateRejectedResponse(t *testing.T) {
+	cfg := &config.Config{
+		RunId:           "12345",
+		LogLevel:        "info",
+		LogOutput:       "stderr",
+		Provisioner:     "xds-v3-grpc",
+		XDSConfigSource: "grpc://127.0.0.1:11111",
+		RunningContext: &config.RunningContext{
+			PodNamespace: "default",
+			IPAddress:    "1.1.1.1",
+		},
+	}
+	p, err := NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	gp := p.(*grpcProvisioner)
+	gp.sendCh = make(chan *discoveryv3.DiscoveryRequest, 1)
+
+	c1 := &clusterv3.Cluster{
+		Name: "httpbin.default.svc.cluster.local",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_EDS,
+		},
+	}
+	c2 := &clusterv3.Cluster{
+		Name: "nginx.default.svc.cluster.local",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_EDS,
+		},
+	}
+	val1, err := proto.Marshal(c1)
+	assert.Nil(t, err)
+	val2, err := proto.Marshal(c2)
+	assert.Nil(t, err)
+
+	err = gp.translate(&discoveryv3.DiscoveryResponse{
+		VersionInfo: "1",
+		TypeUrl:     types.ClusterUrl,
+		Resources: []*any.Any{
+			{TypeUrl: types.ClusterUrl, Value: val1},
+		},
+	})
+	assert.Nil(t, err)
+	<-gp.evChan
+	<-gp.sendCh
+
+	// The second cluster is translated before the broken one, but
+	// nothing of the rejected response is kept.
+	err = gp.translate(&discoveryv3.DiscoveryResponse{
+		VersionInfo: "2",
+		TypeUrl:     types.ClusterUrl,
+		Resources: []*any.Any{
+			{TypeUrl: types.ClusterUrl, Value: val2},
+			{TypeUrl: types.ClusterUrl, Value: []byte{0xff}},
+		},
+	})
+	assert.NotNil(t, err)
+	assert.Len(t, gp.upstreams, 1)
+	assert.Contains(t, gp.upstreams, "httpbin.default.svc.cluster.local")
+	assert.Equal(t, gp.edsRequiredClusters.Strings(), []string{"httpbin.default.svc.cluster.local"})
+	assert.Len(t, gp.sendCh, 0)
+}
+
 type fakeXdsServer struct {
 	t      *testing.T
 	ctx    context.Context
@@ -575,6 +724,8 @@ func TestSendEds(t *testing.T) {
 
 	gp.edsRequiredClusters["outbound|15010||istiod.istio-system.svc.cluster.local"] = struct{}{}
 	gp.edsRequiredClusters["outbound|80||nginx.default.svc.cluster.local"] = struct{}{}
+	gp.ackedVersions[types.ClusterLoadAssignmentUrl] = "3"
+	gp.nonces[types.ClusterLoadAssignmentUrl] = "nonce-3"
 
 	go func() {
 		gp.sendEds()
@@ -589,6 +740,8 @@ func TestSendEds(t *testing.T) {
 		sort.Strings(dr.ResourceNames)
 		assert.Equal(t, dr.ResourceNames[0], "outbound|15010||istiod.istio-system.svc.cluster.local")
 		assert.Equal(t, dr.ResourceNames[1], "outbound|80||nginx.default.svc.cluster.local")
+		assert.Equal(t, dr.VersionInfo, "3")
+		assert.Equal(t, dr.ResponseNonce, "nonce-3")
 	}
 }
 
@@ -711,4 +864,330 @@ func TestTranslateListener(t *testing.T) {
 	assert.Equal(t, dr.TypeUrl, types.RouteConfigurationUrl)
 	assert.Len(t, dr.ResourceNames, 1)
 	assert.Equal(t, dr.ResourceNames[0], "route1")
+	assert.Equal(t, gp.routeOwnership["route1"], "10.0.5.3:8080")
+
+	// Listeners with api_listener have no addresses.
+	li = &listenerv3.Listener{
+		Name: "listener2",
+		ApiListener: &listenerv3.ApiListener{
+			ApiListener: &any1,
+		},
+	}
+	val2, err := proto.Marshal(li)
+	assert.Nil(t, err)
+	dr1.Resources[0].Value = val2
+	err = gp.translate(dr1)
+	assert.Nil(t, err)
+	select {
+	case dr = <-gp.sendCh:
+		break
+	case <-time.After(time.Second):
+		assert.FailNow(t, "DiscoveryRequest was not sent in time")
+	}
+	assert.Equal(t, dr.TypeUrl, types.RouteConfigurationUrl)
+	assert.Equal(t, dr.ResourceNames, []string{"route1"})
+	addr, ok := gp.routeOwnership["route1"]
+	assert.True(t, ok)
+	assert.Equal(t, addr, "")
+}
+
+func TestTranslateStaticRouteConfigurations(t *testing.T) {
+	cfg := &config.Config{
+		RunId:           "12345",
+		LogLevel:        "info",
+		LogOutput:       "stderr",
+		Provisioner:     "xds-v3-grpc",
+		XDSConfigSource: "grpc://127.0.0.1:11111",
+		RunningContext: &config.RunningContext{
+			PodNamespace: "default",
+			IPAddress:    "1.1.1.1",
+		},
+	}
+	p, err := NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	gp := p.(*grpcProvisioner)
+	gp.sendCh = make(chan *discoveryv3.DiscoveryRequest, 1)
+
+	// The route configuration is inline in the listener.
+	var hcm anypb.Any
+	assert.Nil(t, anypb.MarshalFrom(&hcm, &hcmv3.HttpConnectionManager{
+		RouteSpecifier: &hcmv3.HttpConnectionManager_RouteConfig{
+			RouteConfig: &routev3.RouteConfiguration{
+				Name: "static",
+				VirtualHosts: []*routev3.VirtualHost{
+					{
+						Name:    "vhost1",
+						Domains: []string{"*"},
+						Routes: []*routev3.Route{
+							{
+								Name: "route1",
+								Match: &routev3.RouteMatch{
+									PathSpecifier: &routev3.RouteMatch_Prefix{
+										Prefix: "/",
+									},
+								},
+								Action: &routev3.Route_Route{
+									Route: &routev3.RouteAction{
+										ClusterSpecifier: &routev3.RouteAction_Cluster{
+											Cluster: "httpbin.default.svc.cluster.local",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, proto.MarshalOptions{}))
+	li := &listenerv3.Listener{
+		Name: "listener1",
+		Address: &corev3.Address{
+			Address: &corev3.Address_SocketAddress{
+				SocketAddress: &corev3.SocketAddress{
+					Address: "10.0.5.3",
+					PortSpecifier: &corev3.SocketAddress_PortValue{
+						PortValue: 8080,
+					},
+				},
+			},
+		},
+		FilterChains: []*listenerv3.FilterChain{
+			{
+				Filters: []*listenerv3.Filter{
+					{
+						Name: xdswellknown.HTTPConnectionManager,
+						ConfigType: &listenerv3.Filter_TypedConfig{
+							TypedConfig: &hcm,
+						},
+					},
+				},
+			},
+		},
+	}
+	val, err := proto.Marshal(li)
+	assert.Nil(t, err)
+	_, err = gp.translateResponse(&discoveryv3.DiscoveryResponse{
+		VersionInfo: "1",
+		TypeUrl:     types.ListenerUrl,
+		Resources: []*any.Any{
+			{
+				TypeUrl: types.ListenerUrl,
+				Value:   val,
+			},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, gp.staticRouteConfigurations, 1)
+
+	// Routes of the static route configurations are translated along
+	// with the RDS ones.
+	events, err := gp.translateResponse(&discoveryv3.DiscoveryResponse{
+		VersionInfo: "1",
+		TypeUrl:     types.RouteConfigurationUrl,
+	})
+	assert.Nil(t, err)
+	var routes []*apisix.Route
+	for _, ev := range events {
+		if r, ok := ev.Object.(*apisix.Route); ok {
+			routes = append(routes, r)
+		}
+	}
+	assert.Len(t, routes, 1)
+	assert.Len(t, gp.routes, 1)
+}
+
+func TestTranslateHashPolicy(t *testing.T) {
+	cfg := &config.Config{
+		RunId:           "12345",
+		LogLevel:        "info",
+		LogOutput:       "stderr",
+		Provisioner:     "xds-v3-grpc",
+		XDSConfigSource: "grpc://127.0.0.1:11111",
+		RunningContext: &config.RunningContext{
+			PodNamespace: "default",
+			IPAddress:    "1.1.1.1",
+		},
+	}
+	p, err := NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	gp := p.(*grpcProvisioner)
+
+	c := &clusterv3.Cluster{
+		Name: "httpbin.default.svc.cluster.local",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_STATIC,
+		},
+		LbPolicy: clusterv3.Cluster_RING_HASH,
+	}
+	rc := &routev3.RouteConfiguration{
+		Name: "rc1",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name:    "vhost1",
+				Domains: []string{"*"},
+				Routes: []*routev3.Route{
+					{
+						Name: "route1",
+						Match: &routev3.RouteMatch{
+							PathSpecifier: &routev3.RouteMatch_Prefix{
+								Prefix: "/",
+							},
+						},
+						Action: &routev3.Route_Route{
+							Route: &routev3.RouteAction{
+								ClusterSpecifier: &routev3.RouteAction_Cluster{
+									Cluster: "httpbin.default.svc.cluster.local",
+								},
+								HashPolicy: []*routev3.RouteAction_HashPolicy{
+									{
+										PolicySpecifier: &routev3.RouteAction_HashPolicy_Cookie_{
+											Cookie: &routev3.RouteAction_HashPolicy_Cookie{
+												Name: "session",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	val1, err := proto.Marshal(c)
+	assert.Nil(t, err)
+	val2, err := proto.Marshal(rc)
+	assert.Nil(t, err)
+
+	err = gp.translate(&discoveryv3.DiscoveryResponse{
+		VersionInfo: "111",
+		TypeUrl:     types.ClusterUrl,
+		Resources: []*any.Any{
+			{
+				TypeUrl: types.ClusterUrl,
+				Value:   val1,
+			},
+		},
+	})
+	assert.Nil(t, err)
+	evs := <-gp.evChan
+	assert.Len(t, evs, 1)
+	assert.Equal(t, evs[0].Object.(*apisix.Upstream).Type, "chash")
+	assert.Equal(t, evs[0].Object.(*apisix.Upstream).Key, "remote_addr")
+
+	err = gp.translate(&discoveryv3.DiscoveryResponse{
+		VersionInfo: "111",
+		TypeUrl:     types.RouteConfigurationUrl,
+		Resources: []*any.Any{
+			{
+				TypeUrl: types.RouteConfigurationUrl,
+				Value:   val2,
+			},
+		},
+	})
+	assert.Nil(t, err)
+	evs = <-gp.evChan
+	assert.Len(t, evs, 2)
+	assert.Equal(t, evs[0].Type, types.EventAdd)
+	assert.Equal(t, evs[0].Object.(*apisix.Route).Name, "route1#vhost1#rc1")
+	assert.Equal(t, evs[1].Type, types.EventUpdate)
+	assert.Equal(t, evs[1].Object.(*apisix.Upstream).HashOn, "cookie")
+	assert.Equal(t, evs[1].Object.(*apisix.Upstream).Key, "session")
+}
+
+func TestTranslateRetryPolicy(t *testing.T) {
+	cfg := &config.Config{
+		RunId:           "12345",
+		LogLevel:        "info",
+		LogOutput:       "stderr",
+		Provisioner:     "xds-v3-grpc",
+		XDSConfigSource: "grpc://127.0.0.1:11111",
+		RunningContext: &config.RunningContext{
+			PodNamespace: "default",
+			IPAddress:    "1.1.1.1",
+		},
+	}
+	p, err := NewXDSProvisioner(cfg)
+	assert.Nil(t, err)
+	gp := p.(*grpcProvisioner)
+
+	c := &clusterv3.Cluster{
+		Name: "httpbin.default.svc.cluster.local",
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_STATIC,
+		},
+	}
+	route := &routev3.Route{
+		Name: "route1",
+		Match: &routev3.RouteMatch{
+			PathSpecifier: &routev3.RouteMatch_Prefix{
+				Prefix: "/",
+			},
+		},
+		Action: &routev3.Route_Route{
+			Route: &routev3.RouteAction{
+				ClusterSpecifier: &routev3.RouteAction_Cluster{
+					Cluster: "httpbin.default.svc.cluster.local",
+				},
+				RetryPolicy: &routev3.RetryPolicy{
+					RetryOn:    "5xx",
+					NumRetries: &wrappers.UInt32Value{Value: 3},
+				},
+			},
+		},
+	}
+	rc := &routev3.RouteConfiguration{
+		Name: "rc1",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name:    "vhost1",
+				Domains: []string{"*"},
+				Routes:  []*routev3.Route{route},
+			},
+		},
+	}
+	val, err := proto.Marshal(c)
+	assert.Nil(t, err)
+	err = gp.translate(&discoveryv3.DiscoveryResponse{
+		TypeUrl: types.ClusterUrl,
+		Resources: []*any.Any{
+			{
+				TypeUrl: types.ClusterUrl,
+				Value:   val,
+			},
+		},
+	})
+	assert.Nil(t, err)
+	evs := <-gp.evChan
+	assert.Len(t, evs, 1)
+	assert.Equal(t, evs[0].Object.(*apisix.Upstream).Retries, int32(0))
+
+	translateRouteConfiguration := func() []types.Event {
+		val, err := proto.Marshal(rc)
+		assert.Nil(t, err)
+		err = gp.translate(&discoveryv3.DiscoveryResponse{
+			TypeUrl: types.RouteConfigurationUrl,
+			Resources: []*any.Any{
+				{
+					TypeUrl: types.RouteConfigurationUrl,
+					Value:   val,
+				},
+			},
+		})
+		assert.Nil(t, err)
+		return <-gp.evChan
+	}
+	evs = translateRouteConfiguration()
+	assert.Len(t, evs, 2)
+	assert.Equal(t, evs[0].Type, types.EventAdd)
+	assert.Equal(t, evs[1].Type, types.EventUpdate)
+	assert.Equal(t, evs[1].Object.(*apisix.Upstream).Retries, int32(3))
+
+	// The retries are reset once the retry policy is removed.
+	route.GetRoute().RetryPolicy = nil
+	evs = translateRouteConfiguration()
+	assert.Len(t, evs, 1)
+	assert.Equal(t, evs[0].Type, types.EventUpdate)
+	assert.Equal(t, evs[0].Object.(*apisix.Upstream).Retries, int32(0))
 }
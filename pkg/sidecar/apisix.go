@@ -37,6 +37,8 @@ type apisixConfig struct {
 	NodeListen    int
 	GRPCListen    string
 	EtcdKeyPrefix string
+	// DNSResolverValid overrides the TTL of DNS records if it's not 0.
+	DNSResolverValid int
 }
 
 func (ar *apisixRunner) run(wg *sync.WaitGroup) error {
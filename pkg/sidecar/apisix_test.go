@@ -34,6 +34,14 @@ func TestConfigRender(t *testing.T) {
 	assert.Contains(t, string(data), "node_listen: 9080")
 	assert.Contains(t, string(data), "prefix: \"/apisix\"")
 	assert.Contains(t, string(data), "- \"http://127.0.0.1:2379\"")
+	assert.NotContains(t, string(data), "dns_resolver_valid")
+
+	ar.config.DNSResolverValid = 30
+	err = ar.renderConfig()
+	assert.Nil(t, err)
+	data, err = ioutil.ReadFile("./testdata/conf/config.yaml")
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "  node_listen: 9080\n  dns_resolver_valid: 30\n")
 }
 
 func TestApisixRunner(t *testing.T) {
@@ -32,6 +32,26 @@ func (s *Sidecar) reflectToCache(events []types.Event) {
 					zap.String("event", string(ev.Type)),
 				)
 				err = s.cache.Upstream().Insert(obj)
+			case *apisix.PluginConfig:
+				s.logger.Debugw("insert plugin config cache",
+					zap.Any("plugin_config", obj),
+					zap.String("event", string(ev.Type)),
+				)
+				err = s.cache.PluginConfig().Insert(obj)
+			case *apisix.Consumer:
+				s.logger.Debugw("insert consumer cache",
+					zap.Any("consumer", obj),
+					zap.String("event", string(ev.Type)),
+				)
+				err = s.cache.Consumer().Insert(obj)
+			case *apisix.Ssl:
+				// The private key is never logged.
+				s.logger.Debugw("insert ssl cache",
+					zap.String("ssl_id", obj.GetId()),
+					zap.Strings("snis", obj.GetSnis()),
+					zap.String("event", string(ev.Type)),
+				)
+				err = s.cache.Ssl().Insert(obj)
 			default:
 				err = _errUnknownEventObject
 			}
@@ -49,6 +69,24 @@ func (s *Sidecar) reflectToCache(events []types.Event) {
 					zap.String("event", string(ev.Type)),
 				)
 				err = s.cache.Upstream().Delete(obj.GetId())
+			case *apisix.PluginConfig:
+				s.logger.Debugw("delete plugin config cache",
+					zap.Any("plugin_config", obj),
+					zap.String("event", string(ev.Type)),
+				)
+				err = s.cache.PluginConfig().Delete(obj.GetId())
+			case *apisix.Consumer:
+				s.logger.Debugw("delete consumer cache",
+					zap.Any("consumer", obj),
+					zap.String("event", string(ev.Type)),
+				)
+				err = s.cache.Consumer().Delete(obj.GetUsername())
+			case *apisix.Ssl:
+				s.logger.Debugw("delete ssl cache",
+					zap.String("ssl_id", obj.GetId()),
+					zap.String("event", string(ev.Type)),
+				)
+				err = s.cache.Ssl().Delete(obj.GetId())
 			default:
 				err = _errUnknownEventObject
 			}
@@ -44,6 +44,24 @@ func TestReflectToCache(t *testing.T) {
 				Id: "21",
 			},
 		},
+		{
+			Type: types.EventAdd,
+			Object: &apisix.PluginConfig{
+				Id: "pc1",
+			},
+		},
+		{
+			Type: types.EventAdd,
+			Object: &apisix.Consumer{
+				Username: "alice",
+			},
+		},
+		{
+			Type: types.EventAdd,
+			Object: &apisix.Ssl{
+				Id: "s1",
+			},
+		},
 	}
 	err = s.cache.Upstream().Insert(&apisix.Upstream{Id: "21"})
 	assert.Nil(t, err)
@@ -63,4 +81,16 @@ func TestReflectToCache(t *testing.T) {
 	u2, err := s.cache.Upstream().Get("21")
 	assert.Nil(t, u2)
 	assert.Equal(t, err, cache.ErrObjectNotFound)
+
+	pc, err := s.cache.PluginConfig().Get("pc1")
+	assert.NotNil(t, pc)
+	assert.Nil(t, err)
+
+	c, err := s.cache.Consumer().Get("alice")
+	assert.NotNil(t, c)
+	assert.Nil(t, err)
+
+	ssl, err := s.cache.Ssl().Get("s1")
+	assert.NotNil(t, ssl)
+	assert.Nil(t, err)
 }
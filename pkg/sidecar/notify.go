@@ -0,0 +1,257 @@
+package sidecar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+const (
+	// _notifyQueueSize is the max number of notifications waiting to be
+	// sent, notifications exceeding it are dropped so that the delivery
+	// of events is never blocked by the webhook.
+	_notifyQueueSize = 64
+	// _notifyBackoff is the delay before the first retry, it's doubled
+	// for each retry.
+	_notifyBackoff = time.Second
+)
+
+// errNotifierStopped means the notification is interrupted since the
+// notifier is stopped.
+var errNotifierStopped = errors.New("notifier stopped")
+
+// notification is the summary of an applied batch of events, it's POSTed
+// to the notify webhook in JSON. The Source is the source (e.g. the file
+// or the ConfigMap key) which change caused the events, or the name of the
+// provisioner if it's unknown.
+type notification struct {
+	RunId   string       `json:"run_id"`
+	Source  string       `json:"source"`
+	Time    time.Time    `json:"time"`
+	Added   *resourceIds `json:"added"`
+	Updated *resourceIds `json:"updated"`
+	Deleted *resourceIds `json:"deleted"`
+}
+
+// resourceIds are the ids of the changed resources, grouped by the kind.
+type resourceIds struct {
+	Count         int      `json:"count"`
+	Routes        []string `json:"routes,omitempty"`
+	Upstreams     []string `json:"upstreams,omitempty"`
+	PluginConfigs []string `json:"plugin_configs,omitempty"`
+	Consumers     []string `json:"consumers,omitempty"`
+	Ssls          []string `json:"ssls,omitempty"`
+}
+
+func (ids *resourceIds) add(obj interface{}) {
+	switch obj := obj.(type) {
+	case *apisix.Route:
+		ids.Routes = append(ids.Routes, obj.GetId())
+	case *apisix.Upstream:
+		ids.Upstreams = append(ids.Upstreams, obj.GetId())
+	case *apisix.PluginConfig:
+		ids.PluginConfigs = append(ids.PluginConfigs, obj.GetId())
+	case *apisix.Consumer:
+		ids.Consumers = append(ids.Consumers, obj.GetUsername())
+	case *apisix.Ssl:
+		ids.Ssls = append(ids.Ssls, obj.GetId())
+	default:
+		return
+	}
+	ids.Count++
+}
+
+// webhookNotifier POSTs the summaries of the applied batches to the
+// webhook. Notifications are sent one by one in a dedicated goroutine,
+// so they keep the order of batches and never block the delivery. Once
+// it's stopped, the notification being sent is interrupted and the
+// pending ones are dropped, so that the shutdown is not delayed by the
+// webhook.
+type webhookNotifier struct {
+	url   string
+	runId string
+	// provisioner is reported as the source if the events don't tell it.
+	provisioner string
+	retries     int
+	backoff     time.Duration
+	client      *http.Client
+	logger      *log.Logger
+	queue       chan *notification
+	// ctx is canceled once the notifier is stopped.
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// newWebhookNotifier creates the webhookNotifier, nil is returned if the
+// notify webhook is not configured.
+func newWebhookNotifier(cfg *config.Config, logger *log.Logger) *webhookNotifier {
+	if cfg.NotifyWebhook == "" {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &webhookNotifier{
+		url:         cfg.NotifyWebhook,
+		runId:       cfg.RunId,
+		provisioner: cfg.Provisioner,
+		retries:     cfg.NotifyWebhookRetries,
+		backoff:     _notifyBackoff,
+		client: &http.Client{
+			Timeout: cfg.NotifyWebhookTimeout,
+		},
+		logger: logger,
+		queue:  make(chan *notification, _notifyQueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+}
+
+// run sends the queued notifications until the notifier is stopped.
+func (n *webhookNotifier) run() {
+	defer close(n.done)
+	var dropped int
+	for notif := range n.queue {
+		if n.ctx.Err() != nil {
+			dropped++
+			continue
+		}
+		if err := n.send(notif); err == errNotifierStopped {
+			dropped++
+		} else if err != nil {
+			n.logger.Errorw("failed to notify webhook",
+				zap.Error(err),
+				zap.String("url", n.url),
+				zap.Int("added", notif.Added.Count),
+				zap.Int("updated", notif.Updated.Count),
+				zap.Int("deleted", notif.Deleted.Count),
+			)
+		}
+	}
+	if dropped > 0 {
+		n.logger.Warnw("notifications dropped since the notifier is stopped",
+			zap.String("url", n.url),
+			zap.Int("notifications", dropped),
+		)
+	}
+}
+
+// stop interrupts the notification being sent (and its retries), drops the
+// pending ones and waits for the notifier to exit.
+func (n *webhookNotifier) stop() {
+	n.cancel()
+	close(n.queue)
+	<-n.done
+}
+
+// notify queues the summary of the events.
+func (n *webhookNotifier) notify(events []types.Event) {
+	if len(events) == 0 {
+		return
+	}
+	notif := n.summarize(events)
+	select {
+	case n.queue <- notif:
+	default:
+		n.logger.Warnw("notification dropped since the queue is full",
+			zap.String("url", n.url),
+			zap.Int("events", len(events)),
+		)
+	}
+}
+
+func (n *webhookNotifier) summarize(events []types.Event) *notification {
+	notif := &notification{
+		RunId:   n.runId,
+		Source:  n.provisioner,
+		Time:    time.Now().UTC(),
+		Added:   &resourceIds{},
+		Updated: &resourceIds{},
+		Deleted: &resourceIds{},
+	}
+	var (
+		sources []string
+		seen    = make(map[string]struct{})
+	)
+	for _, ev := range events {
+		if _, ok := seen[ev.Source]; ev.Source != "" && !ok {
+			seen[ev.Source] = struct{}{}
+			sources = append(sources, ev.Source)
+		}
+		switch ev.Type {
+		case types.EventAdd:
+			notif.Added.add(ev.Object)
+		case types.EventUpdate:
+			notif.Updated.add(ev.Object)
+		default: // types.EventDelete
+			notif.Deleted.add(ev.Tombstone)
+		}
+	}
+	if len(sources) > 0 {
+		notif.Source = strings.Join(sources, ",")
+	}
+	return notif
+}
+
+// send POSTs the notification, it's retried with the exponential backoff
+// if the request fails or the status code is not 2xx. errNotifierStopped
+// is returned if it's interrupted by the stop of the notifier.
+func (n *webhookNotifier) send(notif *notification) error {
+	data, err := json.Marshal(notif)
+	if err != nil {
+		return err
+	}
+	backoff := n.backoff
+	for i := 0; ; i++ {
+		err = n.post(data)
+		if err == nil {
+			return nil
+		}
+		if n.ctx.Err() != nil {
+			return errNotifierStopped
+		}
+		if i >= n.retries {
+			return err
+		}
+		n.logger.Warnw("failed to notify webhook, will retry",
+			zap.Error(err),
+			zap.String("url", n.url),
+			zap.Duration("backoff", backoff),
+		)
+		select {
+		case <-time.After(backoff):
+		case <-n.ctx.Done():
+			return errNotifierStopped
+		}
+		backoff *= 2
+	}
+}
+
+func (n *webhookNotifier) post(data []byte) error {
+	req, err := http.NewRequestWithContext(n.ctx, http.MethodPost, n.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
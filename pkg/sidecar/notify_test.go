@@ -0,0 +1,138 @@
+package sidecar
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/config"
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	assert.Nil(t, newWebhookNotifier(cfg, log.DefaultLogger))
+
+	var (
+		attempts  int32
+		received  []*notification
+		delivered = make(chan struct{})
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPost)
+		assert.Equal(t, r.Header.Get("Content-Type"), "application/json")
+		// The first attempt fails so it's retried.
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var notif notification
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&notif))
+		received = append(received, &notif)
+		close(delivered)
+	}))
+	defer srv.Close()
+
+	cfg.NotifyWebhook = srv.URL
+	n := newWebhookNotifier(cfg, log.DefaultLogger)
+	assert.NotNil(t, n)
+	n.backoff = time.Millisecond
+	go n.run()
+
+	n.notify(nil)
+	n.notify([]types.Event{
+		{Type: types.EventAdd, Object: &apisix.Route{Id: "1"}},
+		{Type: types.EventAdd, Object: &apisix.Upstream{Id: "2"}},
+		{Type: types.EventUpdate, Object: &apisix.PluginConfig{Id: "3"}},
+		{Type: types.EventDelete, Tombstone: &apisix.Route{Id: "4"}},
+	})
+	<-delivered
+	n.stop()
+
+	assert.Equal(t, atomic.LoadInt32(&attempts), int32(2))
+	assert.Len(t, received, 1)
+	assert.Equal(t, received[0].RunId, cfg.RunId)
+	assert.Equal(t, received[0].Source, config.XDSV3FileProvisioner)
+	assert.Equal(t, received[0].Added, &resourceIds{Count: 2, Routes: []string{"1"}, Upstreams: []string{"2"}})
+	assert.Equal(t, received[0].Updated, &resourceIds{Count: 1, PluginConfigs: []string{"3"}})
+	assert.Equal(t, received[0].Deleted, &resourceIds{Count: 1, Routes: []string{"4"}})
+
+	// The source of events is reported if it's known.
+	notif := n.summarize([]types.Event{
+		{Type: types.EventAdd, Object: &apisix.Route{Id: "1"}, Source: "/etc/xds/route.json"},
+		{Type: types.EventUpdate, Object: &apisix.Upstream{Id: "2"}, Source: "/etc/xds/route.json"},
+	})
+	assert.Equal(t, notif.Source, "/etc/xds/route.json")
+	notif = n.summarize([]types.Event{
+		{Type: types.EventAdd, Object: &apisix.Route{Id: "1"}, Source: "configmap/default/xds/a.json"},
+		{Type: types.EventAdd, Object: &apisix.Route{Id: "2"}, Source: "configmap/default/xds/b.json"},
+		{Type: types.EventAdd, Object: &apisix.Route{Id: "3"}, Source: "configmap/default/xds/a.json"},
+	})
+	assert.Equal(t, notif.Source, "configmap/default/xds/a.json,configmap/default/xds/b.json")
+}
+
+func TestWebhookNotifierRetriesExhausted(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	cfg := config.NewDefaultConfig()
+	cfg.NotifyWebhook = srv.URL
+	cfg.NotifyWebhookRetries = 2
+	n := newWebhookNotifier(cfg, log.DefaultLogger)
+	n.backoff = time.Millisecond
+
+	err := n.send(n.summarize([]types.Event{
+		{Type: types.EventAdd, Object: &apisix.Route{Id: "1"}},
+	}))
+	assert.NotNil(t, err)
+	assert.Equal(t, atomic.LoadInt32(&attempts), int32(3))
+}
+
+func TestWebhookNotifierStop(t *testing.T) {
+	attempted := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempted <- struct{}{}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	cfg := config.NewDefaultConfig()
+	cfg.NotifyWebhook = srv.URL
+	cfg.NotifyWebhookRetries = 3
+	n := newWebhookNotifier(cfg, log.DefaultLogger)
+	n.backoff = time.Hour
+	go n.run()
+
+	n.notify([]types.Event{
+		{Type: types.EventAdd, Object: &apisix.Route{Id: "1"}},
+	})
+	n.notify([]types.Event{
+		{Type: types.EventAdd, Object: &apisix.Route{Id: "2"}},
+	})
+	<-attempted
+
+	// The retry of the first notification is interrupted and the second
+	// one is dropped.
+	stopped := make(chan struct{})
+	go func() {
+		n.stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("notifier is not stopped in time")
+	}
+	assert.Len(t, attempted, 0)
+}
@@ -0,0 +1,119 @@
+package sidecar
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/api7/apisix-mesh-agent/pkg/cache"
+	"github.com/api7/apisix-mesh-agent/pkg/etcdv3"
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner"
+)
+
+// probeServer serves the liveness (/healthz) and readiness (/readyz)
+// probes, the readiness is the same as the one of the etcd server. It
+// also serves POST /reload-certs to reload the client certificates of
+// the provisioner on demand.
+type probeServer struct {
+	listener net.Listener
+	srv      *http.Server
+	cache    cache.Cache
+	reporter provisioner.ReadinessReporter
+	reloader provisioner.CertReloader
+	logger   *log.Logger
+}
+
+func newProbeServer(addr string, c cache.Cache, r provisioner.ReadinessReporter, cr provisioner.CertReloader, logger *log.Logger) (*probeServer, error) {
+	li, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	ps := &probeServer{
+		listener: li,
+		cache:    c,
+		reporter: r,
+		reloader: cr,
+		logger:   logger,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", ps.healthz)
+	mux.HandleFunc("/readyz", ps.readyz)
+	mux.HandleFunc("/reload-certs", ps.reloadCerts)
+	ps.srv = &http.Server{
+		Handler:      mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+	return ps, nil
+}
+
+func (ps *probeServer) serve() error {
+	if err := ps.srv.Serve(ps.listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (ps *probeServer) shutdown(ctx context.Context) error {
+	return ps.srv.Shutdown(ctx)
+}
+
+// healthz reports the process is alive, it always succeeds.
+func (ps *probeServer) healthz(w http.ResponseWriter, req *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("ok")); err != nil {
+		ps.logger.Warnw("failed to send liveness",
+			zap.Error(err),
+		)
+	}
+}
+
+// readyz reports whether the provisioner is ready and the cached
+// configuration has no dangling references, see etcdv3.CheckReadiness.
+func (ps *probeServer) readyz(w http.ResponseWriter, req *http.Request) {
+	etcdv3.WriteReadiness(w, etcdv3.CheckReadiness(ps.cache, ps.reporter), ps.logger)
+}
+
+// certsReloaded is the response of /reload-certs.
+type certsReloaded struct {
+	NotAfter *time.Time `json:"not_after,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// reloadCerts reloads the client certificates of the provisioner, the
+// expiry of the new certificate is responded.
+func (ps *probeServer) reloadCerts(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	var (
+		resp   certsReloaded
+		status = http.StatusOK
+	)
+	notAfter, err := ps.reloader.ReloadCerts()
+	switch {
+	case err == provisioner.ErrNoClientCerts:
+		status = http.StatusNotFound
+		resp.Error = err.Error()
+	case err != nil:
+		status = http.StatusInternalServerError
+		resp.Error = err.Error()
+	default:
+		resp.NotAfter = &notAfter
+	}
+	data, _ := json.Marshal(resp)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(data); err != nil {
+		ps.logger.Warnw("failed to send certificates reloading result",
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,106 @@
+package sidecar
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/cache"
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
+)
+
+type fakeProvisioner struct {
+	ready bool
+}
+
+func (p *fakeProvisioner) Channel() <-chan []types.Event {
+	return nil
+}
+
+func (p *fakeProvisioner) Run(stop chan struct{}) error {
+	<-stop
+	return nil
+}
+
+func (p *fakeProvisioner) Ready() bool {
+	return p.ready
+}
+
+func TestProbeServer(t *testing.T) {
+	p := &fakeProvisioner{}
+	c := cache.NewInMemoryCache()
+	s := &Sidecar{provisioner: p, cache: c}
+	ps, err := newProbeServer("127.0.0.1:0", c, s, s, log.DefaultLogger)
+	assert.Nil(t, err)
+	defer ps.listener.Close()
+
+	w := httptest.NewRecorder()
+	ps.healthz(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, w.Code, http.StatusOK)
+
+	w = httptest.NewRecorder()
+	ps.readyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, w.Code, http.StatusServiceUnavailable)
+	assert.Equal(t, w.Body.String(), `{"ready":false,"reason":"provisioner is not ready"}`)
+
+	p.ready = true
+	w = httptest.NewRecorder()
+	ps.readyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, w.Body.String(), `{"ready":true}`)
+
+	// Dangling references are checked as well.
+	assert.Nil(t, c.Route().Insert(&apisix.Route{Id: "1", UpstreamId: "2"}))
+	w = httptest.NewRecorder()
+	ps.readyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, w.Code, http.StatusServiceUnavailable)
+	assert.Equal(t, w.Body.String(), `{"ready":false,"reason":"1 dangling references: route 1 refers to missing upstream 2"}`)
+}
+
+type certReloaderFunc func() (time.Time, error)
+
+func (f certReloaderFunc) ReloadCerts() (time.Time, error) {
+	return f()
+}
+
+func TestProbeServerReloadCerts(t *testing.T) {
+	c := cache.NewInMemoryCache()
+	s := &Sidecar{provisioner: &fakeProvisioner{}, cache: c}
+	ps, err := newProbeServer("127.0.0.1:0", c, s, s, log.DefaultLogger)
+	assert.Nil(t, err)
+	defer ps.listener.Close()
+
+	w := httptest.NewRecorder()
+	ps.reloadCerts(w, httptest.NewRequest(http.MethodGet, "/reload-certs", nil))
+	assert.Equal(t, w.Code, http.StatusMethodNotAllowed)
+	assert.Equal(t, w.Header().Get("Allow"), http.MethodPost)
+
+	// The provisioner doesn't reload the client certificates.
+	w = httptest.NewRecorder()
+	ps.reloadCerts(w, httptest.NewRequest(http.MethodPost, "/reload-certs", nil))
+	assert.Equal(t, w.Code, http.StatusNotFound)
+	assert.Equal(t, w.Body.String(), `{"error":"no client certificates configured"}`)
+
+	notAfter := time.Date(2027, 1, 2, 3, 4, 5, 0, time.UTC)
+	ps.reloader = certReloaderFunc(func() (time.Time, error) {
+		return notAfter, nil
+	})
+	w = httptest.NewRecorder()
+	ps.reloadCerts(w, httptest.NewRequest(http.MethodPost, "/reload-certs", nil))
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, w.Body.String(), `{"not_after":"2027-01-02T03:04:05Z"}`)
+
+	ps.reloader = certReloaderFunc(func() (time.Time, error) {
+		return time.Time{}, errors.New("tls: failed to find any PEM data in certificate input")
+	})
+	w = httptest.NewRecorder()
+	ps.reloadCerts(w, httptest.NewRequest(http.MethodPost, "/reload-certs", nil))
+	assert.Equal(t, w.Code, http.StatusInternalServerError)
+	assert.Equal(t, w.Body.String(), `{"error":"tls: failed to find any PEM data in certificate input"}`)
+}
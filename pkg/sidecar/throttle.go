@@ -0,0 +1,58 @@
+package sidecar
+
+import (
+	"time"
+
+	"github.com/api7/apisix-mesh-agent/pkg/metrics"
+)
+
+// writeThrottler smooths the writes (events) to the downstream by a token
+// bucket, which is refilled at the rate and holds at most one second of
+// tokens. Writes exceeding the rate are delayed but never dropped. It's used
+// by the single goroutine pushing the batches, so the order of events is kept.
+type writeThrottler struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+
+	// for testing.
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// newWriteThrottler creates the writeThrottler, nil is returned if the rate
+// is not positive, which means no limit.
+func newWriteThrottler(rate int) *writeThrottler {
+	if rate <= 0 {
+		return nil
+	}
+	return &writeThrottler{
+		rate:   float64(rate),
+		tokens: float64(rate),
+		last:   time.Now(),
+		now:    time.Now,
+		sleep:  time.Sleep,
+	}
+}
+
+// wait blocks until n writes are allowed and returns the delay. A batch
+// larger than the bucket is allowed once the tokens are paid off.
+func (t *writeThrottler) wait(n int) time.Duration {
+	now := t.now()
+	t.tokens += now.Sub(t.last).Seconds() * t.rate
+	if t.tokens > t.rate {
+		t.tokens = t.rate
+	}
+	t.last = now
+	t.tokens -= float64(n)
+	if t.tokens >= 0 {
+		metrics.WriteThrottleDelaySeconds.Set(0)
+		return 0
+	}
+
+	delay := time.Duration(-t.tokens / t.rate * float64(time.Second))
+	metrics.WriteThrottleDelaySeconds.Set(delay.Seconds())
+	metrics.WriteThrottleDelaySecondsTotal.Add(delay.Seconds())
+	t.sleep(delay)
+	return delay
+}
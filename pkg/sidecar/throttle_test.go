@@ -0,0 +1,61 @@
+package sidecar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/apisix-mesh-agent/pkg/metrics"
+)
+
+func TestWriteThrottler(t *testing.T) {
+	assert.Nil(t, newWriteThrottler(0))
+
+	now := time.Now()
+	var slept time.Duration
+	wt := newWriteThrottler(10)
+	wt.last = now
+	wt.now = func() time.Time {
+		return now
+	}
+	wt.sleep = func(d time.Duration) {
+		slept += d
+		now = now.Add(d)
+	}
+
+	// The burst is allowed.
+	assert.Equal(t, wt.wait(10), time.Duration(0))
+	// Writes exceeding the rate are delayed.
+	assert.Equal(t, wt.wait(5), 500*time.Millisecond)
+	assert.Equal(t, wt.wait(1), 100*time.Millisecond)
+	assert.Equal(t, slept, 600*time.Millisecond)
+
+	// Tokens are refilled as time goes by.
+	now = now.Add(time.Second)
+	assert.Equal(t, wt.wait(10), time.Duration(0))
+	// Large batches are allowed once the tokens are paid off.
+	assert.Equal(t, wt.wait(20), 2*time.Second)
+}
+
+func TestWriteThrottlerMetrics(t *testing.T) {
+	total := testutil.ToFloat64(metrics.WriteThrottleDelaySecondsTotal)
+
+	now := time.Now()
+	wt := newWriteThrottler(10)
+	wt.last = now
+	wt.now = func() time.Time {
+		return now
+	}
+	var delay, delayTotal float64
+	wt.sleep = func(d time.Duration) {
+		delay = testutil.ToFloat64(metrics.WriteThrottleDelaySeconds)
+		delayTotal = testutil.ToFloat64(metrics.WriteThrottleDelaySecondsTotal)
+		now = now.Add(d)
+	}
+
+	assert.Equal(t, wt.wait(15), 500*time.Millisecond)
+	assert.Equal(t, delay, 0.5)
+	assert.Equal(t, delayTotal, total+0.5)
+}
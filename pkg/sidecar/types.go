@@ -9,16 +9,24 @@ import (
 
 	"go.uber.org/zap"
 
+	apisixutil "github.com/api7/apisix-mesh-agent/pkg/apisix"
 	"github.com/api7/apisix-mesh-agent/pkg/cache"
 	"github.com/api7/apisix-mesh-agent/pkg/config"
 	"github.com/api7/apisix-mesh-agent/pkg/etcdv3"
 	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/metrics"
 	"github.com/api7/apisix-mesh-agent/pkg/provisioner"
+	"github.com/api7/apisix-mesh-agent/pkg/provisioner/util"
+	xdsv3configmap "github.com/api7/apisix-mesh-agent/pkg/provisioner/xds/v3/configmap"
 	xdsv3file "github.com/api7/apisix-mesh-agent/pkg/provisioner/xds/v3/file"
 	xdsv3grpc "github.com/api7/apisix-mesh-agent/pkg/provisioner/xds/v3/grpc"
 	"github.com/api7/apisix-mesh-agent/pkg/types"
 )
 
+// _pushQueueSize is the number of event batches that can be queued for
+// the etcd server before the sidecar loop is blocked.
+const _pushQueueSize = 64
+
 // Sidecar is the entity to joint provisioner, cache, etcd and launch
 // the program.
 type Sidecar struct {
@@ -31,6 +39,23 @@ type Sidecar struct {
 	revision     int64
 	apisixRunner *apisixRunner
 	waitGroup    sync.WaitGroup
+	// pushCh queues the events to be pushed to the etcd server, they're
+	// pushed by a single goroutine so that the order of batches is kept.
+	pushCh chan []types.Event
+	// pushing tracks the events being pushed to the etcd server, so
+	// they're all delivered before the server is shut down.
+	pushing sync.WaitGroup
+	// throttler limits the rate of events, it's nil if there is no limit.
+	throttler *writeThrottler
+	// notifier notifies the webhook of the applied events, it's nil if
+	// the webhook is not configured.
+	notifier *webhookNotifier
+	// metricsSrv serves the metrics, it's nil if the metrics listen
+	// address is not configured.
+	metricsSrv *metrics.Server
+	// probeSrv serves the liveness and readiness probes, it's nil if the
+	// probe listen address is not configured.
+	probeSrv *probeServer
 }
 
 // NewSidecar creates a Sidecar object.
@@ -65,6 +90,8 @@ func NewSidecar(cfg *config.Config) (*Sidecar, error) {
 				NodeListen:    9080,
 				GRPCListen:    cfg.GRPCListen,
 				EtcdKeyPrefix: cfg.EtcdKeyPrefix,
+
+				DNSResolverValid: cfg.DNSResolverValid,
 			},
 		}
 	}
@@ -76,12 +103,53 @@ func NewSidecar(cfg *config.Config) (*Sidecar, error) {
 		provisioner:  p,
 		cache:        cache.NewInMemoryCache(),
 		apisixRunner: ar,
+		pushCh:       make(chan []types.Event, _pushQueueSize),
+		throttler:    newWriteThrottler(cfg.MaxWritesPerSecond),
+		notifier:     newWebhookNotifier(cfg, logger),
 	}
 	etcd, err := etcdv3.NewEtcdV3Server(cfg, s.cache, s)
 	if err != nil {
 		return nil, err
 	}
 	s.etcdSrv = etcd
+	if cfg.MetricsListen != "" {
+		// The metrics server is not essential, the sidecar keeps running
+		// without it (e.g. the port is in use), whether it's up is told by
+		// the logs.
+		if srv, err := metrics.NewServer(cfg.MetricsListen, cfg.EnablePprof); err != nil {
+			logger.Errorw("failed to launch metrics server, the metrics endpoint is disabled",
+				zap.Error(err),
+				zap.String("listen", cfg.MetricsListen),
+			)
+		} else {
+			logger.Infow("metrics server launched",
+				zap.String("listen", cfg.MetricsListen),
+			)
+			s.metricsSrv = srv
+		}
+	}
+	if cfg.ProbeListen != "" {
+		// Like the metrics server, the probe server is not essential.
+		if srv, err := newProbeServer(cfg.ProbeListen, s.cache, s, s, logger); err != nil {
+			logger.Errorw("failed to launch probe server, the probe endpoints are disabled",
+				zap.Error(err),
+				zap.String("listen", cfg.ProbeListen),
+			)
+		} else {
+			s.probeSrv = srv
+		}
+	}
+	if r, ok := p.(provisioner.Restorer); ok {
+		// The provisioner only emits the changes since its persisted
+		// state, so the restored resources are reflected in advance,
+		// they're not changes for the webhook.
+		if events := r.Restored(); len(events) > 0 {
+			logger.Infow("restored resources from the provisioner state",
+				zap.Int("events", len(events)),
+			)
+			s.reflectToCache(events)
+		}
+	}
 	return s, nil
 }
 
@@ -109,6 +177,28 @@ func (s *Sidecar) Run(stop chan struct{}) error {
 			)
 		}
 	}()
+	if s.metricsSrv != nil {
+		s.waitGroup.Add(1)
+		go func() {
+			defer s.waitGroup.Done()
+			if err := s.metricsSrv.Serve(); err != nil {
+				s.logger.Errorw("metrics server run failed, the metrics endpoint is disabled",
+					zap.Error(err),
+				)
+			}
+		}()
+	}
+	if s.probeSrv != nil {
+		s.waitGroup.Add(1)
+		go func() {
+			defer s.waitGroup.Done()
+			if err := s.probeSrv.serve(); err != nil {
+				s.logger.Errorw("probe server run failed",
+					zap.Error(err),
+				)
+			}
+		}()
+	}
 	time.Sleep(time.Second)
 
 	if s.apisixRunner != nil {
@@ -119,19 +209,43 @@ func (s *Sidecar) Run(stop chan struct{}) error {
 		}
 	}
 
+	if s.notifier != nil {
+		go s.notifier.run()
+	}
+
+	s.pushing.Add(1)
+	go s.pushToEtcd()
+
 loop:
 	for {
 		events, ok := <-s.provisioner.Channel()
 		if !ok {
 			break loop
 		}
+		events, heartbeat := util.DropHeartbeatEvents(events)
+		if heartbeat {
+			metrics.LastHeartbeatTimestamp.SetToCurrentTime()
+			s.logger.Debug("heartbeat arrived from provisioner")
+		}
+		if len(events) == 0 {
+			continue
+		}
+		metrics.CountEvents(events)
 		s.reflectToLog(events)
 		// TODO may reflect to etcd after cache one by one.
 		s.reflectToCache(events)
 		s.reflectToEtcd(events)
+		if s.notifier != nil {
+			s.notifier.notify(events)
+		}
 		// sidecar goroutine doesn't need to watch on stop channel,
 		// since it can receive the quit signal from the provisioner.
 	}
+	close(s.pushCh)
+	s.pushing.Wait()
+	if s.notifier != nil {
+		s.notifier.stop()
+	}
 
 	if s.apisixRunner != nil {
 		s.apisixRunner.shutdown()
@@ -144,6 +258,20 @@ loop:
 			zap.Error(err),
 		)
 	}
+	if s.metricsSrv != nil {
+		if err := s.metricsSrv.Shutdown(shutCtx); err != nil {
+			s.logger.Errorw("failed to shutdown metrics server",
+				zap.Error(err),
+			)
+		}
+	}
+	if s.probeSrv != nil {
+		if err := s.probeSrv.shutdown(shutCtx); err != nil {
+			s.logger.Errorw("failed to shutdown probe server",
+				zap.Error(err),
+			)
+		}
+	}
 
 	s.waitGroup.Wait()
 	return nil
@@ -151,14 +279,32 @@ loop:
 
 func (s *Sidecar) reflectToLog(events []types.Event) {
 	s.logger.Debugw("events arrived from provisioner",
-		zap.Any("events", events),
+		zap.Any("events", apisixutil.RedactEvents(events)),
 	)
 }
 
 func (s *Sidecar) reflectToEtcd(events []types.Event) {
-	go func(events []types.Event) {
+	metrics.WriteThrottleQueueDepth.Add(float64(len(events)))
+	s.pushCh <- events
+}
+
+// pushToEtcd pushes the queued events to the etcd server one batch after
+// another until the queue is closed, the batches are delayed by the write
+// throttling if it's configured, while the sidecar loop keeps going.
+func (s *Sidecar) pushToEtcd() {
+	defer s.pushing.Done()
+	for events := range s.pushCh {
+		if s.throttler != nil {
+			if delay := s.throttler.wait(len(events)); delay > 0 {
+				s.logger.Debugw("events delayed by the write throttling",
+					zap.Int("events", len(events)),
+					zap.Duration("delay", delay),
+				)
+			}
+		}
 		s.etcdSrv.PushEvents(events)
-	}(events)
+		metrics.WriteThrottleQueueDepth.Sub(float64(len(events)))
+	}
 }
 
 // Revision implements etcdv3.Revisioner.
@@ -166,12 +312,32 @@ func (s *Sidecar) Revision() int64 {
 	return atomic.LoadInt64(&s.revision)
 }
 
+// Ready implements provisioner.ReadinessReporter, it delegates to the
+// provisioner, which is considered ready if it doesn't report the readiness.
+func (s *Sidecar) Ready() bool {
+	if r, ok := s.provisioner.(provisioner.ReadinessReporter); ok {
+		return r.Ready()
+	}
+	return true
+}
+
+// ReloadCerts implements provisioner.CertReloader, it delegates to the
+// provisioner, which has no client certificates if it doesn't reload them.
+func (s *Sidecar) ReloadCerts() (time.Time, error) {
+	if r, ok := s.provisioner.(provisioner.CertReloader); ok {
+		return r.ReloadCerts()
+	}
+	return time.Time{}, provisioner.ErrNoClientCerts
+}
+
 func newProvisioner(cfg *config.Config) (provisioner.Provisioner, error) {
 	switch cfg.Provisioner {
 	case config.XDSV3FileProvisioner:
 		return xdsv3file.NewXDSProvisioner(cfg)
 	case config.XDSV3GRPCProvisioner:
 		return xdsv3grpc.NewXDSProvisioner(cfg)
+	case config.XDSV3ConfigMapProvisioner:
+		return xdsv3configmap.NewXDSConfigMapProvisioner(cfg)
 	default:
 		return nil, config.ErrUnknownProvisioner
 	}
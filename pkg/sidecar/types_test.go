@@ -1,13 +1,22 @@
 package sidecar
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/api7/apisix-mesh-agent/pkg/config"
 	"github.com/api7/apisix-mesh-agent/pkg/id"
+	"github.com/api7/apisix-mesh-agent/pkg/log"
+	"github.com/api7/apisix-mesh-agent/pkg/metrics"
+	"github.com/api7/apisix-mesh-agent/pkg/types"
+	"github.com/api7/apisix-mesh-agent/pkg/types/apisix"
 )
 
 func TestSidecarRun(t *testing.T) {
@@ -35,3 +44,118 @@ func TestSidecarRun(t *testing.T) {
 	assert.Equal(t, ups.Name, "httpbin.default.svc.cluster.local")
 	assert.Len(t, ups.Nodes, 0)
 }
+
+func TestSidecarMetricsListenInUse(t *testing.T) {
+	li, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer li.Close()
+
+	cfg := config.NewDefaultConfig()
+	cfg.XDSWatchFiles = append(cfg.XDSWatchFiles, "testdata/cluster.json")
+	cfg.GRPCListen = "127.0.0.1:10003"
+	cfg.MetricsListen = li.Addr().String()
+	cfg.ProbeListen = li.Addr().String()
+	// The sidecar runs without the metrics and probe servers.
+	s, err := NewSidecar(cfg)
+	assert.Nil(t, err)
+	assert.Nil(t, s.metricsSrv)
+	assert.Nil(t, s.probeSrv)
+
+	added := eventsCounted(types.EventAdd)
+	stop := make(chan struct{})
+	finishCh := make(chan struct{})
+	go func() {
+		err := s.Run(stop)
+		assert.Nil(t, err)
+		close(finishCh)
+	}()
+
+	time.Sleep(time.Second)
+	close(stop)
+	<-finishCh
+
+	_, err = s.cache.Upstream().Get(id.GenID("httpbin.default.svc.cluster.local"))
+	assert.Nil(t, err)
+	// Events from the provisioner are counted.
+	assert.Equal(t, eventsCounted(types.EventAdd), added+1)
+}
+
+type recordingEtcd struct {
+	sync.Mutex
+	batches []string
+}
+
+func (e *recordingEtcd) Serve(net.Listener) error       { return nil }
+func (e *recordingEtcd) Shutdown(context.Context) error { return nil }
+func (e *recordingEtcd) PushEvents(events []types.Event) {
+	// Slow pushes shouldn't reorder the batches.
+	time.Sleep(time.Millisecond)
+	e.Lock()
+	defer e.Unlock()
+	e.batches = append(e.batches, events[0].Object.(*apisix.Route).Id)
+}
+
+func TestReflectToEtcdOrder(t *testing.T) {
+	etcd := &recordingEtcd{}
+	s := &Sidecar{
+		etcdSrv: etcd,
+		pushCh:  make(chan []types.Event, _pushQueueSize),
+	}
+	s.pushing.Add(1)
+	go s.pushToEtcd()
+
+	var expected []string
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprint(i)
+		expected = append(expected, key)
+		s.reflectToEtcd([]types.Event{{Type: types.EventAdd, Object: &apisix.Route{Id: key}}})
+	}
+	close(s.pushCh)
+	s.pushing.Wait()
+	assert.Equal(t, etcd.batches, expected)
+}
+
+func TestPushToEtcdThrottled(t *testing.T) {
+	etcd := &recordingEtcd{}
+	s := &Sidecar{
+		logger:    log.DefaultLogger,
+		etcdSrv:   etcd,
+		pushCh:    make(chan []types.Event, _pushQueueSize),
+		throttler: newWriteThrottler(10),
+	}
+	now := time.Now()
+	s.throttler.last = now
+	s.throttler.now = func() time.Time {
+		return now
+	}
+	var depths []float64
+	s.throttler.sleep = func(d time.Duration) {
+		depths = append(depths, testutil.ToFloat64(metrics.WriteThrottleQueueDepth))
+		now = now.Add(d)
+	}
+
+	newBatch := func(id string, n int) []types.Event {
+		events := make([]types.Event, n)
+		for i := range events {
+			events[i] = types.Event{Type: types.EventAdd, Object: &apisix.Route{Id: id}}
+		}
+		return events
+	}
+	// The sidecar loop isn't blocked by the throttling.
+	s.reflectToEtcd(newBatch("1", 15))
+	s.reflectToEtcd(newBatch("2", 5))
+	assert.Equal(t, testutil.ToFloat64(metrics.WriteThrottleQueueDepth), float64(20))
+
+	s.pushing.Add(1)
+	go s.pushToEtcd()
+	close(s.pushCh)
+	s.pushing.Wait()
+	// Both batches are pending while the first one is delayed.
+	assert.Equal(t, depths, []float64{20, 5})
+	assert.Equal(t, testutil.ToFloat64(metrics.WriteThrottleQueueDepth), float64(0))
+	assert.Equal(t, etcd.batches, []string{"1", "2"})
+}
+
+func eventsCounted(typ types.EventType) float64 {
+	return testutil.ToFloat64(metrics.Events.WithLabelValues(string(typ)))
+}
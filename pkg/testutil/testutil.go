@@ -0,0 +1,60 @@
+// Package testutil contains the helpers shared by the tests of other
+// packages, it shouldn't be imported by the non-test code.
+package testutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// NewAny wraps the xDS resource (or any other message) into an Any.
+func NewAny(t *testing.T, msg proto.Message) *anypb.Any {
+	res, err := anypb.New(msg)
+	assert.Nil(t, err)
+	return res
+}
+
+// NewCertificate generates a self-signed certificate for the DNS names,
+// it's valid for an hour. The PEM encoded certificate and private key are
+// returned.
+func NewCertificate(t *testing.T, dnsNames ...string) (string, string) {
+	_, cert, key := GenerateCertificate(t, time.Hour, dnsNames...)
+	return cert, key
+}
+
+// GenerateCertificate generates a self-signed certificate for the DNS
+// names, it's valid for the duration. The certificate is returned, along
+// with the PEM encoded one and the private key.
+func GenerateCertificate(t *testing.T, validity time.Duration, dnsNames ...string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(now.UnixNano()),
+		Subject:      pkix.Name{CommonName: "test"},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(validity),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	return cert,
+		string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
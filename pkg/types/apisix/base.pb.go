@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0-devel
-// 	protoc        v3.12.3
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
 // source: base.proto
 
 package apisix
@@ -12,6 +12,7 @@ import (
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -24,23 +25,20 @@ const (
 // Var represents the expression like:
 // ["arg_id", "equal", "543"].
 type Var struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state protoimpl.MessageState `protogen:"open.v1"`
 	// vars in Route is an two-dimensional array which cannot be represented
 	// directly in protobuf, here we use https://github.com/favadi/protoc-go-inject-tag
 	// to hack the ultimate pb.go.
-	Vars []string `protobuf:"bytes,1,rep,name=vars,proto3" json:"vars,omitempty"`
+	Vars          []string `protobuf:"bytes,1,rep,name=vars,proto3" json:"vars,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Var) Reset() {
 	*x = Var{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_base_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_base_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *Var) String() string {
@@ -51,7 +49,7 @@ func (*Var) ProtoMessage() {}
 
 func (x *Var) ProtoReflect() protoreflect.Message {
 	mi := &file_base_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -75,29 +73,29 @@ func (x *Var) GetVars() []string {
 
 var File_base_proto protoreflect.FileDescriptor
 
-var file_base_proto_rawDesc = []byte{
-	0x0a, 0x0a, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x76, 0x61,
-	0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x25, 0x0a, 0x03, 0x56, 0x61, 0x72, 0x12, 0x1e, 0x0a, 0x04,
-	0x76, 0x61, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x42, 0x0a, 0xfa, 0x42, 0x07, 0x92,
-	0x01, 0x04, 0x08, 0x02, 0x10, 0x04, 0x52, 0x04, 0x76, 0x61, 0x72, 0x73, 0x42, 0x0a, 0x5a, 0x08,
-	0x2e, 0x3b, 0x61, 0x70, 0x69, 0x73, 0x69, 0x78, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
+const file_base_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"base.proto\x1a\x17validate/validate.proto\"%\n" +
+	"\x03Var\x12\x1e\n" +
+	"\x04vars\x18\x01 \x03(\tB\n" +
+	"\xfaB\a\x92\x01\x04\b\x02\x10\x04R\x04varsB\n" +
+	"Z\b.;apisixb\x06proto3"
 
 var (
 	file_base_proto_rawDescOnce sync.Once
-	file_base_proto_rawDescData = file_base_proto_rawDesc
+	file_base_proto_rawDescData []byte
 )
 
 func file_base_proto_rawDescGZIP() []byte {
 	file_base_proto_rawDescOnce.Do(func() {
-		file_base_proto_rawDescData = protoimpl.X.CompressGZIP(file_base_proto_rawDescData)
+		file_base_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_base_proto_rawDesc), len(file_base_proto_rawDesc)))
 	})
 	return file_base_proto_rawDescData
 }
 
 var file_base_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
-var file_base_proto_goTypes = []interface{}{
+var file_base_proto_goTypes = []any{
 	(*Var)(nil), // 0: Var
 }
 var file_base_proto_depIdxs = []int32{
@@ -113,25 +111,11 @@ func file_base_proto_init() {
 	if File_base_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_base_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Var); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_base_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_base_proto_rawDesc), len(file_base_proto_rawDesc)),
 			NumEnums:      0,
 			NumMessages:   1,
 			NumExtensions: 0,
@@ -142,7 +126,6 @@ func file_base_proto_init() {
 		MessageInfos:      file_base_proto_msgTypes,
 	}.Build()
 	File_base_proto = out.File
-	file_base_proto_rawDesc = nil
 	file_base_proto_goTypes = nil
 	file_base_proto_depIdxs = nil
 }
@@ -11,11 +11,12 @@ import (
 	"net/mail"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 	"unicode/utf8"
 
-	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 // ensure the imports are used
@@ -30,29 +31,65 @@ var (
 	_ = time.Duration(0)
 	_ = (*url.URL)(nil)
 	_ = (*mail.Address)(nil)
-	_ = ptypes.DynamicAny{}
+	_ = anypb.Any{}
+	_ = sort.Sort
 )
 
-// define the regex for a UUID once up-front
-var _base_uuidPattern = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
-
 // Validate checks the field values on Var with the rules defined in the proto
-// definition for this message. If any rules are violated, an error is returned.
+// definition for this message. If any rules are violated, the first error
+// encountered is returned, or nil if there are no violations.
 func (m *Var) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Var with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in VarMultiError, or nil if none found.
+func (m *Var) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Var) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
+	var errors []error
+
 	if l := len(m.GetVars()); l < 2 || l > 4 {
-		return VarValidationError{
+		err := VarValidationError{
 			field:  "Vars",
 			reason: "value must contain between 2 and 4 items, inclusive",
 		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return VarMultiError(errors)
 	}
 
 	return nil
 }
 
+// VarMultiError is an error wrapping multiple validation errors returned by
+// Var.ValidateAll() if the designated constraints aren't met.
+type VarMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m VarMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m VarMultiError) AllErrors() []error { return m }
+
 // VarValidationError is the validation error returned by Var.Validate if the
 // designated constraints aren't met.
 type VarValidationError struct {
@@ -1,18 +1,18 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0-devel
-// 	protoc        v3.12.3
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
 // source: route.proto
 
 package apisix
 
 import (
 	_ "github.com/envoyproxy/protoc-gen-validate/validate"
-	any "github.com/golang/protobuf/ptypes/any"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -83,10 +83,7 @@ func (Route_RouteStatus) EnumDescriptor() ([]byte, []int) {
 // in Apache APISIX's code base since there are some historical
 // considerations there which are not used here anymore.
 type Route struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state protoimpl.MessageState `protogen:"open.v1"`
 	// URI array used to do the route match.
 	// At least one item should be configured and each of them cannot be
 	// duplicated.
@@ -110,22 +107,28 @@ type Route struct {
 	// Nginx vars used to do the route match.
 	Vars []*Var `protobuf:"bytes,9,rep,name=vars,proto3" json:"vars,omitempty"`
 	// Embedded plugins.
-	Plugins *any.Any `protobuf:"bytes,10,opt,name=plugins,proto3" json:"plugins,omitempty"`
+	Plugins *Plugins `protobuf:"bytes,10,opt,name=plugins,proto3" json:"plugins,omitempty"`
 	// The referred service id.
 	ServiceId string `protobuf:"bytes,11,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
 	// The referred upstream id.
 	UpstreamId string `protobuf:"bytes,12,opt,name=upstream_id,json=upstreamId,proto3" json:"upstream_id,omitempty"`
 	// The route status.
 	Status Route_RouteStatus `protobuf:"varint,13,opt,name=status,proto3,enum=Route_RouteStatus" json:"status,omitempty"`
+	// Key value pairs to specify attributes of the route.
+	Labels map[string]string `protobuf:"bytes,14,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// The referred plugin config id.
+	PluginConfigId string `protobuf:"bytes,15,opt,name=plugin_config_id,json=pluginConfigId,proto3" json:"plugin_config_id,omitempty"`
+	// Timeout settings for this route.
+	Timeout       *Route_Timeout `protobuf:"bytes,16,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Route) Reset() {
 	*x = Route{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_route_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_route_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *Route) String() string {
@@ -136,7 +139,7 @@ func (*Route) ProtoMessage() {}
 
 func (x *Route) ProtoReflect() protoreflect.Message {
 	mi := &file_route_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -214,7 +217,7 @@ func (x *Route) GetVars() []*Var {
 	return nil
 }
 
-func (x *Route) GetPlugins() *any.Any {
+func (x *Route) GetPlugins() *Plugins {
 	if x != nil {
 		return x.Plugins
 	}
@@ -242,84 +245,165 @@ func (x *Route) GetStatus() Route_RouteStatus {
 	return Route_Disable
 }
 
-var File_route_proto protoreflect.FileDescriptor
+func (x *Route) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Route) GetPluginConfigId() string {
+	if x != nil {
+		return x.PluginConfigId
+	}
+	return ""
+}
+
+func (x *Route) GetTimeout() *Route_Timeout {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+// Timeout settings about connecting, sending and reading with the upstream,
+// they override the timeout settings of the upstream.
+type Route_Timeout struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The connect timeout setting (in seconds).
+	Connect float64 `protobuf:"fixed64,1,opt,name=connect,proto3" json:"connect,omitempty"`
+	// The send timeout setting (in seconds).
+	Send float64 `protobuf:"fixed64,2,opt,name=send,proto3" json:"send,omitempty"`
+	// The read timeout setting (in seconds).
+	Read          float64 `protobuf:"fixed64,3,opt,name=read,proto3" json:"read,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Route_Timeout) Reset() {
+	*x = Route_Timeout{}
+	mi := &file_route_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Route_Timeout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Route_Timeout) ProtoMessage() {}
+
+func (x *Route_Timeout) ProtoReflect() protoreflect.Message {
+	mi := &file_route_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Route_Timeout.ProtoReflect.Descriptor instead.
+func (*Route_Timeout) Descriptor() ([]byte, []int) {
+	return file_route_proto_rawDescGZIP(), []int{0, 1}
+}
+
+func (x *Route_Timeout) GetConnect() float64 {
+	if x != nil {
+		return x.Connect
+	}
+	return 0
+}
+
+func (x *Route_Timeout) GetSend() float64 {
+	if x != nil {
+		return x.Send
+	}
+	return 0
+}
 
-var file_route_proto_rawDesc = []byte{
-	0x0a, 0x0b, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0a, 0x62,
-	0x61, 0x73, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x76, 0x61, 0x6c, 0x69, 0x64,
-	0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x1a, 0x19, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xcf, 0x04,
-	0x0a, 0x05, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x1e, 0x0a, 0x04, 0x75, 0x72, 0x69, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x09, 0x42, 0x0a, 0xfa, 0x42, 0x07, 0x92, 0x01, 0x04, 0x08, 0x01, 0x18,
-	0x01, 0x52, 0x04, 0x75, 0x72, 0x69, 0x73, 0x12, 0x1d, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x09, 0xfa, 0x42, 0x06, 0x72, 0x04, 0x10, 0x01, 0x18, 0x64,
-	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1c, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x72, 0x03, 0x18, 0x80, 0x02, 0x52, 0x04,
-	0x64, 0x65, 0x73, 0x63, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79,
-	0x12, 0x6a, 0x0a, 0x07, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28,
-	0x09, 0x42, 0x50, 0xfa, 0x42, 0x05, 0x92, 0x01, 0x02, 0x18, 0x01, 0xfa, 0x42, 0x45, 0x92, 0x01,
-	0x42, 0x22, 0x40, 0x72, 0x3e, 0x52, 0x03, 0x47, 0x45, 0x54, 0x52, 0x04, 0x50, 0x4f, 0x53, 0x54,
-	0x52, 0x03, 0x50, 0x55, 0x54, 0x52, 0x06, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x52, 0x05, 0x50,
-	0x41, 0x54, 0x43, 0x48, 0x52, 0x04, 0x48, 0x45, 0x41, 0x44, 0x52, 0x07, 0x4f, 0x50, 0x54, 0x49,
-	0x4f, 0x4e, 0x53, 0x52, 0x07, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x52, 0x05, 0x54, 0x52,
-	0x41, 0x43, 0x45, 0x52, 0x07, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x73, 0x12, 0x42, 0x0a, 0x05,
-	0x68, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x42, 0x2c, 0xfa, 0x42, 0x09,
-	0x92, 0x01, 0x06, 0x08, 0x01, 0x18, 0x01, 0x28, 0x01, 0xfa, 0x42, 0x1d, 0x92, 0x01, 0x1a, 0x22,
-	0x18, 0x72, 0x16, 0x32, 0x14, 0x5e, 0x5c, 0x2a, 0x3f, 0x5b, 0x30, 0x2d, 0x39, 0x61, 0x2d, 0x7a,
-	0x41, 0x2d, 0x5a, 0x2d, 0x2e, 0x5f, 0x5d, 0x2b, 0x24, 0x52, 0x05, 0x68, 0x6f, 0x73, 0x74, 0x73,
-	0x12, 0x2f, 0x0a, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x73,
-	0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x42, 0x0c, 0xfa, 0x42, 0x09, 0x92, 0x01, 0x06, 0x08, 0x01,
-	0x18, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x41, 0x64, 0x64, 0x72,
-	0x73, 0x12, 0x18, 0x0a, 0x04, 0x76, 0x61, 0x72, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x04, 0x2e, 0x56, 0x61, 0x72, 0x52, 0x04, 0x76, 0x61, 0x72, 0x73, 0x12, 0x2e, 0x0a, 0x07, 0x70,
-	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41,
-	0x6e, 0x79, 0x52, 0x07, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x09, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x75, 0x70,
-	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0a, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x06, 0x73,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x52, 0x6f,
-	0x75, 0x74, 0x65, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
-	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x26, 0x0a, 0x0b, 0x52, 0x6f, 0x75, 0x74, 0x65,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c,
-	0x65, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x10, 0x01, 0x42,
-	0x0a, 0x5a, 0x08, 0x2e, 0x3b, 0x61, 0x70, 0x69, 0x73, 0x69, 0x78, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+func (x *Route_Timeout) GetRead() float64 {
+	if x != nil {
+		return x.Read
+	}
+	return 0
 }
 
+var File_route_proto protoreflect.FileDescriptor
+
+const file_route_proto_rawDesc = "" +
+	"\n" +
+	"\vroute.proto\x1a\n" +
+	"base.proto\x1a\rplugins.proto\x1a\x17validate/validate.proto\"\xef\x06\n" +
+	"\x05Route\x12\x1e\n" +
+	"\x04uris\x18\x01 \x03(\tB\n" +
+	"\xfaB\a\x92\x01\x04\b\x01\x18\x01R\x04uris\x12\x1d\n" +
+	"\x04name\x18\x02 \x01(\tB\t\xfaB\x06r\x04\x10\x01\x18dR\x04name\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x1c\n" +
+	"\x04desc\x18\x04 \x01(\tB\b\xfaB\x05r\x03\x18\x80\x02R\x04desc\x12\x1a\n" +
+	"\bpriority\x18\x05 \x01(\x05R\bpriority\x12d\n" +
+	"\amethods\x18\x06 \x03(\tBJ\xfaBG\x92\x01D\x18\x01\"@r>R\x03GETR\x04POSTR\x03PUTR\x06DELETER\x05PATCHR\x04HEADR\aOPTIONSR\aCONNECTR\x05TRACER\amethods\x12<\n" +
+	"\x05hosts\x18\a \x03(\tB&\xfaB#\x92\x01 \b\x01\x18\x01\"\x18r\x162\x14^\\*?[0-9a-zA-Z-._]+$(\x01R\x05hosts\x12/\n" +
+	"\fremote_addrs\x18\b \x03(\tB\f\xfaB\t\x92\x01\x06\b\x01\x18\x01(\x01R\vremoteAddrs\x12\x18\n" +
+	"\x04vars\x18\t \x03(\v2\x04.VarR\x04vars\x12\"\n" +
+	"\aplugins\x18\n" +
+	" \x01(\v2\b.PluginsR\aplugins\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\v \x01(\tR\tserviceId\x12\x1f\n" +
+	"\vupstream_id\x18\f \x01(\tR\n" +
+	"upstreamId\x12*\n" +
+	"\x06status\x18\r \x01(\x0e2\x12.Route.RouteStatusR\x06status\x12*\n" +
+	"\x06labels\x18\x0e \x03(\v2\x12.Route.LabelsEntryR\x06labels\x12(\n" +
+	"\x10plugin_config_id\x18\x0f \x01(\tR\x0epluginConfigId\x12(\n" +
+	"\atimeout\x18\x10 \x01(\v2\x0e.Route.TimeoutR\atimeout\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a{\n" +
+	"\aTimeout\x12(\n" +
+	"\aconnect\x18\x01 \x01(\x01B\x0e\xfaB\v\x12\t!\x00\x00\x00\x00\x00\x00\x00\x00R\aconnect\x12\"\n" +
+	"\x04send\x18\x02 \x01(\x01B\x0e\xfaB\v\x12\t!\x00\x00\x00\x00\x00\x00\x00\x00R\x04send\x12\"\n" +
+	"\x04read\x18\x03 \x01(\x01B\x0e\xfaB\v\x12\t!\x00\x00\x00\x00\x00\x00\x00\x00R\x04read\"&\n" +
+	"\vRouteStatus\x12\v\n" +
+	"\aDisable\x10\x00\x12\n" +
+	"\n" +
+	"\x06Enable\x10\x01B\n" +
+	"Z\b.;apisixb\x06proto3"
+
 var (
 	file_route_proto_rawDescOnce sync.Once
-	file_route_proto_rawDescData = file_route_proto_rawDesc
+	file_route_proto_rawDescData []byte
 )
 
 func file_route_proto_rawDescGZIP() []byte {
 	file_route_proto_rawDescOnce.Do(func() {
-		file_route_proto_rawDescData = protoimpl.X.CompressGZIP(file_route_proto_rawDescData)
+		file_route_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_route_proto_rawDesc), len(file_route_proto_rawDesc)))
 	})
 	return file_route_proto_rawDescData
 }
 
 var file_route_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_route_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
-var file_route_proto_goTypes = []interface{}{
+var file_route_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_route_proto_goTypes = []any{
 	(Route_RouteStatus)(0), // 0: Route.RouteStatus
 	(*Route)(nil),          // 1: Route
-	(*Var)(nil),            // 2: Var
-	(*any.Any)(nil),        // 3: google.protobuf.Any
+	nil,                    // 2: Route.LabelsEntry
+	(*Route_Timeout)(nil),  // 3: Route.Timeout
+	(*Var)(nil),            // 4: Var
+	(*Plugins)(nil),        // 5: Plugins
 }
 var file_route_proto_depIdxs = []int32{
-	2, // 0: Route.vars:type_name -> Var
-	3, // 1: Route.plugins:type_name -> google.protobuf.Any
+	4, // 0: Route.vars:type_name -> Var
+	5, // 1: Route.plugins:type_name -> Plugins
 	0, // 2: Route.status:type_name -> Route.RouteStatus
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	2, // 3: Route.labels:type_name -> Route.LabelsEntry
+	3, // 4: Route.timeout:type_name -> Route.Timeout
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_route_proto_init() }
@@ -328,27 +412,14 @@ func file_route_proto_init() {
 		return
 	}
 	file_base_proto_init()
-	if !protoimpl.UnsafeEnabled {
-		file_route_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Route); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
+	file_plugins_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_route_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_route_proto_rawDesc), len(file_route_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   1,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -358,7 +429,6 @@ func file_route_proto_init() {
 		MessageInfos:      file_route_proto_msgTypes,
 	}.Build()
 	File_route_proto = out.File
-	file_route_proto_rawDesc = nil
 	file_route_proto_goTypes = nil
 	file_route_proto_depIdxs = nil
 }
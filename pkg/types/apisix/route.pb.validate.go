@@ -11,11 +11,12 @@ import (
 	"net/mail"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 	"unicode/utf8"
 
-	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 // ensure the imports are used
@@ -30,24 +31,40 @@ var (
 	_ = time.Duration(0)
 	_ = (*url.URL)(nil)
 	_ = (*mail.Address)(nil)
-	_ = ptypes.DynamicAny{}
+	_ = anypb.Any{}
+	_ = sort.Sort
 )
 
-// define the regex for a UUID once up-front
-var _route_uuidPattern = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
-
 // Validate checks the field values on Route with the rules defined in the
-// proto definition for this message. If any rules are violated, an error is returned.
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
 func (m *Route) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Route with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in RouteMultiError, or nil if none found.
+func (m *Route) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Route) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
+	var errors []error
+
 	if len(m.GetUris()) < 1 {
-		return RouteValidationError{
+		err := RouteValidationError{
 			field:  "Uris",
 			reason: "value must contain at least 1 item(s)",
 		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
 	}
 
 	_Route_Uris_Unique := make(map[string]struct{}, len(m.GetUris()))
@@ -56,10 +73,14 @@ func (m *Route) Validate() error {
 		_, _ = idx, item
 
 		if _, exists := _Route_Uris_Unique[item]; exists {
-			return RouteValidationError{
+			err := RouteValidationError{
 				field:  fmt.Sprintf("Uris[%v]", idx),
 				reason: "repeated value must contain unique items",
 			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
 		} else {
 			_Route_Uris_Unique[item] = struct{}{}
 		}
@@ -68,19 +89,27 @@ func (m *Route) Validate() error {
 	}
 
 	if l := utf8.RuneCountInString(m.GetName()); l < 1 || l > 100 {
-		return RouteValidationError{
+		err := RouteValidationError{
 			field:  "Name",
 			reason: "value length must be between 1 and 100 runes, inclusive",
 		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
 	}
 
 	// no validation rules for Id
 
 	if utf8.RuneCountInString(m.GetDesc()) > 256 {
-		return RouteValidationError{
+		err := RouteValidationError{
 			field:  "Desc",
 			reason: "value length must be at most 256 runes",
 		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
 	}
 
 	// no validation rules for Priority
@@ -91,81 +120,136 @@ func (m *Route) Validate() error {
 		_, _ = idx, item
 
 		if _, exists := _Route_Methods_Unique[item]; exists {
-			return RouteValidationError{
+			err := RouteValidationError{
 				field:  fmt.Sprintf("Methods[%v]", idx),
 				reason: "repeated value must contain unique items",
 			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
 		} else {
 			_Route_Methods_Unique[item] = struct{}{}
 		}
 
 		if _, ok := _Route_Methods_InLookup[item]; !ok {
-			return RouteValidationError{
+			err := RouteValidationError{
 				field:  fmt.Sprintf("Methods[%v]", idx),
 				reason: "value must be in list [GET POST PUT DELETE PATCH HEAD OPTIONS CONNECT TRACE]",
 			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
 		}
 
 	}
 
-	if len(m.GetHosts()) < 1 {
-		return RouteValidationError{
-			field:  "Hosts",
-			reason: "value must contain at least 1 item(s)",
+	if len(m.GetHosts()) > 0 {
+
+		if len(m.GetHosts()) < 1 {
+			err := RouteValidationError{
+				field:  "Hosts",
+				reason: "value must contain at least 1 item(s)",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
 		}
-	}
 
-	_Route_Hosts_Unique := make(map[string]struct{}, len(m.GetHosts()))
+		_Route_Hosts_Unique := make(map[string]struct{}, len(m.GetHosts()))
 
-	for idx, item := range m.GetHosts() {
-		_, _ = idx, item
+		for idx, item := range m.GetHosts() {
+			_, _ = idx, item
 
-		if _, exists := _Route_Hosts_Unique[item]; exists {
-			return RouteValidationError{
-				field:  fmt.Sprintf("Hosts[%v]", idx),
-				reason: "repeated value must contain unique items",
+			if _, exists := _Route_Hosts_Unique[item]; exists {
+				err := RouteValidationError{
+					field:  fmt.Sprintf("Hosts[%v]", idx),
+					reason: "repeated value must contain unique items",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			} else {
+				_Route_Hosts_Unique[item] = struct{}{}
 			}
-		} else {
-			_Route_Hosts_Unique[item] = struct{}{}
-		}
 
-		if !_Route_Hosts_Pattern.MatchString(item) {
-			return RouteValidationError{
-				field:  fmt.Sprintf("Hosts[%v]", idx),
-				reason: "value does not match regex pattern \"^\\\\*?[0-9a-zA-Z-._]+$\"",
+			if !_Route_Hosts_Pattern.MatchString(item) {
+				err := RouteValidationError{
+					field:  fmt.Sprintf("Hosts[%v]", idx),
+					reason: "value does not match regex pattern \"^\\\\*?[0-9a-zA-Z-._]+$\"",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
 			}
+
 		}
 
 	}
 
-	if len(m.GetRemoteAddrs()) < 1 {
-		return RouteValidationError{
-			field:  "RemoteAddrs",
-			reason: "value must contain at least 1 item(s)",
+	if len(m.GetRemoteAddrs()) > 0 {
+
+		if len(m.GetRemoteAddrs()) < 1 {
+			err := RouteValidationError{
+				field:  "RemoteAddrs",
+				reason: "value must contain at least 1 item(s)",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
 		}
-	}
 
-	_Route_RemoteAddrs_Unique := make(map[string]struct{}, len(m.GetRemoteAddrs()))
+		_Route_RemoteAddrs_Unique := make(map[string]struct{}, len(m.GetRemoteAddrs()))
 
-	for idx, item := range m.GetRemoteAddrs() {
-		_, _ = idx, item
+		for idx, item := range m.GetRemoteAddrs() {
+			_, _ = idx, item
 
-		if _, exists := _Route_RemoteAddrs_Unique[item]; exists {
-			return RouteValidationError{
-				field:  fmt.Sprintf("RemoteAddrs[%v]", idx),
-				reason: "repeated value must contain unique items",
+			if _, exists := _Route_RemoteAddrs_Unique[item]; exists {
+				err := RouteValidationError{
+					field:  fmt.Sprintf("RemoteAddrs[%v]", idx),
+					reason: "repeated value must contain unique items",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			} else {
+				_Route_RemoteAddrs_Unique[item] = struct{}{}
 			}
-		} else {
-			_Route_RemoteAddrs_Unique[item] = struct{}{}
+
+			// no validation rules for RemoteAddrs[idx]
 		}
 
-		// no validation rules for RemoteAddrs[idx]
 	}
 
 	for idx, item := range m.GetVars() {
 		_, _ = idx, item
 
-		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, RouteValidationError{
+						field:  fmt.Sprintf("Vars[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, RouteValidationError{
+						field:  fmt.Sprintf("Vars[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
 			if err := v.Validate(); err != nil {
 				return RouteValidationError{
 					field:  fmt.Sprintf("Vars[%v]", idx),
@@ -177,7 +261,26 @@ func (m *Route) Validate() error {
 
 	}
 
-	if v, ok := interface{}(m.GetPlugins()).(interface{ Validate() error }); ok {
+	if all {
+		switch v := interface{}(m.GetPlugins()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, RouteValidationError{
+					field:  "Plugins",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, RouteValidationError{
+					field:  "Plugins",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetPlugins()).(interface{ Validate() error }); ok {
 		if err := v.Validate(); err != nil {
 			return RouteValidationError{
 				field:  "Plugins",
@@ -193,9 +296,62 @@ func (m *Route) Validate() error {
 
 	// no validation rules for Status
 
+	// no validation rules for Labels
+
+	// no validation rules for PluginConfigId
+
+	if all {
+		switch v := interface{}(m.GetTimeout()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, RouteValidationError{
+					field:  "Timeout",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, RouteValidationError{
+					field:  "Timeout",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetTimeout()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return RouteValidationError{
+				field:  "Timeout",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return RouteMultiError(errors)
+	}
+
 	return nil
 }
 
+// RouteMultiError is an error wrapping multiple validation errors returned by
+// Route.ValidateAll() if the designated constraints aren't met.
+type RouteMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RouteMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RouteMultiError) AllErrors() []error { return m }
+
 // RouteValidationError is the validation error returned by Route.Validate if
 // the designated constraints aren't met.
 type RouteValidationError struct {
@@ -263,3 +419,136 @@ var _Route_Methods_InLookup = map[string]struct{}{
 }
 
 var _Route_Hosts_Pattern = regexp.MustCompile("^\\*?[0-9a-zA-Z-._]+$")
+
+// Validate checks the field values on Route_Timeout with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Route_Timeout) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Route_Timeout with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in Route_TimeoutMultiError, or
+// nil if none found.
+func (m *Route_Timeout) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Route_Timeout) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetConnect() <= 0 {
+		err := Route_TimeoutValidationError{
+			field:  "Connect",
+			reason: "value must be greater than 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetSend() <= 0 {
+		err := Route_TimeoutValidationError{
+			field:  "Send",
+			reason: "value must be greater than 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetRead() <= 0 {
+		err := Route_TimeoutValidationError{
+			field:  "Read",
+			reason: "value must be greater than 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return Route_TimeoutMultiError(errors)
+	}
+
+	return nil
+}
+
+// Route_TimeoutMultiError is an error wrapping multiple validation errors
+// returned by Route_Timeout.ValidateAll() if the designated constraints aren't
+// met.
+type Route_TimeoutMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m Route_TimeoutMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m Route_TimeoutMultiError) AllErrors() []error { return m }
+
+// Route_TimeoutValidationError is the validation error returned by
+// Route_Timeout.Validate if the designated constraints aren't met.
+type Route_TimeoutValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e Route_TimeoutValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e Route_TimeoutValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e Route_TimeoutValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e Route_TimeoutValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e Route_TimeoutValidationError) ErrorName() string { return "Route_TimeoutValidationError" }
+
+// Error satisfies the builtin error interface
+func (e Route_TimeoutValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRoute_Timeout.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = Route_TimeoutValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = Route_TimeoutValidationError{}
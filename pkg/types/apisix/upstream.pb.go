@@ -1,18 +1,19 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0-devel
-// 	protoc        v3.12.3
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
 // source: upstream.proto
 
 package apisix
 
 import (
 	_ "github.com/envoyproxy/protoc-gen-validate/validate"
-	any "github.com/golang/protobuf/ptypes/any"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -24,10 +25,7 @@ const (
 
 // [#protodoc-title: The Apache APISIX Upstream configuration]
 type Upstream struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state protoimpl.MessageState `protogen:"open.v1"`
 	// How many times a request can be retried while communicating to the upstream,
 	// note request can be retried only if no bytes are sent to client.
 	Retries int32 `protobuf:"varint,1,opt,name=retries,proto3" json:"retries,omitempty"`
@@ -58,15 +56,22 @@ type Upstream struct {
 	// Upstream nodes.
 	// @inject_tag: json:"nodes"
 	Nodes []*Node `protobuf:"bytes,13,rep,name=nodes,proto3" json:"nodes"`
+	// Key value pairs to specify attributes of the upstream.
+	Labels map[string]string `protobuf:"bytes,14,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// The time (in seconds) to continue with retries, 0 means no limit.
+	RetryTimeout float64 `protobuf:"fixed64,15,opt,name=retry_timeout,json=retryTimeout,proto3" json:"retry_timeout,omitempty"`
+	// The TLS settings of this upstream, it's only in effective if the
+	// scheme is "https" or "grpcs".
+	Tls           *Upstream_TLS `protobuf:"bytes,16,opt,name=tls,proto3" json:"tls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Upstream) Reset() {
 	*x = Upstream{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_upstream_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_upstream_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *Upstream) String() string {
@@ -77,7 +82,7 @@ func (*Upstream) ProtoMessage() {}
 
 func (x *Upstream) ProtoReflect() protoreflect.Message {
 	mi := &file_upstream_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -183,25 +188,43 @@ func (x *Upstream) GetNodes() []*Node {
 	return nil
 }
 
+func (x *Upstream) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Upstream) GetRetryTimeout() float64 {
+	if x != nil {
+		return x.RetryTimeout
+	}
+	return 0
+}
+
+func (x *Upstream) GetTls() *Upstream_TLS {
+	if x != nil {
+		return x.Tls
+	}
+	return nil
+}
+
 // [#protodoc-title: The Apache APISIX Upstream Health Check configuration]
 type HealthCheck struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state protoimpl.MessageState `protogen:"open.v1"`
 	// Active health check settings.
 	Active *ActiveHealthCheck `protobuf:"bytes,1,opt,name=active,proto3" json:"active,omitempty"`
 	// Passive health check settings.
-	Passive *PassiveHealthCheck `protobuf:"bytes,2,opt,name=passive,proto3" json:"passive,omitempty"`
+	Passive       *PassiveHealthCheck `protobuf:"bytes,2,opt,name=passive,proto3" json:"passive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *HealthCheck) Reset() {
 	*x = HealthCheck{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_upstream_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_upstream_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *HealthCheck) String() string {
@@ -212,7 +235,7 @@ func (*HealthCheck) ProtoMessage() {}
 
 func (x *HealthCheck) ProtoReflect() protoreflect.Message {
 	mi := &file_upstream_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -243,10 +266,7 @@ func (x *HealthCheck) GetPassive() *PassiveHealthCheck {
 
 // [#protodoc-title: Acitve Health Check configuration]
 type ActiveHealthCheck struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state protoimpl.MessageState `protogen:"open.v1"`
 	// The health check probe type.
 	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
 	// Timeout setting for the probe requests.
@@ -266,16 +286,16 @@ type ActiveHealthCheck struct {
 	// health check for judging nodes become unhealthy.
 	Unhealthy *ActiveHealthCheckUnhealthy `protobuf:"bytes,9,opt,name=unhealthy,proto3" json:"unhealthy,omitempty"`
 	// The extra request headers to carry for HTTP probes.
-	ReqHeaders []string `protobuf:"bytes,10,rep,name=req_headers,json=reqHeaders,proto3" json:"req_headers,omitempty"`
+	ReqHeaders    []string `protobuf:"bytes,10,rep,name=req_headers,json=reqHeaders,proto3" json:"req_headers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ActiveHealthCheck) Reset() {
 	*x = ActiveHealthCheck{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_upstream_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_upstream_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ActiveHealthCheck) String() string {
@@ -286,7 +306,7 @@ func (*ActiveHealthCheck) ProtoMessage() {}
 
 func (x *ActiveHealthCheck) ProtoReflect() protoreflect.Message {
 	mi := &file_upstream_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -373,23 +393,20 @@ func (x *ActiveHealthCheck) GetReqHeaders() []string {
 
 // [#protodoc-title: Passive Health Check configuration]
 type PassiveHealthCheck struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state protoimpl.MessageState `protogen:"open.v1"`
 	// The health check probe type.
-	Type      string                       `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Healthy   *PassiveHealthCheckHealthy   `protobuf:"bytes,2,opt,name=healthy,proto3" json:"healthy,omitempty"`
-	Unhealthy *PassiveHealthCheckUnhealthy `protobuf:"bytes,3,opt,name=unhealthy,proto3" json:"unhealthy,omitempty"`
+	Type          string                       `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Healthy       *PassiveHealthCheckHealthy   `protobuf:"bytes,2,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	Unhealthy     *PassiveHealthCheckUnhealthy `protobuf:"bytes,3,opt,name=unhealthy,proto3" json:"unhealthy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PassiveHealthCheck) Reset() {
 	*x = PassiveHealthCheck{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_upstream_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_upstream_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *PassiveHealthCheck) String() string {
@@ -400,7 +417,7 @@ func (*PassiveHealthCheck) ProtoMessage() {}
 
 func (x *PassiveHealthCheck) ProtoReflect() protoreflect.Message {
 	mi := &file_upstream_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -438,25 +455,22 @@ func (x *PassiveHealthCheck) GetUnhealthy() *PassiveHealthCheckUnhealthy {
 
 // [#protodoc-title: Active Health Check configuration for judging nodes become healthy]
 type ActiveHealthCheckHealthy struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state protoimpl.MessageState `protogen:"open.v1"`
 	// The interval to send a probe request.
 	Interval int32 `protobuf:"varint,1,opt,name=interval,proto3" json:"interval,omitempty"`
 	// Probes with status codes in this array will be treated as healthy.
 	HttpStatuses []int32 `protobuf:"varint,2,rep,packed,name=http_statuses,json=httpStatuses,proto3" json:"http_statuses,omitempty"`
 	// How many consecutive success times should meet before a node is set to healthy.
-	Successes int32 `protobuf:"varint,3,opt,name=successes,proto3" json:"successes,omitempty"`
+	Successes     int32 `protobuf:"varint,3,opt,name=successes,proto3" json:"successes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ActiveHealthCheckHealthy) Reset() {
 	*x = ActiveHealthCheckHealthy{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_upstream_proto_msgTypes[4]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_upstream_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ActiveHealthCheckHealthy) String() string {
@@ -467,7 +481,7 @@ func (*ActiveHealthCheckHealthy) ProtoMessage() {}
 
 func (x *ActiveHealthCheckHealthy) ProtoReflect() protoreflect.Message {
 	mi := &file_upstream_proto_msgTypes[4]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -505,10 +519,7 @@ func (x *ActiveHealthCheckHealthy) GetSuccesses() int32 {
 
 // [#protodoc-title: Active Health Check configuration for judging nodes become unhealthy]
 type ActiveHealthCheckUnhealthy struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state protoimpl.MessageState `protogen:"open.v1"`
 	// The interval to send a probe request.
 	Interval int32 `protobuf:"varint,1,opt,name=interval,proto3" json:"interval,omitempty"`
 	// Probes with status codes in this array will be treated as unhealthy.
@@ -518,16 +529,16 @@ type ActiveHealthCheckUnhealthy struct {
 	// How many consecutive failures (tcp) occur should meet before a node is set to healthy.
 	TcpFailures int32 `protobuf:"varint,4,opt,name=tcp_failures,json=tcpFailures,proto3" json:"tcp_failures,omitempty"`
 	// How many consecutive timeouts occur should meet before a node is set to healthy.
-	Timeouts int32 `protobuf:"varint,5,opt,name=timeouts,proto3" json:"timeouts,omitempty"`
+	Timeouts      int32 `protobuf:"varint,5,opt,name=timeouts,proto3" json:"timeouts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ActiveHealthCheckUnhealthy) Reset() {
 	*x = ActiveHealthCheckUnhealthy{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_upstream_proto_msgTypes[5]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_upstream_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ActiveHealthCheckUnhealthy) String() string {
@@ -538,7 +549,7 @@ func (*ActiveHealthCheckUnhealthy) ProtoMessage() {}
 
 func (x *ActiveHealthCheckUnhealthy) ProtoReflect() protoreflect.Message {
 	mi := &file_upstream_proto_msgTypes[5]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -590,23 +601,20 @@ func (x *ActiveHealthCheckUnhealthy) GetTimeouts() int32 {
 
 // [#protodoc-title: Passive Health Check configuration for judging nodes become healthy]
 type PassiveHealthCheckHealthy struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state protoimpl.MessageState `protogen:"open.v1"`
 	// Probes with status codes in this array will be treated as healthy.
 	HttpStatuses []int32 `protobuf:"varint,1,rep,packed,name=http_statuses,json=httpStatuses,proto3" json:"http_statuses,omitempty"`
 	// How many consecutive success times should meet before a node is set to healthy.
-	Successes int32 `protobuf:"varint,2,opt,name=successes,proto3" json:"successes,omitempty"`
+	Successes     int32 `protobuf:"varint,2,opt,name=successes,proto3" json:"successes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PassiveHealthCheckHealthy) Reset() {
 	*x = PassiveHealthCheckHealthy{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_upstream_proto_msgTypes[6]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_upstream_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *PassiveHealthCheckHealthy) String() string {
@@ -617,7 +625,7 @@ func (*PassiveHealthCheckHealthy) ProtoMessage() {}
 
 func (x *PassiveHealthCheckHealthy) ProtoReflect() protoreflect.Message {
 	mi := &file_upstream_proto_msgTypes[6]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -648,10 +656,7 @@ func (x *PassiveHealthCheckHealthy) GetSuccesses() int32 {
 
 // [#protodoc-title: Passive Health Check configuration for judging nodes become unhealthy]
 type PassiveHealthCheckUnhealthy struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state protoimpl.MessageState `protogen:"open.v1"`
 	// Probes with status codes in this array will be treated as unhealthy.
 	HttpStatuses []int32 `protobuf:"varint,1,rep,packed,name=http_statuses,json=httpStatuses,proto3" json:"http_statuses,omitempty"`
 	// How many consecutive failures (http) occur should meet before a node is set to healthy.
@@ -659,16 +664,16 @@ type PassiveHealthCheckUnhealthy struct {
 	// How many consecutive failures (tcp) occur should meet before a node is set to healthy.
 	TcpFailures int32 `protobuf:"varint,3,opt,name=tcp_failures,json=tcpFailures,proto3" json:"tcp_failures,omitempty"`
 	// How many consecutive timeouts occur should meet before a node is set to healthy.
-	Timeouts int32 `protobuf:"varint,4,opt,name=timeouts,proto3" json:"timeouts,omitempty"`
+	Timeouts      int32 `protobuf:"varint,4,opt,name=timeouts,proto3" json:"timeouts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PassiveHealthCheckUnhealthy) Reset() {
 	*x = PassiveHealthCheckUnhealthy{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_upstream_proto_msgTypes[7]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_upstream_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *PassiveHealthCheckUnhealthy) String() string {
@@ -679,7 +684,7 @@ func (*PassiveHealthCheckUnhealthy) ProtoMessage() {}
 
 func (x *PassiveHealthCheckUnhealthy) ProtoReflect() protoreflect.Message {
 	mi := &file_upstream_proto_msgTypes[7]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -724,26 +729,23 @@ func (x *PassiveHealthCheckUnhealthy) GetTimeouts() int32 {
 
 // [#protodoc-title: Upstream Node]
 type Node struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state protoimpl.MessageState `protogen:"open.v1"`
 	// The endpoint host (could be IPv4/IPv6 or domain).
 	Host string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
 	// The endpoint port.
 	Port int32 `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
 	// The endpoint weight.
-	Weight   int32               `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
-	Metadata map[string]*any.Any `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Weight        int32                 `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	Metadata      map[string]*anypb.Any `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Node) Reset() {
 	*x = Node{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_upstream_proto_msgTypes[8]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_upstream_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *Node) String() string {
@@ -754,7 +756,7 @@ func (*Node) ProtoMessage() {}
 
 func (x *Node) ProtoReflect() protoreflect.Message {
 	mi := &file_upstream_proto_msgTypes[8]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -790,7 +792,7 @@ func (x *Node) GetWeight() int32 {
 	return 0
 }
 
-func (x *Node) GetMetadata() map[string]*any.Any {
+func (x *Node) GetMetadata() map[string]*anypb.Any {
 	if x != nil {
 		return x.Metadata
 	}
@@ -799,25 +801,22 @@ func (x *Node) GetMetadata() map[string]*any.Any {
 
 // Timeout settings about connecting, reading and sending with upstream.
 type Upstream_Timeout struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state protoimpl.MessageState `protogen:"open.v1"`
 	// The connect timeout setting (in seconds).
 	Connect float64 `protobuf:"fixed64,1,opt,name=connect,proto3" json:"connect,omitempty"`
 	// The send timeout setting (in seconds).
 	Send float64 `protobuf:"fixed64,2,opt,name=send,proto3" json:"send,omitempty"`
 	// The read timeout setting (in seconds).
-	Read float64 `protobuf:"fixed64,3,opt,name=read,proto3" json:"read,omitempty"`
+	Read          float64 `protobuf:"fixed64,3,opt,name=read,proto3" json:"read,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Upstream_Timeout) Reset() {
 	*x = Upstream_Timeout{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_upstream_proto_msgTypes[9]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_upstream_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *Upstream_Timeout) String() string {
@@ -828,7 +827,7 @@ func (*Upstream_Timeout) ProtoMessage() {}
 
 func (x *Upstream_Timeout) ProtoReflect() protoreflect.Message {
 	mi := &file_upstream_proto_msgTypes[9]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -864,193 +863,160 @@ func (x *Upstream_Timeout) GetRead() float64 {
 	return 0
 }
 
-var File_upstream_proto protoreflect.FileDescriptor
+// TLS settings to communicate with the upstream.
+type Upstream_TLS struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The client certificate (in PEM) to the upstream.
+	ClientCert string `protobuf:"bytes,1,opt,name=client_cert,json=clientCert,proto3" json:"client_cert,omitempty"`
+	// The private key (in PEM) of the client certificate.
+	ClientKey     string `protobuf:"bytes,2,opt,name=client_key,json=clientKey,proto3" json:"client_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Upstream_TLS) Reset() {
+	*x = Upstream_TLS{}
+	mi := &file_upstream_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Upstream_TLS) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Upstream_TLS) ProtoMessage() {}
 
-var file_upstream_proto_rawDesc = []byte{
-	0x0a, 0x0e, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x1a, 0x17, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64,
-	0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xbd, 0x05, 0x0a, 0x08, 0x55, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61,
-	0x6d, 0x12, 0x21, 0x0a, 0x07, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x05, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x1a, 0x02, 0x28, 0x00, 0x52, 0x07, 0x72, 0x65, 0x74,
-	0x72, 0x69, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x55, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
-	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75,
-	0x74, 0x12, 0x3e, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42,
-	0x2a, 0xfa, 0x42, 0x27, 0x72, 0x25, 0x52, 0x05, 0x63, 0x68, 0x61, 0x73, 0x68, 0x52, 0x0a, 0x72,
-	0x6f, 0x75, 0x6e, 0x64, 0x72, 0x6f, 0x62, 0x69, 0x6e, 0x52, 0x04, 0x65, 0x77, 0x6d, 0x61, 0x52,
-	0x0a, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x6e, 0x52, 0x04, 0x74, 0x79, 0x70,
-	0x65, 0x12, 0x50, 0x0a, 0x07, 0x68, 0x61, 0x73, 0x68, 0x5f, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x09, 0x42, 0x37, 0xfa, 0x42, 0x34, 0x72, 0x32, 0x52, 0x04, 0x76, 0x61, 0x72, 0x73, 0x52,
-	0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x06, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x52,
-	0x08, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x52, 0x10, 0x76, 0x61, 0x72, 0x73, 0x5f,
-	0x63, 0x6f, 0x6d, 0x62, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x68, 0x61, 0x73,
-	0x68, 0x4f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x22, 0x0a, 0x05, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x18, 0x06,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65,
-	0x63, 0x6b, 0x52, 0x05, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x37, 0x0a, 0x06, 0x73, 0x63, 0x68,
-	0x65, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x42, 0x1f, 0xfa, 0x42, 0x1c, 0x72, 0x1a,
-	0x52, 0x04, 0x67, 0x72, 0x70, 0x63, 0x52, 0x05, 0x67, 0x72, 0x70, 0x63, 0x73, 0x52, 0x04, 0x68,
-	0x74, 0x74, 0x70, 0x52, 0x05, 0x68, 0x74, 0x74, 0x70, 0x73, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65,
-	0x6d, 0x65, 0x12, 0x37, 0x0a, 0x09, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x18,
-	0x08, 0x20, 0x01, 0x28, 0x09, 0x42, 0x1a, 0xfa, 0x42, 0x17, 0x72, 0x15, 0x52, 0x04, 0x70, 0x61,
-	0x73, 0x73, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x52, 0x07, 0x72, 0x65, 0x77, 0x72, 0x69, 0x74,
-	0x65, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x40, 0x0a, 0x0d, 0x75,
-	0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x09, 0x20, 0x01,
-	0x28, 0x09, 0x42, 0x1b, 0xfa, 0x42, 0x18, 0x72, 0x16, 0x32, 0x14, 0x5e, 0x5c, 0x2a, 0x3f, 0x5b,
-	0x30, 0x2d, 0x39, 0x61, 0x2d, 0x7a, 0x41, 0x2d, 0x5a, 0x2d, 0x2e, 0x5f, 0x5d, 0x2b, 0x24, 0x52,
-	0x0c, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x1d, 0x0a,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x42, 0x09, 0xfa, 0x42, 0x06,
-	0x72, 0x04, 0x10, 0x01, 0x18, 0x64, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x04,
-	0x64, 0x65, 0x73, 0x63, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x72,
-	0x03, 0x18, 0x80, 0x02, 0x52, 0x04, 0x64, 0x65, 0x73, 0x63, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
-	0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x05, 0x6e, 0x6f,
-	0x64, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x4e, 0x6f, 0x64, 0x65,
-	0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x1a, 0x7b, 0x0a, 0x07, 0x54, 0x69, 0x6d, 0x65, 0x6f,
-	0x75, 0x74, 0x12, 0x28, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x01, 0x42, 0x0e, 0xfa, 0x42, 0x0b, 0x12, 0x09, 0x21, 0x00, 0x00, 0x00, 0x00, 0x00,
-	0x00, 0x00, 0x00, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x22, 0x0a, 0x04,
-	0x73, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x42, 0x0e, 0xfa, 0x42, 0x0b, 0x12,
-	0x09, 0x21, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x52, 0x04, 0x73, 0x65, 0x6e, 0x64,
-	0x12, 0x22, 0x0a, 0x04, 0x72, 0x65, 0x61, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x42, 0x0e,
-	0xfa, 0x42, 0x0b, 0x12, 0x09, 0x21, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x52, 0x04,
-	0x72, 0x65, 0x61, 0x64, 0x22, 0x72, 0x0a, 0x0b, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68,
-	0x65, 0x63, 0x6b, 0x12, 0x34, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x48, 0x65, 0x61, 0x6c,
-	0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x8a, 0x01, 0x02, 0x10,
-	0x01, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x70, 0x61, 0x73,
-	0x73, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x50, 0x61, 0x73,
-	0x73, 0x69, 0x76, 0x65, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52,
-	0x07, 0x70, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65, 0x22, 0xeb, 0x03, 0x0a, 0x11, 0x41, 0x63, 0x74,
-	0x69, 0x76, 0x65, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x2b,
-	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x17, 0xfa, 0x42,
-	0x14, 0x72, 0x12, 0x52, 0x04, 0x68, 0x74, 0x74, 0x70, 0x52, 0x05, 0x68, 0x74, 0x74, 0x70, 0x73,
-	0x52, 0x03, 0x74, 0x63, 0x70, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2a, 0x0a, 0x07, 0x74,
-	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x42, 0x10, 0xfa, 0x42,
-	0x0d, 0x12, 0x0b, 0x29, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40, 0x01, 0x52, 0x07,
-	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x2b, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x63, 0x75,
-	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x42, 0x09, 0xfa, 0x42,
-	0x06, 0x1a, 0x04, 0x28, 0x00, 0x40, 0x01, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72,
-	0x65, 0x6e, 0x63, 0x79, 0x12, 0x2f, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x09, 0x42, 0x1b, 0xfa, 0x42, 0x18, 0x72, 0x16, 0x32, 0x14, 0x5e, 0x5c, 0x2a, 0x3f, 0x5b,
-	0x30, 0x2d, 0x39, 0x61, 0x2d, 0x7a, 0x41, 0x2d, 0x5a, 0x2d, 0x2e, 0x5f, 0x5d, 0x2b, 0x24, 0x52,
-	0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x05, 0x42, 0x0b, 0xfa, 0x42, 0x08, 0x1a, 0x06, 0x18, 0xff, 0xff, 0x03, 0x28, 0x01,
-	0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x25, 0x0a, 0x09, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x70,
-	0x61, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x72, 0x03,
-	0xd0, 0x01, 0x01, 0x52, 0x08, 0x68, 0x74, 0x74, 0x70, 0x50, 0x61, 0x74, 0x68, 0x12, 0x38, 0x0a,
-	0x18, 0x68, 0x74, 0x74, 0x70, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x5f, 0x63, 0x65,
-	0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x16, 0x68, 0x74, 0x74, 0x70, 0x73, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x43, 0x65, 0x72, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x33, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x6c, 0x74,
-	0x68, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x76,
-	0x65, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x48, 0x65, 0x61, 0x6c,
-	0x74, 0x68, 0x79, 0x52, 0x07, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x12, 0x39, 0x0a, 0x09,
-	0x75, 0x6e, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1b, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68,
-	0x65, 0x63, 0x6b, 0x55, 0x6e, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x52, 0x09, 0x75, 0x6e,
-	0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x12, 0x2d, 0x0a, 0x0b, 0x72, 0x65, 0x71, 0x5f, 0x68,
-	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x42, 0x0c, 0xfa, 0x42,
-	0x09, 0x92, 0x01, 0x06, 0x08, 0x01, 0x18, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x72, 0x65, 0x71, 0x48,
-	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x22, 0xb3, 0x01, 0x0a, 0x12, 0x50, 0x61, 0x73, 0x73, 0x69,
-	0x76, 0x65, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x2b, 0x0a,
-	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x17, 0xfa, 0x42, 0x14,
-	0x72, 0x12, 0x52, 0x04, 0x68, 0x74, 0x74, 0x70, 0x52, 0x05, 0x68, 0x74, 0x74, 0x70, 0x73, 0x52,
-	0x03, 0x74, 0x63, 0x70, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x34, 0x0a, 0x07, 0x68, 0x65,
-	0x61, 0x6c, 0x74, 0x68, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x50, 0x61,
-	0x73, 0x73, 0x69, 0x76, 0x65, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b,
-	0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x52, 0x07, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79,
-	0x12, 0x3a, 0x0a, 0x09, 0x75, 0x6e, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x50, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65, 0x48, 0x65, 0x61,
-	0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x55, 0x6e, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68,
-	0x79, 0x52, 0x09, 0x75, 0x6e, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x22, 0xb0, 0x01, 0x0a,
-	0x18, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65,
-	0x63, 0x6b, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x12, 0x25, 0x0a, 0x08, 0x69, 0x6e, 0x74,
-	0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x42, 0x09, 0xfa, 0x42, 0x06,
-	0x1a, 0x04, 0x28, 0x01, 0x40, 0x01, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
-	0x12, 0x41, 0x0a, 0x0d, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x65,
-	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x05, 0x42, 0x1c, 0xfa, 0x42, 0x09, 0x92, 0x01, 0x06, 0x08,
-	0x01, 0x18, 0x01, 0x28, 0x01, 0xfa, 0x42, 0x0d, 0x92, 0x01, 0x0a, 0x22, 0x08, 0x1a, 0x06, 0x18,
-	0xd7, 0x04, 0x28, 0xc8, 0x01, 0x52, 0x0c, 0x68, 0x74, 0x74, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x09, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x65, 0x73,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x42, 0x0c, 0xfa, 0x42, 0x09, 0x1a, 0x07, 0x18, 0xfe, 0x01,
-	0x28, 0x01, 0x40, 0x01, 0x52, 0x09, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x65, 0x73, 0x22,
-	0x94, 0x02, 0x0a, 0x1a, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68,
-	0x43, 0x68, 0x65, 0x63, 0x6b, 0x55, 0x6e, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x12, 0x25,
-	0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
-	0x42, 0x09, 0xfa, 0x42, 0x06, 0x1a, 0x04, 0x28, 0x01, 0x40, 0x01, 0x52, 0x08, 0x69, 0x6e, 0x74,
-	0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x41, 0x0a, 0x0d, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x73, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x05, 0x42, 0x1c, 0xfa, 0x42,
-	0x09, 0x92, 0x01, 0x06, 0x08, 0x01, 0x18, 0x01, 0x28, 0x01, 0xfa, 0x42, 0x0d, 0x92, 0x01, 0x0a,
-	0x22, 0x08, 0x1a, 0x06, 0x18, 0xd7, 0x04, 0x28, 0xc8, 0x01, 0x52, 0x0c, 0x68, 0x74, 0x74, 0x70,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x12, 0x31, 0x0a, 0x0d, 0x68, 0x74, 0x74, 0x70,
-	0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x42,
-	0x0c, 0xfa, 0x42, 0x09, 0x1a, 0x07, 0x18, 0xfe, 0x01, 0x28, 0x01, 0x40, 0x01, 0x52, 0x0c, 0x68,
-	0x74, 0x74, 0x70, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x2f, 0x0a, 0x0c, 0x74,
-	0x63, 0x70, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x05, 0x42, 0x0c, 0xfa, 0x42, 0x09, 0x1a, 0x07, 0x18, 0xfe, 0x01, 0x28, 0x01, 0x40, 0x01, 0x52,
-	0x0b, 0x74, 0x63, 0x70, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x08,
-	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x42, 0x0c,
-	0xfa, 0x42, 0x09, 0x1a, 0x07, 0x18, 0xfe, 0x01, 0x28, 0x01, 0x40, 0x01, 0x52, 0x08, 0x74, 0x69,
-	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x73, 0x22, 0x8a, 0x01, 0x0a, 0x19, 0x50, 0x61, 0x73, 0x73, 0x69,
-	0x76, 0x65, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x48, 0x65, 0x61,
-	0x6c, 0x74, 0x68, 0x79, 0x12, 0x41, 0x0a, 0x0d, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x73, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x05, 0x42, 0x1c, 0xfa, 0x42, 0x09,
-	0x92, 0x01, 0x06, 0x08, 0x01, 0x18, 0x01, 0x28, 0x01, 0xfa, 0x42, 0x0d, 0x92, 0x01, 0x0a, 0x22,
-	0x08, 0x1a, 0x06, 0x18, 0xd7, 0x04, 0x28, 0xc8, 0x01, 0x52, 0x0c, 0x68, 0x74, 0x74, 0x70, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x09, 0x73, 0x75, 0x63, 0x63, 0x65,
-	0x73, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x42, 0x0c, 0xfa, 0x42, 0x09, 0x1a,
-	0x07, 0x18, 0xfe, 0x01, 0x28, 0x01, 0x40, 0x01, 0x52, 0x09, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73,
-	0x73, 0x65, 0x73, 0x22, 0xee, 0x01, 0x0a, 0x1b, 0x50, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65, 0x48,
-	0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x55, 0x6e, 0x68, 0x65, 0x61, 0x6c,
-	0x74, 0x68, 0x79, 0x12, 0x41, 0x0a, 0x0d, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x73, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x05, 0x42, 0x1c, 0xfa, 0x42, 0x09, 0x92,
-	0x01, 0x06, 0x08, 0x01, 0x18, 0x01, 0x28, 0x01, 0xfa, 0x42, 0x0d, 0x92, 0x01, 0x0a, 0x22, 0x08,
-	0x1a, 0x06, 0x18, 0xd7, 0x04, 0x28, 0xc8, 0x01, 0x52, 0x0c, 0x68, 0x74, 0x74, 0x70, 0x53, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x12, 0x31, 0x0a, 0x0d, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x66,
-	0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x42, 0x0c, 0xfa,
-	0x42, 0x09, 0x1a, 0x07, 0x18, 0xfe, 0x01, 0x28, 0x01, 0x40, 0x01, 0x52, 0x0c, 0x68, 0x74, 0x74,
-	0x70, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x2f, 0x0a, 0x0c, 0x74, 0x63, 0x70,
-	0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x42,
-	0x0c, 0xfa, 0x42, 0x09, 0x1a, 0x07, 0x18, 0xfe, 0x01, 0x28, 0x01, 0x40, 0x01, 0x52, 0x0b, 0x74,
-	0x63, 0x70, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x08, 0x74, 0x69,
-	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x42, 0x0c, 0xfa, 0x42,
-	0x09, 0x1a, 0x07, 0x18, 0xfe, 0x01, 0x28, 0x01, 0x40, 0x01, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65,
-	0x6f, 0x75, 0x74, 0x73, 0x22, 0xfd, 0x01, 0x0a, 0x04, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x2f, 0x0a,
-	0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x1b, 0xfa, 0x42, 0x18,
-	0x72, 0x16, 0x32, 0x14, 0x5e, 0x5c, 0x2a, 0x3f, 0x5b, 0x30, 0x2d, 0x39, 0x61, 0x2d, 0x7a, 0x41,
-	0x2d, 0x5a, 0x2d, 0x2e, 0x5f, 0x5d, 0x2b, 0x24, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x1f,
-	0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x42, 0x0b, 0xfa, 0x42,
-	0x08, 0x1a, 0x06, 0x18, 0xff, 0xff, 0x03, 0x28, 0x01, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12,
-	0x1f, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x42,
-	0x07, 0xfa, 0x42, 0x04, 0x1a, 0x02, 0x28, 0x00, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74,
-	0x12, 0x2f, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
-	0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
-	0x61, 0x1a, 0x51, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x03, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x3a, 0x02, 0x38, 0x01, 0x42, 0x0a, 0x5a, 0x08, 0x2e, 0x3b, 0x61, 0x70, 0x69, 0x73, 0x69, 0x78,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *Upstream_TLS) ProtoReflect() protoreflect.Message {
+	mi := &file_upstream_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Upstream_TLS.ProtoReflect.Descriptor instead.
+func (*Upstream_TLS) Descriptor() ([]byte, []int) {
+	return file_upstream_proto_rawDescGZIP(), []int{0, 2}
 }
 
+func (x *Upstream_TLS) GetClientCert() string {
+	if x != nil {
+		return x.ClientCert
+	}
+	return ""
+}
+
+func (x *Upstream_TLS) GetClientKey() string {
+	if x != nil {
+		return x.ClientKey
+	}
+	return ""
+}
+
+var File_upstream_proto protoreflect.FileDescriptor
+
+const file_upstream_proto_rawDesc = "" +
+	"\n" +
+	"\x0eupstream.proto\x1a\x17validate/validate.proto\x1a\x19google/protobuf/any.proto\"\xd8\a\n" +
+	"\bUpstream\x12!\n" +
+	"\aretries\x18\x01 \x01(\x05B\a\xfaB\x04\x1a\x02(\x00R\aretries\x12+\n" +
+	"\atimeout\x18\x02 \x01(\v2\x11.Upstream.TimeoutR\atimeout\x12>\n" +
+	"\x04type\x18\x03 \x01(\tB*\xfaB'r%R\x05chashR\n" +
+	"roundrobinR\x04ewmaR\n" +
+	"lease_connR\x04type\x12P\n" +
+	"\ahash_on\x18\x04 \x01(\tB7\xfaB4r2R\x04varsR\x06headerR\x06cookieR\bconsumerR\x10vars_combinationR\x06hashOn\x12\x10\n" +
+	"\x03key\x18\x05 \x01(\tR\x03key\x12\"\n" +
+	"\x05check\x18\x06 \x01(\v2\f.HealthCheckR\x05check\x127\n" +
+	"\x06scheme\x18\a \x01(\tB\x1f\xfaB\x1cr\x1aR\x04grpcR\x05grpcsR\x04httpR\x05httpsR\x06scheme\x127\n" +
+	"\tpass_host\x18\b \x01(\tB\x1a\xfaB\x17r\x15R\x04passR\x04nodeR\arewriteR\bpassHost\x12@\n" +
+	"\rupstream_host\x18\t \x01(\tB\x1b\xfaB\x18r\x162\x14^\\*?[0-9a-zA-Z-._]+$R\fupstreamHost\x12\x1d\n" +
+	"\x04name\x18\n" +
+	" \x01(\tB\t\xfaB\x06r\x04\x10\x01\x18dR\x04name\x12\x1c\n" +
+	"\x04desc\x18\v \x01(\tB\b\xfaB\x05r\x03\x18\x80\x02R\x04desc\x12\x0e\n" +
+	"\x02id\x18\f \x01(\tR\x02id\x12\x1b\n" +
+	"\x05nodes\x18\r \x03(\v2\x05.NodeR\x05nodes\x12-\n" +
+	"\x06labels\x18\x0e \x03(\v2\x15.Upstream.LabelsEntryR\x06labels\x123\n" +
+	"\rretry_timeout\x18\x0f \x01(\x01B\x0e\xfaB\v\x12\t)\x00\x00\x00\x00\x00\x00\x00\x00R\fretryTimeout\x12\x1f\n" +
+	"\x03tls\x18\x10 \x01(\v2\r.Upstream.TLSR\x03tls\x1a{\n" +
+	"\aTimeout\x12(\n" +
+	"\aconnect\x18\x01 \x01(\x01B\x0e\xfaB\v\x12\t!\x00\x00\x00\x00\x00\x00\x00\x00R\aconnect\x12\"\n" +
+	"\x04send\x18\x02 \x01(\x01B\x0e\xfaB\v\x12\t!\x00\x00\x00\x00\x00\x00\x00\x00R\x04send\x12\"\n" +
+	"\x04read\x18\x03 \x01(\x01B\x0e\xfaB\v\x12\t!\x00\x00\x00\x00\x00\x00\x00\x00R\x04read\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aY\n" +
+	"\x03TLS\x12)\n" +
+	"\vclient_cert\x18\x01 \x01(\tB\b\xfaB\x05r\x03\x10\x80\x01R\n" +
+	"clientCert\x12'\n" +
+	"\n" +
+	"client_key\x18\x02 \x01(\tB\b\xfaB\x05r\x03\x10\x80\x01R\tclientKey\"r\n" +
+	"\vHealthCheck\x124\n" +
+	"\x06active\x18\x01 \x01(\v2\x12.ActiveHealthCheckB\b\xfaB\x05\x8a\x01\x02\x10\x01R\x06active\x12-\n" +
+	"\apassive\x18\x02 \x01(\v2\x13.PassiveHealthCheckR\apassive\"\xeb\x03\n" +
+	"\x11ActiveHealthCheck\x12+\n" +
+	"\x04type\x18\x01 \x01(\tB\x17\xfaB\x14r\x12R\x04httpR\x05httpsR\x03tcpR\x04type\x12*\n" +
+	"\atimeout\x18\x02 \x01(\x01B\x10\xfaB\r\x12\v)\x00\x00\x00\x00\x00\x00\x00\x00@\x01R\atimeout\x12+\n" +
+	"\vconcurrency\x18\x03 \x01(\x05B\t\xfaB\x06\x1a\x04(\x00@\x01R\vconcurrency\x12/\n" +
+	"\x04host\x18\x04 \x01(\tB\x1b\xfaB\x18r\x162\x14^\\*?[0-9a-zA-Z-._]+$R\x04host\x12\x1f\n" +
+	"\x04port\x18\x05 \x01(\x05B\v\xfaB\b\x1a\x06\x18\xff\xff\x03(\x01R\x04port\x12%\n" +
+	"\thttp_path\x18\x06 \x01(\tB\b\xfaB\x05r\x03\xd0\x01\x01R\bhttpPath\x128\n" +
+	"\x18https_verify_certificate\x18\a \x01(\bR\x16httpsVerifyCertificate\x123\n" +
+	"\ahealthy\x18\b \x01(\v2\x19.ActiveHealthCheckHealthyR\ahealthy\x129\n" +
+	"\tunhealthy\x18\t \x01(\v2\x1b.ActiveHealthCheckUnhealthyR\tunhealthy\x12-\n" +
+	"\vreq_headers\x18\n" +
+	" \x03(\tB\f\xfaB\t\x92\x01\x06\b\x01\x18\x01(\x01R\n" +
+	"reqHeaders\"\xb3\x01\n" +
+	"\x12PassiveHealthCheck\x12+\n" +
+	"\x04type\x18\x01 \x01(\tB\x17\xfaB\x14r\x12R\x04httpR\x05httpsR\x03tcpR\x04type\x124\n" +
+	"\ahealthy\x18\x02 \x01(\v2\x1a.PassiveHealthCheckHealthyR\ahealthy\x12:\n" +
+	"\tunhealthy\x18\x03 \x01(\v2\x1c.PassiveHealthCheckUnhealthyR\tunhealthy\"\xaa\x01\n" +
+	"\x18ActiveHealthCheckHealthy\x12%\n" +
+	"\binterval\x18\x01 \x01(\x05B\t\xfaB\x06\x1a\x04(\x01@\x01R\binterval\x12;\n" +
+	"\rhttp_statuses\x18\x02 \x03(\x05B\x16\xfaB\x13\x92\x01\x10\b\x01\x18\x01\"\b\x1a\x06\x18\xd7\x04(\xc8\x01(\x01R\fhttpStatuses\x12*\n" +
+	"\tsuccesses\x18\x03 \x01(\x05B\f\xfaB\t\x1a\a\x18\xfe\x01(\x01@\x01R\tsuccesses\"\x8e\x02\n" +
+	"\x1aActiveHealthCheckUnhealthy\x12%\n" +
+	"\binterval\x18\x01 \x01(\x05B\t\xfaB\x06\x1a\x04(\x01@\x01R\binterval\x12;\n" +
+	"\rhttp_statuses\x18\x02 \x03(\x05B\x16\xfaB\x13\x92\x01\x10\b\x01\x18\x01\"\b\x1a\x06\x18\xd7\x04(\xc8\x01(\x01R\fhttpStatuses\x121\n" +
+	"\rhttp_failures\x18\x03 \x01(\x05B\f\xfaB\t\x1a\a\x18\xfe\x01(\x01@\x01R\fhttpFailures\x12/\n" +
+	"\ftcp_failures\x18\x04 \x01(\x05B\f\xfaB\t\x1a\a\x18\xfe\x01(\x01@\x01R\vtcpFailures\x12(\n" +
+	"\btimeouts\x18\x05 \x01(\x05B\f\xfaB\t\x1a\a\x18\xfe\x01(\x01@\x01R\btimeouts\"\x84\x01\n" +
+	"\x19PassiveHealthCheckHealthy\x12;\n" +
+	"\rhttp_statuses\x18\x01 \x03(\x05B\x16\xfaB\x13\x92\x01\x10\b\x01\x18\x01\"\b\x1a\x06\x18\xd7\x04(\xc8\x01(\x01R\fhttpStatuses\x12*\n" +
+	"\tsuccesses\x18\x02 \x01(\x05B\f\xfaB\t\x1a\a\x18\xfe\x01(\x01@\x01R\tsuccesses\"\xe8\x01\n" +
+	"\x1bPassiveHealthCheckUnhealthy\x12;\n" +
+	"\rhttp_statuses\x18\x01 \x03(\x05B\x16\xfaB\x13\x92\x01\x10\b\x01\x18\x01\"\b\x1a\x06\x18\xd7\x04(\xc8\x01(\x01R\fhttpStatuses\x121\n" +
+	"\rhttp_failures\x18\x02 \x01(\x05B\f\xfaB\t\x1a\a\x18\xfe\x01(\x01@\x01R\fhttpFailures\x12/\n" +
+	"\ftcp_failures\x18\x03 \x01(\x05B\f\xfaB\t\x1a\a\x18\xfe\x01(\x01@\x01R\vtcpFailures\x12(\n" +
+	"\btimeouts\x18\x04 \x01(\x05B\f\xfaB\t\x1a\a\x18\xfe\x01(\x01@\x01R\btimeouts\"\xfd\x01\n" +
+	"\x04Node\x12/\n" +
+	"\x04host\x18\x01 \x01(\tB\x1b\xfaB\x18r\x162\x14^\\*?[0-9a-zA-Z-._]+$R\x04host\x12\x1f\n" +
+	"\x04port\x18\x02 \x01(\x05B\v\xfaB\b\x1a\x06\x18\xff\xff\x03(\x01R\x04port\x12\x1f\n" +
+	"\x06weight\x18\x03 \x01(\x05B\a\xfaB\x04\x1a\x02(\x00R\x06weight\x12/\n" +
+	"\bmetadata\x18\x04 \x03(\v2\x13.Node.MetadataEntryR\bmetadata\x1aQ\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12*\n" +
+	"\x05value\x18\x02 \x01(\v2\x14.google.protobuf.AnyR\x05value:\x028\x01B\n" +
+	"Z\b.;apisixb\x06proto3"
+
 var (
 	file_upstream_proto_rawDescOnce sync.Once
-	file_upstream_proto_rawDescData = file_upstream_proto_rawDesc
+	file_upstream_proto_rawDescData []byte
 )
 
 func file_upstream_proto_rawDescGZIP() []byte {
 	file_upstream_proto_rawDescOnce.Do(func() {
-		file_upstream_proto_rawDescData = protoimpl.X.CompressGZIP(file_upstream_proto_rawDescData)
+		file_upstream_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_upstream_proto_rawDesc), len(file_upstream_proto_rawDesc)))
 	})
 	return file_upstream_proto_rawDescData
 }
 
-var file_upstream_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
-var file_upstream_proto_goTypes = []interface{}{
+var file_upstream_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_upstream_proto_goTypes = []any{
 	(*Upstream)(nil),                    // 0: Upstream
 	(*HealthCheck)(nil),                 // 1: HealthCheck
 	(*ActiveHealthCheck)(nil),           // 2: ActiveHealthCheck
@@ -1061,26 +1027,30 @@ var file_upstream_proto_goTypes = []interface{}{
 	(*PassiveHealthCheckUnhealthy)(nil), // 7: PassiveHealthCheckUnhealthy
 	(*Node)(nil),                        // 8: Node
 	(*Upstream_Timeout)(nil),            // 9: Upstream.Timeout
-	nil,                                 // 10: Node.MetadataEntry
-	(*any.Any)(nil),                     // 11: google.protobuf.Any
+	nil,                                 // 10: Upstream.LabelsEntry
+	(*Upstream_TLS)(nil),                // 11: Upstream.TLS
+	nil,                                 // 12: Node.MetadataEntry
+	(*anypb.Any)(nil),                   // 13: google.protobuf.Any
 }
 var file_upstream_proto_depIdxs = []int32{
 	9,  // 0: Upstream.timeout:type_name -> Upstream.Timeout
 	1,  // 1: Upstream.check:type_name -> HealthCheck
 	8,  // 2: Upstream.nodes:type_name -> Node
-	2,  // 3: HealthCheck.active:type_name -> ActiveHealthCheck
-	3,  // 4: HealthCheck.passive:type_name -> PassiveHealthCheck
-	4,  // 5: ActiveHealthCheck.healthy:type_name -> ActiveHealthCheckHealthy
-	5,  // 6: ActiveHealthCheck.unhealthy:type_name -> ActiveHealthCheckUnhealthy
-	6,  // 7: PassiveHealthCheck.healthy:type_name -> PassiveHealthCheckHealthy
-	7,  // 8: PassiveHealthCheck.unhealthy:type_name -> PassiveHealthCheckUnhealthy
-	10, // 9: Node.metadata:type_name -> Node.MetadataEntry
-	11, // 10: Node.MetadataEntry.value:type_name -> google.protobuf.Any
-	11, // [11:11] is the sub-list for method output_type
-	11, // [11:11] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	10, // 3: Upstream.labels:type_name -> Upstream.LabelsEntry
+	11, // 4: Upstream.tls:type_name -> Upstream.TLS
+	2,  // 5: HealthCheck.active:type_name -> ActiveHealthCheck
+	3,  // 6: HealthCheck.passive:type_name -> PassiveHealthCheck
+	4,  // 7: ActiveHealthCheck.healthy:type_name -> ActiveHealthCheckHealthy
+	5,  // 8: ActiveHealthCheck.unhealthy:type_name -> ActiveHealthCheckUnhealthy
+	6,  // 9: PassiveHealthCheck.healthy:type_name -> PassiveHealthCheckHealthy
+	7,  // 10: PassiveHealthCheck.unhealthy:type_name -> PassiveHealthCheckUnhealthy
+	12, // 11: Node.metadata:type_name -> Node.MetadataEntry
+	13, // 12: Node.MetadataEntry.value:type_name -> google.protobuf.Any
+	13, // [13:13] is the sub-list for method output_type
+	13, // [13:13] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_upstream_proto_init() }
@@ -1088,135 +1058,13 @@ func file_upstream_proto_init() {
 	if File_upstream_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_upstream_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Upstream); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_upstream_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HealthCheck); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_upstream_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ActiveHealthCheck); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_upstream_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PassiveHealthCheck); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_upstream_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ActiveHealthCheckHealthy); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_upstream_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ActiveHealthCheckUnhealthy); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_upstream_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PassiveHealthCheckHealthy); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_upstream_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PassiveHealthCheckUnhealthy); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_upstream_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Node); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_upstream_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Upstream_Timeout); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_upstream_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_upstream_proto_rawDesc), len(file_upstream_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -1225,7 +1073,6 @@ func file_upstream_proto_init() {
 		MessageInfos:      file_upstream_proto_msgTypes,
 	}.Build()
 	File_upstream_proto = out.File
-	file_upstream_proto_rawDesc = nil
 	file_upstream_proto_goTypes = nil
 	file_upstream_proto_depIdxs = nil
 }
@@ -1,6 +1,24 @@
 package apisix
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// _typedPluginNames are the names of plugins which have their own fields in
+// the Plugins, they are derived from the JSON tags.
+var _typedPluginNames = func() map[string]struct{} {
+	names := make(map[string]struct{})
+	t := reflect.TypeOf(Plugins{})
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		if name := strings.Split(tag, ",")[0]; name != "" && name != "-" {
+			names[name] = struct{}{}
+		}
+	}
+	return names
+}()
 
 // MarshalJSON implements the json.Marshaler interface.
 func (v *Var) MarshalJSON() ([]byte, error) {
@@ -9,3 +27,59 @@ func (v *Var) MarshalJSON() ([]byte, error) {
 	}
 	return json.Marshal(v.Vars)
 }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (v *Var) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &v.Vars)
+}
+
+// MarshalJSON implements the json.Marshaler interface, the custom plugins
+// are flattened into the plugins object, plugins with fields win if the
+// names are same.
+func (p *Plugins) MarshalJSON() ([]byte, error) {
+	// The alias type drops the methods to avoid the recursion.
+	type plugins Plugins
+	data, err := json.Marshal((*plugins)(p))
+	if err != nil || len(p.GetCustom()) == 0 {
+		return data, err
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	for name, conf := range p.Custom {
+		if _, ok := obj[name]; ok {
+			continue
+		}
+		obj[name] = json.RawMessage(conf)
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, plugins without
+// their own fields are collected into the custom plugins, their configs are
+// normalized so they're comparable with the marshalled ones.
+func (p *Plugins) UnmarshalJSON(data []byte) error {
+	type plugins Plugins
+	if err := json.Unmarshal(data, (*plugins)(p)); err != nil {
+		return err
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	for name, conf := range obj {
+		if _, ok := _typedPluginNames[name]; ok {
+			continue
+		}
+		value, err := json.Marshal(conf)
+		if err != nil {
+			return err
+		}
+		if p.Custom == nil {
+			p.Custom = make(map[string]string)
+		}
+		p.Custom[name] = string(value)
+	}
+	return nil
+}
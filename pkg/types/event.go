@@ -25,6 +25,11 @@ var (
 	EventUpdate = EventType("update")
 	// EventDelete represents the delete event.
 	EventDelete = EventType("delete")
+	// EventHeartbeat represents the heartbeat event, it carries no object
+	// and is emitted periodically (if enabled) by the provisioner, so that
+	// consumers can detect a stalled provisioner by the absence of it.
+	// Consumers which don't recognize it should ignore it.
+	EventHeartbeat = EventType("heartbeat")
 )
 
 // Event describes a specific event generated from the provisioner.
@@ -35,4 +40,8 @@ type Event struct {
 	// in such a case it stands for the final state
 	// of the object.
 	Tombstone interface{}
+	// Source is the source (e.g. the file or the ConfigMap key) which
+	// change caused the event, it's empty if the provisioner doesn't
+	// tell it.
+	Source string
 }
@@ -9,4 +9,6 @@ var (
 	ClusterLoadAssignmentUrl = "type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment"
 	// ListenerUrl is the Listener type url.
 	ListenerUrl = "type.googleapis.com/envoy.config.listener.v3.Listener"
+	// SecretUrl is the SDS type url.
+	SecretUrl = "type.googleapis.com/envoy.extensions.transport_sockets.tls.v3.Secret"
 )